@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -17,20 +18,35 @@ import (
 
 	_ "github.com/breml/rootcerts"
 	"github.com/containrrr/shoutrrr"
+	"github.com/containrrr/shoutrrr/pkg/types"
 	"github.com/qdm12/ddns-updater/internal/backup"
+	"github.com/qdm12/ddns-updater/internal/bodylimit"
 	"github.com/qdm12/ddns-updater/internal/config"
 	"github.com/qdm12/ddns-updater/internal/data"
+	"github.com/qdm12/ddns-updater/internal/egress"
+	"github.com/qdm12/ddns-updater/internal/ha"
 	"github.com/qdm12/ddns-updater/internal/health"
+	"github.com/qdm12/ddns-updater/internal/importer"
+	"github.com/qdm12/ddns-updater/internal/metrics"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/netevents"
 	jsonparams "github.com/qdm12/ddns-updater/internal/params"
 	persistence "github.com/qdm12/ddns-updater/internal/persistence/json"
+	"github.com/qdm12/ddns-updater/internal/propagation"
+	"github.com/qdm12/ddns-updater/internal/ratelimit"
 	recordslib "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/rotate"
 	"github.com/qdm12/ddns-updater/internal/server"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/grpcplugin"
 	"github.com/qdm12/ddns-updater/internal/update"
+	"github.com/qdm12/ddns-updater/internal/usage"
 	"github.com/qdm12/ddns-updater/pkg/publicip"
 	"github.com/qdm12/golibs/connectivity"
 	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/params"
+	libredis "github.com/qdm12/golibs/redis"
 	"github.com/qdm12/goshutdown"
 	"github.com/qdm12/gosplash"
 )
@@ -93,7 +109,8 @@ func main() {
 }
 
 var (
-	errShoutrrrSetup = errors.New("failed setting up Shoutrrr")
+	errShoutrrrSetup   = errors.New("failed setting up Shoutrrr")
+	errAdminTokenUnset = errors.New("admin token is not set")
 )
 
 func _main(ctx context.Context, env params.Interface, args []string, logger logging.ParentLogger,
@@ -114,6 +131,26 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 		return nil
 	}
 
+	if importer.IsImportMode(args) {
+		// Printing record settings for the existing records of a DNS zone,
+		// to be added to the configuration.
+		const timeout = 10 * time.Second
+		client := &http.Client{Timeout: timeout}
+		return importer.Run(ctx, args, client, os.Stdout)
+	}
+
+	if rotate.IsRotateMode(args) {
+		// Rotating the credentials of an account in the configuration file,
+		// after verifying them with a read only call to the provider.
+		var paths config.Paths
+		if err := paths.Get(env); err != nil {
+			return err
+		}
+		const timeout = 10 * time.Second
+		client := &http.Client{Timeout: timeout}
+		return rotate.Run(ctx, args, paths.JSON, client, os.Stdout)
+	}
+
 	announcementExp, err := time.Parse(time.RFC3339, "2021-07-22T00:00:00Z")
 	if err != nil {
 		return err
@@ -150,6 +187,22 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 		Caller: config.Logger.Caller}
 	logger = logging.New(loggerSettings)
 
+	if hasFlag(args, "--dry-run") {
+		config.Update.DryRun = true
+	}
+	if config.Update.DryRun {
+		logger.Warn("dry run mode: provider calls are logged and not sent")
+	}
+	if hasFlag(args, "--once") {
+		config.Update.Once = true
+	}
+	if config.Update.Once {
+		// All the records are checked in the single update cycle,
+		// and the propagation would not be verified before exiting.
+		config.Update.OnboardingRampUp = 0
+		config.Update.PropagationCheck = false
+	}
+
 	sender, err := shoutrrr.CreateSender(config.Shoutrrr.Addresses...)
 	if err != nil {
 		return fmt.Errorf("%w: %s", errShoutrrrSetup, err)
@@ -170,7 +223,36 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 		return err
 	}
 
-	jsonReader := jsonparams.NewReader(logger)
+	usageTracker, err := usage.New(config.Paths.DataDir, config.Update.DailyBudget, logger, timeNow)
+	if err != nil {
+		notify(err.Error())
+		return err
+	}
+
+	matcher := regex.NewMatcher()
+	if err := matcher.Relax(config.Credentials.RelaxedRules); err != nil {
+		return err
+	}
+	if config.Paths.PluginsDir != "" {
+		// Plugins must be discovered before reading the settings,
+		// which reference them by name.
+		names, warnings, err := grpcplugin.Discover(ctx, config.Paths.PluginsDir)
+		for _, w := range warnings {
+			logger.Warn(w)
+		}
+		if err != nil {
+			notify(err.Error())
+			return err
+		}
+		logger.Info("Found " + fmt.Sprint(len(names)) + " plugin(s): " + strings.Join(names, ", "))
+		defer func() {
+			if err := grpcplugin.Close(); err != nil {
+				logger.Error(err.Error())
+			}
+		}()
+	}
+
+	jsonReader := jsonparams.NewReader(logger, matcher)
 	settings, warnings, err := jsonReader.JSONSettings(config.Paths.JSON)
 	for _, w := range warnings {
 		logger.Warn(w)
@@ -181,6 +263,13 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 		return err
 	}
 
+	if config.Server.AdminToken == "" && hasTenants(settings) {
+		err = fmt.Errorf("%w: it must be set with ADMIN_TOKEN to not "+
+			"serve the records of all tenants without authentication", errAdminTokenUnset)
+		notify(err.Error())
+		return err
+	}
+
 	L := len(settings)
 	switch L {
 	case 0:
@@ -191,7 +280,21 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 		logger.Info("Found " + fmt.Sprint(len(settings)) + " settings to update records")
 	}
 
-	client := &http.Client{Timeout: config.Client.Timeout}
+	userAgent := config.Client.UserAgent
+	if userAgent == "" {
+		userAgent = headers.DefaultUserAgent(buildInfo.Version)
+	}
+	transport := bodylimit.NewRoundTripper(http.DefaultTransport, config.Client.MaxResponseSize)
+	client := &http.Client{
+		Timeout:   config.Client.Timeout,
+		Transport: headers.NewRoundTripper(transport, userAgent, config.Client.Headers),
+	}
+	if config.Egress.Guard {
+		allowedHosts := egressAllowedHosts(config, settings)
+		logger.Info("outbound requests are only allowed to: " + strings.Join(allowedHosts, ", "))
+		client.Transport = egress.NewRoundTripper(client.Transport, allowedHosts,
+			logger.NewChild(logging.Settings{Prefix: "egress guard: "}))
+	}
 
 	connectivity := connectivity.NewHTTPSGetChecker(client, http.StatusOK)
 	if err := connectivity.Check(ctx, "https://github.com"); err != nil {
@@ -201,13 +304,23 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 	records := make([]recordslib.Record, len(settings))
 	for i, s := range settings {
 		logger.Info("Reading history from database: domain " +
-			s.Domain() + " host " + s.Host())
-		events, err := persistentDB.GetEvents(s.Domain(), s.Host())
+			s.Settings.Domain() + " host " + s.Settings.Host())
+		events, err := persistentDB.GetEvents(s.Settings.Domain(), s.Settings.Host(), s.Settings.IPVersion())
+		if err != nil {
+			notify(err.Error())
+			return err
+		}
+		records[i] = recordslib.New(s.Settings, s.Options, events)
+		records[i].LastUpdate, err = persistentDB.GetLastUpdate(s.Settings.Domain(), s.Settings.Host(),
+			s.Settings.IPVersion())
 		if err != nil {
 			notify(err.Error())
 			return err
 		}
-		records[i] = recordslib.New(s, events)
+	}
+
+	if config.Update.WarmStart {
+		update.WarmStart(ctx, records, logger, timeNow)
 	}
 
 	defer client.CloseIdleConnections()
@@ -225,9 +338,56 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 		return err
 	}
 
-	updater := update.NewUpdater(db, client, notify, logger)
-	runner := update.NewRunner(db, updater, ipGetter, config.Update.Period,
-		config.IPv6.Mask, config.Update.Cooldown, logger, timeNow)
+	var leader update.Leader = ha.Standalone{}
+	electorHandler, electorCtx, electorDone := goshutdown.NewGoRoutineHandler("leader elector")
+	if config.HA.RedisAddress == "" {
+		close(electorDone)
+	} else {
+		host, port, _ := net.SplitHostPort(config.HA.RedisAddress) // already validated
+		redisDB, err := libredis.NewDB(host, port, config.HA.RedisPassword)
+		if err != nil {
+			return err
+		}
+		defer redisDB.Close()
+		elector := ha.NewElector(ha.NewRedisLocker(redisDB), config.HA.LockKey,
+			config.HA.InstanceID, config.HA.LeaseDuration,
+			logger.NewChild(logging.Settings{Prefix: "high availability: "}))
+		go elector.Run(electorCtx, electorDone)
+		leader = elector
+	}
+
+	tenantNotify, err := makeTenantNotify(settings, &config.Shoutrrr.Params, logger)
+	if err != nil {
+		notify(err.Error())
+		return err
+	}
+	var propagationChecker update.PropagationChecker
+	if config.Update.PropagationCheck {
+		propagationChecker, err = propagation.New(config.Update.Propagation)
+		if err != nil {
+			err = fmt.Errorf("propagation check settings: %w", err)
+			notify(err.Error())
+			return err
+		}
+	}
+	rateLimiter := ratelimit.New(config.Update.RateLimits, config.Update.RateLimitPerAccount, timeNow)
+	updater := update.NewUpdater(db, client, notify, tenantNotify, config.Shoutrrr.Template,
+		usageTracker, rateLimiter, config.Update.Backoff, config.Update.DryRun, propagationChecker, leader, logger)
+	snapshotter := backup.NewSnapshotter(config.Paths.DataDir, db, updater, timeNow)
+	if err := snapshotter.ApplyRestoredState(); err != nil {
+		logger.Warn("applying restored state: " + err.Error())
+	}
+	runner := update.NewRunner(db, updater, ipGetter, config.Update.Periods,
+		config.IPv6.Mask, config.IPv6.CompareMask, config.Update.Cooldown, config.Update.ForceUpdatePeriod,
+		config.Update.Anomalies, config.Update.Hysteresis,
+		config.Update.OnboardingRampUp, config.Update.Lookup, config.Update.Workers, leader, logger, timeNow)
+
+	if config.Update.Once {
+		return runner.RunOnce(ctx, os.Stdout)
+	}
+
+	updaterHandler, updaterCtx, updaterDone := goshutdown.NewGoRoutineHandler("updater")
+	go updater.Run(updaterCtx, updaterDone)
 
 	runnerHandler, runnerCtx, runnerDone := goshutdown.NewGoRoutineHandler("runner")
 	go runner.Run(runnerCtx, runnerDone)
@@ -243,9 +403,22 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 	healthServerHandler, healthServerCtx, healthServerDone := goshutdown.NewGoRoutineHandler("health server")
 	go healthServer.Run(healthServerCtx, healthServerDone)
 
+	shutdownGroup := goshutdown.NewGroupHandler("")
+	if config.Server.StatusMirrorAddress != "" {
+		statusMirror := server.NewStatusMirror(ctx, config.Server.StatusMirrorAddress,
+			config.Server.RootURL, db, runner, config.Server.AdminToken,
+			logger.NewChild(logging.Settings{Prefix: "status mirror server: "}))
+		statusMirrorHandler, statusMirrorCtx, statusMirrorDone := goshutdown.NewGoRoutineHandler("status mirror server")
+		go statusMirror.Run(statusMirrorCtx, statusMirrorDone)
+		shutdownGroup.Add(statusMirrorHandler)
+	}
+
+	metricsHandler := metrics.NewHandler(db, updater, runner, config.Server.MetricsStalePeriod, timeNow)
 	address := ":" + strconv.Itoa(int(config.Server.Port))
 	serverLogger := logger.NewChild(logging.Settings{Prefix: "http server: "})
-	server := server.New(ctx, address, config.Server.RootURL, db, serverLogger, runner)
+	server := server.New(ctx, address, config.Server.RootURL, db, serverLogger,
+		runner, runner, updater, usageTracker, metricsHandler, snapshotter, config.Server.DynDNS2,
+		config.Server.WebhookToken, config.Server.SnapshotToken, config.Server.AdminToken)
 	serverHandler, serverCtx, serverDone := goshutdown.NewGoRoutineHandler("server")
 	go server.Run(serverCtx, serverDone)
 	notify("Launched with " + strconv.Itoa(len(records)) + " records to watch")
@@ -254,8 +427,12 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 	go backupRunLoop(backupCtx, backupDone, config.Backup.Period, config.Paths.DataDir, config.Backup.Directory,
 		logger.NewChild(logging.Settings{Prefix: "backup: "}), timeNow)
 
-	shutdownGroup := goshutdown.NewGroupHandler("")
-	shutdownGroup.Add(runnerHandler, healthServerHandler, serverHandler, backupHandler)
+	netEventsHandler, netEventsCtx, netEventsDone := goshutdown.NewGoRoutineHandler("network events")
+	go networkEventsLoop(netEventsCtx, netEventsDone, config.Update.NetworkEvents, runner,
+		logger.NewChild(logging.Settings{Prefix: "network events: "}))
+
+	shutdownGroup.Add(runnerHandler, updaterHandler, healthServerHandler, serverHandler,
+		backupHandler, electorHandler, netEventsHandler)
 
 	<-ctx.Done()
 
@@ -266,6 +443,77 @@ func _main(ctx context.Context, env params.Interface, args []string, logger logg
 	return nil
 }
 
+func hasTenants(settings []recordslib.Config) bool {
+	for _, s := range settings {
+		if s.Options.Tenant != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// makeTenantNotify creates a Shoutrrr sender for each
+// tenant of the record settings with notification targets.
+func makeTenantNotify(settings []recordslib.Config, params *types.Params,
+	logger logging.Logger) (tenantNotify map[string]func(message string), err error) {
+	tenantNotify = make(map[string]func(message string))
+	for _, s := range settings {
+		tenant := s.Options.Tenant
+		if tenant == nil || len(tenant.Notifications) == 0 {
+			continue
+		} else if _, ok := tenantNotify[tenant.Name]; ok {
+			continue
+		}
+		sender, err := shoutrrr.CreateSender(tenant.Notifications...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: for tenant %s: %s", errShoutrrrSetup, tenant.Name, err)
+		}
+		tenantNotify[tenant.Name] = func(message string) {
+			errs := sender.Send(message, params)
+			for i, err := range errs {
+				if err != nil {
+					destination := strings.Split(tenant.Notifications[i], ":")[0]
+					logger.Error("tenant " + tenant.Name + ": " + destination + ": " + err.Error())
+				}
+			}
+		}
+	}
+	return tenantNotify, nil
+}
+
+func egressAllowedHosts(config config.Config, settings []recordslib.Config) (
+	allowedHosts []string) {
+	allowedHosts = append(allowedHosts, config.Egress.AllowedHosts...)
+	allowedHosts = append(allowedHosts, config.PubIP.HTTPHosts...)
+	allowedHosts = append(allowedHosts, "github.com") // connectivity check
+	for _, s := range settings {
+		allowedHosts = append(allowedHosts, s.Settings.APIHosts()...)
+		if s.Options.Fallback != nil {
+			allowedHosts = append(allowedHosts, s.Options.Fallback.APIHosts()...)
+		}
+		if s.Options.PTR != nil {
+			allowedHosts = append(allowedHosts, s.Options.PTR.APIHost())
+		}
+		for _, webhook := range s.Options.Webhooks {
+			u, err := url.Parse(webhook.URL)
+			if err == nil { // already validated
+				allowedHosts = append(allowedHosts, u.Hostname())
+			}
+		}
+	}
+	return allowedHosts
+}
+
+// hasFlag returns true if the flag given is one of the program arguments.
+func hasFlag(args []string, flag string) bool {
+	for i := 1; i < len(args); i++ {
+		if args[i] == flag {
+			return true
+		}
+	}
+	return false
+}
+
 func backupRunLoop(ctx context.Context, done chan<- struct{}, backupPeriod time.Duration,
 	dataDir, outputDir string, logger logging.Logger, timeNow func() time.Time) {
 	defer close(done)
@@ -296,3 +544,46 @@ func backupRunLoop(ctx context.Context, done chan<- struct{}, backupPeriod time.
 		}
 	}
 }
+
+// networkEventsLoop forces an update when the IP addresses of the network
+// interfaces change, waiting for the changes to settle since switching
+// networks usually produces several events in a short time.
+func networkEventsLoop(ctx context.Context, done chan<- struct{}, enabled bool,
+	runner *update.Runner, logger logging.Logger) {
+	defer close(done)
+	if !enabled {
+		return
+	}
+
+	events := make(chan struct{}, 1)
+	watchErr := make(chan error)
+	go func() {
+		watchErr <- netevents.Watch(ctx, events)
+	}()
+	logger.Info("watching for network changes")
+
+	const settleDuration = 3 * time.Second
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			<-watchErr
+			return
+		case err := <-watchErr:
+			timer.Stop()
+			if err != nil {
+				logger.Warn(err.Error())
+			}
+			return
+		case <-events:
+			timer.Reset(settleDuration)
+		case <-timer.C:
+			logger.Info("network change detected, updating records")
+			for _, err := range runner.ForceUpdate(ctx) {
+				logger.Error(err.Error())
+			}
+		}
+	}
+}
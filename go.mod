@@ -8,12 +8,16 @@ require (
 	github.com/containrrr/shoutrrr v0.5.1
 	github.com/go-chi/chi v1.5.4
 	github.com/golang/mock v1.6.0
+	github.com/gomodule/redigo v2.0.0+incompatible
 	github.com/miekg/dns v1.1.42
 	github.com/qdm12/golibs v0.0.0-20210822203818-5c568b0777b6
 	github.com/qdm12/goshutdown v0.3.0
 	github.com/qdm12/gosplash v0.1.0
 	github.com/stretchr/testify v1.7.0
+	github.com/yuin/gopher-lua v1.1.1
 	google.golang.org/api v0.96.0
+	google.golang.org/grpc v1.47.0
+	google.golang.org/protobuf v1.28.0
 )
 
 require (
@@ -45,8 +49,6 @@ require (
 	golang.org/x/xerrors v0.0.0-20220609144429-65e65417b02f // indirect
 	google.golang.org/appengine v1.6.7 // indirect
 	google.golang.org/genproto v0.0.0-20220624142145-8cd45d7dbd1f // indirect
-	google.golang.org/grpc v1.47.0 // indirect
-	google.golang.org/protobuf v1.28.0 // indirect
 	gopkg.in/ini.v1 v1.62.0 // indirect
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
 	gopkg.in/yaml.v2 v2.3.0 // indirect
@@ -0,0 +1,293 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+const (
+	configFilename  = "config.json"
+	updatesFilename = "updates.json"
+	usageFilename   = "usage.json"
+	stateFilename   = "state.json"
+	// maxSnapshotSize is the maximum uncompressed size of a snapshot.
+	maxSnapshotSize = 64 * 1024 * 1024
+)
+
+// State is the runtime state not persisted in the data directory,
+// which is carried over to another instance through a snapshot.
+type State struct {
+	// RecordCooldowns maps a record string to the time its cooldown ends.
+	RecordCooldowns map[string]time.Time `json:"record_cooldowns"`
+	// RecordBackoffs maps a record string to its backoff state
+	// after consecutive failed updates.
+	RecordBackoffs map[string]RecordBackoff `json:"record_backoffs"`
+	// Accounts maps an account key to its ban and circuit breaker state.
+	Accounts map[string]update.AccountState `json:"accounts"`
+}
+
+// RecordBackoff is the backoff state of a record.
+type RecordBackoff struct {
+	// Failures is the number of consecutive failed updates.
+	Failures uint `json:"failures"`
+	// Until is the time before which no update is attempted.
+	Until time.Time `json:"until"`
+}
+
+type SnapshotDatabase interface {
+	SelectAll() (records []records.Record)
+	Update(recordID uint, record records.Record) (err error)
+	// RLock and RUnlock are used to prevent the data
+	// store from being written to while reading it.
+	RLock()
+	RUnlock()
+}
+
+type AccountStater interface {
+	AccountStates() (states map[string]update.AccountState)
+	RestoreAccountStates(states map[string]update.AccountState)
+}
+
+// Snapshotter creates and restores snapshots of the configuration,
+// the data store and the runtime state, as gzipped tarballs.
+type Snapshotter struct {
+	dataDir string
+	db      SnapshotDatabase
+	updater AccountStater
+	timeNow func() time.Time
+}
+
+func NewSnapshotter(dataDir string, db SnapshotDatabase,
+	updater AccountStater, timeNow func() time.Time) *Snapshotter {
+	return &Snapshotter{
+		dataDir: dataDir,
+		db:      db,
+		updater: updater,
+		timeNow: timeNow,
+	}
+}
+
+// Snapshot writes a gzipped tarball of the configuration,
+// the data store and the runtime state to the writer given.
+func (s *Snapshotter) Snapshot(writer io.Writer) (err error) {
+	state := State{
+		RecordCooldowns: make(map[string]time.Time),
+		RecordBackoffs:  make(map[string]RecordBackoff),
+		Accounts:        s.updater.AccountStates(),
+	}
+	for _, record := range s.db.SelectAll() {
+		key := record.Settings.String()
+		if !record.CooldownUntil.IsZero() {
+			state.RecordCooldowns[key] = record.CooldownUntil
+		}
+		if record.Failures > 0 || !record.BackoffUntil.IsZero() {
+			state.RecordBackoffs[key] = RecordBackoff{
+				Failures: record.Failures,
+				Until:    record.BackoffUntil,
+			}
+		}
+	}
+	stateData, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding state: %w", err)
+	}
+
+	files, err := s.readDataFiles()
+	if err != nil {
+		return err
+	}
+	files[stateFilename] = stateData
+
+	gzipWriter := gzip.NewWriter(writer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	modTime := s.timeNow()
+	// Write files in a fixed order for the snapshot to be reproducible.
+	for _, name := range []string{configFilename, updatesFilename, usageFilename, stateFilename} {
+		data, ok := files[name]
+		if !ok {
+			continue
+		}
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: modTime,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("writing tar header for %s: %w", name, err)
+		}
+		if _, err := tarWriter.Write(data); err != nil {
+			return fmt.Errorf("writing %s to tarball: %w", name, err)
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("closing tar writer: %w", err)
+	}
+	return gzipWriter.Close()
+}
+
+// readDataFiles reads the files of the data directory while preventing
+// the data store from being written to, for the snapshot to be consistent.
+func (s *Snapshotter) readDataFiles() (files map[string][]byte, err error) {
+	s.db.RLock()
+	defer s.db.RUnlock()
+
+	files = make(map[string][]byte)
+	for _, name := range []string{configFilename, updatesFilename, usageFilename} {
+		data, err := os.ReadFile(filepath.Join(s.dataDir, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) && name == usageFilename {
+				continue
+			}
+			return nil, err
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+var (
+	ErrSnapshotFileUnexpected = errors.New("unexpected file in snapshot")
+	ErrSnapshotFileMissing    = errors.New("file missing in snapshot")
+	ErrSnapshotFileMalformed  = errors.New("file in snapshot is not valid JSON")
+	ErrSnapshotTooLarge       = errors.New("snapshot is too large")
+)
+
+// Restore extracts a snapshot created with Snapshot to the data directory.
+// The snapshot is fully validated before any file is written, and each file
+// is replaced atomically. The program must be restarted to use the restored
+// configuration and data store, and the restored runtime state is applied
+// at the next launch with ApplyRestoredState.
+func (s *Snapshotter) Restore(reader io.Reader) (err error) {
+	files, err := readSnapshot(reader)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{configFilename, updatesFilename} {
+		if _, ok := files[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrSnapshotFileMissing, name)
+		}
+	}
+	for name, data := range files {
+		if !json.Valid(data) {
+			return fmt.Errorf("%w: %s", ErrSnapshotFileMalformed, name)
+		}
+	}
+
+	temporaryPaths := make(map[string]string, len(files))
+	defer func() {
+		for _, path := range temporaryPaths {
+			_ = os.Remove(path)
+		}
+	}()
+	for name, data := range files {
+		path := filepath.Join(s.dataDir, name+".restore")
+		const perm = 0600
+		if err := os.WriteFile(path, data, perm); err != nil {
+			return err
+		}
+		temporaryPaths[name] = path
+	}
+
+	for name, temporaryPath := range temporaryPaths {
+		if err := os.Rename(temporaryPath, filepath.Join(s.dataDir, name)); err != nil {
+			return err
+		}
+		delete(temporaryPaths, name)
+	}
+	return nil
+}
+
+func readSnapshot(reader io.Reader) (files map[string][]byte, err error) {
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("creating gzip reader: %w", err)
+	}
+	defer gzipReader.Close()
+
+	allowed := map[string]struct{}{
+		configFilename:  {},
+		updatesFilename: {},
+		usageFilename:   {},
+		stateFilename:   {},
+	}
+	files = make(map[string][]byte, len(allowed))
+	limitedReader := &io.LimitedReader{R: gzipReader, N: maxSnapshotSize + 1}
+	tarReader := tar.NewReader(limitedReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading tarball: %w", err)
+		}
+
+		_, isAllowed := allowed[header.Name]
+		if !isAllowed || header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotFileUnexpected, header.Name)
+		}
+
+		buffer := bytes.NewBuffer(nil)
+		if _, err := io.Copy(buffer, tarReader); err != nil { //nolint:gosec
+			return nil, fmt.Errorf("reading %s from tarball: %w", header.Name, err)
+		}
+		if limitedReader.N <= 0 {
+			return nil, fmt.Errorf("%w: exceeding %d bytes", ErrSnapshotTooLarge, maxSnapshotSize)
+		}
+		files[header.Name] = buffer.Bytes()
+	}
+	return files, nil
+}
+
+// ApplyRestoredState applies the runtime state restored from a snapshot,
+// if any, to the records and accounts, such as their cooldown and backoff, and removes its file such that
+// it is only applied once.
+func (s *Snapshotter) ApplyRestoredState() (err error) {
+	path := filepath.Join(s.dataDir, stateFilename)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("decoding %s: %w", path, err)
+	}
+
+	for i, record := range s.db.SelectAll() {
+		key := record.Settings.String()
+		cooldownUntil, hasCooldown := state.RecordCooldowns[key]
+		backoff, hasBackoff := state.RecordBackoffs[key]
+		if !hasCooldown && !hasBackoff {
+			continue
+		}
+		if hasCooldown {
+			record.CooldownUntil = cooldownUntil
+		}
+		if hasBackoff {
+			record.Failures = backoff.Failures
+			record.BackoffUntil = backoff.Until
+		}
+		if err := s.db.Update(uint(i), record); err != nil {
+			return err
+		}
+	}
+	s.updater.RestoreAccountStates(state.Accounts)
+
+	return os.Remove(path)
+}
@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/internal/update"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabase struct {
+	sync.RWMutex
+	records []records.Record
+}
+
+func (db *testDatabase) SelectAll() []records.Record { return db.records }
+
+func (db *testDatabase) Update(id uint, record records.Record) error {
+	db.records[id] = record
+	return nil
+}
+
+type testAccounts map[string]update.AccountState
+
+func (a testAccounts) AccountStates() map[string]update.AccountState { return a }
+
+func (a testAccounts) RestoreAccountStates(states map[string]update.AccountState) {
+	for key, state := range states {
+		a[key] = state
+	}
+}
+
+func Test_Snapshotter(t *testing.T) {
+	t.Parallel()
+
+	timeNow := func() time.Time { return time.Unix(0, 0) }
+	sourceDir := t.TempDir()
+	const perm = 0600
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "config.json"), []byte(`{"settings":[]}`), perm))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "updates.json"), []byte(`{"records":[]}`), perm))
+	bannedUntil := time.Unix(3600, 0).UTC()
+	source := NewSnapshotter(sourceDir, &testDatabase{},
+		testAccounts{"cloudflare/work": {BannedUntil: bannedUntil}}, timeNow)
+
+	buffer := bytes.NewBuffer(nil)
+	err := source.Snapshot(buffer)
+	require.NoError(t, err)
+
+	targetDir := t.TempDir()
+	targetAccounts := testAccounts{}
+	target := NewSnapshotter(targetDir, &testDatabase{}, targetAccounts, timeNow)
+	err = target.Restore(buffer)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(targetDir, "updates.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"records":[]}`, string(data))
+
+	err = target.ApplyRestoredState()
+	require.NoError(t, err)
+	assert.Equal(t, testAccounts{"cloudflare/work": {BannedUntil: bannedUntil}}, targetAccounts)
+	_, err = os.Stat(filepath.Join(targetDir, "state.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func newTestRecord(t *testing.T, host string) records.Record {
+	t.Helper()
+	settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", host, ipversion.IP4)
+	require.NoError(t, err)
+	return records.Record{Settings: settings}
+}
+
+func Test_Snapshotter_recordState(t *testing.T) {
+	t.Parallel()
+
+	timeNow := func() time.Time { return time.Unix(0, 0) }
+	sourceDir := t.TempDir()
+	const perm = 0600
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "config.json"), []byte(`{"settings":[]}`), perm))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "updates.json"), []byte(`{"records":[]}`), perm))
+
+	cooledDown := newTestRecord(t, "cooldown")
+	cooledDown.CooldownUntil = time.Unix(600, 0).UTC()
+	backedOff := newTestRecord(t, "backoff")
+	backedOff.Failures = 3
+	backedOff.BackoffUntil = time.Unix(240, 0).UTC()
+	healthy := newTestRecord(t, "healthy")
+	source := NewSnapshotter(sourceDir, &testDatabase{
+		records: []records.Record{cooledDown, backedOff, healthy},
+	}, testAccounts{}, timeNow)
+
+	buffer := bytes.NewBuffer(nil)
+	err := source.Snapshot(buffer)
+	require.NoError(t, err)
+
+	targetDir := t.TempDir()
+	targetDB := &testDatabase{records: []records.Record{
+		newTestRecord(t, "healthy"),
+		newTestRecord(t, "backoff"),
+		newTestRecord(t, "cooldown"),
+	}}
+	target := NewSnapshotter(targetDir, targetDB, testAccounts{}, timeNow)
+	err = target.Restore(buffer)
+	require.NoError(t, err)
+	err = target.ApplyRestoredState()
+	require.NoError(t, err)
+
+	assert.Zero(t, targetDB.records[0].CooldownUntil)
+	assert.Zero(t, targetDB.records[0].Failures)
+	assert.Zero(t, targetDB.records[0].BackoffUntil)
+	assert.Zero(t, targetDB.records[1].CooldownUntil)
+	assert.Equal(t, uint(3), targetDB.records[1].Failures)
+	assert.Equal(t, backedOff.BackoffUntil, targetDB.records[1].BackoffUntil)
+	assert.Equal(t, cooledDown.CooldownUntil, targetDB.records[2].CooldownUntil)
+	assert.Zero(t, targetDB.records[2].Failures)
+	assert.Zero(t, targetDB.records[2].BackoffUntil)
+}
+
+func Test_Snapshotter_Restore_unexpectedFile(t *testing.T) {
+	t.Parallel()
+
+	buffer := bytes.NewBuffer(nil)
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	content := []byte("malicious")
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+		Name: "../config.json", Mode: 0600, Size: int64(len(content)),
+	}))
+	_, err := tarWriter.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	snapshotter := NewSnapshotter(t.TempDir(), &testDatabase{}, testAccounts{}, time.Now)
+	err = snapshotter.Restore(buffer)
+	assert.ErrorIs(t, err, ErrSnapshotFileUnexpected)
+	assert.EqualError(t, err, "unexpected file in snapshot: ../config.json")
+}
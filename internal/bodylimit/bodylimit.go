@@ -0,0 +1,75 @@
+// Package bodylimit limits the size of HTTP response bodies,
+// so a misbehaving or malicious endpoint cannot exhaust memory.
+package bodylimit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var ErrBodyTooLarge = errors.New("response body is too large")
+
+type roundTripper struct {
+	proxied  http.RoundTripper
+	maxBytes int64
+}
+
+// NewRoundTripper returns a round tripper wrapping the proxied round
+// tripper, where reading more than maxBytes bytes from a response body
+// returns an error wrapping ErrBodyTooLarge.
+func NewRoundTripper(proxied http.RoundTripper, maxBytes int64) http.RoundTripper {
+	return &roundTripper{
+		proxied:  proxied,
+		maxBytes: maxBytes,
+	}
+}
+
+func (r *roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := r.proxied.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.ContentLength > r.maxBytes {
+		_ = response.Body.Close()
+		return nil, fmt.Errorf("%w: content length of %d bytes exceeds %d bytes for %s",
+			ErrBodyTooLarge, response.ContentLength, r.maxBytes, request.URL.Host)
+	}
+
+	response.Body = &limitedBody{
+		body:      response.Body,
+		remaining: r.maxBytes,
+		maxBytes:  r.maxBytes,
+	}
+	return response, nil
+}
+
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	maxBytes  int64
+}
+
+func (l *limitedBody) Read(p []byte) (n int, err error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, l.maxBytes)
+	}
+	// Read one byte more than the remaining bytes
+	// to detect if the body is too large.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err = l.body.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		n += int(l.remaining) // only return bytes within the limit
+		return n, fmt.Errorf("%w: exceeds %d bytes", ErrBodyTooLarge, l.maxBytes)
+	}
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
@@ -0,0 +1,78 @@
+package bodylimit
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func Test_roundTripper(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		body          string
+		contentLength int64
+		maxBytes      int64
+		data          string
+		errWrapped    error
+	}{
+		"body within limit": {
+			body:          "1.2.3.4",
+			contentLength: -1,
+			maxBytes:      7,
+			data:          "1.2.3.4",
+		},
+		"body too large": {
+			body:          "1.2.3.4",
+			contentLength: -1,
+			maxBytes:      4,
+			data:          "1.2.",
+			errWrapped:    ErrBodyTooLarge,
+		},
+		"content length too large": {
+			body:          "1.2.3.4",
+			contentLength: 7,
+			maxBytes:      4,
+			errWrapped:    ErrBodyTooLarge,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			proxied := roundTripFunc(func(request *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode:    http.StatusOK,
+					ContentLength: testCase.contentLength,
+					Body:          io.NopCloser(strings.NewReader(testCase.body)),
+				}, nil
+			})
+			client := &http.Client{
+				Transport: NewRoundTripper(proxied, testCase.maxBytes),
+			}
+
+			response, err := client.Get("http://example.com")
+			if err != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				return
+			}
+			defer response.Body.Close()
+
+			data, err := io.ReadAll(response.Body)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			require.Equal(t, testCase.data, string(data))
+		})
+	}
+}
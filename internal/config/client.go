@@ -1,7 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/qdm12/golibs/params"
@@ -9,6 +14,14 @@ import (
 
 type Client struct {
 	Timeout time.Duration
+	// UserAgent is the User-Agent header value for all HTTP requests.
+	// If empty, a default value with the program version is used.
+	UserAgent string
+	// Headers are extra headers set on all HTTP requests.
+	Headers http.Header
+	// MaxResponseSize is the maximum size in bytes
+	// of HTTP response bodies.
+	MaxResponseSize int64
 }
 
 func (c *Client) get(env params.Interface) (err error) {
@@ -17,5 +30,57 @@ func (c *Client) get(env params.Interface) (err error) {
 		return fmt.Errorf("%w: for environment variable HTTP_TIMEOUT", err)
 	}
 
+	maxResponseSize, err := env.IntRange("HTTP_RESPONSE_MAX_SIZE", 1, math.MaxInt32,
+		params.Default("1048576"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HTTP_RESPONSE_MAX_SIZE", err)
+	}
+	c.MaxResponseSize = int64(maxResponseSize)
+
+	c.UserAgent, err = env.Get("HTTP_USER_AGENT", params.CaseSensitiveValue())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HTTP_USER_AGENT", err)
+	}
+
+	headers, err := env.CSV("HTTP_HEADERS", params.CaseSensitiveValue())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HTTP_HEADERS", err)
+	}
+	c.Headers, err = parseHTTPHeaders(headers)
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HTTP_HEADERS", err)
+	}
+
+	contactEmail, err := env.Get("HTTP_CONTACT_EMAIL", params.CaseSensitiveValue())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HTTP_CONTACT_EMAIL", err)
+	} else if contactEmail != "" {
+		_, err = mail.ParseAddress(contactEmail)
+		if err != nil {
+			return fmt.Errorf("%w: for environment variable HTTP_CONTACT_EMAIL: %s",
+				ErrContactEmailMalformed, err)
+		}
+		c.Headers.Set("From", contactEmail)
+	}
+
 	return nil
 }
+
+var (
+	ErrContactEmailMalformed = errors.New("contact email is malformed")
+	ErrHTTPHeaderMalformed   = errors.New("HTTP header is malformed")
+)
+
+// parseHTTPHeaders parses headers of the form name:value.
+func parseHTTPHeaders(headers []string) (parsed http.Header, err error) {
+	parsed = make(http.Header, len(headers))
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %s", ErrHTTPHeaderMalformed, header)
+		}
+		parsed.Add(name, strings.TrimSpace(value))
+	}
+	return parsed, nil
+}
@@ -5,16 +5,19 @@ import (
 )
 
 type Config struct {
-	Client   Client
-	Update   Update
-	PubIP    PubIP
-	IPv6     IPv6
-	Server   Server
-	Health   Health
-	Paths    Paths
-	Backup   Backup
-	Logger   Logger
-	Shoutrrr Shoutrrr
+	Client      Client
+	Update      Update
+	PubIP       PubIP
+	IPv6        IPv6
+	Server      Server
+	Health      Health
+	Paths       Paths
+	Backup      Backup
+	Logger      Logger
+	Shoutrrr    Shoutrrr
+	HA          HA
+	Egress      Egress
+	Credentials Credentials
 }
 
 func (c *Config) Get(env params.Interface) (warnings []string, err error) {
@@ -50,7 +53,7 @@ func (c *Config) Get(env params.Interface) (warnings []string, err error) {
 		return warnings, err
 	}
 
-	if err := c.Paths.get(env); err != nil {
+	if err := c.Paths.Get(env); err != nil {
 		return warnings, err
 	}
 
@@ -68,5 +71,17 @@ func (c *Config) Get(env params.Interface) (warnings []string, err error) {
 		return warnings, err
 	}
 
+	if err := c.HA.get(env); err != nil {
+		return warnings, err
+	}
+
+	if err := c.Egress.get(env); err != nil {
+		return warnings, err
+	}
+
+	if err := c.Credentials.get(env); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/golibs/params"
+)
+
+type Credentials struct {
+	// RelaxedRules are the names of the credential format
+	// rules to not check, for example if a provider changed
+	// its credentials format.
+	RelaxedRules []string
+}
+
+func (c *Credentials) get(env params.Interface) (err error) {
+	c.RelaxedRules, err = env.CSV("RELAXED_CREDENTIALS_RULES")
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable RELAXED_CREDENTIALS_RULES", err)
+	}
+
+	err = regex.CheckRules(c.RelaxedRules)
+	if err != nil {
+		return fmt.Errorf("for environment variable RELAXED_CREDENTIALS_RULES: %w", err)
+	}
+
+	return nil
+}
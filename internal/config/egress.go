@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/qdm12/golibs/params"
+)
+
+type Egress struct {
+	Guard bool
+	// AllowedHosts are additional hostnames allowed on top of the
+	// hostnames of the configured providers and public IP fetchers.
+	AllowedHosts []string
+}
+
+func (e *Egress) get(env params.Interface) (err error) {
+	e.Guard, err = env.OnOff("EGRESS_GUARD", params.Default("off"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable EGRESS_GUARD", err)
+	}
+
+	e.AllowedHosts, err = env.CSV("EGRESS_ALLOWED_HOSTS")
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable EGRESS_ALLOWED_HOSTS", err)
+	}
+
+	return nil
+}
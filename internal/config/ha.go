@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/qdm12/golibs/params"
+)
+
+type HA struct {
+	// RedisAddress is the host:port address of the Redis server
+	// used for leader election. If empty, high availability is disabled.
+	RedisAddress  string
+	RedisPassword string
+	LockKey       string
+	InstanceID    string
+	LeaseDuration time.Duration
+}
+
+func (h *HA) get(env params.Interface) (err error) {
+	h.RedisAddress, err = env.Get("HA_REDIS_ADDRESS")
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HA_REDIS_ADDRESS", err)
+	} else if h.RedisAddress == "" {
+		return nil
+	}
+
+	_, _, err = net.SplitHostPort(h.RedisAddress)
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HA_REDIS_ADDRESS", err)
+	}
+
+	h.RedisPassword, err = env.Get("HA_REDIS_PASSWORD", params.CaseSensitiveValue(), params.Unset())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HA_REDIS_PASSWORD", err)
+	}
+
+	h.LockKey, err = env.Get("HA_LOCK_KEY", params.Default("ddns-updater-leader"),
+		params.CaseSensitiveValue())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HA_LOCK_KEY", err)
+	}
+
+	defaultInstanceID, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("cannot get hostname for default instance ID: %w", err)
+	}
+	h.InstanceID, err = env.Get("HA_INSTANCE_ID", params.Default(defaultInstanceID),
+		params.CaseSensitiveValue())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HA_INSTANCE_ID", err)
+	}
+
+	h.LeaseDuration, err = env.Duration("HA_LEASE_DURATION", params.Default("30s"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable HA_LEASE_DURATION", err)
+	}
+
+	return nil
+}
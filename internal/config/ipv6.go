@@ -11,6 +11,9 @@ import (
 
 type IPv6 struct {
 	Mask net.IPMask
+	// CompareMask is the mask applied to IPv6 addresses to compare
+	// them, without changing the IPv6 address published.
+	CompareMask net.IPMask
 }
 
 func (i *IPv6) get(env params.Interface) (err error) {
@@ -23,6 +26,15 @@ func (i *IPv6) get(env params.Interface) (err error) {
 		return fmt.Errorf("%w: for environment variable IPV6_PREFIX", err)
 	}
 
+	compareMaskStr, err := env.Get("IPV6_COMPARE_PREFIX", params.Default("/128"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable IPV6_COMPARE_PREFIX", err)
+	}
+	i.CompareMask, err = ipv6DecimalPrefixToMask(compareMaskStr)
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable IPV6_COMPARE_PREFIX", err)
+	}
+
 	return nil
 }
 
@@ -10,14 +10,22 @@ import (
 type Paths struct {
 	DataDir string
 	JSON    string // obtained from DataDir
+	// PluginsDir is the directory containing plugin unix sockets,
+	// and plugins are disabled if it is empty.
+	PluginsDir string
 }
 
-func (p *Paths) get(env params.Interface) (err error) {
+func (p *Paths) Get(env params.Interface) (err error) {
 	p.DataDir, err = env.Path("DATADIR", params.Default("./data"))
 	if err != nil {
 		return fmt.Errorf("%w: for environment variable DATADIR", err)
 	}
 
 	p.JSON = filepath.Join(p.DataDir, "config.json")
+
+	p.PluginsDir, err = env.Get("PLUGINS_DIR", params.CaseSensitiveValue())
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PLUGINS_DIR", err)
+	}
 	return nil
 }
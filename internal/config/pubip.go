@@ -18,6 +18,9 @@ const all = "all"
 type PubIP struct {
 	HTTPSettings publicip.HTTPSettings
 	DNSSettings  publicip.DNSSettings
+	// HTTPHosts contains the hostnames of the HTTP providers
+	// configured, obtained from the HTTP providers settings.
+	HTTPHosts []string
 }
 
 func (p *PubIP) get(env params.Interface) (warnings []string, err error) {
@@ -45,6 +48,9 @@ func (p *PubIP) get(env params.Interface) (warnings []string, err error) {
 		http.SetProvidersIP4(httpIP4Providers[0], httpIP4Providers[1:]...),
 		http.SetProvidersIP6(httpIP6Providers[0], httpIP6Providers[1:]...),
 	}
+	p.HTTPHosts = httpProvidersHosts(httpIPProviders, ipversion.IP4or6)
+	p.HTTPHosts = append(p.HTTPHosts, httpProvidersHosts(httpIP4Providers, ipversion.IP4)...)
+	p.HTTPHosts = append(p.HTTPHosts, httpProvidersHosts(httpIP6Providers, ipversion.IP6)...)
 
 	dnsIPProviders, err := p.getDNSProviders(env)
 	if err != nil {
@@ -135,6 +141,18 @@ func (p *PubIP) getIPv6HTTPProviders(env params.Interface) (
 	return httpIPMethod(env, "PUBLICIPV6_HTTP_PROVIDERS", "IPV6_METHOD", ipversion.IP6)
 }
 
+func httpProvidersHosts(providers []http.Provider, version ipversion.IPVersion) (
+	hosts []string) {
+	hosts = make([]string, 0, len(providers))
+	for _, provider := range providers {
+		host, ok := provider.Hostname(version)
+		if ok {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
 var (
 	ErrInvalidPublicIPHTTPProvider = errors.New("invalid public IP HTTP provider")
 )
@@ -1,14 +1,35 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/qdm12/ddns-updater/internal/server"
 	"github.com/qdm12/golibs/params"
 )
 
 type Server struct {
 	Port    uint16
 	RootURL string
+	DynDNS2 server.DynDNS2Settings
+	// WebhookToken is the token to authenticate confirmation
+	// webhook requests. If empty, the webhook is disabled.
+	WebhookToken string
+	// SnapshotToken is the token to authenticate snapshot and
+	// restore requests. If empty, the snapshot API is disabled.
+	SnapshotToken string
+	// AdminToken is the token to authenticate requests to the routes
+	// serving or updating all the records. If empty, these routes
+	// are not authenticated, which is refused if tenants are set.
+	AdminToken string
+	// MetricsStalePeriod is the duration after which a record
+	// failing to update is reported as stale in metrics.
+	MetricsStalePeriod time.Duration
+	// StatusMirrorAddress is the listening address of the read-only
+	// status server. If empty, the status server is disabled.
+	StatusMirrorAddress string
 }
 
 func (s *Server) get(env params.Interface) (warning string, err error) {
@@ -22,5 +43,91 @@ func (s *Server) get(env params.Interface) (warning string, err error) {
 		return "", fmt.Errorf("%w: for environment variable LISTENING_PORT", err)
 	}
 
-	return warning, err
+	s.WebhookToken, err = env.Get("WEBHOOK_TOKEN", params.CaseSensitiveValue(), params.Unset())
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable WEBHOOK_TOKEN", err)
+	}
+
+	s.SnapshotToken, err = env.Get("SNAPSHOT_TOKEN", params.CaseSensitiveValue(), params.Unset())
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable SNAPSHOT_TOKEN", err)
+	}
+
+	s.AdminToken, err = env.Get("ADMIN_TOKEN", params.CaseSensitiveValue(), params.Unset())
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable ADMIN_TOKEN", err)
+	}
+
+	s.MetricsStalePeriod, err = env.Duration("METRICS_STALE_PERIOD", params.Default("24h"))
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable METRICS_STALE_PERIOD", err)
+	}
+
+	s.StatusMirrorAddress, err = env.Get("STATUS_MIRROR_ADDRESS", params.Unset())
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable STATUS_MIRROR_ADDRESS", err)
+	}
+	if s.StatusMirrorAddress != "" {
+		var mirrorWarning string
+		s.StatusMirrorAddress, mirrorWarning, err = env.ListeningAddress("STATUS_MIRROR_ADDRESS")
+		if err != nil {
+			return warning, fmt.Errorf("%w: for environment variable STATUS_MIRROR_ADDRESS", err)
+		}
+		if warning == "" {
+			warning = mirrorWarning
+		}
+	}
+
+	s.DynDNS2.Enabled, err = env.OnOff("DYNDNS2_SERVER", params.Default("off"))
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable DYNDNS2_SERVER", err)
+	}
+
+	if !s.DynDNS2.Enabled {
+		return warning, nil
+	}
+
+	credentials, err := env.CSV("DYNDNS2_SERVER_CREDENTIALS",
+		params.CaseSensitiveValue(), params.Compulsory())
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable DYNDNS2_SERVER_CREDENTIALS", err)
+	}
+	s.DynDNS2.Users, err = parseDynDNS2Credentials(credentials)
+	if err != nil {
+		return warning, fmt.Errorf("%w: for environment variable DYNDNS2_SERVER_CREDENTIALS", err)
+	}
+
+	return warning, nil
+}
+
+var (
+	ErrDynDNS2CredentialMalformed = errors.New("DynDNS2 credential is malformed")
+	ErrDynDNS2PasswordMismatch    = errors.New("DynDNS2 user has different passwords")
+)
+
+// parseDynDNS2Credentials parses credentials each in the form
+// username:password:hostname, where the same username and password
+// can be repeated to allow updating multiple hostnames.
+func parseDynDNS2Credentials(credentials []string) (
+	users map[string]server.DynDNS2User, err error) {
+	users = make(map[string]server.DynDNS2User, len(credentials))
+	for i, credential := range credentials {
+		const expectedFields = 3
+		fields := strings.SplitN(credential, ":", expectedFields)
+		if len(fields) != expectedFields || fields[0] == "" ||
+			fields[1] == "" || fields[2] == "" {
+			return nil, fmt.Errorf("%w: at position %d of %d",
+				ErrDynDNS2CredentialMalformed, i+1, len(credentials))
+		}
+		username, password, hostname := fields[0], fields[1], fields[2]
+
+		user, exists := users[username]
+		if exists && user.Password != password {
+			return nil, fmt.Errorf("%w: for user %s", ErrDynDNS2PasswordMismatch, username)
+		}
+		user.Password = password
+		user.Hostnames = append(user.Hostnames, strings.ToLower(hostname))
+		users[username] = user
+	}
+	return users, nil
 }
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseDynDNS2Credentials(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		credentials []string
+		users       map[string]server.DynDNS2User
+		err         string
+	}{
+		"empty": {
+			users: map[string]server.DynDNS2User{},
+		},
+		"missing hostname": {
+			credentials: []string{"user:password"},
+			err:         "DynDNS2 credential is malformed: at position 1 of 1",
+		},
+		"empty password": {
+			credentials: []string{"user::host.example.com"},
+			err:         "DynDNS2 credential is malformed: at position 1 of 1",
+		},
+		"password mismatch": {
+			credentials: []string{
+				"user:password:a.example.com",
+				"user:other:b.example.com",
+			},
+			err: "DynDNS2 user has different passwords: for user user",
+		},
+		"multiple hostnames for user": {
+			credentials: []string{
+				"user:password:A.example.com",
+				"user:password:b.example.com",
+				"router:secret:*",
+			},
+			users: map[string]server.DynDNS2User{
+				"user": {
+					Password:  "password",
+					Hostnames: []string{"a.example.com", "b.example.com"},
+				},
+				"router": {
+					Password:  "secret",
+					Hostnames: []string{"*"},
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			users, err := parseDynDNS2Credentials(testCase.credentials)
+
+			if testCase.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, testCase.users, users)
+		})
+	}
+}
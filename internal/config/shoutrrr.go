@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"path"
 	"strings"
+	"text/template"
 
 	"github.com/containrrr/shoutrrr"
 	"github.com/containrrr/shoutrrr/pkg/types"
@@ -14,6 +15,9 @@ import (
 type Shoutrrr struct {
 	Addresses []string
 	Params    types.Params
+	// Template is the global notification template.
+	// It is nil if not set.
+	Template *template.Template
 }
 
 func (s *Shoutrrr) get(env params.Interface) (warnings []string, err error) {
@@ -57,6 +61,16 @@ func (s *Shoutrrr) get(env params.Interface) (warnings []string, err error) {
 		s.Params[key] = value
 	}
 
+	templateString, err := env.Get("NOTIFICATION_TEMPLATE", params.CaseSensitiveValue())
+	if err != nil {
+		return warnings, fmt.Errorf("%w: for environment variable NOTIFICATION_TEMPLATE", err)
+	} else if templateString != "" {
+		s.Template, err = template.New("notification").Parse(templateString)
+		if err != nil {
+			return warnings, fmt.Errorf("for environment variable NOTIFICATION_TEMPLATE: %w", err)
+		}
+	}
+
 	return warnings, nil
 }
 
@@ -1,16 +1,64 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/qdm12/ddns-updater/internal/cron"
+	"github.com/qdm12/ddns-updater/internal/propagation"
+	"github.com/qdm12/ddns-updater/internal/resolver"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/update"
 	"github.com/qdm12/golibs/params"
 )
 
 type Update struct {
 	Period   time.Duration
+	Periods  update.Periods
 	Cooldown time.Duration
+	// ForceUpdatePeriod is the period after which records are updated
+	// even if their IP address did not change, and is disabled if 0.
+	ForceUpdatePeriod time.Duration
+	Anomalies         update.AnomalySettings
+	Hysteresis        update.HysteresisSettings
+	Backoff           update.BackoffSettings
+	// DailyBudget is the maximum number of API requests per
+	// provider account and per day, and is disabled if 0.
+	DailyBudget uint
+	// WarmStart seeds the current IP address of records
+	// without history from DNS at startup.
+	WarmStart bool
+	// NetworkEvents triggers an update when the operating system
+	// reports a change of the network interfaces IP addresses.
+	NetworkEvents bool
+	// OnboardingRampUp is the window over which the first update of
+	// records without history is spread, and is disabled if 0.
+	OnboardingRampUp time.Duration
+	// PropagationCheck enables verifying the IP address of records
+	// is served by DNS servers after each successful update.
+	PropagationCheck bool
+	Propagation      propagation.Settings
+	// Lookup contains the settings of the DNS lookup
+	// of records done before updating them.
+	Lookup update.LookupSettings
+	// RateLimits maps a provider to its maximum number
+	// of API requests per second.
+	RateLimits map[string]float64
+	// RateLimitPerAccount is true to rate limit each
+	// account of a provider independently.
+	RateLimitPerAccount bool
+	// DryRun is true to log the provider calls
+	// instead of sending them.
+	DryRun bool
+	// Once is true to run a single update cycle and exit.
+	Once bool
+	// Workers is the maximum number of providers
+	// whose records are updated concurrently.
+	Workers uint
 }
 
 func (u *Update) get(env params.Interface) (warning string, err error) {
@@ -19,14 +67,209 @@ func (u *Update) get(env params.Interface) (warning string, err error) {
 		return warning, err
 	}
 
+	err = u.getIPVersionPeriods(env)
+	if err != nil {
+		return warning, err
+	}
+
+	cronExpression, err := env.Get("UPDATE_CRON")
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable UPDATE_CRON", err)
+	} else if cronExpression != "" {
+		u.Periods.Cron, err = cron.Parse(cronExpression)
+		if err != nil {
+			return "", fmt.Errorf("%w: for environment variable UPDATE_CRON", err)
+		}
+	}
+
 	u.Cooldown, err = env.Duration("UPDATE_COOLDOWN_PERIOD", params.Default("5m"))
 	if err != nil {
 		return "", fmt.Errorf("%w: for environment variable UPDATE_COOLDOWN_PERIOD", err)
 	}
 
+	u.ForceUpdatePeriod, err = env.Duration("FORCE_UPDATE_PERIOD", params.Default("0s"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable FORCE_UPDATE_PERIOD", err)
+	}
+
+	maxChanges, err := env.IntRange("ANOMALY_MAX_CHANGES_PER_HOUR", 0, math.MaxInt32, params.Default("0"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable ANOMALY_MAX_CHANGES_PER_HOUR", err)
+	}
+	u.Anomalies.MaxChangesPerHour = uint(maxChanges)
+
+	u.Anomalies.Block, err = env.OnOff("ANOMALY_BLOCK", params.Default("off"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable ANOMALY_BLOCK", err)
+	}
+
+	confirmations, err := env.IntRange("IP_CHANGE_CONFIRMATIONS", 0, math.MaxInt32, params.Default("1"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable IP_CHANGE_CONFIRMATIONS", err)
+	}
+	u.Hysteresis.Fetches = uint(confirmations)
+
+	u.Hysteresis.Duration, err = env.Duration("IP_CHANGE_MIN_DURATION", params.Default("0s"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable IP_CHANGE_MIN_DURATION", err)
+	}
+
+	u.Backoff.Initial, err = env.Duration("UPDATE_BACKOFF_INITIAL", params.Default("1m"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable UPDATE_BACKOFF_INITIAL", err)
+	}
+
+	u.Backoff.Max, err = env.Duration("UPDATE_BACKOFF_MAX", params.Default("1h"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable UPDATE_BACKOFF_MAX", err)
+	}
+
+	dailyBudget, err := env.IntRange("API_DAILY_BUDGET", 0, math.MaxInt32, params.Default("0"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable API_DAILY_BUDGET", err)
+	}
+	u.DailyBudget = uint(dailyBudget)
+
+	u.WarmStart, err = env.OnOff("WARM_START", params.Default("off"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable WARM_START", err)
+	}
+
+	u.NetworkEvents, err = env.OnOff("NETWORK_EVENTS", params.Default("off"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable NETWORK_EVENTS", err)
+	}
+
+	u.OnboardingRampUp, err = env.Duration("ONBOARDING_RAMP_UP", params.Default("0s"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable ONBOARDING_RAMP_UP", err)
+	}
+
+	workers, err := env.IntRange("UPDATE_WORKERS", 1, math.MaxInt32, params.Default("4"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable UPDATE_WORKERS", err)
+	}
+	u.Workers = uint(workers)
+
+	u.DryRun, err = env.OnOff("DRY_RUN", params.Default("off"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable DRY_RUN", err)
+	}
+
+	u.Once, err = env.OnOff("GO_ONCE", params.Default("off"))
+	if err != nil {
+		return "", fmt.Errorf("%w: for environment variable GO_ONCE", err)
+	}
+
+	err = u.getRateLimits(env)
+	if err != nil {
+		return "", err
+	}
+
+	err = u.getPropagation(env)
+	if err != nil {
+		return "", err
+	}
+
+	err = u.getLookup(env)
+	if err != nil {
+		return "", err
+	}
+
 	return warning, nil
 }
 
+var ErrRateLimitNotPositive = errors.New("rate limit must be a positive number")
+
+// getRateLimits reads the rate limit of each provider from the
+// environment variable <PROVIDER>_RPS, for example CLOUDFLARE_RPS,
+// where non alphanumeric characters of the provider are replaced
+// by underscores, for example NAME_COM_RPS.
+func (u *Update) getRateLimits(env params.Interface) (err error) {
+	u.RateLimits = make(map[string]float64)
+	for _, provider := range constants.ProviderChoices() {
+		key := rateLimitEnvKey(string(provider))
+		s, err := env.Get(key)
+		if err != nil {
+			return fmt.Errorf("%w: for environment variable %s", err, key)
+		} else if s == "" {
+			continue
+		}
+		rate, err := strconv.ParseFloat(s, 64) //nolint:gomnd
+		if err != nil {
+			return fmt.Errorf("%w: for environment variable %s", err, key)
+		} else if rate <= 0 {
+			return fmt.Errorf("%w: %s for environment variable %s", ErrRateLimitNotPositive, s, key)
+		}
+		u.RateLimits[string(provider)] = rate
+	}
+
+	u.RateLimitPerAccount, err = env.OnOff("RATE_LIMIT_PER_ACCOUNT", params.Default("off"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable RATE_LIMIT_PER_ACCOUNT", err)
+	}
+
+	return nil
+}
+
+func rateLimitEnvKey(provider string) (key string) {
+	mapping := func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}
+	return strings.Map(mapping, provider) + "_RPS"
+}
+
+func (u *Update) getPropagation(env params.Interface) (err error) {
+	u.PropagationCheck, err = env.OnOff("PROPAGATION_CHECK", params.Default("off"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PROPAGATION_CHECK", err)
+	}
+
+	tries, err := env.IntRange("PROPAGATION_CHECK_TRIES", 1, math.MaxInt32, params.Default("10"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PROPAGATION_CHECK_TRIES", err)
+	}
+	u.Propagation.Tries = uint(tries)
+
+	u.Propagation.Interval, err = env.Duration("PROPAGATION_CHECK_INTERVAL", params.Default("30s"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PROPAGATION_CHECK_INTERVAL", err)
+	}
+
+	u.Propagation.Resolvers, err = env.CSV("PROPAGATION_CHECK_RESOLVERS")
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PROPAGATION_CHECK_RESOLVERS", err)
+	}
+
+	return nil
+}
+
+func (u *Update) getLookup(env params.Interface) (err error) {
+	s, err := env.Get("LOOKUP_RESOLVER", params.Default("system"))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable LOOKUP_RESOLVER", err)
+	}
+
+	switch s {
+	case "system":
+	case "authoritative":
+		u.Lookup.Authoritative = true
+	default:
+		u.Lookup.Resolver, err = resolver.New(strings.Split(s, ","))
+		if err != nil {
+			return fmt.Errorf("%w: for environment variable LOOKUP_RESOLVER", err)
+		}
+	}
+	return nil
+}
+
 func (u *Update) getPeriod(env params.Interface) (warning string, err error) {
 	// Backward compatibility: DELAY
 	s, err := env.Get("DELAY", params.Compulsory())
@@ -53,3 +296,21 @@ func (u *Update) getPeriod(env params.Interface) (warning string, err error) {
 
 	return "", err
 }
+
+// getIPVersionPeriods reads the periods of the IPv4 and IPv6 update
+// pipelines, which default to the period set by PERIOD.
+func (u *Update) getIPVersionPeriods(env params.Interface) (err error) {
+	u.Periods.IP = u.Period
+
+	u.Periods.IPv4, err = env.Duration("PERIOD_IPV4", params.Default(u.Period.String()))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PERIOD_IPV4", err)
+	}
+
+	u.Periods.IPv6, err = env.Duration("PERIOD_IPV6", params.Default(u.Period.String()))
+	if err != nil {
+		return fmt.Errorf("%w: for environment variable PERIOD_IPV6", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimitEnvKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"cloudflare": "CLOUDFLARE_RPS",
+		"name.com":   "NAME_COM_RPS",
+		"1984.is":    "1984_IS_RPS",
+	}
+
+	for provider, expectedKey := range testCases {
+		provider, expectedKey := provider, expectedKey
+		t.Run(provider, func(t *testing.T) {
+			t.Parallel()
+
+			key := rateLimitEnvKey(provider)
+
+			assert.Equal(t, expectedKey, key)
+		})
+	}
+}
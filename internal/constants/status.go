@@ -8,4 +8,10 @@ const (
 	UPTODATE models.Status = "up to date"
 	UPDATING models.Status = "updating"
 	UNSET    models.Status = "unset"
+	// NOTPROPAGATED is the status of a record updated successfully
+	// whose IP address is not yet served by all the DNS servers checked.
+	NOTPROPAGATED models.Status = "not propagated"
+	// COOLDOWN is the status of a record not updated for some time
+	// since its provider reported an abuse or rate limited it.
+	COOLDOWN models.Status = "cooldown"
 )
@@ -0,0 +1,177 @@
+// Package cron parses cron expressions with five fields, minute, hour,
+// day of month, month and day of week, and computes their next times.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	expression  string
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+	// anyDay is true if the day of month or the day of week is "*",
+	// in which case a day must match both fields instead of either.
+	anyDay bool
+}
+
+var (
+	ErrFieldsCount    = errors.New("cron expression must have 5 fields")
+	ErrFieldMalformed = errors.New("cron field is malformed")
+	ErrNeverMatches   = errors.New("cron expression never matches")
+)
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+// Parse parses a cron expression such as "*/5 6-23 * * *", where each
+// field is "*", a value, a range such as "6-23" or a list of these
+// such as "0,30", each optionally followed by a step such as "/5".
+// The day of week ranges from 0 to 7, both being Sunday.
+func Parse(expression string) (schedule *Schedule, err error) {
+	fields := strings.Fields(expression)
+	const fieldsCount = 5
+	if len(fields) != fieldsCount {
+		return nil, fmt.Errorf("%w: %q", ErrFieldsCount, expression)
+	}
+
+	allBounds := [fieldsCount]bounds{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12},
+		{name: "day of week", min: 0, max: 7},
+	}
+	var bits [fieldsCount]uint64
+	for i, field := range fields {
+		bits[i], err = parseField(field, allBounds[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	const sunday, otherSunday = 0, 7
+	if bits[4]&(1<<otherSunday) != 0 {
+		bits[4] |= 1 << sunday
+	}
+
+	schedule = &Schedule{
+		expression:  expression,
+		minutes:     bits[0],
+		hours:       bits[1],
+		daysOfMonth: bits[2],
+		months:      bits[3],
+		daysOfWeek:  bits[4],
+		anyDay:      strings.HasPrefix(fields[2], "*") || strings.HasPrefix(fields[4], "*"),
+	}
+
+	reference := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC) //nolint:gomnd
+	if schedule.Next(reference).IsZero() {
+		return nil, fmt.Errorf("%w: %q", ErrNeverMatches, expression)
+	}
+
+	return schedule, nil
+}
+
+func parseField(field string, b bounds) (bits uint64, err error) {
+	for _, element := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.IndexByte(element, '/'); i >= 0 {
+			step, err = strconv.Atoi(element[i+1:])
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("%w: %s step in %q", ErrFieldMalformed, b.name, field)
+			}
+			element = element[:i]
+		}
+
+		var start, end int
+		switch {
+		case element == "*":
+			start, end = b.min, b.max
+		case strings.Contains(element, "-"):
+			parts := strings.SplitN(element, "-", 2) //nolint:gomnd
+			start, err = parseValue(parts[0], b)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %q", err, field)
+			}
+			end, err = parseValue(parts[1], b)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %q", err, field)
+			} else if end < start {
+				return 0, fmt.Errorf("%w: %s range %q is reversed", ErrFieldMalformed, b.name, element)
+			}
+		default:
+			start, err = parseValue(element, b)
+			if err != nil {
+				return 0, fmt.Errorf("%w: %q", err, field)
+			}
+			end = start
+			if step > 1 { // "5/15" is the same as "5-59/15"
+				end = b.max
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (value int, err error) {
+	value, err = strconv.Atoi(s)
+	if err != nil || value < b.min || value > b.max {
+		return 0, fmt.Errorf("%w: %s %q must be between %d and %d",
+			ErrFieldMalformed, b.name, s, b.min, b.max)
+	}
+	return value, nil
+}
+
+func (s *Schedule) String() string {
+	return s.expression
+}
+
+// Next returns the first time matching the schedule strictly after
+// the time given, in the location of the time given. It returns the
+// zero time if there is no such time within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	location := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	const maxYears = 5
+	limit := t.AddDate(maxYears, 0, 0)
+	for t.Before(limit) {
+		year, month, day := t.Date()
+		switch {
+		case s.months&(1<<month) == 0:
+			t = time.Date(year, month+1, 1, 0, 0, 0, 0, location)
+		case !s.dayMatches(t):
+			t = time.Date(year, month, day+1, 0, 0, 0, 0, location)
+		case s.hours&(1<<t.Hour()) == 0:
+			t = time.Date(year, month, day, t.Hour()+1, 0, 0, 0, location)
+		case s.minutes&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dayOfMonth := s.daysOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := s.daysOfWeek&(1<<t.Weekday()) != 0
+	if s.anyDay {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
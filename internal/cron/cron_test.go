@@ -0,0 +1,135 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		errWrapped error
+		errMessage string
+	}{
+		"every 5 minutes during the day": {
+			expression: "*/5 6-23 * * *",
+		},
+		"lists and sunday as 7": {
+			expression: "0,30 4 1,15 1-12/2 7",
+		},
+		"too few fields": {
+			expression: "* * * *",
+			errWrapped: ErrFieldsCount,
+			errMessage: `cron expression must have 5 fields: "* * * *"`,
+		},
+		"minute out of bounds": {
+			expression: "60 * * * *",
+			errWrapped: ErrFieldMalformed,
+			errMessage: `cron field is malformed: minute "60" must be between 0 and 59: "60"`,
+		},
+		"zero step": {
+			expression: "*/0 * * * *",
+			errWrapped: ErrFieldMalformed,
+			errMessage: `cron field is malformed: minute step in "*/0"`,
+		},
+		"reversed range": {
+			expression: "* 23-6 * * *",
+			errWrapped: ErrFieldMalformed,
+			errMessage: `cron field is malformed: hour range "23-6" is reversed`,
+		},
+		"never matches": {
+			expression: "0 0 30 2 *",
+			errWrapped: ErrNeverMatches,
+			errMessage: `cron expression never matches: "0 0 30 2 *"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := Parse(testCase.expression)
+
+			if testCase.errWrapped != nil {
+				assert.Nil(t, schedule)
+				assert.True(t, errors.Is(err, testCase.errWrapped))
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expression, schedule.String())
+		})
+	}
+}
+
+func Test_Schedule_Next(t *testing.T) {
+	t.Parallel()
+
+	date := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2022, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	testCases := map[string]struct {
+		expression string
+		from       time.Time
+		next       time.Time
+	}{
+		"next minute step": {
+			expression: "*/5 6-23 * * *",
+			from:       date(time.March, 1, 10, 2),
+			next:       date(time.March, 1, 10, 5),
+		},
+		"strictly after": {
+			expression: "*/5 6-23 * * *",
+			from:       date(time.March, 1, 10, 5),
+			next:       date(time.March, 1, 10, 10),
+		},
+		"outside of hours": {
+			expression: "*/5 6-23 * * *",
+			from:       date(time.March, 1, 23, 58),
+			next:       date(time.March, 2, 6, 0),
+		},
+		"day of month or day of week": {
+			// 2022-03-01 is a Tuesday
+			expression: "0 0 15 * 5",
+			from:       date(time.March, 1, 0, 0),
+			next:       date(time.March, 4, 0, 0),
+		},
+		"day of week with any day of month": {
+			expression: "0 0 * * 0",
+			from:       date(time.March, 1, 0, 0),
+			next:       date(time.March, 6, 0, 0),
+		},
+		"next year": {
+			expression: "30 4 1 1 *",
+			from:       date(time.March, 1, 0, 0),
+			next:       time.Date(2023, time.January, 1, 4, 30, 0, 0, time.UTC),
+		},
+		"leap day": {
+			expression: "0 0 29 2 *",
+			from:       date(time.March, 1, 0, 0),
+			next:       time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := Parse(testCase.expression)
+			require.NoError(t, err)
+
+			next := schedule.Next(testCase.from)
+
+			assert.Equal(t, testCase.next, next)
+		})
+	}
+}
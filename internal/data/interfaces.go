@@ -5,11 +5,13 @@ import (
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
 type PersistentDatabase interface {
 	Close() error
-	StoreNewIP(domain, host string, ip net.IP, t time.Time) (err error)
-	GetEvents(domain, host string) (events []models.HistoryEvent, err error)
+	StoreNewIP(domain, host string, ipVersion ipversion.IPVersion, ip net.IP, t time.Time) (err error)
+	GetEvents(domain, host string, ipVersion ipversion.IPVersion) (events []models.HistoryEvent, err error)
+	StoreLastUpdate(domain, host string, ipVersion ipversion.IPVersion, t time.Time) (err error)
 	Check() error
 }
@@ -5,10 +5,12 @@ import (
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
-func (db *Database) GetEvents(domain, host string) (events []models.HistoryEvent, err error) {
-	return db.persistentDB.GetEvents(domain, host)
+func (db *Database) GetEvents(domain, host string, ipVersion ipversion.IPVersion) (
+	events []models.HistoryEvent, err error) {
+	return db.persistentDB.GetEvents(domain, host, ipVersion)
 }
 
 func (db *Database) Update(id uint, record records.Record) (err error) {
@@ -17,20 +19,53 @@ func (db *Database) Update(id uint, record records.Record) (err error) {
 	if int(id) > len(db.data)-1 {
 		return fmt.Errorf("%w: for id %d", ErrRecordNotFound, id)
 	}
+	return db.update(id, record)
+}
+
+// Modify reads the record with the id given, modifies it with the
+// modify function given and writes it back, all under the database lock,
+// such that no concurrent update of the record is lost. The record is
+// left unchanged if the modify function returns false.
+func (db *Database) Modify(id uint, modify func(record *records.Record) (changed bool)) (err error) {
+	db.Lock()
+	defer db.Unlock()
+	if int(id) > len(db.data)-1 {
+		return fmt.Errorf("%w: for id %d", ErrRecordNotFound, id)
+	}
+	record := db.data[id]
+	if !modify(&record) {
+		return nil
+	}
+	return db.update(id, record)
+}
+
+func (db *Database) update(id uint, record records.Record) (err error) {
 	currentCount := len(db.data[id].History)
 	newCount := len(record.History)
+	lastUpdateChanged := record.LastUpdate.After(db.data[id].LastUpdate)
 	db.data[id] = record
 	// new IP address added
 	if newCount > currentCount {
 		if err := db.persistentDB.StoreNewIP(
 			record.Settings.Domain(),
 			record.Settings.Host(),
+			record.Settings.IPVersion(),
 			record.History.GetCurrentIP(),
 			record.History.GetSuccessTime(),
 		); err != nil {
 			return err
 		}
 	}
+	if lastUpdateChanged {
+		if err := db.persistentDB.StoreLastUpdate(
+			record.Settings.Domain(),
+			record.Settings.Host(),
+			record.Settings.IPVersion(),
+			record.LastUpdate,
+		); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
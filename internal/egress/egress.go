@@ -0,0 +1,69 @@
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Logger interface {
+	Warn(s string)
+}
+
+// RoundTripper only lets requests through to an allowed list of hostnames,
+// and blocks and logs any other request.
+type RoundTripper struct {
+	proxied http.RoundTripper
+	// allowed is the set of allowed lowercase hostnames.
+	allowed map[string]struct{}
+	// allowedSuffixes are lowercase domain suffixes such as
+	// .example.com obtained from allowed hostnames *.example.com.
+	allowedSuffixes []string
+	logger          Logger
+}
+
+func NewRoundTripper(proxied http.RoundTripper, allowedHosts []string,
+	logger Logger) *RoundTripper {
+	allowed := make(map[string]struct{}, len(allowedHosts))
+	var allowedSuffixes []string
+	for _, host := range allowedHosts {
+		host = strings.ToLower(host)
+		if strings.HasPrefix(host, "*.") {
+			allowedSuffixes = append(allowedSuffixes, strings.TrimPrefix(host, "*"))
+			continue
+		}
+		allowed[host] = struct{}{}
+	}
+
+	return &RoundTripper{
+		proxied:         proxied,
+		allowed:         allowed,
+		allowedSuffixes: allowedSuffixes,
+		logger:          logger,
+	}
+}
+
+var ErrHostNotAllowed = errors.New("outbound request to host is not allowed")
+
+func (r *RoundTripper) RoundTrip(request *http.Request) (
+	response *http.Response, err error) {
+	host := strings.ToLower(request.URL.Hostname())
+	if !r.isAllowed(host) {
+		r.logger.Warn("blocked outbound request to " + host)
+		return nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	return r.proxied.RoundTrip(request)
+}
+
+func (r *RoundTripper) isAllowed(host string) bool {
+	if _, ok := r.allowed[host]; ok {
+		return true
+	}
+	for _, suffix := range r.allowedSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
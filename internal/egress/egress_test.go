@@ -0,0 +1,48 @@
+package egress
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_RoundTripper_isAllowed(t *testing.T) {
+	t.Parallel()
+
+	roundTripper := NewRoundTripper(nil,
+		[]string{"api.Cloudflare.com", "*.example.com"}, nil)
+
+	testCases := map[string]struct {
+		host    string
+		allowed bool
+	}{
+		"exact match": {
+			host:    "api.cloudflare.com",
+			allowed: true,
+		},
+		"not allowed": {
+			host: "cloudflare.com",
+		},
+		"wildcard match": {
+			host:    "a.b.example.com",
+			allowed: true,
+		},
+		"wildcard does not match apex": {
+			host: "example.com",
+		},
+		"wildcard does not match other domain": {
+			host: "badexample.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			allowed := roundTripper.isAllowed(testCase.host)
+
+			assert.Equal(t, testCase.allowed, allowed)
+		})
+	}
+}
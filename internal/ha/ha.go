@@ -0,0 +1,102 @@
+package ha
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+)
+
+// Elector elects a single leader amongst multiple instances of
+// the program sharing the same lock backend. Only the leader
+// should update records, the other instances stand by.
+type Elector struct {
+	locker Locker
+	key    string
+	id     string
+	lease  time.Duration
+	logger logging.Logger
+
+	leaderMutex sync.RWMutex
+	leader      bool
+}
+
+func NewElector(locker Locker, key, id string, lease time.Duration,
+	logger logging.Logger) *Elector {
+	return &Elector{
+		locker: locker,
+		key:    key,
+		id:     id,
+		lease:  lease,
+		logger: logger,
+	}
+}
+
+// IsLeader returns true if the instance currently holds the leader lock.
+func (e *Elector) IsLeader() bool {
+	e.leaderMutex.RLock()
+	defer e.leaderMutex.RUnlock()
+	return e.leader
+}
+
+func (e *Elector) setLeader(leader bool) {
+	e.leaderMutex.Lock()
+	defer e.leaderMutex.Unlock()
+	if e.leader == leader {
+		return
+	}
+	e.leader = leader
+	if leader {
+		e.logger.Info("instance " + e.id + " is now the leader")
+	} else {
+		e.logger.Warn("instance " + e.id + " is now standing by")
+	}
+}
+
+// Run tries to acquire the leader lock and keeps on renewing it
+// once acquired, until the context is canceled.
+func (e *Elector) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	const renewalsPerLease = 3
+	ticker := time.NewTicker(e.lease / renewalsPerLease)
+	defer ticker.Stop()
+
+	for {
+		e.elect(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if e.IsLeader() {
+				e.setLeader(false)
+				const releaseTimeout = time.Second
+				releaseCtx, cancel := context.WithTimeout(context.Background(), releaseTimeout)
+				err := e.locker.Release(releaseCtx, e.key, e.id)
+				cancel()
+				if err != nil {
+					e.logger.Error("releasing leader lock: " + err.Error())
+				}
+			}
+			return
+		}
+	}
+}
+
+func (e *Elector) elect(ctx context.Context) {
+	if e.IsLeader() {
+		renewed, err := e.locker.Renew(ctx, e.key, e.id, e.lease)
+		if err != nil {
+			e.logger.Error("renewing leader lock: " + err.Error())
+		}
+		e.setLeader(renewed)
+		return
+	}
+
+	acquired, err := e.locker.Acquire(ctx, e.key, e.id, e.lease)
+	if err != nil {
+		e.logger.Error("acquiring leader lock: " + err.Error())
+	}
+	e.setLeader(acquired)
+}
@@ -0,0 +1,176 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/golibs/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLocker is an in memory Locker shared by the electors of a test.
+type fakeLocker struct {
+	mutex      sync.Mutex
+	owners     map[string]string
+	acquireErr error
+	renewErr   error
+	released   []string
+}
+
+func newFakeLocker() *fakeLocker {
+	return &fakeLocker{owners: make(map[string]string)}
+}
+
+func (l *fakeLocker) Acquire(_ context.Context, key, id string,
+	_ time.Duration) (acquired bool, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.acquireErr != nil {
+		return false, l.acquireErr
+	} else if _, ok := l.owners[key]; ok {
+		return false, nil
+	}
+	l.owners[key] = id
+	return true, nil
+}
+
+func (l *fakeLocker) Renew(_ context.Context, key, id string,
+	_ time.Duration) (renewed bool, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.renewErr != nil {
+		return false, l.renewErr
+	}
+	return l.owners[key] == id, nil
+}
+
+func (l *fakeLocker) Release(_ context.Context, key, id string) (err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.owners[key] != id {
+		return ErrLockNotOwned
+	}
+	delete(l.owners, key)
+	l.released = append(l.released, id)
+	return nil
+}
+
+// expire simulates the expiry of the lease of the key.
+func (l *fakeLocker) expire(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.owners, key)
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string)             {}
+func (noopLogger) Info(string)              {}
+func (noopLogger) Warn(string)              {}
+func (noopLogger) Error(string)             {}
+func (noopLogger) PatchLevel(logging.Level) {}
+func (noopLogger) PatchPrefix(string)       {}
+
+func Test_Elector_elect(t *testing.T) {
+	t.Parallel()
+
+	const key = "key"
+	errTest := errors.New("test error")
+
+	type step struct {
+		setup func(locker *fakeLocker)
+		// secondFirst is true to have the second elector elect first.
+		secondFirst bool
+		first       bool
+		second      bool
+	}
+
+	testCases := map[string]struct {
+		steps []step
+	}{
+		"acquire": {
+			steps: []step{
+				{first: true},
+			},
+		},
+		"acquire error": {
+			steps: []step{
+				{setup: func(locker *fakeLocker) { locker.acquireErr = errTest }},
+				{setup: func(locker *fakeLocker) { locker.acquireErr = nil }, first: true},
+			},
+		},
+		"renew": {
+			steps: []step{
+				{first: true},
+				{first: true},
+				{first: true},
+			},
+		},
+		"renew error demotes": {
+			steps: []step{
+				{first: true},
+				{setup: func(locker *fakeLocker) { locker.renewErr = errTest }},
+			},
+		},
+		"lease lost demotes": {
+			steps: []step{
+				{first: true},
+				{
+					setup:       func(locker *fakeLocker) { locker.expire(key) },
+					secondFirst: true,
+					second:      true,
+				},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			locker := newFakeLocker()
+			first := NewElector(locker, key, "first", time.Minute, noopLogger{})
+			second := NewElector(locker, key, "second", time.Minute, noopLogger{})
+			ctx := context.Background()
+
+			for i, step := range testCase.steps {
+				if step.setup != nil {
+					step.setup(locker)
+				}
+				if step.secondFirst {
+					second.elect(ctx)
+					first.elect(ctx)
+				} else {
+					first.elect(ctx)
+					second.elect(ctx)
+				}
+				assert.Equal(t, step.first, first.IsLeader(), "step %d", i)
+				assert.Equal(t, step.second, second.IsLeader(), "step %d", i)
+			}
+		})
+	}
+}
+
+func Test_Elector_Run(t *testing.T) {
+	t.Parallel()
+
+	locker := newFakeLocker()
+	elector := NewElector(locker, "key", "id", time.Hour, noopLogger{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go elector.Run(ctx, done)
+
+	require.Eventually(t, elector.IsLeader, time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	assert.False(t, elector.IsLeader())
+	assert.Equal(t, []string{"id"}, locker.released)
+	assert.Empty(t, locker.owners)
+}
@@ -0,0 +1,18 @@
+package ha
+
+import (
+	"context"
+	"time"
+)
+
+// Locker is a distributed lock backend shared by all instances.
+type Locker interface {
+	// Acquire sets the lock key to the instance id for the lease duration,
+	// only if the key is not already set.
+	Acquire(ctx context.Context, key, id string, lease time.Duration) (acquired bool, err error)
+	// Renew extends the lease duration of the lock key,
+	// only if it is still owned by the instance id.
+	Renew(ctx context.Context, key, id string, lease time.Duration) (renewed bool, err error)
+	// Release deletes the lock key, only if it is owned by the instance id.
+	Release(ctx context.Context, key, id string) (err error)
+}
@@ -0,0 +1,90 @@
+package ha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	libredis "github.com/qdm12/golibs/redis"
+)
+
+// RedisLocker is a Locker using a Redis server as shared backend.
+type RedisLocker struct {
+	db *libredis.DB
+}
+
+func NewRedisLocker(db *libredis.DB) *RedisLocker {
+	return &RedisLocker{
+		db: db,
+	}
+}
+
+var (
+	// renewScript extends the expiry of the key only if it is owned by the instance.
+	renewScript = redis.NewScript(1, `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	// releaseScript deletes the key only if it is owned by the instance.
+	releaseScript = redis.NewScript(1, `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+func (r *RedisLocker) Acquire(ctx context.Context, key, id string,
+	lease time.Duration) (acquired bool, err error) {
+	conn, err := r.db.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	reply, err := conn.Do("SET", key, id, "NX", "PX", lease.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", libredis.ErrDoCommand, err)
+	} else if libredis.IsNil(reply) { // key already set by another instance
+		return false, nil
+	}
+
+	err = libredis.CheckOKString(reply)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *RedisLocker) Renew(ctx context.Context, key, id string,
+	lease time.Duration) (renewed bool, err error) {
+	conn, err := r.db.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	result, err := redis.Int(renewScript.Do(conn, key, id, lease.Milliseconds()))
+	if err != nil {
+		return false, fmt.Errorf("%w: %s", libredis.ErrDoCommand, err)
+	}
+	return result == 1, nil
+}
+
+var ErrLockNotOwned = errors.New("lock is not owned by this instance")
+
+func (r *RedisLocker) Release(ctx context.Context, key, id string) (err error) {
+	conn, err := r.db.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	result, err := redis.Int(releaseScript.Do(conn, key, id))
+	if err != nil {
+		return fmt.Errorf("%w: %s", libredis.ErrDoCommand, err)
+	} else if result == 0 {
+		return fmt.Errorf("%w: %s", ErrLockNotOwned, key)
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package ha
+
+// Standalone is used when high availability is disabled,
+// and the single instance is always the leader.
+type Standalone struct{}
+
+func (Standalone) IsLeader() bool { return true }
@@ -1,6 +1,7 @@
 package health
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -28,13 +29,19 @@ var (
 func isHealthy(db AllSelecter, lookupIP lookupIPFunc) (err error) {
 	records := db.SelectAll()
 	for _, record := range records {
-		if record.Status == constants.FAIL {
+		if record.Status == constants.FAIL || record.Status == constants.COOLDOWN {
 			return fmt.Errorf("%w: %s", ErrRecordUpdateFailed, record.String())
 		} else if record.Settings.Proxied() {
 			continue
 		}
 		hostname := record.Settings.BuildDomainName()
-		lookedUpIPs, err := lookupIP(hostname)
+		recordLookupIP := lookupIP
+		if record.Options.Resolver != nil {
+			recordLookupIP = func(host string) ([]net.IP, error) {
+				return record.Options.Resolver.LookupIP(context.Background(), "ip", host)
+			}
+		}
+		lookedUpIPs, err := recordLookupIP(hostname)
 		if err != nil {
 			return err
 		}
@@ -42,6 +49,16 @@ func isHealthy(db AllSelecter, lookupIP lookupIPFunc) (err error) {
 		if currentIP == nil {
 			return fmt.Errorf("%w: for hostname %s", ErrRecordIPNotSet, hostname)
 		}
+		if record.Status == constants.NOTPROPAGATED {
+			// the propagation of the IP address is being verified
+			// or failed to be verified, which is reported in the status.
+			continue
+		}
+		if record.Confirmed {
+			// the provider confirmed the update is applied,
+			// the DNS resolution may not be propagated yet.
+			continue
+		}
 		found := false
 		lookedUpIPsString := make([]string, len(lookedUpIPs))
 		for i, lookedUpIP := range lookedUpIPs {
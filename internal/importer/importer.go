@@ -0,0 +1,123 @@
+// Package importer generates record settings from the
+// existing A and AAAA records of a DNS zone.
+package importer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/settings"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+func IsImportMode(args []string) bool {
+	return len(args) > 1 && args[1] == "import"
+}
+
+var (
+	ErrUsage                = errors.New(`usage: import '{"provider": "...", "domain": "...", ...}' [host1,host2]`)
+	ErrDecodeSettings       = errors.New("cannot decode settings")
+	ErrListingNotSupported  = errors.New("listing records is not supported by the provider")
+	ErrNoRecordFound        = errors.New("no record found")
+	ErrEncodeImportedConfig = errors.New("cannot encode imported configuration")
+)
+
+// Run lists the A and AAAA records of the zone using the provider settings
+// given as JSON in args[2], and writes to the writer a configuration with a
+// record settings entry for each of them. The optional args[3] is a comma
+// separated list of hosts to import, all records are imported otherwise.
+func Run(ctx context.Context, args []string, client *http.Client, writer io.Writer) (err error) {
+	const minArgs, maxArgs = 3, 4
+	if len(args) < minArgs || len(args) > maxArgs {
+		return ErrUsage
+	}
+	rawSettings := json.RawMessage(args[2])
+	var selectedHosts []string
+	if len(args) == maxArgs {
+		selectedHosts = strings.Split(args[3], ",")
+	}
+
+	var common struct {
+		Provider string `json:"provider"`
+		Domain   string `json:"domain"`
+	}
+	if err := json.Unmarshal(rawSettings, &common); err != nil {
+		return fmt.Errorf("%w: %s", ErrDecodeSettings, err)
+	}
+
+	provider, err := settings.New(models.Provider(common.Provider), rawSettings,
+		common.Domain, "@", ipversion.IP4or6, regex.NewMatcher())
+	if err != nil {
+		return err
+	}
+	lister, ok := provider.(settings.ZoneLister)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrListingNotSupported, common.Provider)
+	}
+
+	records, err := lister.ListRecords(ctx, client)
+	if err != nil {
+		return fmt.Errorf("listing records: %w", err)
+	}
+
+	entries, err := makeEntries(rawSettings, records, selectedHosts)
+	if err != nil {
+		return err
+	} else if len(entries) == 0 {
+		return fmt.Errorf("%w: for domain %s", ErrNoRecordFound, common.Domain)
+	}
+
+	config := struct {
+		Settings []map[string]json.RawMessage `json:"settings"`
+	}{Settings: entries}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(config); err != nil {
+		return fmt.Errorf("%w: %s", ErrEncodeImportedConfig, err)
+	}
+	return nil
+}
+
+// makeEntries creates a settings entry for each record, using the
+// raw settings given and setting the host and IP version of the record.
+func makeEntries(rawSettings json.RawMessage, records []models.ZoneRecord,
+	selectedHosts []string) (entries []map[string]json.RawMessage, err error) {
+	for _, record := range records {
+		if !isSelected(record.Host, selectedHosts) {
+			continue
+		}
+
+		var entry map[string]json.RawMessage
+		if err := json.Unmarshal(rawSettings, &entry); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDecodeSettings, err)
+		}
+		entry["host"], _ = json.Marshal(record.Host)
+		ipVersion := ipversion.IP4
+		if record.Type == constants.AAAA {
+			ipVersion = ipversion.IP6
+		}
+		entry["ip_version"], _ = json.Marshal(ipVersion.String())
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func isSelected(host string, selectedHosts []string) bool {
+	if len(selectedHosts) == 0 {
+		return true
+	}
+	for _, selectedHost := range selectedHosts {
+		if strings.EqualFold(host, selectedHost) {
+			return true
+		}
+	}
+	return false
+}
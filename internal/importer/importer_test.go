@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeEntries(t *testing.T) {
+	t.Parallel()
+
+	rawSettings := json.RawMessage(`{"provider":"digitalocean","domain":"example.com","token":"x"}`)
+	records := []models.ZoneRecord{
+		{Host: "@", Type: "A", IP: net.IPv4(1, 2, 3, 4)},
+		{Host: "www", Type: "AAAA", IP: net.ParseIP("2001:db8::1")},
+		{Host: "mail", Type: "A", IP: net.IPv4(1, 2, 3, 5)},
+	}
+
+	testCases := map[string]struct {
+		selectedHosts []string
+		entriesJSON   string
+	}{
+		"all hosts": {
+			entriesJSON: `[` +
+				`{"domain":"example.com","host":"@","ip_version":"ipv4","provider":"digitalocean","token":"x"},` +
+				`{"domain":"example.com","host":"www","ip_version":"ipv6","provider":"digitalocean","token":"x"},` +
+				`{"domain":"example.com","host":"mail","ip_version":"ipv4","provider":"digitalocean","token":"x"}]`,
+		},
+		"selected hosts": {
+			selectedHosts: []string{"WWW", "other"},
+			entriesJSON: `[` +
+				`{"domain":"example.com","host":"www","ip_version":"ipv6","provider":"digitalocean","token":"x"}]`,
+		},
+		"no matching host": {
+			selectedHosts: []string{"other"},
+			entriesJSON:   `null`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			entries, err := makeEntries(rawSettings, records, testCase.selectedHosts)
+			require.NoError(t, err)
+
+			entriesJSON, err := json.Marshal(entries)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.entriesJSON, string(entriesJSON))
+		})
+	}
+}
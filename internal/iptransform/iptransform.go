@@ -0,0 +1,143 @@
+// Package iptransform transforms the public IP address detected before
+// it is published to a record, for gateways publishing a different
+// address than the one they detect.
+package iptransform
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Transform replaces IP addresses within its From network, either with
+// a static IP address or by substituting the prefix of the From network
+// with the prefix of the To network, for example to publish an address
+// with a network specific NAT64 prefix instead of the well-known one.
+// It can also substitute the bits after the prefix of the IP address,
+// see NewSuffix.
+type Transform struct {
+	From *net.IPNet
+	// ToIP is the static IP address replacing the IP address,
+	// and is nil if the prefix is substituted instead.
+	ToIP net.IP
+	// ToNet is the network whose prefix substitutes the
+	// prefix of the IP address, and is nil if ToIP is set.
+	ToNet *net.IPNet
+	// Suffix holds the bits substituting the bits of the IP address
+	// outside its mask, and is nil if ToIP or ToNet is set.
+	Suffix *net.IPNet
+}
+
+// Transforms are applied in order, and only the
+// first transform matching an IP address is applied.
+type Transforms []Transform
+
+// Settings are the JSON settings of a transform.
+type Settings struct {
+	// From is the network of IP addresses to transform, in CIDR notation.
+	From string `json:"from"`
+	// To is either an IP address or a network in CIDR notation
+	// with the same prefix length as From.
+	To string `json:"to"`
+}
+
+var (
+	ErrFromMalformed    = errors.New("from network is malformed")
+	ErrToMalformed      = errors.New("to address or network is malformed")
+	ErrFamilyMismatch   = errors.New("from and to are not of the same IP family")
+	ErrPrefixLengthDiff = errors.New("from and to networks have different prefix lengths")
+)
+
+// New parses the transforms from their settings.
+func New(settings []Settings) (transforms Transforms, err error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	transforms = make(Transforms, len(settings))
+	for i, s := range settings {
+		transforms[i], err = parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("transform %d: %w", i+1, err)
+		}
+	}
+	return transforms, nil
+}
+
+func parse(settings Settings) (transform Transform, err error) {
+	_, transform.From, err = net.ParseCIDR(settings.From)
+	if err != nil {
+		return transform, fmt.Errorf("%w: %s", ErrFromMalformed, err)
+	}
+	isFromIPv4 := transform.From.IP.To4() != nil
+
+	if !strings.Contains(settings.To, "/") {
+		transform.ToIP = net.ParseIP(settings.To)
+		if transform.ToIP == nil {
+			return transform, fmt.Errorf("%w: %q", ErrToMalformed, settings.To)
+		} else if (transform.ToIP.To4() != nil) != isFromIPv4 {
+			return transform, fmt.Errorf("%w: %s and %s", ErrFamilyMismatch, settings.From, settings.To)
+		}
+		return transform, nil
+	}
+
+	_, transform.ToNet, err = net.ParseCIDR(settings.To)
+	if err != nil {
+		return transform, fmt.Errorf("%w: %s", ErrToMalformed, err)
+	} else if (transform.ToNet.IP.To4() != nil) != isFromIPv4 {
+		return transform, fmt.Errorf("%w: %s and %s", ErrFamilyMismatch, settings.From, settings.To)
+	}
+	fromOnes, _ := transform.From.Mask.Size()
+	toOnes, _ := transform.ToNet.Mask.Size()
+	if fromOnes != toOnes {
+		return transform, fmt.Errorf("%w: /%d and /%d", ErrPrefixLengthDiff, fromOnes, toOnes)
+	}
+	return transform, nil
+}
+
+// Apply returns the IP address transformed by the first transform
+// matching it, or the IP address itself if no transform matches.
+func (t Transforms) Apply(ip net.IP) net.IP {
+	if ip == nil {
+		return nil
+	}
+	for _, transform := range t {
+		if !transform.From.Contains(ip) {
+			continue
+		}
+		switch {
+		case transform.ToIP != nil:
+			return transform.ToIP
+		case transform.Suffix != nil:
+			return substituteSuffix(ip, transform.Suffix)
+		}
+		return substitutePrefix(ip, transform.ToNet)
+	}
+	return ip
+}
+
+// substitutePrefix returns the IP address with its prefix bits replaced
+// by the ones of the network, which is of the same IP family.
+func substitutePrefix(ip net.IP, network *net.IPNet) net.IP {
+	if ipv4 := ip.To4(); ipv4 != nil && len(network.IP) == net.IPv4len {
+		ip = ipv4
+	} else {
+		ip = ip.To16()
+	}
+	result := make(net.IP, len(ip))
+	for i := range ip {
+		result[i] = network.IP[i]&network.Mask[i] | ip[i]&^network.Mask[i]
+	}
+	return result
+}
+
+// substituteSuffix returns the IPv6 address with the bits outside the
+// mask of the suffix network replaced by the ones of its IP address.
+func substituteSuffix(ip net.IP, suffix *net.IPNet) net.IP {
+	ip = ip.To16()
+	result := make(net.IP, len(ip))
+	for i := range ip {
+		result[i] = ip[i]&suffix.Mask[i] | suffix.IP[i]&^suffix.Mask[i]
+	}
+	return result
+}
@@ -0,0 +1,184 @@
+package iptransform
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   []Settings
+		errWrapped error
+		errMessage string
+	}{
+		"no settings": {},
+		"valid": {
+			settings: []Settings{
+				{From: "10.0.0.0/8", To: "1.2.3.4"},
+				{From: "64:ff9b::/96", To: "2001:db8:64::/96"},
+			},
+		},
+		"malformed from": {
+			settings:   []Settings{{From: "10.0.0.0", To: "1.2.3.4"}},
+			errWrapped: ErrFromMalformed,
+			errMessage: "transform 1: from network is malformed: invalid CIDR address: 10.0.0.0",
+		},
+		"malformed to": {
+			settings:   []Settings{{From: "10.0.0.0/8", To: "x"}},
+			errWrapped: ErrToMalformed,
+			errMessage: `transform 1: to address or network is malformed: "x"`,
+		},
+		"family mismatch": {
+			settings:   []Settings{{From: "10.0.0.0/8", To: "::1"}},
+			errWrapped: ErrFamilyMismatch,
+			errMessage: "transform 1: from and to are not of the same IP family: 10.0.0.0/8 and ::1",
+		},
+		"prefix length mismatch": {
+			settings:   []Settings{{From: "64:ff9b::/96", To: "2001:db8::/64"}},
+			errWrapped: ErrPrefixLengthDiff,
+			errMessage: "transform 1: from and to networks have different prefix lengths: /96 and /64",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transforms, err := New(testCase.settings)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.Len(t, transforms, len(testCase.settings))
+		})
+	}
+}
+
+func Test_Transforms_Apply(t *testing.T) {
+	t.Parallel()
+
+	transforms, err := New([]Settings{
+		{From: "203.0.113.0/24", To: "198.51.100.7"},
+		{From: "64:ff9b::/96", To: "2001:db8:64::/96"},
+		{From: "10.0.0.0/8", To: "172.16.0.0/8"},
+		{From: "203.0.0.0/8", To: "1.1.1.1"},
+	})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		ip          net.IP
+		transformed net.IP
+	}{
+		"nil IP": {},
+		"no match": {
+			ip:          net.IPv4(1, 2, 3, 4),
+			transformed: net.IPv4(1, 2, 3, 4),
+		},
+		"static mapping": {
+			ip:          net.IPv4(203, 0, 113, 5),
+			transformed: net.IPv4(198, 51, 100, 7),
+		},
+		"NAT64 prefix substitution": {
+			ip:          net.ParseIP("64:ff9b::c000:201"),
+			transformed: net.ParseIP("2001:db8:64::c000:201"),
+		},
+		"IPv4 prefix substitution": {
+			ip:          net.IPv4(10, 1, 2, 3),
+			transformed: net.IPv4(172, 1, 2, 3),
+		},
+		"first match only": {
+			ip:          net.IPv4(203, 0, 113, 9),
+			transformed: net.IPv4(198, 51, 100, 7),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transformed := transforms.Apply(testCase.ip)
+			assert.True(t, testCase.transformed.Equal(transformed),
+				"expected %s, got %s", testCase.transformed, transformed)
+		})
+	}
+}
+
+func Test_NewSuffix(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		suffix      string
+		ip          net.IP
+		transformed net.IP
+		errWrapped  error
+		errMessage  string
+	}{
+		"interface identifier": {
+			suffix:      "::1234:5678:9abc:def0",
+			ip:          net.ParseIP("2001:db8:1:2:aaaa:bbbb:cccc:dddd"),
+			transformed: net.ParseIP("2001:db8:1:2:1234:5678:9abc:def0"),
+		},
+		"prefix length": {
+			suffix:      "::42:0:0:0:1/56",
+			ip:          net.ParseIP("2001:db8:1:2ff::"),
+			transformed: net.ParseIP("2001:db8:1:242::1"),
+		},
+		"MAC address": {
+			suffix:      "00:11:22:33:44:55",
+			ip:          net.ParseIP("2001:db8:1:2::1"),
+			transformed: net.ParseIP("2001:db8:1:2:211:22ff:fe33:4455"),
+		},
+		"IPv4 address untouched": {
+			suffix:      "::1",
+			ip:          net.IPv4(1, 2, 3, 4),
+			transformed: net.IPv4(1, 2, 3, 4),
+		},
+		"malformed suffix": {
+			suffix:     "xyz",
+			errWrapped: ErrSuffixMalformed,
+			errMessage: `IPv6 suffix is malformed: "xyz"`,
+		},
+		"IPv4 suffix": {
+			suffix:     "1.2.3.4",
+			errWrapped: ErrSuffixMalformed,
+			errMessage: `IPv6 suffix is malformed: "1.2.3.4" is not an IPv6 address`,
+		},
+		"MAC address with prefix length": {
+			suffix:     "00:11:22:33:44:55/56",
+			errWrapped: ErrSuffixMalformed,
+			errMessage: `IPv6 suffix is malformed: "00:11:22:33:44:55/56"`,
+		},
+		"malformed prefix length": {
+			suffix:     "::1/129",
+			errWrapped: ErrSuffixPrefixMalformed,
+			errMessage: `IPv6 suffix prefix length is malformed: "::1/129"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transform, err := NewSuffix(testCase.suffix)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			transformed := Transforms{transform}.Apply(testCase.ip)
+			assert.True(t, testCase.transformed.Equal(transformed),
+				"expected %s, got %s", testCase.transformed, transformed)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package iptransform
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrSuffixMalformed       = errors.New("IPv6 suffix is malformed")
+	ErrSuffixPrefixMalformed = errors.New("IPv6 suffix prefix length is malformed")
+)
+
+// NewSuffix returns a transform replacing the interface identifier of
+// IPv6 addresses, to publish the address of another machine of the
+// delegated prefix detected. The suffix is either:
+//   - an IPv6 address holding the interface identifier bits, such as
+//     ::1234:5678:9abc:def0, optionally followed by the prefix length
+//     kept from the detected address such as ::1234/56, which defaults to /64.
+//   - a 48 bit MAC address such as 00:11:22:33:44:55, from which the
+//     modified EUI-64 interface identifier is derived, for a /64 prefix.
+func NewSuffix(suffix string) (transform Transform, err error) {
+	const defaultPrefixLength = 64
+	prefixLength := defaultPrefixLength
+	address := suffix
+	if i := strings.IndexByte(suffix, '/'); i >= 0 {
+		address = suffix[:i]
+		prefixLength, err = strconv.Atoi(suffix[i+1:])
+		const maxPrefixLength = 8 * net.IPv6len
+		if err != nil || prefixLength < 0 || prefixLength > maxPrefixLength {
+			return transform, fmt.Errorf("%w: %q", ErrSuffixPrefixMalformed, suffix)
+		}
+	}
+
+	ip := net.ParseIP(address)
+	if ip == nil {
+		mac, err := net.ParseMAC(address)
+		const macLength = 6
+		if err != nil || len(mac) != macLength || address != suffix {
+			return transform, fmt.Errorf("%w: %q", ErrSuffixMalformed, suffix)
+		}
+		ip = eui64(mac)
+	} else if ip.To4() != nil {
+		return transform, fmt.Errorf("%w: %q is not an IPv6 address", ErrSuffixMalformed, suffix)
+	}
+
+	transform.From = &net.IPNet{
+		IP:   net.IPv6zero,
+		Mask: net.CIDRMask(0, 8*net.IPv6len),
+	}
+	transform.Suffix = &net.IPNet{
+		IP:   ip.To16(),
+		Mask: net.CIDRMask(prefixLength, 8*net.IPv6len),
+	}
+	return transform, nil
+}
+
+// eui64 returns the IPv6 address with the modified EUI-64
+// interface identifier derived from the MAC address.
+func eui64(mac net.HardwareAddr) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	const universalLocalBit = 0x02
+	ip[8] = mac[0] ^ universalLocalBit
+	ip[9], ip[10] = mac[1], mac[2]
+	ip[11], ip[12] = 0xff, 0xfe
+	ip[13], ip[14], ip[15] = mac[3], mac[4], mac[5]
+	return ip
+}
@@ -0,0 +1,166 @@
+// Package metrics serves the state of records in the
+// Prometheus text exposition format.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update"
+)
+
+type Database interface {
+	SelectAll() (records []records.Record)
+}
+
+type StateGetter interface {
+	RecordState(id uint, record records.Record, now time.Time) (state update.RecordState)
+}
+
+type CycleSummarizer interface {
+	CycleSummaries() (summaries []update.CycleSummary)
+}
+
+// Handler serves per record metrics, with explicit boolean gauges
+// such that alert rules can be written without recording rules.
+type Handler struct {
+	db          Database
+	states      StateGetter
+	summaries   CycleSummarizer
+	stalePeriod time.Duration
+	timeNow     func() time.Time
+}
+
+// NewHandler creates a metrics HTTP handler. A record is considered
+// stale if it failed to be updated for longer than the stale period.
+func NewHandler(db Database, states StateGetter, summaries CycleSummarizer,
+	stalePeriod time.Duration, timeNow func() time.Time) *Handler {
+	return &Handler{
+		db:          db,
+		states:      states,
+		summaries:   summaries,
+		stalePeriod: stalePeriod,
+		timeNow:     timeNow,
+	}
+}
+
+type metric struct {
+	name   string
+	help   string
+	kind   string // gauge or counter
+	values []sample
+}
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	now := h.timeNow()
+
+	up := metric{name: "ddns_updater_record_up", kind: "gauge",
+		help: "1 if the last update or check of the record succeeded, 0 otherwise."}
+	stale := metric{name: "ddns_updater_record_stale", kind: "gauge",
+		help: "1 if the record failed to be updated for longer than " + h.stalePeriod.String() + "."}
+	banned := metric{name: "ddns_updater_record_banned", kind: "gauge",
+		help: "1 if the record or its account is banned by the provider."}
+	circuitOpen := metric{name: "ddns_updater_record_circuit_open", kind: "gauge",
+		help: "1 if the record account failed too many consecutive times and is not updated for now."}
+	lastSuccess := metric{name: "ddns_updater_record_last_success_timestamp_seconds", kind: "gauge",
+		help: "Unix timestamp of the last IP address change of the record, 0 if never changed."}
+	lastError := metric{name: "ddns_updater_record_last_error", kind: "gauge",
+		help: "1 with the code of the last update error of the record, only for records whose last update failed."}
+	notPropagated := metric{name: "ddns_updater_record_not_propagated", kind: "gauge",
+		help: "1 if the record was updated but its IP address is not yet served by all the DNS servers checked."}
+	updates := metric{name: "ddns_updater_record_updates_total", kind: "counter",
+		help: "Number of updates of the record since launch, by result."}
+
+	for i, record := range h.db.SelectAll() {
+		labels := recordLabels(record)
+		state := h.states.RecordState(uint(i), record, now)
+
+		isNotPropagated := record.Status == constants.NOTPROPAGATED
+		isUp := record.Status == constants.SUCCESS || record.Status == constants.UPTODATE || isNotPropagated
+		isStale := !isUp && now.Sub(record.Time) > h.stalePeriod
+		var lastSuccessTimestamp float64
+		if successTime := record.History.GetSuccessTime(); !successTime.IsZero() {
+			lastSuccessTimestamp = float64(successTime.Unix())
+		}
+
+		up.values = append(up.values, sample{labels: labels, value: boolToFloat(isUp)})
+		stale.values = append(stale.values, sample{labels: labels, value: boolToFloat(isStale)})
+		banned.values = append(banned.values, sample{labels: labels, value: boolToFloat(state.Banned)})
+		circuitOpen.values = append(circuitOpen.values,
+			sample{labels: labels, value: boolToFloat(state.CircuitOpen)})
+		lastSuccess.values = append(lastSuccess.values, sample{labels: labels, value: lastSuccessTimestamp})
+		notPropagated.values = append(notPropagated.values,
+			sample{labels: labels, value: boolToFloat(isNotPropagated)})
+		if record.Status == constants.FAIL && record.ErrorCode != "" {
+			lastError.values = append(lastError.values, sample{
+				labels: labels + `,code="` + escapeLabelValue(string(record.ErrorCode)) + `"`,
+				value:  1,
+			})
+		}
+		updates.values = append(updates.values,
+			sample{labels: labels + `,result="success"`, value: float64(state.Successes)},
+			sample{labels: labels + `,result="failure"`, value: float64(state.Failures)})
+	}
+
+	cycleRecords := metric{name: "ddns_updater_cycle_records", kind: "gauge",
+		help: "Number of records of the last update cycle of the IP version, by result."}
+	cycleDuration := metric{name: "ddns_updater_cycle_duration_seconds", kind: "gauge",
+		help: "Duration of the last update cycle of the IP version."}
+	cycleTimestamp := metric{name: "ddns_updater_cycle_last_timestamp_seconds", kind: "gauge",
+		help: "Unix timestamp of the start of the last update cycle of the IP version."}
+
+	for _, summary := range h.summaries.CycleSummaries() {
+		labels := `ip_version="` + escapeLabelValue(summary.IPVersion.String()) + `"`
+		cycleRecords.values = append(cycleRecords.values,
+			sample{labels: labels + `,result="updated"`, value: float64(summary.Updated)},
+			sample{labels: labels + `,result="unchanged"`, value: float64(summary.Unchanged)},
+			sample{labels: labels + `,result="failed"`, value: float64(summary.Failed)})
+		cycleDuration.values = append(cycleDuration.values,
+			sample{labels: labels, value: summary.Duration.Seconds()})
+		cycleTimestamp.values = append(cycleTimestamp.values,
+			sample{labels: labels, value: float64(summary.Start.Unix())})
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	for _, m := range []metric{up, stale, banned, circuitOpen, lastSuccess, lastError, notPropagated, updates,
+		cycleRecords, cycleDuration, cycleTimestamp} {
+		fmt.Fprintf(buffer, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		for _, s := range m.values {
+			fmt.Fprintf(buffer, "%s{%s} %g\n", m.name, s.labels, s.value)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buffer.Bytes())
+}
+
+func recordLabels(record records.Record) string {
+	return fmt.Sprintf(`domain="%s",host="%s",provider="%s",ip_version="%s"`,
+		escapeLabelValue(record.Settings.Domain()),
+		escapeLabelValue(record.Settings.Host()),
+		escapeLabelValue(string(record.Settings.Provider())),
+		escapeLabelValue(record.Settings.IPVersion().String()))
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(s string) string {
+	return labelValueReplacer.Replace(s)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
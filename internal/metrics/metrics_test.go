@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/internal/update"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabase []records.Record
+
+func (db testDatabase) SelectAll() []records.Record { return db }
+
+type testStates map[uint]update.RecordState
+
+func (s testStates) RecordState(id uint, _ records.Record, _ time.Time) update.RecordState {
+	return s[id]
+}
+
+type testSummaries []update.CycleSummary
+
+func (s testSummaries) CycleSummaries() []update.CycleSummary { return s }
+
+func Test_Handler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(100000, 0)
+	settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+
+	db := testDatabase{
+		{Settings: settings, Status: constants.UPTODATE, Time: now},
+		{Settings: settings, Status: constants.FAIL, Time: now.Add(-25 * time.Hour),
+			ErrorCode: settingserrors.CodeProviderAuthFailed},
+		{Settings: settings, Status: constants.NOTPROPAGATED, Time: now},
+	}
+	states := testStates{
+		1: {Successes: 2, Failures: 3, CircuitOpen: true},
+	}
+	summaries := testSummaries{
+		{IPVersion: ipversion.IP4, Start: now, Duration: 1500 * time.Millisecond,
+			Updated: 1, Unchanged: 4, Failed: 2},
+	}
+	handler := NewHandler(db, states, summaries, 24*time.Hour, func() time.Time { return now })
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	const labels = `domain="example.com",host="@",provider="ns1",ip_version="ipv4"`
+	body := recorder.Body.String()
+	assert.Contains(t, body, "# TYPE ddns_updater_record_up gauge\n"+
+		"ddns_updater_record_up{"+labels+"} 1\n"+
+		"ddns_updater_record_up{"+labels+"} 0\n"+
+		"ddns_updater_record_up{"+labels+"} 1\n")
+	assert.Contains(t, body, "ddns_updater_record_stale{"+labels+"} 0\n"+
+		"ddns_updater_record_stale{"+labels+"} 1\n")
+	assert.Contains(t, body, "ddns_updater_record_not_propagated{"+labels+"} 0\n"+
+		"ddns_updater_record_not_propagated{"+labels+"} 0\n"+
+		"ddns_updater_record_not_propagated{"+labels+"} 1\n")
+	assert.Contains(t, body, "ddns_updater_record_circuit_open{"+labels+"} 1\n")
+	assert.Contains(t, body, "ddns_updater_record_last_error{"+labels+`,code="PROVIDER_AUTH_FAILED"} 1`+"\n")
+	assert.Contains(t, body, "ddns_updater_record_updates_total{"+labels+`,result="failure"} 3`+"\n")
+	assert.Contains(t, body, `ddns_updater_cycle_records{ip_version="ipv4",result="updated"} 1`+"\n"+
+		`ddns_updater_cycle_records{ip_version="ipv4",result="unchanged"} 4`+"\n"+
+		`ddns_updater_cycle_records{ip_version="ipv4",result="failed"} 2`+"\n")
+	assert.Contains(t, body, `ddns_updater_cycle_duration_seconds{ip_version="ipv4"} 1.5`+"\n")
+	assert.Contains(t, body, `ddns_updater_cycle_last_timestamp_seconds{ip_version="ipv4"} 100000`+"\n")
+}
@@ -0,0 +1,12 @@
+package models
+
+import "net"
+
+// ZoneRecord is an existing A or AAAA record of a DNS zone.
+type ZoneRecord struct {
+	// Host is the host relative to the domain, i.e. "@" or "sub".
+	Host string
+	// Type is A or AAAA.
+	Type string
+	IP   net.IP
+}
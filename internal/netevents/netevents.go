@@ -0,0 +1,29 @@
+// Package netevents watches the operating system for changes of
+// the IP addresses of the network interfaces, such that records
+// can be updated as soon as the machine switches networks.
+// It uses a netlink socket on Linux, a routing socket on macOS
+// instead of the SystemConfiguration framework which requires cgo,
+// and the IP helper API on Windows.
+package netevents
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrNotSupported = errors.New("network events are not supported on this platform")
+
+// Watch sends on the events channel each time the IP addresses of
+// the network interfaces change, until the context is canceled.
+// Events are dropped if the channel is not ready to receive them,
+// so the channel should be buffered.
+func Watch(ctx context.Context, events chan<- struct{}) (err error) {
+	return watch(ctx, events)
+}
+
+func notify(events chan<- struct{}) {
+	select {
+	case events <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,24 @@
+package netevents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_notify(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan struct{}, 1)
+
+	notify(events)
+	notify(events) // dropped since the channel is full
+
+	assert.Len(t, events, 1)
+	<-events
+	notify(events)
+	assert.Len(t, events, 1)
+
+	unbuffered := make(chan struct{})
+	notify(unbuffered) // dropped since nothing is receiving
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin
+
+package netevents
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// readMessages reads messages from the socket file until the context
+// is canceled, and notifies an event for each message for which
+// isAddressChange returns true.
+func readMessages(ctx context.Context, file *os.File, events chan<- struct{},
+	isAddressChange func(data []byte) bool) (err error) {
+	go func() {
+		<-ctx.Done()
+		_ = file.Close()
+	}()
+
+	const bufferSize = 65536
+	buffer := make([]byte, bufferSize)
+	for {
+		n, err := file.Read(buffer)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, os.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		if isAddressChange(buffer[:n]) {
+			notify(events)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+//go:build linux || darwin
+
+package netevents
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readMessages(t *testing.T) {
+	t.Parallel()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = writer.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan struct{}, 1)
+	isAddressChange := func(data []byte) bool {
+		return len(data) > 0 && data[0] == 1
+	}
+	errCh := make(chan error)
+	go func() {
+		errCh <- readMessages(ctx, reader, events, isAddressChange)
+	}()
+
+	_, err = writer.Write([]byte{0})
+	require.NoError(t, err)
+	select {
+	case <-events:
+		t.Fatal("unexpected event for a message filtered out")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_, err = writer.Write([]byte{1})
+	require.NoError(t, err)
+	select {
+	case <-events:
+	case <-time.After(time.Second):
+		t.Fatal("no event received for an address change message")
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("readMessages did not return after the context was canceled")
+	}
+}
+
+func Test_readMessages_readError(t *testing.T) {
+	t.Parallel()
+
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	err = readMessages(context.Background(), reader, make(chan struct{}, 1),
+		func([]byte) bool { return true })
+
+	assert.EqualError(t, err, "EOF")
+}
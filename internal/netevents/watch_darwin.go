@@ -0,0 +1,45 @@
+package netevents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// watch uses a routing socket instead of the SystemConfiguration
+// framework, since the latter requires cgo. The routing socket receives
+// the same interface address changes.
+func watch(ctx context.Context, events chan<- struct{}) (err error) {
+	fd, err := syscall.Socket(syscall.AF_ROUTE, syscall.SOCK_RAW, syscall.AF_UNSPEC)
+	if err != nil {
+		return fmt.Errorf("creating routing socket: %w", err)
+	}
+	syscall.CloseOnExec(fd)
+
+	// The socket is set as non blocking to be handled by the runtime
+	// poller, such that closing the file interrupts a pending read.
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		_ = syscall.Close(fd)
+		return fmt.Errorf("setting routing socket as non blocking: %w", err)
+	}
+	file := os.NewFile(uintptr(fd), "route")
+
+	return readMessages(ctx, file, events, isRoutingAddressChange)
+}
+
+// isRoutingAddressChange returns true if the routing socket message
+// is an interface address added or deleted message.
+func isRoutingAddressChange(data []byte) bool {
+	// Each read returns a single message, whose
+	// type is the 4th byte of its header.
+	const typeOffset = 3
+	if len(data) <= typeOffset {
+		return false
+	}
+	switch data[typeOffset] {
+	case syscall.RTM_NEWADDR, syscall.RTM_DELADDR:
+		return true
+	}
+	return false
+}
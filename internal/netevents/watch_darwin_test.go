@@ -0,0 +1,44 @@
+package netevents
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isRoutingAddressChange(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data   []byte
+		change bool
+	}{
+		"empty": {},
+		"truncated": {
+			data: []byte{0, 0, 5},
+		},
+		"new address": {
+			data:   []byte{0, 0, 5, syscall.RTM_NEWADDR},
+			change: true,
+		},
+		"deleted address": {
+			data:   []byte{0, 0, 5, syscall.RTM_DELADDR},
+			change: true,
+		},
+		"route added": {
+			data: []byte{0, 0, 5, syscall.RTM_ADD},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			change := isRoutingAddressChange(testCase.data)
+
+			assert.Equal(t, testCase.change, change)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package netevents
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Netlink multicast groups from linux/rtnetlink.h,
+// not defined in the syscall package.
+const (
+	rtmgrpIPv4IfAddr = 0x10
+	rtmgrpIPv6IfAddr = 0x100
+)
+
+// watch uses a netlink route socket subscribed to the
+// IPv4 and IPv6 interface address groups.
+func watch(ctx context.Context, events chan<- struct{}) (err error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK,
+		syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		return fmt.Errorf("creating netlink socket: %w", err)
+	}
+
+	address := &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: rtmgrpIPv4IfAddr | rtmgrpIPv6IfAddr,
+	}
+	if err := syscall.Bind(fd, address); err != nil {
+		_ = syscall.Close(fd)
+		return fmt.Errorf("binding netlink socket: %w", err)
+	}
+
+	// The socket is set as non blocking to be handled by the runtime
+	// poller, such that closing the file interrupts a pending read.
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		_ = syscall.Close(fd)
+		return fmt.Errorf("setting netlink socket as non blocking: %w", err)
+	}
+	file := os.NewFile(uintptr(fd), "netlink")
+
+	return readMessages(ctx, file, events, isNetlinkAddressChange)
+}
+
+// isNetlinkAddressChange returns true if the netlink data
+// contains an interface address added or deleted message.
+func isNetlinkAddressChange(data []byte) bool {
+	messages, err := syscall.ParseNetlinkMessage(data)
+	if err != nil {
+		return false
+	}
+	for _, message := range messages {
+		switch message.Header.Type {
+		case syscall.RTM_NEWADDR, syscall.RTM_DELADDR:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package netevents
+
+import (
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func makeNetlinkMessage(messageType uint16) []byte {
+	header := syscall.NlMsghdr{
+		Len:  syscall.SizeofNlMsghdr,
+		Type: messageType,
+	}
+	data := (*[syscall.SizeofNlMsghdr]byte)(unsafe.Pointer(&header))
+	return append([]byte(nil), data[:]...)
+}
+
+func Test_isNetlinkAddressChange(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data   []byte
+		change bool
+	}{
+		"empty": {},
+		"truncated": {
+			data: makeNetlinkMessage(syscall.RTM_NEWADDR)[:4],
+		},
+		"new address": {
+			data:   makeNetlinkMessage(syscall.RTM_NEWADDR),
+			change: true,
+		},
+		"deleted address": {
+			data:   makeNetlinkMessage(syscall.RTM_DELADDR),
+			change: true,
+		},
+		"new route": {
+			data: makeNetlinkMessage(syscall.RTM_NEWROUTE),
+		},
+		"new link then new address": {
+			data: append(makeNetlinkMessage(syscall.RTM_NEWLINK),
+				makeNetlinkMessage(syscall.RTM_NEWADDR)...),
+			change: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			change := isNetlinkAddressChange(testCase.data)
+
+			assert.Equal(t, testCase.change, change)
+		})
+	}
+}
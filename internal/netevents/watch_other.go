@@ -0,0 +1,9 @@
+//go:build !linux && !darwin && !windows
+
+package netevents
+
+import "context"
+
+func watch(context.Context, chan<- struct{}) (err error) {
+	return ErrNotSupported
+}
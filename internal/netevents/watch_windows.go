@@ -0,0 +1,46 @@
+package netevents
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+//nolint:gochecknoglobals
+var (
+	iphlpapi                    = syscall.NewLazyDLL("iphlpapi.dll")
+	procNotifyIPInterfaceChange = iphlpapi.NewProc("NotifyIpInterfaceChange")
+	procCancelMibChangeNotify2  = iphlpapi.NewProc("CancelMibChangeNotify2")
+)
+
+// watch uses NotifyIpInterfaceChange of the IP helper API, which calls
+// back on each IPv4 or IPv6 interface change.
+func watch(ctx context.Context, events chan<- struct{}) (err error) {
+	if err := procNotifyIPInterfaceChange.Find(); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotSupported, err)
+	}
+
+	callback := syscall.NewCallback(func(_, _, _ uintptr) uintptr {
+		notify(events)
+		return 0
+	})
+
+	const (
+		afUnspec            = 0
+		initialNotification = 0
+	)
+	var handle uintptr
+	result, _, _ := procNotifyIPInterfaceChange.Call(afUnspec, callback, 0,
+		initialNotification, uintptr(unsafe.Pointer(&handle)))
+	if result != 0 {
+		return fmt.Errorf("registering for interface changes: %w", syscall.Errno(result))
+	}
+
+	<-ctx.Done()
+	result, _, _ = procCancelMibChangeNotify2.Call(handle)
+	if result != 0 {
+		return fmt.Errorf("canceling interface changes registration: %w", syscall.Errno(result))
+	}
+	return nil
+}
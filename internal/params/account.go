@@ -0,0 +1,54 @@
+package params
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	errAccountNotFound     = errors.New("account not found")
+	errUnmarshalAccount    = errors.New("cannot unmarshal account settings")
+	errAccountProviderDiff = errors.New("account provider differs from record provider")
+)
+
+// mergeAccountSettings adds the settings of the account referenced
+// by the "account" field of the record raw settings, if any.
+// Settings of the record take precedence over the account settings.
+func mergeAccountSettings(rawSettings json.RawMessage,
+	accounts map[string]json.RawMessage) (merged json.RawMessage, err error) {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(rawSettings, &record); err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnmarshalRaw, err)
+	}
+
+	var accountName string
+	if rawAccountName, ok := record["account"]; !ok {
+		return rawSettings, nil
+	} else if err := json.Unmarshal(rawAccountName, &accountName); err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnmarshalAccount, err)
+	}
+
+	rawAccount, ok := accounts[accountName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", errAccountNotFound, accountName)
+	}
+	var account map[string]json.RawMessage
+	if err := json.Unmarshal(rawAccount, &account); err != nil {
+		return nil, fmt.Errorf("%w: for account %q: %s", errUnmarshalAccount, accountName, err)
+	}
+
+	for key, value := range account {
+		recordValue, ok := record[key]
+		if !ok {
+			record[key] = value
+			continue
+		}
+		if key == "provider" && string(recordValue) != string(value) {
+			return nil, fmt.Errorf("%w: %s for account %q and %s for record",
+				errAccountProviderDiff, value, accountName, recordValue)
+		}
+	}
+
+	return json.Marshal(record)
+}
@@ -0,0 +1,63 @@
+package params
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mergeAccountSettings(t *testing.T) {
+	t.Parallel()
+
+	accounts := map[string]json.RawMessage{
+		"work": json.RawMessage(`{"provider":"cloudflare","token":"abc","ttl":600}`),
+	}
+
+	testCases := map[string]struct {
+		rawSettings json.RawMessage
+		merged      string
+		errWrapped  error
+		errMessage  string
+	}{
+		"no account": {
+			rawSettings: json.RawMessage(`{"provider":"cloudflare","token":"x"}`),
+			merged:      `{"provider":"cloudflare","token":"x"}`,
+		},
+		"account settings added": {
+			rawSettings: json.RawMessage(`{"account":"work","domain":"example.com"}`),
+			merged:      `{"account":"work","domain":"example.com","provider":"cloudflare","token":"abc","ttl":600}`,
+		},
+		"record settings take precedence": {
+			rawSettings: json.RawMessage(`{"account":"work","provider":"cloudflare","ttl":1}`),
+			merged:      `{"account":"work","provider":"cloudflare","token":"abc","ttl":1}`,
+		},
+		"account not found": {
+			rawSettings: json.RawMessage(`{"account":"home"}`),
+			errWrapped:  errAccountNotFound,
+			errMessage:  `account not found: "home"`,
+		},
+		"provider differs": {
+			rawSettings: json.RawMessage(`{"account":"work","provider":"godaddy"}`),
+			errWrapped:  errAccountProviderDiff,
+			errMessage: `account provider differs from record provider: ` +
+				`"cloudflare" for account "work" and "godaddy" for record`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			merged, err := mergeAccountSettings(testCase.rawSettings, accounts)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.JSONEq(t, testCase.merged, string(merged))
+		})
+	}
+}
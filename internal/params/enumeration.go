@@ -0,0 +1,234 @@
+package params
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	errEnumerationMalformed = errors.New("host enumeration is malformed")
+	errEnumerationTooLarge  = errors.New("host enumeration is too large")
+	errUnmarshalOverrides   = errors.New("cannot unmarshal overrides")
+	errOverrideUnknownHost  = errors.New("override host is not in the host enumeration")
+	errHostsEmpty           = errors.New("hosts array is empty")
+	errHostDuplicate        = errors.New("host is duplicated")
+)
+
+// maxEnumerationHosts is the maximum number of hosts a record
+// enumeration can expand to, to catch typos such as {1..10000}.
+const maxEnumerationHosts = 1000
+
+// expandEnumeration expands the host enumeration of the record raw
+// settings, for example "node-{1..20}" or "{eu,us}-gateway", into one
+// raw settings per host. Each expanded host gets the settings of its
+// entry in the "overrides" object of the record, if any.
+// The host can also be an array of hosts or host patterns, for example
+// ["@", "www", "node-{1..3}"], such that one record with its credentials
+// fans out into one record per host.
+// The raw settings are returned as is if the host has no enumeration.
+func expandEnumeration(rawSettings json.RawMessage) (expanded []json.RawMessage, err error) {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(rawSettings, &record); err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnmarshalRaw, err)
+	}
+
+	var host string
+	var hostsArray []string
+	if rawHost, ok := record["host"]; ok {
+		if json.Unmarshal(rawHost, &hostsArray) != nil {
+			hostsArray = nil
+			_ = json.Unmarshal(rawHost, &host) // type checked later on
+		} else if len(hostsArray) == 0 {
+			return nil, errHostsEmpty
+		}
+	}
+	rawOverrides, hasOverrides := record["overrides"]
+	if hostsArray == nil && !strings.Contains(host, "{") && !hasOverrides {
+		return []json.RawMessage{rawSettings}, nil
+	}
+
+	var hosts []string
+	if hostsArray == nil {
+		hosts, err = expandHosts(host)
+	} else {
+		hosts, err = expandHostsArray(hostsArray)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides map[string]map[string]json.RawMessage
+	if hasOverrides {
+		if err := json.Unmarshal(rawOverrides, &overrides); err != nil {
+			return nil, fmt.Errorf("%w: %s", errUnmarshalOverrides, err)
+		}
+	}
+	delete(record, "overrides")
+
+	hostsSet := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		hostsSet[host] = struct{}{}
+	}
+	for host := range overrides {
+		if _, ok := hostsSet[host]; !ok {
+			return nil, fmt.Errorf("%w: %q", errOverrideUnknownHost, host)
+		}
+	}
+
+	expanded = make([]json.RawMessage, len(hosts))
+	for i, host := range hosts {
+		item := make(map[string]json.RawMessage, len(record))
+		for key, value := range record {
+			item[key] = value
+		}
+		item["host"], _ = json.Marshal(host)
+		for key, value := range overrides[host] {
+			item[key] = value
+		}
+		expanded[i], err = json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+// expandHosts expands a comma separated list of host patterns,
+// where each pattern can contain brace expressions which are either
+// a numeric range such as {1..20} or {01..20} with zero padding,
+// or a comma separated list such as {eu,us}.
+func expandHosts(s string) (hosts []string, err error) {
+	for _, pattern := range splitOutsideBraces(s) {
+		patternHosts, err := expandPattern(pattern)
+		if errors.Is(err, errEnumerationTooLarge) {
+			return nil, err
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %q: %s", errEnumerationMalformed, pattern, err)
+		}
+		hosts = append(hosts, patternHosts...)
+		if len(hosts) > maxEnumerationHosts {
+			return nil, fmt.Errorf("%w: more than %d hosts", errEnumerationTooLarge, maxEnumerationHosts)
+		}
+	}
+	return hosts, nil
+}
+
+// expandHostsArray expands each host pattern of the array,
+// and checks the resulting hosts are not duplicated.
+func expandHostsArray(patterns []string) (hosts []string, err error) {
+	for _, pattern := range patterns {
+		patternHosts, err := expandHosts(pattern)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, patternHosts...)
+		if len(hosts) > maxEnumerationHosts {
+			return nil, fmt.Errorf("%w: more than %d hosts", errEnumerationTooLarge, maxEnumerationHosts)
+		}
+	}
+
+	seen := make(map[string]struct{}, len(hosts))
+	for _, host := range hosts {
+		if _, ok := seen[host]; ok {
+			return nil, fmt.Errorf("%w: %q", errHostDuplicate, host)
+		}
+		seen[host] = struct{}{}
+	}
+	return hosts, nil
+}
+
+func splitOutsideBraces(s string) (parts []string) {
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+var (
+	errBraceUnclosed = errors.New("brace is not closed")
+	errBraceNested   = errors.New("braces cannot be nested")
+	errBraceContent  = errors.New("braces must contain a range such as 1..20 or a list such as a,b")
+)
+
+var rangeRegex = regexp.MustCompile(`^(\d+)\.\.(\d+)$`)
+
+func expandPattern(pattern string) (hosts []string, err error) {
+	open := strings.Index(pattern, "{")
+	if open == -1 {
+		if strings.Contains(pattern, "}") {
+			return nil, errBraceContent
+		}
+		return []string{pattern}, nil
+	}
+	closing := strings.Index(pattern[open:], "}")
+	if closing == -1 {
+		return nil, errBraceUnclosed
+	}
+	closing += open
+	content := pattern[open+1 : closing]
+	if strings.Contains(content, "{") {
+		return nil, errBraceNested
+	}
+
+	values, err := expandBraceContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	suffixes, err := expandPattern(pattern[closing+1:])
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := pattern[:open]
+	hosts = make([]string, 0, len(values)*len(suffixes))
+	for _, value := range values {
+		for _, suffix := range suffixes {
+			hosts = append(hosts, prefix+value+suffix)
+		}
+		if len(hosts) > maxEnumerationHosts {
+			return nil, fmt.Errorf("%w: more than %d hosts", errEnumerationTooLarge, maxEnumerationHosts)
+		}
+	}
+	return hosts, nil
+}
+
+func expandBraceContent(content string) (values []string, err error) {
+	if match := rangeRegex.FindStringSubmatch(content); match != nil {
+		start, startErr := strconv.Atoi(match[1])
+		end, endErr := strconv.Atoi(match[2])
+		if startErr != nil || endErr != nil || end < start || end-start >= maxEnumerationHosts {
+			return nil, fmt.Errorf("%w: %d..%d", errBraceContent, start, end)
+		}
+		width := 0
+		if len(match[1]) > 1 && match[1][0] == '0' {
+			width = len(match[1])
+		}
+		values = make([]string, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			values = append(values, fmt.Sprintf("%0*d", width, i))
+		}
+		return values, nil
+	}
+
+	values = strings.Split(content, ",")
+	if len(values) < 2 { //nolint:gomnd
+		return nil, errBraceContent
+	}
+	return values, nil
+}
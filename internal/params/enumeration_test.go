@@ -0,0 +1,92 @@
+package params
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_expandEnumeration(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rawSettings json.RawMessage
+		expanded    []string
+		errWrapped  error
+	}{
+		"no enumeration": {
+			rawSettings: json.RawMessage(`{"host":"@,www","ttl":1}`),
+			expanded:    []string{`{"host":"@,www","ttl":1}`},
+		},
+		"range": {
+			rawSettings: json.RawMessage(`{"host":"node-{1..3}"}`),
+			expanded:    []string{`{"host":"node-1"}`, `{"host":"node-2"}`, `{"host":"node-3"}`},
+		},
+		"zero padded range with list": {
+			rawSettings: json.RawMessage(`{"host":"{eu,us}-{08..09}"}`),
+			expanded: []string{`{"host":"eu-08"}`, `{"host":"eu-09"}`,
+				`{"host":"us-08"}`, `{"host":"us-09"}`},
+		},
+		"comma separated patterns": {
+			rawSettings: json.RawMessage(`{"host":"@,node-{1..2}"}`),
+			expanded:    []string{`{"host":"@"}`, `{"host":"node-1"}`, `{"host":"node-2"}`},
+		},
+		"overrides": {
+			rawSettings: json.RawMessage(`{"host":"node-{1..2}","ttl":1,` +
+				`"overrides":{"node-2":{"ttl":2,"ip_version":"ipv6"}}}`),
+			expanded: []string{`{"host":"node-1","ttl":1}`,
+				`{"host":"node-2","ip_version":"ipv6","ttl":2}`},
+		},
+		"hosts array": {
+			rawSettings: json.RawMessage(`{"host":["@","www","vpn"],"ttl":1}`),
+			expanded: []string{`{"host":"@","ttl":1}`, `{"host":"www","ttl":1}`,
+				`{"host":"vpn","ttl":1}`},
+		},
+		"hosts array with patterns and overrides": {
+			rawSettings: json.RawMessage(`{"host":["@","node-{1..2}"],` +
+				`"overrides":{"node-2":{"ttl":2}}}`),
+			expanded: []string{`{"host":"@"}`, `{"host":"node-1"}`, `{"host":"node-2","ttl":2}`},
+		},
+		"empty hosts array": {
+			rawSettings: json.RawMessage(`{"host":[]}`),
+			errWrapped:  errHostsEmpty,
+		},
+		"duplicate host in array": {
+			rawSettings: json.RawMessage(`{"host":["www","{www,vpn}"]}`),
+			errWrapped:  errHostDuplicate,
+		},
+		"override of unknown host": {
+			rawSettings: json.RawMessage(`{"host":"node-{1..2}","overrides":{"node-3":{}}}`),
+			errWrapped:  errOverrideUnknownHost,
+		},
+		"unclosed brace": {
+			rawSettings: json.RawMessage(`{"host":"node-{1..2"}`),
+			errWrapped:  errEnumerationMalformed,
+		},
+		"reversed range": {
+			rawSettings: json.RawMessage(`{"host":"node-{2..1}"}`),
+			errWrapped:  errEnumerationMalformed,
+		},
+		"too large": {
+			rawSettings: json.RawMessage(`{"host":"{1..100}-{1..100}"}`),
+			errWrapped:  errEnumerationTooLarge,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expanded, err := expandEnumeration(testCase.rawSettings)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			var expandedStrings []string
+			for _, rawSettings := range expanded {
+				expandedStrings = append(expandedStrings, string(rawSettings))
+			}
+			assert.Equal(t, testCase.expanded, expandedStrings)
+		})
+	}
+}
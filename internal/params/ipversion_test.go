@@ -0,0 +1,51 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseIPVersions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s          string
+		ipVersions []ipversion.IPVersion
+		errWrapped error
+		errMessage string
+	}{
+		"empty": {
+			ipVersions: []ipversion.IPVersion{ipversion.IP4or6},
+		},
+		"ipv6": {
+			s:          "ipv6",
+			ipVersions: []ipversion.IPVersion{ipversion.IP6},
+		},
+		"dual stack": {
+			s:          "IPv4 and IPv6",
+			ipVersions: []ipversion.IPVersion{ipversion.IP4, ipversion.IP6},
+		},
+		"invalid": {
+			s:          "ipv5",
+			errWrapped: ipversion.ErrInvalidIPVersion,
+			errMessage: `invalid IP version: "ipv5"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ipVersions, err := parseIPVersions(testCase.s)
+
+			assert.Equal(t, testCase.ipVersions, ipVersions)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
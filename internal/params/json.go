@@ -6,11 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/url"
 	"os"
 	"strings"
+	"text/template"
+	"time"
 
+	"github.com/qdm12/ddns-updater/internal/iptransform"
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/ptr"
+	"github.com/qdm12/ddns-updater/internal/records"
 	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/resolver"
 	"github.com/qdm12/ddns-updater/internal/settings"
 	"github.com/qdm12/ddns-updater/internal/settings/constants"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
@@ -22,6 +29,31 @@ type commonSettings struct {
 	Domain    string `json:"domain"`
 	Host      string `json:"host"`
 	IPVersion string `json:"ip_version"`
+	AllowULA  bool   `json:"allow_ula"`
+	Account   string `json:"account"`
+	Tenant    string `json:"tenant"`
+	// Labels and NotificationTemplate are used for notifications
+	Labels               map[string]string `json:"labels"`
+	NotificationTemplate string            `json:"notification_template"`
+	// Fallback contains the settings of the fallback record
+	Fallback json.RawMessage `json:"fallback"`
+	// Resolvers are DNS servers to check the record IP address with
+	Resolvers []string `json:"resolvers"`
+	// Webhooks are notified on each successful update
+	Webhooks []webhookSettings `json:"webhooks"`
+	// ReplaceCNAME allows replacing a CNAME record on the host
+	ReplaceCNAME bool `json:"replace_cname"`
+	// PTR contains the settings to update the reverse DNS record
+	PTR json.RawMessage `json:"ptr"`
+	// Transforms are applied to the public IP address before publishing it
+	Transforms []iptransform.Settings `json:"transforms"`
+	// IPv6Suffix replaces the interface identifier of the IPv6 address
+	// published, keeping the delegated prefix detected
+	IPv6Suffix string `json:"ipv6_suffix"`
+	// Period overrides the global period to check the record
+	Period string `json:"period"`
+	// TTL is only used to warn when the provider ignores it
+	TTL json.RawMessage `json:"ttl"`
 	// Retro values for warnings
 	IPMethod *string `json:"ip_method,omitempty"`
 	Delay    *uint64 `json:"delay,omitempty"`
@@ -30,7 +62,7 @@ type commonSettings struct {
 // JSONSettings obtain the update settings from the JSON content, first trying from the environment variable CONFIG
 // and then from the file config.json.
 func (r *Reader) JSONSettings(filePath string) (
-	allSettings []settings.Settings, warnings []string, err error) {
+	allSettings []records.Config, warnings []string, err error) {
 	allSettings, warnings, err = r.getSettingsFromEnv(filePath)
 	if allSettings != nil || warnings != nil || err != nil {
 		return allSettings, warnings, err
@@ -42,7 +74,7 @@ var errWriteConfigToFile = errors.New("cannot write configuration to file")
 
 // getSettingsFromFile obtain the update settings from config.json.
 func (r *Reader) getSettingsFromFile(filePath string) (
-	allSettings []settings.Settings, warnings []string, err error) {
+	allSettings []records.Config, warnings []string, err error) {
 	r.logger.Info("reading JSON config from file " + filePath)
 	bytes, err := r.readFile(filePath)
 	if err != nil {
@@ -62,13 +94,13 @@ func (r *Reader) getSettingsFromFile(filePath string) (
 	}
 	r.logger.Debug("config read: " + string(bytes))
 
-	return extractAllSettings(bytes)
+	return extractAllSettings(bytes, r.matcher)
 }
 
 // getSettingsFromEnv obtain the update settings from the environment variable CONFIG.
 // If the settings are valid, they are written to the filePath.
 func (r *Reader) getSettingsFromEnv(filePath string) (
-	allSettings []settings.Settings, warnings []string, err error) {
+	allSettings []records.Config, warnings []string, err error) {
 	s, err := r.env.Get("CONFIG", params.CaseSensitiveValue())
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: for environment variable CONFIG", err)
@@ -80,7 +112,7 @@ func (r *Reader) getSettingsFromEnv(filePath string) (
 
 	b := []byte(s)
 
-	allSettings, warnings, err = extractAllSettings(b)
+	allSettings, warnings, err = extractAllSettings(b, r.matcher)
 	if err != nil {
 		return allSettings, warnings, fmt.Errorf("configuration given: %w", err)
 	}
@@ -103,37 +135,68 @@ var (
 	errUnmarshalRaw    = errors.New("cannot unmarshal raw configuration")
 )
 
-func extractAllSettings(jsonBytes []byte) (
-	allSettings []settings.Settings, warnings []string, err error) {
-	config := struct {
-		CommonSettings []commonSettings `json:"settings"`
-	}{}
+func extractAllSettings(jsonBytes []byte, matcher *regex.Matcher) (
+	allSettings []records.Config, warnings []string, err error) {
 	rawConfig := struct {
 		Settings []json.RawMessage `json:"settings"`
+		// Accounts maps an account name to its provider and
+		// credentials settings, shared by the records using it.
+		Accounts map[string]json.RawMessage `json:"accounts"`
+		// Tenants maps a tenant name to its API token
+		// and notification targets.
+		Tenants map[string]tenantSettings `json:"tenants"`
 	}{}
-	if err := json.Unmarshal(jsonBytes, &config); err != nil {
-		return nil, nil, fmt.Errorf("%w: %s", errUnmarshalCommon, err)
-	}
 	if err := json.Unmarshal(jsonBytes, &rawConfig); err != nil {
 		return nil, nil, fmt.Errorf("%w: %s", errUnmarshalRaw, err)
 	}
-	matcher := regex.NewMatcher()
 
-	for i, common := range config.CommonSettings {
-		newSettings, newWarnings, err := makeSettingsFromObject(common, rawConfig.Settings[i], matcher)
-		warnings = append(warnings, newWarnings...)
+	tenants, err := makeTenants(rawConfig.Tenants)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, rawSettings := range rawConfig.Settings {
+		rawSettings, err = mergeAccountSettings(rawSettings, rawConfig.Accounts)
+		if err != nil {
+			return nil, warnings, err
+		}
+
+		expandedSettings, err := expandEnumeration(rawSettings)
 		if err != nil {
 			return nil, warnings, err
 		}
-		allSettings = append(allSettings, newSettings...)
+
+		for _, rawSettings := range expandedSettings {
+			var common commonSettings
+			if err := json.Unmarshal(rawSettings, &common); err != nil {
+				return nil, warnings, fmt.Errorf("%w: %s", errUnmarshalCommon, err)
+			}
+
+			newSettings, newWarnings, err := makeSettingsFromObject(common, rawSettings, tenants, matcher)
+			warnings = append(warnings, newWarnings...)
+			if err != nil {
+				return nil, warnings, err
+			}
+			allSettings = append(allSettings, newSettings...)
+		}
 	}
 
 	return allSettings, warnings, nil
 }
 
+var (
+	errNotificationTemplate = errors.New("notification template is malformed")
+	errFallbackSettings     = errors.New("fallback settings are invalid")
+	errResolvers            = errors.New("resolvers are invalid")
+	errWebhookURL           = errors.New("webhook URL is invalid")
+	errPTRSettings          = errors.New("PTR settings are invalid")
+	errTenantNotFound       = errors.New("tenant not found")
+	errTransforms           = errors.New("IP transforms are invalid")
+	errPeriodMalformed      = errors.New("period is malformed")
+)
+
 func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
-	matcher *regex.Matcher) (
-	settingsSlice []settings.Settings, warnings []string, err error) {
+	tenants map[string]*records.Tenant, matcher *regex.Matcher) (
+	settingsSlice []records.Config, warnings []string, err error) {
 	provider := models.Provider(common.Provider)
 	if provider == constants.DuckDNS { // only hosts, no domain
 		if len(common.Domain) > 0 { // retro compatibility
@@ -151,21 +214,156 @@ func makeSettingsFromObject(common commonSettings, rawSettings json.RawMessage,
 	}
 	hosts := strings.Split(common.Host, ",")
 
-	if len(common.IPVersion) == 0 {
-		common.IPVersion = ipversion.IP4or6.String()
-	}
-	ipVersion, err := ipversion.Parse(common.IPVersion)
+	ipVersions, err := parseIPVersions(common.IPVersion)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	settingsSlice = make([]settings.Settings, len(hosts))
-	for i, host := range hosts {
-		settingsSlice[i], err = settings.New(provider, rawSettings, common.Domain,
-			host, ipVersion, matcher)
+	options := records.Options{
+		AllowULA:     common.AllowULA,
+		Account:      common.Account,
+		Labels:       common.Labels,
+		ReplaceCNAME: common.ReplaceCNAME,
+	}
+	if common.Period != "" {
+		options.Period, err = time.ParseDuration(common.Period)
 		if err != nil {
-			return nil, warnings, err
+			return nil, warnings, fmt.Errorf("%w: %s", errPeriodMalformed, err)
+		} else if options.Period <= 0 {
+			return nil, warnings, fmt.Errorf("%w: %s must be positive", errPeriodMalformed, common.Period)
+		}
+	}
+	if common.Tenant != "" {
+		options.Tenant = tenants[common.Tenant]
+		if options.Tenant == nil {
+			return nil, warnings, fmt.Errorf("%w: %q", errTenantNotFound, common.Tenant)
+		}
+	}
+	if common.NotificationTemplate != "" {
+		options.NotificationTemplate, err = template.New("notification").
+			Parse(common.NotificationTemplate)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %s", errNotificationTemplate, err)
+		}
+	}
+	if len(common.Resolvers) > 0 {
+		options.Resolver, err = resolver.New(common.Resolvers)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %s", errResolvers, err)
+		}
+	}
+	options.Webhooks, err = makeWebhooks(common.Webhooks)
+	if err != nil {
+		return nil, warnings, err
+	}
+	if len(common.PTR) > 0 {
+		options.PTR, err = ptr.New(common.PTR)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %s", errPTRSettings, err)
+		}
+	}
+	options.Transforms, err = iptransform.New(common.Transforms)
+	if err != nil {
+		return nil, warnings, fmt.Errorf("%w: %s", errTransforms, err)
+	}
+	if common.IPv6Suffix != "" {
+		suffixTransform, err := iptransform.NewSuffix(common.IPv6Suffix)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%w: %s", errTransforms, err)
+		}
+		options.Transforms = append(options.Transforms, suffixTransform)
+		if len(ipVersions) == 1 && ipVersions[0] == ipversion.IP4 {
+			warnings = append(warnings,
+				fmt.Sprintf("ignoring ipv6_suffix for %s record of domain %q with IP version ipv4",
+					provider, common.Domain))
+		}
+	}
+
+	settingsSlice = make([]records.Config, 0, len(ipVersions)*len(hosts))
+	for _, ipVersion := range ipVersions {
+		versionOptions := options
+		if len(common.Fallback) > 0 {
+			versionOptions.Fallback, err = makeFallbackSettings(common.Fallback, ipVersion, matcher)
+			if err != nil {
+				return nil, warnings, fmt.Errorf("%w: %s", errFallbackSettings, err)
+			}
+		}
+
+		for _, host := range hosts {
+			config := records.Config{Options: versionOptions}
+			config.Settings, err = settings.New(provider, rawSettings, common.Domain,
+				host, ipVersion, matcher)
+			if err != nil {
+				return nil, warnings, err
+			}
+			settingsSlice = append(settingsSlice, config)
+		}
+	}
+	if len(common.TTL) > 0 && len(settingsSlice) > 0 {
+		if _, ok := settingsSlice[0].Settings.(settings.TTLer); !ok {
+			warnings = append(warnings,
+				fmt.Sprintf("ignoring ttl for %s record of domain %q because the provider does not support it",
+					provider, common.Domain))
 		}
 	}
 	return settingsSlice, warnings, nil
 }
+
+// ipVersionDualStack is the IP version of a record updating
+// both its A and AAAA records, which is split in an IPv4 record
+// and an IPv6 record.
+const ipVersionDualStack = "ipv4 and ipv6"
+
+// parseIPVersions returns the IP versions of the records to create
+// for the IP version string given, which defaults to "ipv4 or ipv6".
+func parseIPVersions(s string) (ipVersions []ipversion.IPVersion, err error) {
+	switch {
+	case s == "":
+		return []ipversion.IPVersion{ipversion.IP4or6}, nil
+	case strings.EqualFold(s, ipVersionDualStack):
+		return []ipversion.IPVersion{ipversion.IP4, ipversion.IP6}, nil
+	}
+	ipVersion, err := ipversion.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return []ipversion.IPVersion{ipVersion}, nil
+}
+
+type webhookSettings struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+func makeWebhooks(settings []webhookSettings) (webhooks []records.Webhook, err error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+	webhooks = make([]records.Webhook, len(settings))
+	for i, s := range settings {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errWebhookURL, err)
+		} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: %q must be an http or https URL", errWebhookURL, s.URL)
+		}
+		webhooks[i] = records.Webhook{
+			URL:    s.URL,
+			Secret: s.Secret,
+		}
+	}
+	return webhooks, nil
+}
+
+// makeFallbackSettings creates the settings of a fallback record,
+// using the IP version of the record it is the fallback of.
+func makeFallbackSettings(rawSettings json.RawMessage, ipVersion ipversion.IPVersion,
+	matcher *regex.Matcher) (fallback settings.Settings, err error) {
+	var common commonSettings
+	if err := json.Unmarshal(rawSettings, &common); err != nil {
+		return nil, fmt.Errorf("%w: %s", errUnmarshalCommon, err)
+	}
+	provider := models.Provider(common.Provider)
+	return settings.New(provider, rawSettings, common.Domain,
+		common.Host, ipVersion, matcher)
+}
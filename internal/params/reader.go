@@ -4,20 +4,23 @@ import (
 	"io/fs"
 	"os"
 
+	"github.com/qdm12/ddns-updater/internal/regex"
 	"github.com/qdm12/golibs/logging"
 	"github.com/qdm12/golibs/params"
 )
 
 type Reader struct {
 	logger    logging.Logger
+	matcher   *regex.Matcher
 	env       envInterface
 	readFile  func(filename string) ([]byte, error)
 	writeFile func(filename string, data []byte, perm fs.FileMode) (err error)
 }
 
-func NewReader(logger logging.Logger) *Reader {
+func NewReader(logger logging.Logger, matcher *regex.Matcher) *Reader {
 	return &Reader{
 		logger:    logger,
+		matcher:   matcher,
 		env:       params.New(),
 		readFile:  os.ReadFile,
 		writeFile: os.WriteFile,
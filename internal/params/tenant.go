@@ -0,0 +1,40 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+type tenantSettings struct {
+	Token         string   `json:"token"`
+	Notifications []string `json:"notifications"`
+}
+
+var errTenantToken = errors.New("tenant token is invalid")
+
+// makeTenants creates the tenants of the configuration, which
+// must each have a distinct token to authenticate with.
+func makeTenants(settings map[string]tenantSettings) (
+	tenants map[string]*records.Tenant, err error) {
+	tenants = make(map[string]*records.Tenant, len(settings))
+	tokenToName := make(map[string]string, len(settings))
+	for name, s := range settings {
+		const minTokenLength = 16
+		if len(s.Token) < minTokenLength {
+			return nil, fmt.Errorf("%w: for tenant %q: must be at least %d characters",
+				errTenantToken, name, minTokenLength)
+		} else if otherName, ok := tokenToName[s.Token]; ok {
+			return nil, fmt.Errorf("%w: for tenant %q: same as tenant %q",
+				errTenantToken, name, otherName)
+		}
+		tokenToName[s.Token] = name
+		tenants[name] = &records.Tenant{
+			Name:          name,
+			Token:         s.Token,
+			Notifications: s.Notifications,
+		}
+	}
+	return tenants, nil
+}
@@ -0,0 +1,64 @@
+package params
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_makeTenants(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		settings   map[string]tenantSettings
+		tenants    map[string]*records.Tenant
+		errWrapped error
+		errMessage string
+	}{
+		"no tenant": {
+			tenants: map[string]*records.Tenant{},
+		},
+		"tenants": {
+			settings: map[string]tenantSettings{
+				"acme": {Token: "0123456789abcdef", Notifications: []string{"generic://example.com"}},
+			},
+			tenants: map[string]*records.Tenant{
+				"acme": {Name: "acme", Token: "0123456789abcdef",
+					Notifications: []string{"generic://example.com"}},
+			},
+		},
+		"token too short": {
+			settings: map[string]tenantSettings{
+				"acme": {Token: "short"},
+			},
+			errWrapped: errTenantToken,
+			errMessage: `tenant token is invalid: for tenant "acme": must be at least 16 characters`,
+		},
+		"duplicate token": {
+			settings: map[string]tenantSettings{
+				"a": {Token: "0123456789abcdef"},
+				"b": {Token: "0123456789abcdef"},
+			},
+			errWrapped: errTenantToken,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tenants, err := makeTenants(testCase.settings)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				if testCase.errMessage != "" {
+					assert.EqualError(t, err, testCase.errMessage)
+				}
+				return
+			}
+			assert.Equal(t, testCase.tenants, tenants)
+		})
+	}
+}
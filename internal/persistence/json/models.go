@@ -2,6 +2,7 @@ package json
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 )
@@ -11,9 +12,16 @@ type dataModel struct {
 }
 
 type record struct {
-	Domain string                `json:"domain"`
-	Host   string                `json:"host"`
-	Events []models.HistoryEvent `json:"ips"`
+	Domain string `json:"domain"`
+	Host   string `json:"host"`
+	// IPVersion is the IP version of the record, such that records
+	// of the same host with different IP versions have their own
+	// history. It is empty for records written by older versions.
+	IPVersion string                `json:"ip_version,omitempty"`
+	Events    []models.HistoryEvent `json:"ips"`
+	// LastUpdate is the time of the last successful update,
+	// even if the IP address did not change.
+	LastUpdate *time.Time `json:"last_update,omitempty"`
 }
 
 func (r record) String() string {
@@ -5,41 +5,132 @@ import (
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
-// StoreNewIP stores a new IP address for a certain domain and host.
-func (db *Database) StoreNewIP(domain, host string, ip net.IP, t time.Time) (err error) {
+// StoreNewIP stores a new IP address for a certain domain, host and IP version.
+func (db *Database) StoreNewIP(domain, host string, ipVersion ipversion.IPVersion,
+	ip net.IP, t time.Time) (err error) {
 	db.Lock()
 	defer db.Unlock()
-	for i, record := range db.data.Records {
-		if record.Domain == domain && record.Host == host {
-			db.data.Records[i].Events = append(db.data.Records[i].Events, models.HistoryEvent{
-				IP:   ip,
-				Time: t,
-			})
-			return db.write()
-		}
+	event := models.HistoryEvent{
+		IP:   ip,
+		Time: t,
 	}
-	db.data.Records = append(db.data.Records, record{
-		Domain: domain,
-		Host:   host,
-		Events: []models.HistoryEvent{{
-			IP:   ip,
-			Time: t,
-		}},
-	})
+	i := db.findForWrite(domain, host, ipVersion)
+	db.data.Records[i].Events = append(db.data.Records[i].Events, event)
 	return db.write()
 }
 
-// GetEvents gets all the IP addresses history for a certain domain and host, in the order
-// from oldest to newest.
-func (db *Database) GetEvents(domain, host string) (events []models.HistoryEvent, err error) {
+// GetEvents gets all the IP addresses history for a certain domain, host
+// and IP version, in the order from oldest to newest.
+func (db *Database) GetEvents(domain, host string, ipVersion ipversion.IPVersion) (
+	events []models.HistoryEvent, err error) {
 	db.RLock()
 	defer db.RUnlock()
-	for _, record := range db.data.Records {
-		if record.Domain == domain && record.Host == host {
-			return append(events, record.Events...), nil
+	i, legacy := db.find(domain, host, ipVersion)
+	switch {
+	case i == -1:
+		return nil, nil
+	case legacy:
+		return filterEvents(db.data.Records[i].Events, ipVersion, true), nil
+	default:
+		return append(events, db.data.Records[i].Events...), nil
+	}
+}
+
+// StoreLastUpdate stores the time of the last successful update
+// for a certain domain, host and IP version.
+func (db *Database) StoreLastUpdate(domain, host string, ipVersion ipversion.IPVersion,
+	t time.Time) (err error) {
+	db.Lock()
+	defer db.Unlock()
+	i := db.findForWrite(domain, host, ipVersion)
+	db.data.Records[i].LastUpdate = &t
+	return db.write()
+}
+
+// GetLastUpdate gets the time of the last successful update for a certain
+// domain, host and IP version, and returns the zero time if it is not set.
+func (db *Database) GetLastUpdate(domain, host string, ipVersion ipversion.IPVersion) (
+	t time.Time, err error) {
+	db.RLock()
+	defer db.RUnlock()
+	i, _ := db.find(domain, host, ipVersion)
+	if i == -1 || db.data.Records[i].LastUpdate == nil {
+		return t, nil
+	}
+	return *db.data.Records[i].LastUpdate, nil
+}
+
+// find returns the index of the record matching the domain, host and IP
+// version given. If there is no such record, it returns the index of the
+// record of the domain and host written by older versions without IP
+// version, with legacy set to true. It returns -1 if there is neither.
+func (db *Database) find(domain, host string, ipVersion ipversion.IPVersion) (
+	index int, legacy bool) {
+	index = -1
+	for i, record := range db.data.Records {
+		if record.Domain != domain || record.Host != host {
+			continue
+		}
+		switch record.IPVersion {
+		case ipVersion.String():
+			return i, false
+		case "":
+			index = i
+		}
+	}
+	return index, index != -1
+}
+
+// findForWrite returns the index of the record matching the domain, host
+// and IP version given, creating it if needed. The events of the IP version
+// of a legacy record without IP version are moved to the record returned,
+// such that records of the same host with different IP versions do not
+// share their history.
+func (db *Database) findForWrite(domain, host string, ipVersion ipversion.IPVersion) (index int) {
+	index, legacy := db.find(domain, host, ipVersion)
+	if index != -1 && !legacy {
+		return index
+	}
+
+	newRecord := record{
+		Domain:    domain,
+		Host:      host,
+		IPVersion: ipVersion.String(),
+	}
+	if index == -1 {
+		db.data.Records = append(db.data.Records, newRecord)
+		return len(db.data.Records) - 1
+	}
+
+	legacyRecord := db.data.Records[index]
+	newRecord.Events = filterEvents(legacyRecord.Events, ipVersion, true)
+	newRecord.LastUpdate = legacyRecord.LastUpdate
+	remainingEvents := filterEvents(legacyRecord.Events, ipVersion, false)
+	if len(remainingEvents) == 0 {
+		db.data.Records[index] = newRecord
+		return index
+	}
+	db.data.Records[index].Events = remainingEvents
+	db.data.Records = append(db.data.Records, newRecord)
+	return len(db.data.Records) - 1
+}
+
+// filterEvents returns the events whose IP address matches
+// the IP version given if keep is true, and the other events
+// otherwise. All the events match the IP version "ipv4 or ipv6".
+func filterEvents(events []models.HistoryEvent, ipVersion ipversion.IPVersion,
+	keep bool) (filtered []models.HistoryEvent) {
+	for _, event := range events {
+		isIPv4 := event.IP.To4() != nil
+		matches := ipVersion == ipversion.IP4or6 ||
+			(ipVersion == ipversion.IP4 && isIPv4) ||
+			(ipVersion == ipversion.IP6 && !isIPv4)
+		if matches == keep {
+			filtered = append(filtered, event)
 		}
 	}
-	return nil, nil
+	return filtered
 }
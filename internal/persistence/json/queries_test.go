@@ -0,0 +1,98 @@
+package json
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Database_dualStack(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	db, err := NewDatabase(dataDir)
+	require.NoError(t, err)
+
+	ipv4 := net.IPv4(1, 2, 3, 4)
+	ipv6 := net.ParseIP("2001:db8::1")
+	t1 := time.Unix(1, 0).UTC()
+	t2 := time.Unix(2, 0).UTC()
+
+	err = db.StoreNewIP("example.com", "@", ipversion.IP4, ipv4, t1)
+	require.NoError(t, err)
+	err = db.StoreNewIP("example.com", "@", ipversion.IP6, ipv6, t2)
+	require.NoError(t, err)
+	err = db.StoreLastUpdate("example.com", "@", ipversion.IP4, t2)
+	require.NoError(t, err)
+
+	// read back the file to check the records are persisted separately
+	db, err = NewDatabase(dataDir)
+	require.NoError(t, err)
+
+	events, err := db.GetEvents("example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	assert.Equal(t, []models.HistoryEvent{{IP: ipv4, Time: t1}}, events)
+	events, err = db.GetEvents("example.com", "@", ipversion.IP6)
+	require.NoError(t, err)
+	assert.Equal(t, []models.HistoryEvent{{IP: ipv6, Time: t2}}, events)
+
+	lastUpdate, err := db.GetLastUpdate("example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	assert.Equal(t, t2, lastUpdate)
+	lastUpdate, err = db.GetLastUpdate("example.com", "@", ipversion.IP6)
+	require.NoError(t, err)
+	assert.True(t, lastUpdate.IsZero())
+}
+
+func Test_Database_legacyMigration(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	const legacyData = `{"records": [{"domain": "example.com", "host": "@", "ips": [
+	{"ip": "1.2.3.4", "time": "1970-01-01T00:00:01Z"},
+	{"ip": "2001:db8::1", "time": "1970-01-01T00:00:02Z"}
+]}]}`
+	err := os.WriteFile(filepath.Join(dataDir, "updates.json"), []byte(legacyData), 0600)
+	require.NoError(t, err)
+	db, err := NewDatabase(dataDir)
+	require.NoError(t, err)
+
+	ipv4 := net.ParseIP("1.2.3.4")
+	ipv6 := net.ParseIP("2001:db8::1")
+	t1 := time.Unix(1, 0).UTC()
+	t2 := time.Unix(2, 0).UTC()
+	t3 := time.Unix(3, 0).UTC()
+
+	// legacy events are split by IP version when read
+	events, err := db.GetEvents("example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	assert.Equal(t, []models.HistoryEvent{{IP: ipv4, Time: t1}}, events)
+	events, err = db.GetEvents("example.com", "@", ipversion.IP6)
+	require.NoError(t, err)
+	assert.Equal(t, []models.HistoryEvent{{IP: ipv6, Time: t2}}, events)
+
+	// writing migrates the events of the IP version to their own record
+	newIPv4 := net.ParseIP("5.6.7.8")
+	err = db.StoreNewIP("example.com", "@", ipversion.IP4, newIPv4, t3)
+	require.NoError(t, err)
+
+	expectedRecords := []record{
+		{Domain: "example.com", Host: "@",
+			Events: []models.HistoryEvent{{IP: ipv6, Time: t2}}},
+		{Domain: "example.com", Host: "@", IPVersion: "ipv4",
+			Events: []models.HistoryEvent{{IP: ipv4, Time: t1}, {IP: newIPv4, Time: t3}}},
+	}
+	assert.Equal(t, expectedRecords, db.data.Records)
+
+	err = db.StoreNewIP("example.com", "@", ipversion.IP6, ipv6, t3)
+	require.NoError(t, err)
+	assert.Equal(t, "ipv6", db.data.Records[0].IPVersion)
+	assert.Len(t, db.data.Records, 2)
+}
@@ -0,0 +1,188 @@
+// Package propagation verifies the IP address published to a record
+// is served by DNS servers, such as the authoritative nameservers of
+// the zone of the record and public resolvers.
+package propagation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/resolver"
+)
+
+// Settings are the settings of the propagation verification.
+type Settings struct {
+	// Tries is the maximum number of verifications.
+	Tries uint
+	// Interval is the duration to wait between two verifications.
+	Interval time.Duration
+	// Resolvers are addresses of DNS servers queried in addition
+	// to the authoritative nameservers, such as 1.1.1.1 or 8.8.8.8.
+	Resolvers []string
+}
+
+// Checker verifies the propagation of IP addresses published to records.
+type Checker struct {
+	tries     uint
+	interval  time.Duration
+	resolvers []string
+	lookupNS  func(ctx context.Context, zone string) ([]*net.NS, error)
+	lookupIP  func(ctx context.Context, server, network, hostname string) ([]net.IP, error)
+	// lookupRecordIP looks up the IP addresses of the hostname
+	// with the resolver of a record.
+	lookupRecordIP func(ctx context.Context, resolver *net.Resolver, network, hostname string) ([]net.IP, error)
+}
+
+// New creates a propagation checker from its settings.
+func New(settings Settings) (checker *Checker, err error) {
+	for _, address := range settings.Resolvers {
+		_, err = resolver.New([]string{address})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tries := settings.Tries
+	if tries == 0 {
+		tries = 1
+	}
+
+	return &Checker{
+		tries:     tries,
+		interval:  settings.Interval,
+		resolvers: settings.Resolvers,
+		lookupNS:  net.DefaultResolver.LookupNS,
+		lookupIP:  lookupIP,
+		lookupRecordIP: func(ctx context.Context, resolver *net.Resolver,
+			network, hostname string) ([]net.IP, error) {
+			return resolver.LookupIP(ctx, network, hostname)
+		},
+	}, nil
+}
+
+var (
+	ErrNoNameserver  = errors.New("no authoritative nameserver found")
+	ErrNotPropagated = errors.New("IP address is not propagated")
+)
+
+// Check verifies the IP address is returned for the hostname by the
+// authoritative nameservers of the zone and by the extra resolvers.
+// It verifies again after each interval, until all the DNS servers
+// return the IP address or the maximum number of tries is reached.
+// A DNS server returning several IP addresses, such as for a round
+// robin record, only needs to return the IP address among them.
+// If the record resolver is not nil, it is queried instead of the
+// authoritative nameservers and the extra resolvers, for records
+// set with their own DNS servers such as in split-horizon setups.
+func (c *Checker) Check(ctx context.Context, zone, hostname string, ip net.IP,
+	recordResolver *net.Resolver) (err error) {
+	// A query for a wildcard host is answered by the wildcard record.
+	hostname = strings.Replace(hostname, "*", "ddns-updater-propagation-check", 1)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for try := uint(1); ; try++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		if recordResolver != nil {
+			err = c.checkRecordResolver(ctx, recordResolver, hostname, ip)
+		} else {
+			err = c.check(ctx, zone, hostname, ip)
+		}
+		if err == nil || try == c.tries {
+			break
+		}
+		timer.Reset(c.interval)
+	}
+	if err != nil {
+		return fmt.Errorf("after %d tries: %w", c.tries, err)
+	}
+	return nil
+}
+
+func (c *Checker) check(ctx context.Context, zone, hostname string, ip net.IP) (err error) {
+	nameservers, err := c.lookupNS(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("looking up nameservers of %s: %w", zone, err)
+	} else if len(nameservers) == 0 {
+		return fmt.Errorf("%w: for zone %s", ErrNoNameserver, zone)
+	}
+
+	servers := make([]string, 0, len(nameservers)+len(c.resolvers))
+	for _, nameserver := range nameservers {
+		servers = append(servers, strings.TrimSuffix(nameserver.Host, "."))
+	}
+	servers = append(servers, c.resolvers...)
+
+	network := ipNetwork(ip)
+	var notPropagated []string
+	for _, server := range servers {
+		ips, err := c.lookupIP(ctx, server, network, hostname)
+		switch {
+		case err != nil:
+			notPropagated = append(notPropagated, server+" ("+err.Error()+")")
+		case !containsIP(ips, ip):
+			notPropagated = append(notPropagated, server+" ("+ipsToString(ips)+")")
+		}
+	}
+
+	if len(notPropagated) > 0 {
+		return fmt.Errorf("%w: on %s", ErrNotPropagated, strings.Join(notPropagated, ", "))
+	}
+	return nil
+}
+
+func (c *Checker) checkRecordResolver(ctx context.Context, recordResolver *net.Resolver,
+	hostname string, ip net.IP) (err error) {
+	ips, err := c.lookupRecordIP(ctx, recordResolver, ipNetwork(ip), hostname)
+	switch {
+	case err != nil:
+		return fmt.Errorf("%w: on record resolvers (%s)", ErrNotPropagated, err)
+	case !containsIP(ips, ip):
+		return fmt.Errorf("%w: on record resolvers (%s)", ErrNotPropagated, ipsToString(ips))
+	}
+	return nil
+}
+
+func ipNetwork(ip net.IP) (network string) {
+	if ip.To4() != nil {
+		return "ip4"
+	}
+	return "ip6"
+}
+
+func lookupIP(ctx context.Context, server, network, hostname string) ([]net.IP, error) {
+	r, err := resolver.New([]string{server})
+	if err != nil {
+		return nil, err
+	}
+	return r.LookupIP(ctx, network, hostname)
+}
+
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, element := range ips {
+		if element.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func ipsToString(ips []net.IP) string {
+	if len(ips) == 0 {
+		return "no IP address"
+	}
+	s := make([]string, len(ips))
+	for i, ip := range ips {
+		s[i] = ip.String()
+	}
+	return strings.Join(s, ", ")
+}
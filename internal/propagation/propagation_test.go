@@ -0,0 +1,173 @@
+package propagation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Checker_Check(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		hostname string
+		// serverIPs maps a DNS server to the IP addresses it
+		// returns at each try, the last one being repeated.
+		serverIPs  map[string][][]net.IP
+		tries      uint
+		errWrapped error
+		errMessage string
+	}{
+		"propagated": {
+			hostname: "home.example.com",
+			serverIPs: map[string][][]net.IP{
+				"ns1.example.com": {{net.IPv4(1, 2, 3, 4)}},
+				"1.1.1.1":         {{net.IPv4(1, 2, 3, 4)}},
+			},
+			tries: 3,
+		},
+		"propagated at second try": {
+			hostname: "home.example.com",
+			serverIPs: map[string][][]net.IP{
+				"ns1.example.com": {{net.IPv4(1, 2, 3, 4)}},
+				"1.1.1.1":         {{net.IPv4(5, 6, 7, 8)}, {net.IPv4(1, 2, 3, 4)}},
+			},
+			tries: 3,
+		},
+		"round robin member": {
+			hostname: "*.example.com",
+			serverIPs: map[string][][]net.IP{
+				"ns1.example.com": {{net.IPv4(5, 6, 7, 8), net.IPv4(1, 2, 3, 4)}},
+				"1.1.1.1":         {{net.IPv4(1, 2, 3, 4)}},
+			},
+			tries: 1,
+		},
+		"not propagated": {
+			hostname: "home.example.com",
+			serverIPs: map[string][][]net.IP{
+				"ns1.example.com": {{net.IPv4(1, 2, 3, 4)}},
+				"1.1.1.1":         {{}},
+			},
+			tries:      2,
+			errWrapped: ErrNotPropagated,
+			errMessage: "after 2 tries: IP address is not propagated: on 1.1.1.1 (no IP address)",
+		},
+		"lookup error": {
+			hostname: "home.example.com",
+			serverIPs: map[string][][]net.IP{
+				"1.1.1.1": {{net.IPv4(1, 2, 3, 4)}},
+			},
+			tries:      1,
+			errWrapped: ErrNotPropagated,
+			errMessage: "after 1 tries: IP address is not propagated: on ns1.example.com (test error)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			checker, err := New(Settings{
+				Tries:     testCase.tries,
+				Resolvers: []string{"1.1.1.1"},
+			})
+			require.NoError(t, err)
+			checker.lookupNS = func(_ context.Context, zone string) ([]*net.NS, error) {
+				assert.Equal(t, "example.com", zone)
+				return []*net.NS{{Host: "ns1.example.com."}}, nil
+			}
+			checker.lookupIP = func(_ context.Context, server, network, hostname string) ([]net.IP, error) {
+				assert.Equal(t, "ip4", network)
+				assert.NotContains(t, hostname, "*")
+				tries, ok := testCase.serverIPs[server]
+				if !ok {
+					return nil, errTest
+				}
+				ips := tries[0]
+				if len(tries) > 1 {
+					testCase.serverIPs[server] = tries[1:]
+				}
+				return ips, nil
+			}
+
+			err = checker.Check(context.Background(), "example.com",
+				testCase.hostname, net.IPv4(1, 2, 3, 4), nil)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
+
+func Test_Checker_Check_recordResolver(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		ips        []net.IP
+		lookupErr  error
+		errWrapped error
+		errMessage string
+	}{
+		"propagated": {
+			ips: []net.IP{net.IPv4(1, 2, 3, 4)},
+		},
+		"not propagated": {
+			ips:        []net.IP{net.IPv4(5, 6, 7, 8)},
+			errWrapped: ErrNotPropagated,
+			errMessage: "after 1 tries: IP address is not propagated: on record resolvers (5.6.7.8)",
+		},
+		"lookup error": {
+			lookupErr:  errTest,
+			errWrapped: ErrNotPropagated,
+			errMessage: "after 1 tries: IP address is not propagated: on record resolvers (test error)",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			recordResolver := &net.Resolver{}
+			checker, err := New(Settings{
+				Tries:     1,
+				Resolvers: []string{"1.1.1.1"},
+			})
+			require.NoError(t, err)
+			checker.lookupNS = func(context.Context, string) ([]*net.NS, error) {
+				t.Error("authoritative nameservers looked up")
+				return nil, nil
+			}
+			checker.lookupIP = func(context.Context, string, string, string) ([]net.IP, error) {
+				t.Error("extra resolvers queried")
+				return nil, nil
+			}
+			checker.lookupRecordIP = func(_ context.Context, resolver *net.Resolver,
+				network, hostname string) ([]net.IP, error) {
+				assert.Same(t, recordResolver, resolver)
+				assert.Equal(t, "ip4", network)
+				assert.Equal(t, "home.example.com", hostname)
+				return testCase.ips, testCase.lookupErr
+			}
+
+			err = checker.Check(context.Background(), "example.com",
+				"home.example.com", net.IPv4(1, 2, 3, 4), recordResolver)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package ptr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+// hetznerRobot updates PTR records of dedicated servers
+// with the Hetzner Robot webservice.
+type hetznerRobot struct {
+	username string
+	password string
+}
+
+func newHetznerRobot(data json.RawMessage) (h *hetznerRobot, err error) {
+	var settings struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnmarshal, err)
+	}
+	switch {
+	case settings.Username == "":
+		return nil, fmt.Errorf("%w: username", ErrSettingMissing)
+	case settings.Password == "":
+		return nil, fmt.Errorf("%w: password", ErrSettingMissing)
+	}
+	return &hetznerRobot{
+		username: settings.Username,
+		password: settings.Password,
+	}, nil
+}
+
+func (h *hetznerRobot) APIHost() string {
+	return "robot-ws.your-server.de"
+}
+
+// UpdatePTR creates or updates the PTR record of the IP address.
+// See https://robot.your-server.de/doc/webservice/en.html#post-rdns-ip
+func (h *hetznerRobot) UpdatePTR(ctx context.Context, client *http.Client,
+	ip net.IP, hostname string) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   h.APIHost(),
+		Path:   "/rdns/" + ip.String(),
+	}
+	values := url.Values{}
+	values.Set("ptr", hostname)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(),
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/x-www-form-urlencoded")
+	request.SetBasicAuth(h.username, h.password)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d: %s", ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
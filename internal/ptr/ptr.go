@@ -0,0 +1,48 @@
+// Package ptr updates reverse DNS (PTR) records of IP addresses, for
+// the PTR record of the public IP address to point to the hostname of
+// its record, as needed for example by mail servers.
+package ptr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Updater updates the PTR record of an IP address.
+type Updater interface {
+	// UpdatePTR sets the PTR record of the IP address to the hostname.
+	UpdatePTR(ctx context.Context, client *http.Client, ip net.IP, hostname string) (err error)
+	// APIHost returns the host of the API, for the egress guard.
+	APIHost() string
+}
+
+var (
+	ErrUnmarshal       = errors.New("cannot unmarshal PTR settings")
+	ErrProviderUnknown = errors.New("PTR provider is unknown")
+	ErrSettingMissing  = errors.New("PTR setting is missing")
+	ErrBadHTTPStatus   = errors.New("bad HTTP status")
+)
+
+// New creates a PTR updater from its JSON settings,
+// where the "provider" field selects the updater.
+func New(data json.RawMessage) (updater Updater, err error) {
+	var settings struct {
+		Provider string `json:"provider"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnmarshal, err)
+	}
+
+	switch settings.Provider {
+	case "hetzner_robot":
+		return newHetznerRobot(data)
+	case "vultr":
+		return newVultr(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrProviderUnknown, settings.Provider)
+	}
+}
@@ -0,0 +1,69 @@
+package ptr
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"hetzner robot": {
+			data: `{"provider":"hetzner_robot","username":"user","password":"pass"}`,
+		},
+		"vultr": {
+			data: `{"provider":"vultr","api_key":"key","instance_id":"id"}`,
+		},
+		"unknown provider": {
+			data:       `{"provider":"ripe"}`,
+			errWrapped: ErrProviderUnknown,
+		},
+		"missing setting": {
+			data:       `{"provider":"vultr","api_key":"key"}`,
+			errWrapped: ErrSettingMissing,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_hetznerRobot_UpdatePTR(t *testing.T) {
+	t.Parallel()
+
+	updater, err := New(json.RawMessage(`{"provider":"hetzner_robot","username":"user","password":"pass"}`))
+	require.NoError(t, err)
+
+	registrar := providertest.NewRegistrar()
+	defer registrar.Close()
+	registrar.Script(providertest.Response{Status: http.StatusCreated})
+
+	err = updater.UpdatePTR(context.Background(), registrar.Client(),
+		net.IPv4(203, 0, 113, 1), "mail.example.com")
+
+	require.NoError(t, err)
+	requests := registrar.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/rdns/203.0.113.1", requests[0].URL.Path)
+	assert.Equal(t, "ptr=mail.example.com", requests[0].Body)
+}
@@ -0,0 +1,91 @@
+package ptr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+// vultr updates PTR records of the IP addresses of a Vultr instance.
+type vultr struct {
+	apiKey     string
+	instanceID string
+}
+
+func newVultr(data json.RawMessage) (v *vultr, err error) {
+	var settings struct {
+		APIKey     string `json:"api_key"`
+		InstanceID string `json:"instance_id"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnmarshal, err)
+	}
+	switch {
+	case settings.APIKey == "":
+		return nil, fmt.Errorf("%w: api_key", ErrSettingMissing)
+	case settings.InstanceID == "":
+		return nil, fmt.Errorf("%w: instance_id", ErrSettingMissing)
+	}
+	return &vultr{
+		apiKey:     settings.APIKey,
+		instanceID: settings.InstanceID,
+	}, nil
+}
+
+func (v *vultr) APIHost() string {
+	return "api.vultr.com"
+}
+
+// UpdatePTR sets the reverse DNS of the IP address of the instance.
+// See https://www.vultr.com/api/#tag/instances/operation/post-instances-instance-id-ipv4-reverse
+func (v *vultr) UpdatePTR(ctx context.Context, client *http.Client,
+	ip net.IP, hostname string) (err error) {
+	version := "ipv4"
+	if ip.To4() == nil {
+		version = "ipv6"
+	}
+	u := url.URL{
+		Scheme: "https",
+		Host:   v.APIHost(),
+		Path:   "/v2/instances/" + v.instanceID + "/" + version + "/reverse",
+	}
+
+	requestData := struct {
+		IP      string `json:"ip"`
+		Reverse string `json:"reverse"`
+	}{
+		IP:      ip.String(),
+		Reverse: hostname,
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), buffer)
+	if err != nil {
+		return err
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAuthBearer(request, v.apiKey)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("%w: %d: %s", ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Package ratelimit limits the rate of API requests made to each
+// provider, and optionally to each account of a provider, with
+// token buckets, such that many records of the same provider do not
+// exceed the documented rate limits of its API.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limiter limits the rate of requests per provider or per account.
+type Limiter struct {
+	// rates maps a provider to its maximum number of requests per second.
+	rates map[string]float64
+	// perAccount is true to rate limit each account of a provider
+	// independently, instead of all the accounts of a provider together.
+	perAccount bool
+	buckets    map[string]*bucket
+	timeNow    func() time.Time
+	mutex      sync.Mutex
+}
+
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// New creates a rate limiter with the maximum number of requests per
+// second of each provider given. Providers without a rate are not
+// rate limited.
+func New(rates map[string]float64, perAccount bool, timeNow func() time.Time) *Limiter {
+	return &Limiter{
+		rates:      rates,
+		perAccount: perAccount,
+		buckets:    make(map[string]*bucket),
+		timeNow:    timeNow,
+	}
+}
+
+// RoundTripper returns an HTTP round tripper waiting for the rate limit
+// of the provider and account given before each request. It returns the
+// proxied round tripper if the provider is not rate limited.
+func (l *Limiter) RoundTripper(proxied http.RoundTripper,
+	provider, account string) http.RoundTripper {
+	rate, ok := l.rates[provider]
+	if !ok || rate <= 0 {
+		return proxied
+	}
+	if proxied == nil {
+		proxied = http.DefaultTransport
+	}
+
+	key := provider
+	if l.perAccount && account != "" {
+		key += "/" + account
+	}
+	return &roundTripper{
+		proxied: proxied,
+		limiter: l,
+		key:     key,
+		rate:    rate,
+	}
+}
+
+type roundTripper struct {
+	proxied http.RoundTripper
+	limiter *Limiter
+	key     string
+	rate    float64
+}
+
+func (r *roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	err := r.limiter.wait(request.Context(), r.key, r.rate)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxied.RoundTrip(request)
+}
+
+func (l *Limiter) wait(ctx context.Context, key string, rate float64) (err error) {
+	wait := l.reserve(key, rate, l.timeNow())
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket of the key given and returns
+// the duration to wait for the token to be available.
+func (l *Limiter) reserve(key string, rate float64, now time.Time) (wait time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		// The burst allows the requests of a single update, which
+		// are usually a few, to be made without waiting.
+		burst := rate
+		if burst < 1 {
+			burst = 1
+		}
+		b = &bucket{
+			rate:   rate,
+			burst:  burst,
+			tokens: burst,
+			last:   now,
+		}
+		l.buckets[key] = b
+	}
+
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Limiter_reserve(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(0, 0)
+	limiter := New(map[string]float64{"cloudflare": 2}, false, nil)
+
+	// burst of 2 requests
+	assert.Zero(t, limiter.reserve("cloudflare", 2, start))
+	assert.Zero(t, limiter.reserve("cloudflare", 2, start))
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve("cloudflare", 2, start))
+	assert.Equal(t, time.Second, limiter.reserve("cloudflare", 2, start))
+
+	// tokens are refilled over time
+	assert.Equal(t, 500*time.Millisecond, limiter.reserve("cloudflare", 2, start.Add(time.Second)))
+	assert.Zero(t, limiter.reserve("cloudflare", 2, start.Add(3*time.Second)))
+
+	// slow rates have a burst of 1
+	assert.Zero(t, limiter.reserve("godaddy", 0.5, start))
+	assert.Equal(t, 2*time.Second, limiter.reserve("godaddy", 0.5, start))
+}
+
+func Test_Limiter_RoundTripper(t *testing.T) {
+	t.Parallel()
+
+	limiter := New(map[string]float64{"cloudflare": 1}, true, nil)
+
+	assert.Equal(t, http.DefaultTransport,
+		limiter.RoundTripper(http.DefaultTransport, "duckdns", "home"))
+
+	roundTripper, ok := limiter.RoundTripper(nil, "cloudflare", "home").(*roundTripper)
+	assert.True(t, ok)
+	assert.Equal(t, "cloudflare/home", roundTripper.key)
+	assert.Equal(t, http.DefaultTransport, roundTripper.proxied)
+}
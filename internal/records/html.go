@@ -16,6 +16,9 @@ func (r *Record) HTML(now time.Time) models.HTMLRow {
 	if r.Status == constants.UPTODATE {
 		message = "no IP change for " + r.History.GetDurationSinceSuccess(now)
 	}
+	if r.Confirmed {
+		message += ", confirmed by provider"
+	}
 	if len(message) > 0 {
 		message = fmt.Sprintf("(%s)", message)
 	}
@@ -58,6 +61,10 @@ func convertStatus(status models.Status) models.HTML {
 		return `<font color="red"><b>Failure</b></font>`
 	case constants.UPTODATE:
 		return `<font color="#00CC66"><b>Up to date</b></font>`
+	case constants.NOTPROPAGATED:
+		return `<font color="#99CC00"><b>Not propagated</b></font>`
+	case constants.COOLDOWN:
+		return `<font color="#CC3300"><b>Cooldown</b></font>`
 	case constants.UPDATING:
 		return `<font color="orange"><b>Updating</b></font>`
 	case constants.UNSET:
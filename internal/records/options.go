@@ -0,0 +1,83 @@
+package records
+
+import (
+	"net"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/iptransform"
+	"github.com/qdm12/ddns-updater/internal/ptr"
+	"github.com/qdm12/ddns-updater/internal/settings"
+)
+
+// Options contains provider agnostic options for a record.
+type Options struct {
+	// AllowULA allows publishing IPv6 unique local addresses (fc00::/7),
+	// for example to a DNS provider serving an internal network only.
+	AllowULA bool
+	// Account is the name of the account whose settings are used by
+	// the record. Records sharing an account share its ban and circuit
+	// breaker states. It is empty if the record has no account.
+	Account string
+	// Labels are arbitrary key values made available to notification templates.
+	Labels map[string]string
+	// NotificationTemplate overrides the global notification template
+	// for the record. It is nil if not set.
+	NotificationTemplate *template.Template
+	// Fallback is a secondary record updated with the IP address
+	// when the record provider persistently fails, for example a
+	// DuckDNS subdomain used as CNAME target. It is nil if not set.
+	Fallback settings.Settings
+	// Period is the period to check the record is up to date,
+	// overriding the global period. It is zero if not set.
+	Period time.Duration
+	// Resolver is the resolver used to check the IP address of the
+	// record before updating it and after updating it for the health
+	// check, for example using the provider authoritative servers in a
+	// split-horizon setup. It is nil to use the default resolver.
+	Resolver *net.Resolver
+	// Webhooks are notified each time the record is updated
+	// successfully, for programmatic consumers of its IP address.
+	Webhooks []Webhook
+	// PTR updates the reverse DNS record of the record IP address
+	// to point to the record hostname. It is nil if not set.
+	PTR ptr.Updater
+	// ReplaceCNAME allows removing a CNAME record found on the
+	// host of the record, for it to be replaced by an A or AAAA
+	// record, if the provider supports it.
+	ReplaceCNAME bool
+	// Tenant is the tenant owning the record, whose status is served
+	// to the tenant only and whose notifications are also sent to the
+	// tenant notification targets. It is nil if the record has no tenant.
+	Tenant *Tenant
+	// Transforms are applied to the public IP address detected
+	// before it is compared to and published to the record.
+	Transforms iptransform.Transforms
+}
+
+// Tenant groups records of a customer served by the same instance.
+type Tenant struct {
+	Name string
+	// Token is the API token the tenant uses to get
+	// the status of its records.
+	Token string
+	// Notifications are Shoutrrr addresses notified
+	// for the records of the tenant.
+	Notifications []string
+}
+
+// Webhook is an HTTP endpoint receiving a JSON payload with the
+// new IP address each time its record is updated successfully.
+type Webhook struct {
+	URL string
+	// Secret is used to sign the payload with HMAC-SHA256,
+	// and no signature is sent if it is empty.
+	Secret string
+}
+
+// Config contains the provider settings and the
+// provider agnostic options to create a record.
+type Config struct {
+	Settings settings.Settings
+	Options  Options
+}
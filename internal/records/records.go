@@ -7,27 +7,58 @@ import (
 	"github.com/qdm12/ddns-updater/internal/constants"
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/settings"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
 )
 
 // Record contains all the information to update and display a DNS record.
 type Record struct { // internal
 	Settings settings.Settings // fixed
+	Options  Options           // fixed
 	History  models.History    // past information
 	Status   models.Status
 	Message  string
-	Time     time.Time
-	LastBan  *time.Time // nil means no last ban
+	// ErrorCode is the machine readable code of the last
+	// update error, and is empty if the last update succeeded.
+	ErrorCode errors.Code
+	Time      time.Time
+	// LastUpdate is the time of the last successful update of
+	// the record, even if its IP address did not change.
+	LastUpdate time.Time
+	// CooldownUntil is the time before which no update is attempted
+	// after the provider reported an abuse or rate limited the record,
+	// and is the zero time if the record is not in cooldown.
+	CooldownUntil time.Time
+	// Failures is the number of consecutive failed updates.
+	Failures uint
+	// BackoffUntil is the time before which no update is attempted
+	// after failed updates, and is the zero time if there is no backoff.
+	BackoffUntil time.Time
+	// Confirmed is true if the provider confirmed through the webhook
+	// that the last update is applied. It is reset on each update.
+	Confirmed bool
 }
 
-// New returns a new Record with settings and some history.
-func New(settings settings.Settings, events []models.HistoryEvent) Record {
+// New returns a new Record with settings, options and some history.
+func New(settings settings.Settings, options Options, events []models.HistoryEvent) Record {
 	return Record{
 		Settings: settings,
+		Options:  options,
 		History:  events,
 		Status:   constants.UNSET,
 	}
 }
 
+// LastSuccessTime returns the time of the last successful update of
+// the record, falling back on the time of its last IP address change
+// for records updated before the last update time was stored.
+func (r *Record) LastSuccessTime() time.Time {
+	successTime := r.History.GetSuccessTime()
+	if r.LastUpdate.After(successTime) {
+		return r.LastUpdate
+	}
+	return successTime
+}
+
 func (r *Record) String() string {
 	status := string(r.Status)
 	if len(r.Message) > 0 {
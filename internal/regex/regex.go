@@ -1,46 +1,116 @@
 package regex
 
-import "regexp"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
 
-type Matcher struct {
-	goDaddyKey, duckDNSToken, namecheapPassword, dreamhostKey, cloudflareKey,
-	cloudflareUserServiceKey, dnsOMaticUsername, dnsOMaticPassword, gandiKey *regexp.Regexp
-}
-
-var (
-	gandiKey                 = regexp.MustCompile(`^[A-Za-z0-9]{24}$`)
-	goDaddyKey               = regexp.MustCompile(`^[A-Za-z0-9]{8,14}\_[A-Za-z0-9]{21,22}$`)
-	duckDNSToken             = regexp.MustCompile(`^[a-f0-9]{8}\-[a-f0-9]{4}\-[a-f0-9]{4}\-[a-f0-9]{4}\-[a-f0-9]{12}$`)
-	namecheapPassword        = regexp.MustCompile(`^[a-f0-9]{32}$`)
-	dreamhostKey             = regexp.MustCompile(`^[a-zA-Z0-9]{16}$`)
-	cloudflareKey            = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
-	cloudflareUserServiceKey = regexp.MustCompile(`^v1\.0.+$`)
-	dnsOMaticUsername        = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,25}$`)
-	dnsOMaticPassword        = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{5,19}$`)
+// Rule is the name of a credential format rule.
+type Rule string
+
+const (
+	GandiKey                 Rule = "gandi_key"
+	GoDaddyKey               Rule = "godaddy_key"
+	DuckDNSToken             Rule = "duckdns_token"
+	NamecheapPassword        Rule = "namecheap_password"
+	DreamhostKey             Rule = "dreamhost_key"
+	CloudflareKey            Rule = "cloudflare_key"
+	CloudflareUserServiceKey Rule = "cloudflare_user_service_key"
+	DNSOMaticUsername        Rule = "dnsomatic_username"
+	DNSOMaticPassword        Rule = "dnsomatic_password"
 )
 
+// rules declares the credential format of each rule.
+//
+//nolint:gochecknoglobals
+var rules = map[Rule]*regexp.Regexp{
+	GandiKey:                 regexp.MustCompile(`^[A-Za-z0-9]{24}$`),
+	GoDaddyKey:               regexp.MustCompile(`^[A-Za-z0-9]{8,14}\_[A-Za-z0-9]{21,22}$`),
+	DuckDNSToken:             regexp.MustCompile(`^[a-f0-9]{8}\-[a-f0-9]{4}\-[a-f0-9]{4}\-[a-f0-9]{4}\-[a-f0-9]{12}$`),
+	NamecheapPassword:        regexp.MustCompile(`^[a-f0-9]{32}$`),
+	DreamhostKey:             regexp.MustCompile(`^[a-zA-Z0-9]{16}$`),
+	CloudflareKey:            regexp.MustCompile(`^[a-zA-Z0-9]+$`),
+	CloudflareUserServiceKey: regexp.MustCompile(`^v1\.0.+$`),
+	DNSOMaticUsername:        regexp.MustCompile(`^[a-zA-Z0-9._-]{3,25}$`),
+	DNSOMaticPassword:        regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{5,19}$`),
+}
+
+// Rules returns all the rule names sorted alphabetically.
+func Rules() (names []string) {
+	names = make([]string, 0, len(rules))
+	for rule := range rules {
+		names = append(names, string(rule))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RelaxAll can be given to Relax to relax all the rules.
+const RelaxAll = "all"
+
+var ErrRuleUnknown = errors.New("credential format rule is unknown")
+
+// CheckRules returns an error if one of the rule names given is unknown.
+func CheckRules(names []string) (err error) {
+	for _, name := range names {
+		if _, ok := rules[Rule(name)]; !ok && name != RelaxAll {
+			return fmt.Errorf("%w: %s", ErrRuleUnknown, name)
+		}
+	}
+	return nil
+}
+
+type Matcher struct {
+	rules   map[Rule]*regexp.Regexp
+	relaxed map[Rule]struct{}
+}
+
 func NewMatcher() *Matcher {
 	return &Matcher{
-		gandiKey:                 gandiKey,
-		goDaddyKey:               goDaddyKey,
-		duckDNSToken:             duckDNSToken,
-		namecheapPassword:        namecheapPassword,
-		dreamhostKey:             dreamhostKey,
-		cloudflareKey:            cloudflareKey,
-		cloudflareUserServiceKey: cloudflareUserServiceKey,
-		dnsOMaticUsername:        dnsOMaticUsername,
-		dnsOMaticPassword:        dnsOMaticPassword,
+		rules:   rules,
+		relaxed: make(map[Rule]struct{}),
+	}
+}
+
+// Relax disables the format checks of the rules given, for example
+// if a provider changed its credentials format. The name "all"
+// relaxes all the rules.
+func (m *Matcher) Relax(names []string) (err error) {
+	err = CheckRules(names)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if name == RelaxAll {
+			for rule := range m.rules {
+				m.relaxed[rule] = struct{}{}
+			}
+			continue
+		}
+		m.relaxed[Rule(name)] = struct{}{}
+	}
+	return nil
+}
+
+// Match returns true if s matches the format of the rule,
+// or if the rule is relaxed.
+func (m *Matcher) Match(rule Rule, s string) bool {
+	if _, relaxed := m.relaxed[rule]; relaxed {
+		return true
 	}
+	return m.rules[rule].MatchString(s)
 }
 
-func (m *Matcher) GandiKey(s string) bool          { return m.gandiKey.MatchString(s) }
-func (m *Matcher) GodaddyKey(s string) bool        { return m.goDaddyKey.MatchString(s) }
-func (m *Matcher) DuckDNSToken(s string) bool      { return m.duckDNSToken.MatchString(s) }
-func (m *Matcher) NamecheapPassword(s string) bool { return m.namecheapPassword.MatchString(s) }
-func (m *Matcher) DreamhostKey(s string) bool      { return m.dreamhostKey.MatchString(s) }
-func (m *Matcher) CloudflareKey(s string) bool     { return m.cloudflareKey.MatchString(s) }
+func (m *Matcher) GandiKey(s string) bool          { return m.Match(GandiKey, s) }
+func (m *Matcher) GodaddyKey(s string) bool        { return m.Match(GoDaddyKey, s) }
+func (m *Matcher) DuckDNSToken(s string) bool      { return m.Match(DuckDNSToken, s) }
+func (m *Matcher) NamecheapPassword(s string) bool { return m.Match(NamecheapPassword, s) }
+func (m *Matcher) DreamhostKey(s string) bool      { return m.Match(DreamhostKey, s) }
+func (m *Matcher) CloudflareKey(s string) bool     { return m.Match(CloudflareKey, s) }
 func (m *Matcher) CloudflareUserServiceKey(s string) bool {
-	return m.cloudflareUserServiceKey.MatchString(s)
+	return m.Match(CloudflareUserServiceKey, s)
 }
-func (m *Matcher) DNSOMaticUsername(s string) bool { return m.dnsOMaticUsername.MatchString(s) }
-func (m *Matcher) DNSOMaticPassword(s string) bool { return m.dnsOMaticPassword.MatchString(s) }
+func (m *Matcher) DNSOMaticUsername(s string) bool { return m.Match(DNSOMaticUsername, s) }
+func (m *Matcher) DNSOMaticPassword(s string) bool { return m.Match(DNSOMaticPassword, s) }
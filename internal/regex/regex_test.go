@@ -0,0 +1,65 @@
+package regex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Matcher_Relax(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		relaxed    []string
+		errWrapped error
+		errMessage string
+		matches    map[Rule]bool
+	}{
+		"no relaxed rule": {
+			matches: map[Rule]bool{
+				GandiKey:     false,
+				DreamhostKey: false,
+			},
+		},
+		"one relaxed rule": {
+			relaxed: []string{"gandi_key"},
+			matches: map[Rule]bool{
+				GandiKey:     true,
+				DreamhostKey: false,
+			},
+		},
+		"all rules relaxed": {
+			relaxed: []string{"all"},
+			matches: map[Rule]bool{
+				GandiKey:     true,
+				DreamhostKey: true,
+			},
+		},
+		"unknown rule": {
+			relaxed:    []string{"unknown"},
+			errWrapped: ErrRuleUnknown,
+			errMessage: "credential format rule is unknown: unknown",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			matcher := NewMatcher()
+			err := matcher.Relax(testCase.relaxed)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				require.EqualError(t, err, testCase.errMessage)
+				return
+			}
+
+			for rule, expected := range testCase.matches {
+				assert.Equal(t, expected, matcher.Match(rule, "bad-format!"), rule)
+			}
+		})
+	}
+}
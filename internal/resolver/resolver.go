@@ -0,0 +1,82 @@
+// Package resolver creates DNS resolvers using specific DNS servers,
+// for example the authoritative servers of a provider.
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+var (
+	ErrNoAddress        = errors.New("no DNS server address given")
+	ErrAddressMalformed = errors.New("DNS server address is malformed")
+	ErrNoNameserver     = errors.New("no authoritative nameserver found")
+)
+
+// New returns a resolver sending its queries to the DNS servers
+// addresses given, rotating through them on each query attempt such
+// that an unresponsive server is skipped when the query is retried.
+// An address is a host with an optional port defaulting to 53.
+func New(addresses []string) (resolver *net.Resolver, err error) {
+	if len(addresses) == 0 {
+		return nil, ErrNoAddress
+	}
+
+	hostPorts := make([]string, len(addresses))
+	for i, address := range addresses {
+		hostPorts[i], err = parseAddress(address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dialer := &net.Dialer{}
+	var counter uint32
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			index := (atomic.AddUint32(&counter, 1) - 1) % uint32(len(hostPorts))
+			return dialer.DialContext(ctx, network, hostPorts[index])
+		},
+	}, nil
+}
+
+// NewAuthoritative returns a resolver sending its queries to the
+// authoritative nameservers of the zone, which are looked up with
+// the parent resolver.
+func NewAuthoritative(ctx context.Context, parent *net.Resolver,
+	zone string) (resolver *net.Resolver, err error) {
+	nameservers, err := parent.LookupNS(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("looking up nameservers of %s: %w", zone, err)
+	} else if len(nameservers) == 0 {
+		return nil, fmt.Errorf("%w: for zone %s", ErrNoNameserver, zone)
+	}
+
+	addresses := make([]string, len(nameservers))
+	for i, nameserver := range nameservers {
+		addresses[i] = strings.TrimSuffix(nameserver.Host, ".")
+	}
+	return New(addresses)
+}
+
+func parseAddress(address string) (hostPort string, err error) {
+	address = strings.TrimSpace(address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		// no port, or IPv6 address without brackets nor port
+		host, port = strings.Trim(address, "[]"), "53"
+	}
+
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", fmt.Errorf("%w: %q", ErrAddressMalformed, address)
+	} else if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+		return "", fmt.Errorf("%w: port in %q", ErrAddressMalformed, address)
+	}
+	return net.JoinHostPort(host, port), nil
+}
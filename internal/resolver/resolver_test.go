@@ -0,0 +1,61 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseAddress(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		address  string
+		hostPort string
+		err      error
+	}{
+		"IPv4 without port": {
+			address:  "1.1.1.1",
+			hostPort: "1.1.1.1:53",
+		},
+		"IPv4 with port": {
+			address:  "1.1.1.1:5353",
+			hostPort: "1.1.1.1:5353",
+		},
+		"IPv6 without port": {
+			address:  "2606:4700:4700::1111",
+			hostPort: "[2606:4700:4700::1111]:53",
+		},
+		"IPv6 with brackets": {
+			address:  "[2606:4700:4700::1111]",
+			hostPort: "[2606:4700:4700::1111]:53",
+		},
+		"IPv6 with port": {
+			address:  "[2606:4700:4700::1111]:5353",
+			hostPort: "[2606:4700:4700::1111]:5353",
+		},
+		"hostname": {
+			address:  "ns1.example.com",
+			hostPort: "ns1.example.com:53",
+		},
+		"empty": {
+			err: ErrAddressMalformed,
+		},
+		"URL": {
+			address: "https://1.1.1.1/dns-query",
+			err:     ErrAddressMalformed,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hostPort, err := parseAddress(testCase.address)
+
+			assert.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.hostPort, hostPort)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+// Package rotate rotates the credentials of a provider account
+// shared by records of the configuration file.
+package rotate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/settings"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+func IsRotateMode(args []string) bool {
+	return len(args) > 1 && args[1] == "rotate"
+}
+
+const skipVerifyFlag = "--skip-verify"
+
+var (
+	ErrUsage = errors.New(`usage: rotate <account> '{"token": "..."}' [` +
+		skipVerifyFlag + `]`)
+	ErrDecodeConfig         = errors.New("cannot decode configuration")
+	ErrDecodeCredentials    = errors.New("cannot decode new credentials")
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrProviderChange       = errors.New("provider of an account cannot be rotated")
+	ErrCredentialOverridden = errors.New("record overrides the account credential")
+	ErrNoRecord             = errors.New("no record uses the account")
+	ErrVerifyNotSupported   = errors.New("verifying credentials is not supported by the provider")
+	ErrVerify               = errors.New("new credentials verification failed")
+	ErrWriteConfig          = errors.New("cannot write configuration")
+)
+
+// Run rotates the credentials of the account named args[2] in the
+// configuration file at configPath, using the new credential fields given
+// as JSON in args[3]. The new credentials are first verified with a read
+// only call to the provider for each domain of the account records, unless
+// args[4] is --skip-verify. All the records of the account are then switched
+// to the new credentials at once, by atomically replacing the configuration
+// file, and the previous credentials are removed from it.
+func Run(ctx context.Context, args []string, configPath string,
+	client *http.Client, writer io.Writer) (err error) {
+	const minArgs, maxArgs = 4, 5
+	if len(args) < minArgs || len(args) > maxArgs ||
+		(len(args) == maxArgs && args[4] != skipVerifyFlag) {
+		return ErrUsage
+	}
+	accountName := args[2]
+	credentials := json.RawMessage(args[3])
+	skipVerify := len(args) == maxArgs
+
+	configBytes, err := os.ReadFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	newConfig, staged, err := stage(configBytes, accountName, credentials)
+	if err != nil {
+		return err
+	}
+
+	if skipVerify {
+		fmt.Fprintln(writer, "skipping verification of the new credentials")
+	} else {
+		domains, err := verify(ctx, client, staged)
+		if err != nil {
+			return err
+		}
+		for _, domain := range domains {
+			fmt.Fprintf(writer, "new credentials verified for domain %s\n", domain)
+		}
+	}
+
+	err = writeFileAtomic(configPath, newConfig)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWriteConfig, err)
+	}
+	fmt.Fprintf(writer, "%d record(s) of account %q switched to the new credentials, "+
+		"the previous credentials were removed from %s and can be revoked "+
+		"once the program is restarted\n", len(staged), accountName, configPath)
+	return nil
+}
+
+// stage returns the new configuration with the credentials of the account
+// replaced, and the raw settings of each record of the account using the
+// new credentials, for these to be verified.
+func stage(configBytes []byte, accountName string, credentials json.RawMessage) (
+	newConfig []byte, staged []json.RawMessage, err error) {
+	var newCredentials map[string]json.RawMessage
+	if err := json.Unmarshal(credentials, &newCredentials); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrDecodeCredentials, err)
+	}
+	if _, ok := newCredentials["provider"]; ok {
+		return nil, nil, ErrProviderChange
+	}
+
+	var config map[string]json.RawMessage
+	if err := json.Unmarshal(configBytes, &config); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrDecodeConfig, err)
+	}
+	var accounts map[string]map[string]json.RawMessage
+	if rawAccounts, ok := config["accounts"]; ok {
+		if err := json.Unmarshal(rawAccounts, &accounts); err != nil {
+			return nil, nil, fmt.Errorf("%w: accounts: %s", ErrDecodeConfig, err)
+		}
+	}
+	account, ok := accounts[accountName]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %q", ErrAccountNotFound, accountName)
+	}
+	for key, value := range newCredentials {
+		account[key] = value
+	}
+
+	var records []map[string]json.RawMessage
+	if rawRecords, ok := config["settings"]; ok {
+		if err := json.Unmarshal(rawRecords, &records); err != nil {
+			return nil, nil, fmt.Errorf("%w: settings: %s", ErrDecodeConfig, err)
+		}
+	}
+	for _, record := range records {
+		var name string
+		_ = json.Unmarshal(record["account"], &name)
+		if name != accountName {
+			continue
+		}
+		for key := range newCredentials {
+			if _, ok := record[key]; ok {
+				return nil, nil, fmt.Errorf("%w: %s for record %s",
+					ErrCredentialOverridden, key, record["host"])
+			}
+		}
+		merged := make(map[string]json.RawMessage, len(account)+len(record))
+		for key, value := range account {
+			merged[key] = value
+		}
+		for key, value := range record {
+			merged[key] = value
+		}
+		rawMerged, err := json.Marshal(merged)
+		if err != nil {
+			return nil, nil, err
+		}
+		staged = append(staged, rawMerged)
+	}
+	if len(staged) == 0 {
+		return nil, nil, fmt.Errorf("%w: %q", ErrNoRecord, accountName)
+	}
+
+	config["accounts"], err = json.Marshal(accounts)
+	if err != nil {
+		return nil, nil, err
+	}
+	newConfig, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	return newConfig, staged, nil
+}
+
+// verify lists the records of the zone of each distinct domain of
+// the staged record settings, which is a read only call to check
+// the new credentials are accepted by the provider.
+func verify(ctx context.Context, client *http.Client, staged []json.RawMessage) (
+	domains []string, err error) {
+	matcher := regex.NewMatcher()
+	domainToSettings := make(map[string]json.RawMessage, len(staged))
+	for _, rawSettings := range staged {
+		var common struct {
+			Domain string `json:"domain"`
+		}
+		_ = json.Unmarshal(rawSettings, &common)
+		domainToSettings[common.Domain] = rawSettings
+	}
+	for domain := range domainToSettings {
+		domains = append(domains, domain)
+	}
+	sort.Strings(domains)
+
+	for _, domain := range domains {
+		rawSettings := domainToSettings[domain]
+		var common struct {
+			Provider string `json:"provider"`
+		}
+		if err := json.Unmarshal(rawSettings, &common); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDecodeConfig, err)
+		}
+
+		// The host is not needed to list the zone records, and may be
+		// an enumeration or a comma separated list of hosts.
+		provider, err := settings.New(models.Provider(common.Provider), rawSettings,
+			domain, "@", ipversion.IP4or6, matcher)
+		if err != nil {
+			return nil, fmt.Errorf("%w: for domain %s: %s", ErrVerify, domain, err)
+		}
+		lister, ok := provider.(settings.ZoneLister)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s, use %s to rotate without verification",
+				ErrVerifyNotSupported, common.Provider, skipVerifyFlag)
+		}
+		_, err = lister.ListRecords(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("%w: for domain %s: %s", ErrVerify, domain, err)
+		}
+	}
+	return domains, nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the directory
+// of the file path and renames it to the file path, such that the
+// configuration is never left partially written.
+func writeFileAtomic(path string, data []byte) (err error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+
+	const mode = fs.FileMode(0600)
+	err = file.Chmod(mode)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	} else if closeErr != nil {
+		return closeErr
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,134 @@
+package rotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_stage(t *testing.T) {
+	t.Parallel()
+
+	const config = `{
+  "accounts": {
+    "home": {"provider": "cloudflare", "zone_identifier": "zone", "token": "old"},
+    "work": {"provider": "cloudflare", "zone_identifier": "zone", "token": "work"}
+  },
+  "settings": [
+    {"account": "home", "domain": "home.com", "host": "@"},
+    {"account": "work", "domain": "work.com", "host": "@"},
+    {"account": "home", "domain": "home.org", "host": "www", "ttl": 600}
+  ]
+}`
+
+	testCases := map[string]struct {
+		config      string
+		account     string
+		credentials string
+		newConfig   string
+		staged      []string
+		errWrapped  error
+		errMessage  string
+	}{
+		"credentials rotated": {
+			config:      config,
+			account:     "home",
+			credentials: `{"token": "new"}`,
+			newConfig: `{
+  "accounts": {
+    "home": {
+      "provider": "cloudflare",
+      "token": "new",
+      "zone_identifier": "zone"
+    },
+    "work": {
+      "provider": "cloudflare",
+      "token": "work",
+      "zone_identifier": "zone"
+    }
+  },
+  "settings": [
+    {"account": "home", "domain": "home.com", "host": "@"},
+    {"account": "work", "domain": "work.com", "host": "@"},
+    {"account": "home", "domain": "home.org", "host": "www", "ttl": 600}
+  ]
+}`,
+			staged: []string{
+				`{"account":"home","domain":"home.com","host":"@","provider":"cloudflare","token":"new","zone_identifier":"zone"}`,
+				`{"account":"home","domain":"home.org","host":"www","provider":"cloudflare","token":"new","ttl":600,"zone_identifier":"zone"}`,
+			},
+		},
+		"account not found": {
+			config:      config,
+			account:     "other",
+			credentials: `{"token": "new"}`,
+			errWrapped:  ErrAccountNotFound,
+			errMessage:  `account not found: "other"`,
+		},
+		"provider change": {
+			config:      config,
+			account:     "home",
+			credentials: `{"provider": "godaddy"}`,
+			errWrapped:  ErrProviderChange,
+			errMessage:  "provider of an account cannot be rotated",
+		},
+		"credential overridden": {
+			config:      config,
+			account:     "home",
+			credentials: `{"ttl": 1}`,
+			errWrapped:  ErrCredentialOverridden,
+			errMessage:  `record overrides the account credential: ttl for record "www"`,
+		},
+		"no record": {
+			config:      `{"accounts": {"home": {"provider": "cloudflare"}}}`,
+			account:     "home",
+			credentials: `{"token": "new"}`,
+			errWrapped:  ErrNoRecord,
+			errMessage:  `no record uses the account: "home"`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			newConfig, staged, err := stage([]byte(testCase.config),
+				testCase.account, []byte(testCase.credentials))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.JSONEq(t, testCase.newConfig, string(newConfig))
+			stagedStrings := make([]string, len(staged))
+			for i := range staged {
+				stagedStrings[i] = string(staged[i])
+			}
+			assert.Equal(t, testCase.staged, stagedStrings)
+		})
+	}
+}
+
+func Test_writeFileAtomic(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte("old"), 0600)
+	require.NoError(t, err)
+
+	err = writeFileAtomic(path, []byte("new"))
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipaddr"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// DynDNS2Settings contains the settings for the inbound
+// DynDNS2 update endpoint, used by routers to push their IP address.
+type DynDNS2Settings struct {
+	Enabled bool
+	// Users maps a username to its password and the
+	// record hostnames it is allowed to update.
+	Users map[string]DynDNS2User
+}
+
+// DynDNS2User is a user of the inbound DynDNS2 update endpoint.
+type DynDNS2User struct {
+	Password string
+	// Hostnames is the list of hostnames the user can update.
+	// The hostname "*" allows the user to update all records.
+	Hostnames []string
+}
+
+func (u DynDNS2User) canUpdate(hostname string) bool {
+	for _, allowed := range u.Hostnames {
+		if allowed == "*" || strings.EqualFold(allowed, hostname) {
+			return true
+		}
+	}
+	return false
+}
+
+// DynDNS2 response codes, see
+// https://help.dyn.com/remote-access-api/return-codes/.
+const (
+	dyndns2Good    = "good"
+	dyndns2NoChg   = "nochg"
+	dyndns2BadAuth = "badauth"
+	dyndns2NotFQDN = "notfqdn"
+	dyndns2NoHost  = "nohost"
+	dyndns2DNSErr  = "dnserr"
+	dyndns2Server  = "911"
+)
+
+// dyndns2Update handles DynDNS2 update requests such as
+// GET /nic/update?hostname=home.example.com&myip=1.2.3.4
+// with basic authentication.
+func (h *handlers) dyndns2Update(w http.ResponseWriter, r *http.Request) {
+	username, password, ok := r.BasicAuth()
+	user, userExists := h.dyndns2.Users[username]
+	passwordMatches := subtle.ConstantTimeCompare([]byte(password), []byte(user.Password)) == 1
+	if !ok || !userExists || !passwordMatches {
+		w.Header().Set("WWW-Authenticate", `Basic realm="DDNS Updater"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(dyndns2BadAuth))
+		return
+	}
+
+	hostnamesParam := r.URL.Query().Get("hostname")
+	if hostnamesParam == "" {
+		_, _ = w.Write([]byte(dyndns2NotFQDN))
+		return
+	}
+
+	ips := parseDynDNS2IPs(r.URL.Query().Get("myip"))
+	if len(ips) == 0 {
+		ip := h.clientIP.ParseHTTPRequest(r)
+		if ip == nil {
+			_, _ = w.Write([]byte(dyndns2Server))
+			return
+		}
+		ips = []net.IP{ip}
+	}
+
+	hostnames := strings.Split(hostnamesParam, ",")
+	lines := make([]string, len(hostnames))
+	for i, hostname := range hostnames {
+		if !user.canUpdate(hostname) {
+			lines[i] = dyndns2NoHost
+			continue
+		}
+		lines[i] = h.dyndns2UpdateHostname(r, hostname, ips)
+	}
+
+	_, _ = w.Write([]byte(strings.Join(lines, "\n")))
+}
+
+func (h *handlers) dyndns2UpdateHostname(r *http.Request, hostname string,
+	ips []net.IP) (result string) {
+	found := false
+	var changedIPs, unchangedIPs []string
+	for i, record := range h.db.SelectAll() {
+		if !strings.EqualFold(record.Settings.BuildDomainName(), hostname) {
+			continue
+		}
+		found = true
+
+		ip := ipForRecord(record, ips)
+		if ip == nil {
+			continue
+		}
+
+		if ip.Equal(record.History.GetCurrentIP()) {
+			unchangedIPs = append(unchangedIPs, ip.String())
+			continue
+		}
+
+		h.logger.Info("DynDNS2 update request for " + hostname + " to use " + ip.String())
+		err := h.updater.Update(r.Context(), uint(i), ip, h.timeNow())
+		if err != nil {
+			h.logger.Error(err.Error())
+			return dyndns2DNSErr
+		}
+		changedIPs = append(changedIPs, ip.String())
+	}
+
+	switch {
+	case !found:
+		return dyndns2NoHost
+	case len(changedIPs) > 0:
+		return dyndns2Good + " " + strings.Join(changedIPs, ",")
+	case len(unchangedIPs) > 0:
+		return dyndns2NoChg + " " + strings.Join(unchangedIPs, ",")
+	default: // no IP address matching the IP version of the records
+		return dyndns2NoChg
+	}
+}
+
+func parseDynDNS2IPs(myIP string) (ips []net.IP) {
+	if myIP == "" {
+		return nil
+	}
+	fields := strings.Split(myIP, ",")
+	ips = make([]net.IP, 0, len(fields))
+	for _, field := range fields {
+		ip := ipaddr.Parse(strings.TrimSpace(field))
+		if ip == nil {
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+func ipForRecord(record records.Record, ips []net.IP) (ip net.IP) {
+	for _, ip := range ips {
+		isIPv4 := ip.To4() != nil
+		switch record.Settings.IPVersion() {
+		case ipversion.IP4or6:
+			return ip
+		case ipversion.IP4:
+			if isIPv4 {
+				return ip
+			}
+		case ipversion.IP6:
+			if !isIPv4 {
+				return ip
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/qdm12/golibs/clientip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testUpdater appends the IP address updated to the record history.
+type testUpdater struct {
+	db  *testDatabase
+	err error
+}
+
+func (u *testUpdater) Update(_ context.Context, id uint, ip net.IP, now time.Time) error {
+	if u.err != nil {
+		return u.err
+	}
+	return u.db.Modify(id, func(record *records.Record) (changed bool) {
+		record.History = append(record.History, models.HistoryEvent{IP: ip, Time: now})
+		return true
+	})
+}
+
+func Test_handlers_dyndns2Update(t *testing.T) {
+	t.Parallel()
+
+	settings := DynDNS2Settings{
+		Enabled: true,
+		Users: map[string]DynDNS2User{
+			"user":    {Password: "pass", Hostnames: []string{"*"}},
+			"limited": {Password: "pass", Hostnames: []string{"www.example.com"}},
+		},
+	}
+
+	testCases := map[string]struct {
+		url        string
+		noAuth     bool
+		username   string
+		password   string
+		updateErr  error
+		status     int
+		body       string
+		currentIPs []string
+	}{
+		"no authentication": {
+			url:        "/nic/update?hostname=example.com&myip=5.6.7.8",
+			noAuth:     true,
+			status:     http.StatusUnauthorized,
+			body:       "badauth",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"unknown user": {
+			url:        "/nic/update?hostname=example.com&myip=5.6.7.8",
+			username:   "unknown",
+			password:   "pass",
+			status:     http.StatusUnauthorized,
+			body:       "badauth",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"wrong password": {
+			url:        "/nic/update?hostname=example.com&myip=5.6.7.8",
+			username:   "user",
+			password:   "wrong",
+			status:     http.StatusUnauthorized,
+			body:       "badauth",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"missing hostname": {
+			url:        "/nic/update?myip=5.6.7.8",
+			username:   "user",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "notfqdn",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"unknown hostname": {
+			url:        "/nic/update?hostname=unknown.com&myip=5.6.7.8",
+			username:   "user",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "nohost",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"hostname not allowed": {
+			url:        "/nic/update?hostname=example.com&myip=5.6.7.8",
+			username:   "limited",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "nohost",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"good": {
+			url:        "/nic/update?hostname=www.example.com&myip=5.6.7.8",
+			username:   "limited",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "good 5.6.7.8",
+			currentIPs: []string{"1.2.3.4", "::1", "5.6.7.8"},
+		},
+		"nochg": {
+			url:        "/nic/update?hostname=www.example.com&myip=1.2.3.4",
+			username:   "user",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "nochg 1.2.3.4",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"client IP address": {
+			url:        "/nic/update?hostname=www.example.com",
+			username:   "user",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "good 192.0.2.1",
+			currentIPs: []string{"1.2.3.4", "::1", "192.0.2.1"},
+		},
+		"hostname and myip lists": {
+			url:        "/nic/update?hostname=example.com,www.example.com,unknown.com&myip=5.6.7.8,::1",
+			username:   "user",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "good 5.6.7.8\ngood 5.6.7.8\nnohost",
+			currentIPs: []string{"5.6.7.8", "::1", "5.6.7.8"},
+		},
+		"myip list with unchanged addresses": {
+			url:        "/nic/update?hostname=example.com&myip=1.2.3.4,::1",
+			username:   "user",
+			password:   "pass",
+			status:     http.StatusOK,
+			body:       "nochg 1.2.3.4,::1",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+		"update error": {
+			url:        "/nic/update?hostname=www.example.com&myip=5.6.7.8",
+			username:   "user",
+			password:   "pass",
+			updateErr:  errors.New("test error"),
+			status:     http.StatusOK,
+			body:       "dnserr",
+			currentIPs: []string{"1.2.3.4", "::1", "1.2.3.4"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ipv6Settings, err := ns1.New([]byte(`{"api_key": "key"}`),
+				"example.com", "@", ipversion.IP6)
+			require.NoError(t, err)
+			ipv6Record := records.New(ipv6Settings, records.Options{}, nil)
+			ipv6Record.History = models.History{{IP: net.ParseIP("::1")}}
+			db := &testDatabase{records: []records.Record{
+				newTestRecord(t, "example.com", "@", net.IPv4(1, 2, 3, 4), constants.UPTODATE),
+				ipv6Record,
+				newTestRecord(t, "example.com", "www", net.IPv4(1, 2, 3, 4), constants.UPTODATE),
+			}}
+			handlers := &handlers{
+				db:       db,
+				updater:  &testUpdater{db: db, err: testCase.updateErr},
+				dyndns2:  settings,
+				clientIP: clientip.NewParser(),
+				logger:   noopLogger{},
+				timeNow:  time.Now,
+			}
+			request := httptest.NewRequest(http.MethodGet, testCase.url, nil)
+			if !testCase.noAuth {
+				request.SetBasicAuth(testCase.username, testCase.password)
+			}
+			recorder := httptest.NewRecorder()
+
+			handlers.dyndns2Update(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.body, recorder.Body.String())
+			currentIPs := make([]string, len(db.records))
+			for i, record := range db.records {
+				currentIPs[i] = record.History.GetCurrentIP().String()
+			}
+			assert.Equal(t, testCase.currentIPs, currentIPs)
+		})
+	}
+}
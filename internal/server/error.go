@@ -3,6 +3,8 @@ package server
 import (
 	"encoding/json"
 	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/update"
 )
 
 type errJSONWrapper struct {
@@ -23,17 +25,21 @@ func httpError(w http.ResponseWriter, status int, errString string) {
 
 type errorsJSONWrapper struct {
 	Errors []string `json:"errors"`
+	// Codes are the machine readable codes of the errors, in the same order.
+	Codes []string `json:"codes"`
 }
 
 func httpErrors(w http.ResponseWriter, status int, errors []error) {
 	w.WriteHeader(status)
 
 	errs := make([]string, len(errors))
+	codes := make([]string, len(errors))
 	for i := range errors {
 		errs[i] = errors[i].Error()
+		codes[i] = string(update.ErrorCode(errors[i]))
 	}
 
-	body := errorsJSONWrapper{Errors: errs}
+	body := errorsJSONWrapper{Errors: errs, Codes: codes}
 	err := json.NewEncoder(w).Encode(body)
 	if err != nil {
 		panic(err)
@@ -9,6 +9,8 @@ import (
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/qdm12/golibs/clientip"
+	"github.com/qdm12/golibs/logging"
 )
 
 type handlers struct {
@@ -16,6 +18,16 @@ type handlers struct {
 	// Objects
 	db            Database
 	runner        UpdateForcer
+	summaries     CycleSummarizer
+	updater       RecordUpdater
+	usage         UsageReporter
+	snapshotter   Snapshotter
+	dyndns2       DynDNS2Settings
+	webhookToken  string
+	snapshotToken string
+	adminToken    string
+	clientIP      *clientip.Parser
+	logger        logging.Logger
 	indexTemplate *template.Template
 	// Mockable functions
 	timeNow func() time.Time
@@ -25,7 +37,9 @@ type handlers struct {
 var uiFS embed.FS
 
 func newHandler(ctx context.Context, rootURL string,
-	db Database, runner UpdateForcer) http.Handler {
+	db Database, runner UpdateForcer, summaries CycleSummarizer, updater RecordUpdater, usage UsageReporter,
+	metrics http.Handler, snapshotter Snapshotter, dyndns2 DynDNS2Settings,
+	webhookToken, snapshotToken, adminToken string, logger logging.Logger) http.Handler {
 	indexTemplate := template.Must(template.ParseFS(uiFS, "ui/index.html"))
 
 	handlers := &handlers{
@@ -33,17 +47,67 @@ func newHandler(ctx context.Context, rootURL string,
 		db:            db,
 		indexTemplate: indexTemplate,
 		// TODO build information
-		timeNow: time.Now,
-		runner:  runner,
+		timeNow:       time.Now,
+		runner:        runner,
+		summaries:     summaries,
+		updater:       updater,
+		usage:         usage,
+		snapshotter:   snapshotter,
+		snapshotToken: snapshotToken,
+		adminToken:    adminToken,
+		dyndns2:       dyndns2,
+		webhookToken:  webhookToken,
+		clientIP:      clientip.NewParser(),
+		logger:        logger,
 	}
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.Logger)
 
-	router.Get(rootURL+"/", handlers.index)
+	router.Group(func(router chi.Router) {
+		// Routes serving or updating all the records
+		router.Use(handlers.requireAdmin)
 
-	router.Get(rootURL+"/update", handlers.update)
+		router.Get(rootURL+"/", handlers.index)
+
+		router.Get(rootURL+"/update", handlers.update)
+
+		router.Get(rootURL+"/usage", handlers.apiUsage)
+
+		router.Get(rootURL+"/status", handlers.status)
+
+		router.Get(rootURL+"/summary", handlers.summary)
+
+		router.Method(http.MethodGet, rootURL+"/metrics", metrics)
+	})
+
+	router.Get(rootURL+"/tenants/{tenant}/status", handlers.tenantStatus)
+
+	if dyndns2.Enabled {
+		router.Get(rootURL+"/nic/update", handlers.dyndns2Update)
+	}
+
+	if webhookToken != "" {
+		router.Post(rootURL+"/webhook/confirm", handlers.webhookConfirm)
+	}
+
+	if snapshotToken != "" {
+		router.Get(rootURL+"/snapshot", handlers.snapshot)
+		router.Post(rootURL+"/snapshot/restore", handlers.restore)
+	}
 
 	return router
 }
+
+// requireAdmin responds with an unauthorized error to requests
+// without the admin token, if the admin token is set.
+func (h *handlers) requireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.adminToken != "" && !isAuthorized(r, h.adminToken) {
+			httpError(w, http.StatusUnauthorized, "")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
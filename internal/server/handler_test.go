@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_handlers_requireAdmin(t *testing.T) {
+	t.Parallel()
+
+	const adminToken = "admin-token-0123456789"
+	tenantRecord := newTestRecord(t, "acme.com", "@", nil, constants.SUCCESS)
+	tenantRecord.Options.Tenant = &records.Tenant{Name: "acme", Token: "acme-token-0123456789"}
+	db := &testDatabase{records: []records.Record{tenantRecord}}
+
+	testCases := map[string]struct {
+		adminToken string
+		path       string
+		header     string
+		status     int
+	}{
+		"admin token unset": {
+			path:   "/status",
+			status: http.StatusOK,
+		},
+		"no token": {
+			adminToken: adminToken,
+			path:       "/status",
+			status:     http.StatusUnauthorized,
+		},
+		"tenant token": {
+			adminToken: adminToken,
+			path:       "/status",
+			header:     "Bearer acme-token-0123456789",
+			status:     http.StatusUnauthorized,
+		},
+		"admin token header": {
+			adminToken: adminToken,
+			path:       "/status",
+			header:     "Bearer " + adminToken,
+			status:     http.StatusOK,
+		},
+		"admin token query": {
+			adminToken: adminToken,
+			path:       "/status?token=" + adminToken,
+			status:     http.StatusOK,
+		},
+		"tenant status with tenant token": {
+			adminToken: adminToken,
+			path:       "/tenants/acme/status",
+			header:     "Bearer acme-token-0123456789",
+			status:     http.StatusOK,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := newHandler(context.Background(), "", db, nil, nil, nil, nil,
+				http.NotFoundHandler(), nil, DynDNS2Settings{}, "", "",
+				testCase.adminToken, noopLogger{})
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			if testCase.header != "" {
+				request.Header.Set("Authorization", testCase.header)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+		})
+	}
+}
+
+func Test_newStatusMirrorHandler_adminToken(t *testing.T) {
+	t.Parallel()
+
+	db := &testDatabase{}
+	handler := newStatusMirrorHandler(context.Background(), "", db, nil,
+		"admin-token-0123456789", noopLogger{})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet,
+		"/status?token=admin-token-0123456789", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
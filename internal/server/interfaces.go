@@ -2,14 +2,39 @@ package server
 
 import (
 	"context"
+	"io"
+	"net"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/update"
 )
 
 type Database interface {
 	SelectAll() (records []records.Record)
+	Update(recordID uint, record records.Record) (err error)
+	Modify(recordID uint, modify func(record *records.Record) (changed bool)) (err error)
 }
 
 type UpdateForcer interface {
 	ForceUpdate(ctx context.Context) (errors []error)
+	ForceUpdateConfirmed(ctx context.Context) (errors []error)
+}
+
+type CycleSummarizer interface {
+	CycleSummaries() (summaries []update.CycleSummary)
+}
+
+type UsageReporter interface {
+	Today() (counts map[string]uint)
+	Budget() uint
+}
+
+type Snapshotter interface {
+	Snapshot(writer io.Writer) (err error)
+	Restore(reader io.Reader) (err error)
+}
+
+type RecordUpdater interface {
+	Update(ctx context.Context, recordID uint, ip net.IP, now time.Time) (err error)
 }
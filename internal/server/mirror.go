@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/qdm12/golibs/logging"
+)
+
+// NewStatusMirror creates a server serving only the status page, the
+// status JSON and the cycle summaries, authenticated with the admin token
+// if it is set. It has no route changing state, so it can listen on a
+// network where the main server should not be reachable.
+func NewStatusMirror(ctx context.Context, address, rootURL string, db Database,
+	summaries CycleSummarizer, adminToken string, logger logging.Logger) *Server {
+	return &Server{
+		address: address,
+		logger:  logger,
+		handler: newStatusMirrorHandler(ctx, rootURL, db, summaries, adminToken, logger),
+	}
+}
+
+func newStatusMirrorHandler(ctx context.Context, rootURL string, db Database,
+	summaries CycleSummarizer, adminToken string, logger logging.Logger) http.Handler {
+	handlers := &handlers{
+		ctx:           ctx,
+		db:            db,
+		summaries:     summaries,
+		adminToken:    adminToken,
+		logger:        logger,
+		indexTemplate: template.Must(template.ParseFS(uiFS, "ui/index.html")),
+		timeNow:       time.Now,
+	}
+
+	router := chi.NewRouter()
+
+	router.Use(middleware.Logger)
+
+	router.Use(handlers.requireAdmin)
+
+	router.Get(rootURL+"/", handlers.index)
+
+	router.Get(rootURL+"/status", handlers.status)
+
+	router.Get(rootURL+"/summary", handlers.summary)
+
+	return router
+}
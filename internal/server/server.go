@@ -15,8 +15,11 @@ type Server struct {
 }
 
 func New(ctx context.Context, address, rootURL string, db Database,
-	logger logging.Logger, runner UpdateForcer) *Server {
-	handler := newHandler(ctx, rootURL, db, runner)
+	logger logging.Logger, runner UpdateForcer, summaries CycleSummarizer,
+	updater RecordUpdater, usage UsageReporter, metrics http.Handler, snapshotter Snapshotter,
+	dyndns2 DynDNS2Settings, webhookToken, snapshotToken, adminToken string) *Server {
+	handler := newHandler(ctx, rootURL, db, runner, summaries, updater, usage,
+		metrics, snapshotter, dyndns2, webhookToken, snapshotToken, adminToken, logger)
 	return &Server{
 		address: address,
 		logger:  logger,
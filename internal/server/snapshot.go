@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// restoreReadTimeout is the read deadline given to the restore route,
+// since the server wide read timeout is too short for snapshot bodies
+// of up to 64MiB.
+const restoreReadTimeout = 10 * time.Minute
+
+// snapshot responds with a gzipped tarball of the configuration,
+// the data store and the runtime state.
+func (h *handlers) snapshot(w http.ResponseWriter, r *http.Request) {
+	if !isAuthorized(r, h.snapshotToken) {
+		httpError(w, http.StatusUnauthorized, "")
+		return
+	}
+
+	filename := "ddns-updater-snapshot-" + strconv.Itoa(int(h.timeNow().Unix())) + ".tar.gz"
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	err := h.snapshotter.Snapshot(w)
+	if err != nil {
+		// headers and part of the body may already be sent
+		h.logger.Error("creating snapshot: " + err.Error())
+	}
+}
+
+// restore restores a snapshot sent as the request body.
+// The program must be restarted afterwards to use it.
+func (h *handlers) restore(w http.ResponseWriter, r *http.Request) {
+	if !isAuthorized(r, h.snapshotToken) {
+		httpError(w, http.StatusUnauthorized, "")
+		return
+	}
+
+	err := setReadDeadline(w, h.timeNow().Add(restoreReadTimeout))
+	if err != nil {
+		h.logger.Warn("extending the read deadline for the snapshot restore: " + err.Error())
+	}
+
+	err = h.snapshotter.Restore(r.Body)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	h.logger.Warn("snapshot restored, restart the program to use it")
+	_, _ = w.Write([]byte("Snapshot restored, restart the program to use it"))
+}
+
+var errReadDeadlineUnsupported = errors.New("response writer does not support setting the read deadline")
+
+// setReadDeadline sets the read deadline of the connection of the
+// response writer, unwrapping middleware response writers as needed.
+func setReadDeadline(w http.ResponseWriter, deadline time.Time) error {
+	for {
+		switch typed := w.(type) {
+		case interface {
+			SetReadDeadline(deadline time.Time) error
+		}:
+			return typed.SetReadDeadline(deadline)
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = typed.Unwrap()
+		default:
+			return fmt.Errorf("%w: %T", errReadDeadlineUnsupported, w)
+		}
+	}
+}
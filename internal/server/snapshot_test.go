@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deadlineWriter struct {
+	http.ResponseWriter
+	deadline time.Time
+}
+
+func (w *deadlineWriter) SetReadDeadline(deadline time.Time) error {
+	w.deadline = deadline
+	return nil
+}
+
+func Test_setReadDeadline(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Unix(1000, 0)
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+		err := setReadDeadline(httptest.NewRecorder(), deadline)
+		assert.ErrorIs(t, err, errReadDeadlineUnsupported)
+	})
+
+	t.Run("wrapped by middleware", func(t *testing.T) {
+		t.Parallel()
+		writer := &deadlineWriter{ResponseWriter: httptest.NewRecorder()}
+		wrapped := middleware.NewWrapResponseWriter(writer, 1)
+		err := setReadDeadline(wrapped, deadline)
+		require.NoError(t, err)
+		assert.Equal(t, deadline, writer.deadline)
+	})
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings"
+)
+
+type recordStatusJSON struct {
+	Domain    string    `json:"domain"`
+	Host      string    `json:"host"`
+	Provider  string    `json:"provider"`
+	IPVersion string    `json:"ip_version"`
+	Status    string    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	ErrorCode string    `json:"error_code,omitempty"`
+	CurrentIP string    `json:"current_ip,omitempty"`
+	TTL       uint      `json:"ttl,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+func newRecordStatusJSON(record records.Record) (status recordStatusJSON) {
+	status = recordStatusJSON{
+		Domain:    record.Settings.Domain(),
+		Host:      record.Settings.Host(),
+		Provider:  string(record.Settings.Provider()),
+		IPVersion: record.Settings.IPVersion().String(),
+		Status:    string(record.Status),
+		Message:   record.Message,
+		ErrorCode: string(record.ErrorCode),
+		Time:      record.Time,
+	}
+	if currentIP := record.History.GetCurrentIP(); currentIP != nil {
+		status.CurrentIP = currentIP.String()
+	}
+	if ttler, ok := record.Settings.(settings.TTLer); ok {
+		status.TTL = ttler.TTL()
+	}
+	return status
+}
+
+// status responds with the status of each record, including
+// the machine readable code of its last update error if any.
+func (h *handlers) status(w http.ResponseWriter, _ *http.Request) {
+	records := h.db.SelectAll()
+	body := make([]recordStatusJSON, len(records))
+	for i, record := range records {
+		body[i] = newRecordStatusJSON(record)
+	}
+	writeStatus(w, body)
+}
+
+// tenantStatus responds with the status of each record of the tenant,
+// if the request has the token of the tenant. Unknown tenants are
+// treated as unauthorized to not disclose the names of tenants.
+func (h *handlers) tenantStatus(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "tenant")
+	body := []recordStatusJSON{}
+	authorized := false
+	for _, record := range h.db.SelectAll() {
+		tenant := record.Options.Tenant
+		if tenant == nil || tenant.Name != name {
+			continue
+		}
+		if !authorized && !isAuthorized(r, tenant.Token) {
+			break
+		}
+		authorized = true
+		body = append(body, newRecordStatusJSON(record))
+	}
+	if !authorized {
+		httpError(w, http.StatusUnauthorized, "")
+		return
+	}
+	writeStatus(w, body)
+}
+
+func writeStatus(w http.ResponseWriter, body []recordStatusJSON) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type cycleSummaryJSON struct {
+	IPVersion       string    `json:"ip_version"`
+	Start           time.Time `json:"start"`
+	DurationSeconds float64   `json:"duration_seconds"`
+	Updated         uint      `json:"updated"`
+	Unchanged       uint      `json:"unchanged"`
+	Failed          uint      `json:"failed"`
+	IPs             []string  `json:"ips"`
+}
+
+// summary responds with the summary of the last
+// update cycle of each IP version.
+func (h *handlers) summary(w http.ResponseWriter, _ *http.Request) {
+	summaries := h.summaries.CycleSummaries()
+	body := make([]cycleSummaryJSON, len(summaries))
+	for i, summary := range summaries {
+		body[i] = cycleSummaryJSON{
+			IPVersion:       summary.IPVersion.String(),
+			Start:           summary.Start,
+			DurationSeconds: summary.Duration.Seconds(),
+			Updated:         summary.Updated,
+			Unchanged:       summary.Unchanged,
+			Failed:          summary.Failed,
+			IPs:             make([]string, len(summary.IPs)),
+		}
+		for j, ip := range summary.IPs {
+			body[i].IPs[j] = ip.String()
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err.Error())
+	}
+}
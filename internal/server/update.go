@@ -6,7 +6,12 @@ import (
 
 func (h *handlers) update(w http.ResponseWriter, r *http.Request) {
 	start := h.timeNow()
-	errors := h.runner.ForceUpdate(h.ctx)
+	forceUpdate := h.runner.ForceUpdate
+	if r.URL.Query().Get("confirm") == "true" {
+		// publish public IP addresses blocked as anomalous
+		forceUpdate = h.runner.ForceUpdateConfirmed
+	}
+	errors := forceUpdate(h.ctx)
 	duration := h.timeNow().Sub(start)
 	if len(errors) > 0 {
 		httpErrors(w, http.StatusInternalServerError, errors)
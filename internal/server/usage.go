@@ -0,0 +1,29 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type usageJSON struct {
+	// Budget is the daily budget of API requests per
+	// provider account, and is 0 if disabled.
+	Budget uint `json:"budget"`
+	// Today maps each provider account to its number
+	// of API requests made today.
+	Today map[string]uint `json:"today"`
+}
+
+// apiUsage responds with the number of API requests
+// made today for each provider account.
+func (h *handlers) apiUsage(w http.ResponseWriter, _ *http.Request) {
+	body := usageJSON{
+		Budget: h.usage.Budget(),
+		Today:  h.usage.Today(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		httpError(w, http.StatusInternalServerError, err.Error())
+	}
+}
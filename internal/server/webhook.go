@@ -0,0 +1,82 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// webhookConfirm handles callbacks from providers or self-hosted setups,
+// confirming the last update of the records matching the hostname given
+// is applied. Records waiting for their IP address to propagate are set
+// to success. The optional ip parameter restricts the confirmation to
+// records currently set to this IP address.
+func (h *handlers) webhookConfirm(w http.ResponseWriter, r *http.Request) {
+	if !isAuthorized(r, h.webhookToken) {
+		httpError(w, http.StatusUnauthorized, "")
+		return
+	}
+
+	hostname := r.URL.Query().Get("hostname")
+	if hostname == "" {
+		httpError(w, http.StatusBadRequest, "hostname parameter is missing")
+		return
+	}
+
+	var ip net.IP
+	if ipString := r.URL.Query().Get("ip"); ipString != "" {
+		ip = net.ParseIP(ipString)
+		if ip == nil {
+			httpError(w, http.StatusBadRequest, "ip parameter is malformed")
+			return
+		}
+	}
+
+	confirmed := 0
+	for i, record := range h.db.SelectAll() {
+		if !strings.EqualFold(record.Settings.BuildDomainName(), hostname) {
+			continue
+		}
+		err := h.db.Modify(uint(i), func(record *records.Record) (changed bool) {
+			currentIP := record.History.GetCurrentIP()
+			if currentIP == nil || (ip != nil && !ip.Equal(currentIP)) {
+				return false
+			}
+			record.Confirmed = true
+			if record.Status == constants.NOTPROPAGATED {
+				// the pending propagation check leaves the record untouched
+				record.Status = constants.SUCCESS
+				record.Message = "changed to " + currentIP.String()
+			}
+			confirmed++
+			return true
+		})
+		if err != nil {
+			httpError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	if confirmed == 0 {
+		httpError(w, http.StatusNotFound, "no record to confirm for hostname "+hostname)
+		return
+	}
+
+	h.logger.Info("update of " + hostname + " confirmed by webhook")
+	_, _ = w.Write([]byte(strconv.Itoa(confirmed) + " record(s) confirmed"))
+}
+
+// isAuthorized returns true if the request has the token given,
+// either as a bearer token or as the token URL query parameter.
+func isAuthorized(r *http.Request, expectedToken string) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expectedToken)) == 1
+}
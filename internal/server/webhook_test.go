@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/qdm12/golibs/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabase struct {
+	records []records.Record
+	mutex   sync.Mutex
+}
+
+func (db *testDatabase) SelectAll() []records.Record {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	return append([]records.Record(nil), db.records...)
+}
+
+func (db *testDatabase) Update(id uint, record records.Record) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.records[id] = record
+	return nil
+}
+
+func (db *testDatabase) Modify(id uint, modify func(record *records.Record) (changed bool)) error {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	modify(&db.records[id])
+	return nil
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string)             {}
+func (noopLogger) Info(string)              {}
+func (noopLogger) Warn(string)              {}
+func (noopLogger) Error(string)             {}
+func (noopLogger) PatchLevel(logging.Level) {}
+func (noopLogger) PatchPrefix(string)       {}
+
+func newTestRecord(t *testing.T, domain, host string, ip net.IP,
+	status models.Status) records.Record {
+	t.Helper()
+	settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		domain, host, ipversion.IP4)
+	require.NoError(t, err)
+	record := records.New(settings, records.Options{}, nil)
+	record.Status = status
+	if ip != nil {
+		record.History = models.History{{IP: ip, Time: time.Unix(0, 0)}}
+	}
+	return record
+}
+
+func Test_handlers_webhookConfirm(t *testing.T) {
+	t.Parallel()
+
+	ip := net.IPv4(1, 2, 3, 4)
+
+	testCases := map[string]struct {
+		url       string
+		token     string
+		status    int
+		body      string
+		confirmed bool
+		record    models.Status
+	}{
+		"bad token": {
+			url:    "/webhook/confirm?hostname=example.com",
+			token:  "wrong",
+			status: http.StatusUnauthorized,
+			body:   `{"error":"Unauthorized"}` + "\n",
+			record: constants.NOTPROPAGATED,
+		},
+		"missing hostname": {
+			url:    "/webhook/confirm",
+			token:  "secret",
+			status: http.StatusBadRequest,
+			body:   `{"error":"hostname parameter is missing"}` + "\n",
+			record: constants.NOTPROPAGATED,
+		},
+		"unknown record": {
+			url:    "/webhook/confirm?hostname=unknown.com",
+			token:  "secret",
+			status: http.StatusNotFound,
+			body:   `{"error":"no record to confirm for hostname unknown.com"}` + "\n",
+			record: constants.NOTPROPAGATED,
+		},
+		"different ip": {
+			url:    "/webhook/confirm?hostname=example.com&ip=5.6.7.8",
+			token:  "secret",
+			status: http.StatusNotFound,
+			body:   `{"error":"no record to confirm for hostname example.com"}` + "\n",
+			record: constants.NOTPROPAGATED,
+		},
+		"successful confirm": {
+			url:       "/webhook/confirm?hostname=example.com&ip=1.2.3.4",
+			token:     "secret",
+			status:    http.StatusOK,
+			body:      "1 record(s) confirmed",
+			confirmed: true,
+			record:    constants.SUCCESS,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			db := &testDatabase{records: []records.Record{
+				newTestRecord(t, "example.com", "@", ip, constants.NOTPROPAGATED),
+			}}
+			handlers := &handlers{
+				db:           db,
+				webhookToken: "secret",
+				logger:       noopLogger{},
+			}
+			request := httptest.NewRequest(http.MethodPost, testCase.url, nil)
+			request.Header.Set("Authorization", "Bearer "+testCase.token)
+			recorder := httptest.NewRecorder()
+
+			handlers.webhookConfirm(recorder, request)
+
+			assert.Equal(t, testCase.status, recorder.Code)
+			assert.Equal(t, testCase.body, recorder.Body.String())
+			record := db.records[0]
+			assert.Equal(t, testCase.confirmed, record.Confirmed)
+			assert.Equal(t, testCase.record, record.Status)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package constants
+
+import "github.com/qdm12/ddns-updater/internal/models"
+
+// HostCapabilities describes the hosts a provider supports.
+type HostCapabilities struct {
+	// Wildcard is true if wildcard hosts such as "*" or "*.sub" are supported.
+	Wildcard bool
+	// MultiLevel is true if hosts with several labels such as "a.b" are supported.
+	MultiLevel bool
+	// Apex is true if the "@" host for the domain itself is supported.
+	Apex bool
+	// ApexOnly is true if only the "@" host for the domain itself is supported.
+	ApexOnly bool
+}
+
+// ProviderHostCapabilities returns the host capabilities of the provider.
+// Providers not listed support all hosts, and restrictions depending
+// on the provider settings are checked by the provider itself.
+func ProviderHostCapabilities(provider models.Provider) (capabilities HostCapabilities) {
+	capabilities = HostCapabilities{
+		Wildcard:   true,
+		MultiLevel: true,
+		Apex:       true,
+	}
+	switch provider {
+	case AllInkl, DdnssDe, DyFi, Dyn, Dynu, DynV6, Infomaniak, Joker, NoIP,
+		NsupdateInfo, OpenDNS, SelfhostDe, Servercow, Spdyn, Strato, Variomedia:
+		// dynamic DNS services updating existing hostnames only
+		capabilities.Wildcard = false
+	case DuckDNS:
+		// the host is the single label of the DuckDNS subdomain
+		capabilities.Wildcard = false
+		capabilities.MultiLevel = false
+		capabilities.Apex = false
+	}
+	return capabilities
+}
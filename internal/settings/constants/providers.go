@@ -4,76 +4,146 @@ import "github.com/qdm12/ddns-updater/internal/models"
 
 // All possible provider values.
 const (
+	AdGuardHome  models.Provider = "adguardhome"
 	Aliyun       models.Provider = "aliyun"
 	AllInkl      models.Provider = "allinkl"
+	Bunny        models.Provider = "bunny"
 	Cloudflare   models.Provider = "cloudflare"
+	CoreNetworks models.Provider = "corenetworks"
+	CPanel       models.Provider = "cpanel"
 	Dd24         models.Provider = "dd24"
 	DdnssDe      models.Provider = "ddnss"
 	DigitalOcean models.Provider = "digitalocean"
+	DirectAdmin  models.Provider = "directadmin"
 	DNSOMatic    models.Provider = "dnsomatic"
 	DNSPod       models.Provider = "dnspod"
+	Domeneshop   models.Provider = "domeneshop"
 	DonDominio   models.Provider = "dondominio"
 	Dreamhost    models.Provider = "dreamhost"
 	DuckDNS      models.Provider = "duckdns"
+	DyFi         models.Provider = "dy.fi"
 	Dyn          models.Provider = "dyn"
+	DynDNS2      models.Provider = "dyndns2"
 	Dynu         models.Provider = "dynu"
 	DynV6        models.Provider = "dynv6"
+	EasyDNS      models.Provider = "easydns"
+	Epik         models.Provider = "epik"
+	Exec         models.Provider = "exec"
 	FreeDNS      models.Provider = "freedns"
 	Gandi        models.Provider = "gandi"
+	Gcore        models.Provider = "gcore"
 	GCP          models.Provider = "gcp"
 	GoDaddy      models.Provider = "godaddy"
 	Google       models.Provider = "google"
 	HE           models.Provider = "he"
+	Hosting1984  models.Provider = "1984.is"
+	Hostinger    models.Provider = "hostinger"
+	HuaweiCloud  models.Provider = "huaweicloud"
 	Infomaniak   models.Provider = "infomaniak"
+	Ionos        models.Provider = "ionos"
+	Joker        models.Provider = "joker"
 	Linode       models.Provider = "linode"
+	Lua          models.Provider = "lua"
 	LuaDNS       models.Provider = "luadns"
+	MailInABox   models.Provider = "mailinabox"
 	Namecheap    models.Provider = "namecheap"
+	NameCom      models.Provider = "name.com"
+	Netcup       models.Provider = "netcup"
+	Netlify      models.Provider = "netlify"
 	Njalla       models.Provider = "njalla"
 	NoIP         models.Provider = "noip"
+	NS1          models.Provider = "ns1"
+	NsupdateInfo models.Provider = "nsupdate.info"
 	OpenDNS      models.Provider = "opendns"
 	OVH          models.Provider = "ovh"
+	Plesk        models.Provider = "plesk"
+	Plugin       models.Provider = "plugin"
 	Porkbun      models.Provider = "porkbun"
+	PowerDNS     models.Provider = "powerdns"
+	Regru        models.Provider = "regru"
+	RFC2136      models.Provider = "rfc2136"
+	Route53      models.Provider = "route53"
 	SelfhostDe   models.Provider = "selfhost.de"
 	Servercow    models.Provider = "servercow"
+	Simply       models.Provider = "simply"
 	Spdyn        models.Provider = "spdyn"
 	Strato       models.Provider = "strato"
+	Technitium   models.Provider = "technitium"
 	Variomedia   models.Provider = "variomedia"
+	Vercel       models.Provider = "vercel"
+	Webhook      models.Provider = "webhook"
+	Zonomi       models.Provider = "zonomi"
 )
 
 func ProviderChoices() []models.Provider {
 	return []models.Provider{
+		AdGuardHome,
 		Aliyun,
 		AllInkl,
+		Bunny,
 		Cloudflare,
+		CoreNetworks,
+		CPanel,
 		Dd24,
 		DdnssDe,
 		DigitalOcean,
+		DirectAdmin,
 		DNSOMatic,
 		DNSPod,
+		Domeneshop,
 		DonDominio,
 		Dreamhost,
 		DuckDNS,
+		DyFi,
 		Dyn,
+		DynDNS2,
 		Dynu,
 		DynV6,
+		EasyDNS,
+		Epik,
+		Exec,
 		FreeDNS,
 		Gandi,
+		Gcore,
 		GCP,
 		GoDaddy,
 		Google,
 		HE,
+		Hosting1984,
+		Hostinger,
+		HuaweiCloud,
 		Infomaniak,
+		Ionos,
+		Joker,
 		Linode,
+		Lua,
 		LuaDNS,
+		MailInABox,
 		Namecheap,
+		NameCom,
+		Netcup,
+		Netlify,
 		Njalla,
 		NoIP,
+		NS1,
+		NsupdateInfo,
 		OpenDNS,
 		OVH,
+		Plesk,
+		Plugin,
 		Porkbun,
+		PowerDNS,
+		Regru,
+		RFC2136,
+		Route53,
 		SelfhostDe,
+		Simply,
 		Spdyn,
 		Strato,
+		Technitium,
 		Variomedia,
+		Vercel,
+		Webhook,
+		Zonomi,
 	}
 }
@@ -0,0 +1,74 @@
+package errors
+
+import "errors"
+
+// Code is a stable machine readable error code,
+// for automation to branch on the type of failure.
+type Code string
+
+const (
+	CodeAccountInactive      Code = "ACCOUNT_INACTIVE"
+	CodeBadRequest           Code = "BAD_REQUEST"
+	CodeConflictingRecord    Code = "CONFLICTING_RECORD"
+	CodeIPMismatch           Code = "IP_MISMATCH"
+	CodeMalformedResponse    Code = "MALFORMED_RESPONSE"
+	CodeProviderAuthFailed   Code = "PROVIDER_AUTH_FAILED"
+	CodeProviderServerError  Code = "PROVIDER_SERVER_ERROR"
+	CodeProviderUnavailable  Code = "PROVIDER_FEATURE_UNAVAILABLE"
+	CodeRateLimited          Code = "RATE_LIMITED"
+	CodeRecordDisabled       Code = "RECORD_DISABLED"
+	CodeRecordNotFound       Code = "RECORD_NOT_FOUND"
+	CodeUnexpectedHTTPStatus Code = "UNEXPECTED_HTTP_STATUS"
+	CodeUnknown              Code = "UNKNOWN"
+	CodeZoneNotFound         Code = "ZONE_NOT_FOUND"
+)
+
+// codes maps errors to their code, and is ordered such that more
+// specific errors are matched first for errors wrapping several of them.
+var codes = []struct { //nolint:gochecknoglobals
+	err  error
+	code Code
+}{
+	{ErrAbuse, CodeRateLimited},
+	{ErrBannedUserAgent, CodeRateLimited},
+	{ErrAuth, CodeProviderAuthFailed},
+	{ErrAssumeRole, CodeProviderAuthFailed},
+	{ErrAccountInactive, CodeAccountInactive},
+	{ErrIPReceivedMismatch, CodeIPMismatch},
+	{ErrIPReceivedMalformed, CodeMalformedResponse},
+	{ErrNoIPInResponse, CodeMalformedResponse},
+	{ErrNoResultReceived, CodeMalformedResponse},
+	{ErrNumberOfResultsReceived, CodeMalformedResponse},
+	{ErrUnknownResponse, CodeMalformedResponse},
+	{ErrUnmarshalResponse, CodeMalformedResponse},
+	{ErrZoneNotFound, CodeZoneNotFound},
+	{ErrGetZoneID, CodeZoneNotFound},
+	{ErrRecordNotFound, CodeRecordNotFound},
+	{ErrHostnameNotExists, CodeRecordNotFound},
+	{ErrDomainIDNotFound, CodeRecordNotFound},
+	{ErrNotFound, CodeRecordNotFound},
+	{ErrDomainDisabled, CodeRecordDisabled},
+	{ErrRecordNotEditable, CodeRecordDisabled},
+	{ErrConflictingRecord, CodeConflictingRecord},
+	{ErrFeatureUnavailable, CodeProviderUnavailable},
+	{ErrDNSServerSide, CodeProviderServerError},
+	{ErrBadRequest, CodeBadRequest},
+	{ErrMalformedIPSent, CodeBadRequest},
+	{ErrPrivateIPSent, CodeBadRequest},
+	{ErrInvalidSystemParam, CodeBadRequest},
+	{ErrBadHTTPStatus, CodeUnexpectedHTTPStatus},
+}
+
+// CodeOf returns the code of the error given, CodeUnknown if
+// the error has no code and an empty code if the error is nil.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	return CodeUnknown
+}
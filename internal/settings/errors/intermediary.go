@@ -3,6 +3,7 @@ package errors
 import "errors"
 
 var (
+	ErrAssumeRole      = errors.New("cannot assume role") // Route53
 	ErrCreateRecord    = errors.New("cannot create record")
 	ErrGetDomainID     = errors.New("cannot get domain ID")
 	ErrGetRecordID     = errors.New("cannot get record ID")
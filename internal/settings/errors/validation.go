@@ -6,7 +6,9 @@ var (
 	ErrCredentialsNotSet       = errors.New("credentials are not set")
 	ErrEmptyAPIKey             = errors.New("empty API key")
 	ErrEmptyAppKey             = errors.New("empty app key")
+	ErrEmptyCommand            = errors.New("empty command")
 	ErrEmptyConsumerKey        = errors.New("empty consumer key")
+	ErrEmptyCustomerNumber     = errors.New("empty customer number")
 	ErrEmptyEmail              = errors.New("empty email")
 	ErrEmptyKey                = errors.New("empty key")
 	ErrEmptyName               = errors.New("empty name")
@@ -17,18 +19,30 @@ var (
 	ErrEmptyAccessKeyID        = errors.New("empty access key id")
 	ErrEmptyAccessKeySecret    = errors.New("empty key secret")
 	ErrEmptyTTL                = errors.New("TTL is not set")
+	ErrEmptyURL                = errors.New("empty URL")
 	ErrEmptyUsername           = errors.New("empty username")
 	ErrEmptyZoneIdentifier     = errors.New("empty zone identifier")
 	ErrEmptyHost               = errors.New("host cannot be empty")
 	ErrGCPProjectNotSet        = errors.New("GCP project is not set")
+	ErrHostMultiLevel          = errors.New("host cannot have multiple levels")
 	ErrHostOnlyAt              = errors.New(`host can only be "@"`)
 	ErrHostOnlySubdomain       = errors.New("host can only be a subdomain")
-	ErrHostWildcard            = errors.New(`host cannot be a "*"`)
+	ErrHostWildcard            = errors.New("host cannot be a wildcard")
 	ErrIPv6NotSupported        = errors.New("IPv6 is not supported by this provider")
+	ErrMalformedDuration       = errors.New("malformed duration")
 	ErrMalformedEmail          = errors.New("malformed email address")
+	ErrMalformedIPv6Prefix     = errors.New("malformed IPv6 prefix length")
+	ErrMalformedIP             = errors.New("malformed IP address")
+	ErrMalformedJSONPath       = errors.New("malformed JSON path")
 	ErrMalformedKey            = errors.New("malformed key")
 	ErrMalformedPassword       = errors.New("malformed password")
+	ErrMalformedRegex          = errors.New("malformed regular expression")
+	ErrMalformedScript         = errors.New("malformed script")
+	ErrMalformedRoleARN        = errors.New("malformed role ARN")
 	ErrMalformedToken          = errors.New("malformed token")
+	ErrMalformedURL            = errors.New("malformed URL")
 	ErrMalformedUsername       = errors.New("malformed username")
 	ErrMalformedUserServiceKey = errors.New("malformed user service key")
+	ErrTTLTooLow               = errors.New("TTL is too low")
+	ErrUnknownPlugin           = errors.New("unknown plugin")
 )
@@ -2,8 +2,12 @@ package headers
 
 import "net/http"
 
+const contactEmail = "quentin.mcgaw@gmail.com"
+
+// SetUserAgent sets a default User-Agent header, which can be
+// overridden for all requests using the round tripper from NewRoundTripper.
 func SetUserAgent(request *http.Request) {
-	request.Header.Set("User-Agent", "DDNS-Updater quentin.mcgaw@gmail.com")
+	request.Header.Set("User-Agent", "DDNS-Updater "+contactEmail)
 }
 
 func SetContentType(request *http.Request, contentType string) {
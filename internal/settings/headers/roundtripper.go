@@ -0,0 +1,42 @@
+package headers
+
+import (
+	"net/http"
+)
+
+// DefaultUserAgent returns the default User-Agent value
+// with the program version embedded.
+func DefaultUserAgent(version string) string {
+	return "DDNS-Updater/" + version + " " + contactEmail
+}
+
+type roundTripper struct {
+	proxied   http.RoundTripper
+	userAgent string
+	extra     http.Header
+}
+
+// NewRoundTripper returns a round tripper setting the User-Agent header
+// to the given user agent and setting the extra headers given on every
+// request, before sending it with the proxied round tripper.
+func NewRoundTripper(proxied http.RoundTripper, userAgent string,
+	extra http.Header) http.RoundTripper {
+	return &roundTripper{
+		proxied:   proxied,
+		userAgent: userAgent,
+		extra:     extra,
+	}
+}
+
+func (r *roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	// a round tripper must not modify the request given
+	request = request.Clone(request.Context())
+	request.Header.Set("User-Agent", r.userAgent)
+	for key, values := range r.extra {
+		request.Header.Del(key)
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	return r.proxied.RoundTrip(request)
+}
@@ -0,0 +1,14 @@
+package settings
+
+import (
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+// validateHost checks the host is supported by the provider,
+// according to the provider host capabilities.
+func validateHost(provider models.Provider, host string) error {
+	capabilities := constants.ProviderHostCapabilities(provider)
+	return utils.ValidateHost(provider, capabilities, host)
+}
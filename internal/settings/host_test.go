@@ -0,0 +1,78 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateHost(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		provider   models.Provider
+		host       string
+		errWrapped error
+		errMessage string
+	}{
+		"all hosts supported": {
+			provider: constants.Cloudflare,
+			host:     "*.a.b",
+		},
+		"wildcard not supported": {
+			provider:   constants.NoIP,
+			host:       "*",
+			errWrapped: errors.ErrHostWildcard,
+			errMessage: `host cannot be a wildcard: provider noip does not support the wildcard host "*"`,
+		},
+		"sub wildcard not supported": {
+			provider:   constants.NoIP,
+			host:       "*.sub",
+			errWrapped: errors.ErrHostWildcard,
+			errMessage: `host cannot be a wildcard: provider noip does not support the wildcard host "*.sub"`,
+		},
+		"dyn wildcard not supported": {
+			provider:   constants.Dyn,
+			host:       "*",
+			errWrapped: errors.ErrHostWildcard,
+			errMessage: `host cannot be a wildcard: provider dyn does not support the wildcard host "*"`,
+		},
+		"multi level supported": {
+			provider: constants.NoIP,
+			host:     "a.b",
+		},
+		"multi level not supported": {
+			provider:   constants.DuckDNS,
+			host:       "a.b",
+			errWrapped: errors.ErrHostMultiLevel,
+			errMessage: `host cannot have multiple levels: provider duckdns does not support the host "a.b"`,
+		},
+		"apex not supported": {
+			provider:   constants.DuckDNS,
+			host:       "@",
+			errWrapped: errors.ErrHostOnlySubdomain,
+			errMessage: `host can only be a subdomain: provider duckdns does not support the "@" host`,
+		},
+		"subdomain": {
+			provider: constants.DuckDNS,
+			host:     "sub",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateHost(testCase.provider, testCase.host)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+			}
+		})
+	}
+}
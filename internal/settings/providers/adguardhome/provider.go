@@ -0,0 +1,229 @@
+package adguardhome
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	serverURL *url.URL
+	username  string
+	password  string
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		ServerURL string `json:"server_url"`
+		Username  string `json:"username"`
+		Password  string `json:"password"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	if extraSettings.ServerURL == "" {
+		return nil, errors.ErrEmptyURL
+	}
+	serverURL, err := url.Parse(extraSettings.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an http or https URL",
+			errors.ErrMalformedURL, extraSettings.ServerURL)
+	}
+
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		serverURL: serverURL,
+		username:  extraSettings.Username,
+		password:  extraSettings.Password,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.AdGuardHome, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.AdGuardHome
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.serverURL.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://adguard.com/adguard-home/overview.html\">AdGuard Home</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.SetBasicAuth(p.username, p.password)
+}
+
+type rewrite struct {
+	Domain string `json:"domain"`
+	Answer string `json:"answer"`
+}
+
+// Using https://github.com/AdguardTeam/AdGuardHome/tree/master/openapi
+// The rewrite of the same IP family is deleted and added again, since
+// the update endpoint is not available on older AdGuard Home versions.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	rewrites, err := p.listRewrites(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	domainName := p.BuildDomainName()
+	isIPv4 := ip.To4() != nil
+	for _, existing := range rewrites {
+		if !strings.EqualFold(existing.Domain, domainName) {
+			continue
+		}
+		existingIP := net.ParseIP(existing.Answer)
+		if existingIP == nil || (existingIP.To4() != nil) != isIPv4 {
+			continue // CNAME rewrite or other IP family
+		} else if existingIP.Equal(ip) {
+			return ip, nil
+		}
+		err = p.postRewrite(ctx, client, "/control/rewrite/delete", existing)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrRemoveRecord, err)
+		}
+	}
+
+	err = p.postRewrite(ctx, client, "/control/rewrite/add",
+		rewrite{Domain: domainName, Answer: ip.String()})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+	}
+	return ip, nil
+}
+
+func (p *Provider) makeURL(path string) string {
+	u := *p.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	return u.String()
+}
+
+func (p *Provider) listRewrites(ctx context.Context, client *http.Client) (
+	rewrites []rewrite, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		p.makeURL("/control/rewrite/list"), nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if err := checkStatus(response); err != nil {
+		return nil, err
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&rewrites); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return rewrites, nil
+}
+
+func (p *Provider) postRewrite(ctx context.Context, client *http.Client,
+	path string, r rewrite) (err error) {
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(r); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.makeURL(path), buffer)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return checkStatus(response)
+}
+
+func checkStatus(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", errors.ErrBadRequest, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,60 @@
+package adguardhome
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"server_url": "http://adguard.lan", "username": "admin", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4or6)
+		require.NoError(t, err)
+		return provider
+	}
+	responses := func(rewrites string) []providertest.Response {
+		return []providertest.Response{
+			{Body: rewrites},
+			{Body: `OK`},
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return responses(`[]`)
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"unchanged": {
+				Responses: responses(`[{"domain":"home.domain.com","answer":"203.0.113.1"}]`),
+				Requests:  []string{"GET /control/rewrite/list"},
+			},
+			"changed": {
+				Responses: responses(`[{"domain":"home.domain.com","answer":"2001:db8::1"},` +
+					`{"domain":"home.domain.com","answer":"203.0.113.2"}]`),
+				Requests: []string{
+					"GET /control/rewrite/list",
+					"POST /control/rewrite/delete",
+					"POST /control/rewrite/add",
+				},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					assert.Equal(t, `{"domain":"home.domain.com","answer":"203.0.113.2"}`+"\n", requests[1].Body)
+				},
+			},
+			"CNAME rewrite kept": {
+				Responses: responses(`[{"domain":"home.domain.com","answer":"other.domain.com"}]`),
+				Requests: []string{
+					"GET /control/rewrite/list",
+					"POST /control/rewrite/add",
+				},
+			},
+		},
+	})
+}
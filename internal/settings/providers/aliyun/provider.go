@@ -66,6 +66,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Aliyun, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Aliyun
+}
+
+func (p *Provider) APIHosts() []string {
+	return nil // the Aliyun SDK uses its own HTTP client
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -58,8 +58,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case p.password == "":
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -68,6 +66,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.AllInkl, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.AllInkl
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dyndns.kasserver.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -0,0 +1,298 @@
+package bunny
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	accessKey string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		AccessKey string `json:"access_key"`
+		TTL       uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		accessKey: extraSettings.AccessKey,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.accessKey == "" {
+		return errors.ErrEmptyKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Bunny, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Bunny
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.bunny.net"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://bunny.net/dns/\">Bunny.net</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("AccessKey", p.accessKey)
+}
+
+// Record types as numbered by the Bunny API.
+const (
+	recordTypeA     = 0
+	recordTypeAAAA  = 1
+	recordTypeCNAME = 2
+)
+
+type record struct {
+	ID    int    `json:"Id,omitempty"`
+	Type  int    `json:"Type"`
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+	TTL   uint   `json:"Ttl,omitempty"`
+}
+
+// Using https://docs.bunny.net/reference/dnszonepublic_index
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := recordTypeA
+	if ip.To4() == nil {
+		recordType = recordTypeAAAA
+	}
+
+	name := p.recordName()
+	zoneID, records, err := p.getZone(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetZoneID, err)
+	}
+
+	newRecord := record{
+		Type:  recordType,
+		Name:  name,
+		Value: ip.String(),
+		TTL:   p.ttl,
+	}
+	for _, existing := range records {
+		if existing.Type == recordType && strings.EqualFold(existing.Name, name) {
+			newRecord.ID = existing.ID
+			break
+		}
+	}
+
+	path := "/dnszone/" + strconv.Itoa(zoneID) + "/records"
+	method := http.MethodPut // create a record
+	if newRecord.ID != 0 {
+		path += "/" + strconv.Itoa(newRecord.ID)
+		method = http.MethodPost // update the existing record
+	}
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.bunny.net",
+		Path:   path,
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(newRecord); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		if newRecord.ID == 0 {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+// recordName returns the host as expected by Bunny,
+// where the zone apex is the empty string.
+func (p *Provider) recordName() string {
+	if p.host == "@" {
+		return ""
+	}
+	return p.host
+}
+
+// RemoveCNAME removes the CNAME record of the host, if any.
+func (p *Provider) RemoveCNAME(ctx context.Context, client *http.Client) (err error) {
+	zoneID, records, err := p.getZone(ctx, client)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrGetZoneID, err)
+	}
+
+	for _, existing := range records {
+		if existing.Type != recordTypeCNAME || !strings.EqualFold(existing.Name, p.recordName()) {
+			continue
+		}
+		u := url.URL{
+			Scheme: "https",
+			Host:   "api.bunny.net",
+			Path:   "/dnszone/" + strconv.Itoa(zoneID) + "/records/" + strconv.Itoa(existing.ID),
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodDelete, u.String(), nil)
+		if err != nil {
+			return err
+		}
+		p.setHeaders(request)
+
+		response, err := client.Do(request)
+		if err != nil {
+			return err
+		}
+		err = checkStatus(response)
+		_ = response.Body.Close()
+		if err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRemoveRecord, err)
+		}
+	}
+	return nil
+}
+
+// getZone returns the ID and the records of the zone of the domain.
+func (p *Provider) getZone(ctx context.Context, client *http.Client) (
+	zoneID int, records []record, err error) {
+	values := url.Values{}
+	values.Set("search", p.domain)
+	values.Set("perPage", "1000")
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.bunny.net",
+		Path:     "/dnszone",
+		RawQuery: values.Encode(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var data struct {
+		Items []struct {
+			ID      int      `json:"Id"`
+			Domain  string   `json:"Domain"`
+			Records []record `json:"Records"`
+		} `json:"Items"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return 0, nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	// The search matches zones containing the domain, so
+	// the zone with the exact domain name is looked for.
+	for _, zone := range data.Items {
+		if strings.EqualFold(zone.Domain, p.domain) {
+			return zone.ID, zone.Records, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+}
+
+func checkStatus(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,32 @@
+package bunny
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"access_key": "key"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"Items":[{"Id":1,"Domain":"domain.com","Records":[` +
+					`{"Id":2,"Type":0,"Name":"home","Value":"1.2.3.4","Ttl":300}]}]}`},
+				{Status: http.StatusNoContent},
+			}
+		},
+	})
+}
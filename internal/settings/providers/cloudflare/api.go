@@ -0,0 +1,134 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+type resultInfo struct {
+	TotalPages int `json:"total_pages"`
+}
+
+// doRequest sends a request to the Cloudflare API v4 at the path given,
+// encoding requestData as JSON if it is not nil, and decodes the result
+// field of the response into result if it is not nil.
+// See https://api.cloudflare.com/#getting-started-responses.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client, method, path string,
+	values url.Values, requestData, result interface{}) (info resultInfo, err error) {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.cloudflare.com",
+		Path:     "/client/v4" + path,
+		RawQuery: values.Encode(),
+	}
+
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		encoder := json.NewEncoder(buffer)
+		if err := encoder.Encode(requestData); err != nil {
+			return info, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return info, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return info, err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return info, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	var parsedJSON struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+		Result     json.RawMessage `json:"result"`
+		ResultInfo resultInfo      `json:"result_info"`
+	}
+	decodeErr := json.Unmarshal(b, &parsedJSON)
+	messages := make([]string, len(parsedJSON.Errors))
+	for i, e := range parsedJSON.Errors {
+		messages[i] = fmt.Sprintf("error %d: %s", e.Code, e.Message)
+	}
+	message := strings.Join(messages, "; ")
+	if decodeErr != nil || message == "" {
+		message = utils.ToSingleLine(string(b))
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return info, fmt.Errorf("%w: %s", errors.ErrAuth, message)
+	case http.StatusNotFound:
+		return info, fmt.Errorf("%w: %s", errors.ErrNotFound, message)
+	case http.StatusTooManyRequests:
+		return info, fmt.Errorf("%w: %s", errors.ErrAbuse, message)
+	default:
+		return info, fmt.Errorf("%w: %d: %s",
+			errors.ErrBadHTTPStatus, response.StatusCode, message)
+	}
+
+	switch {
+	case decodeErr != nil:
+		return info, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, decodeErr)
+	case !parsedJSON.Success:
+		return info, fmt.Errorf("%w: %s", errors.ErrUnsuccessfulResponse, message)
+	case result == nil:
+		return parsedJSON.ResultInfo, nil
+	}
+
+	err = json.Unmarshal(parsedJSON.Result, result)
+	if err != nil {
+		return info, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return parsedJSON.ResultInfo, nil
+}
+
+// getZoneIdentifier returns the configured zone identifier, or finds
+// it from the domain name in the zones list shared by all providers.
+func (p *Provider) getZoneIdentifier(ctx context.Context, client *http.Client) (
+	identifier string, err error) {
+	if p.zoneIdentifier != "" {
+		return p.zoneIdentifier, nil
+	}
+
+	listZones := func(ctx context.Context) (zones map[string]string, err error) {
+		return p.listZones(ctx, client)
+	}
+	identifier, found, err := p.batches.zoneIdentifier(ctx, p.credentialsKey(), p.domain, listZones)
+	if err != nil {
+		return "", err
+	} else if !found {
+		return "", fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+	}
+	return identifier, nil
+}
+
+// credentialsKey returns the key identifying the Cloudflare
+// account for the data shared between providers.
+func (p *Provider) credentialsKey() string {
+	return session.Key(p.token, p.userServiceKey, p.email, p.key)
+}
@@ -0,0 +1,193 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// batches is shared by all Cloudflare providers, such that records
+// of the same zone share a single zones list and a single records
+// list per update cycle, instead of each doing their own lookups.
+var batches = newBatcher(time.Now) //nolint:gochecknoglobals
+
+const (
+	// zonesLifetime is how long the zones list is reused for.
+	zonesLifetime = time.Hour
+	// recordsLifetime is how long a zone records list is reused for,
+	// and is meant to span a single update cycle of all records.
+	recordsLifetime = 30 * time.Second
+)
+
+type batcher struct {
+	accounts map[string]*account
+	mutex    sync.Mutex
+	timeNow  func() time.Time
+}
+
+// account holds the zones and records visible with a set of credentials.
+type account struct {
+	// zones maps zone names to zone identifiers.
+	zones          map[string]string
+	zonesFetchedAt time.Time
+	records        map[string]*zoneRecords
+	// mutex is locked while reading or fetching data, such that
+	// concurrent updates wait for a single fetch in progress.
+	mutex sync.Mutex
+}
+
+type zoneRecords struct {
+	records   []record
+	fetchedAt time.Time
+}
+
+func newBatcher(timeNow func() time.Time) *batcher {
+	return &batcher{
+		accounts: make(map[string]*account),
+		timeNow:  timeNow,
+	}
+}
+
+func (b *batcher) getAccount(key string) *account {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	a, ok := b.accounts[key]
+	if !ok {
+		a = &account{records: make(map[string]*zoneRecords)}
+		b.accounts[key] = a
+	}
+	return a
+}
+
+// zoneIdentifier returns the identifier of the zone named domain,
+// listing all zones of the account if they are not cached or if
+// the zone is missing from a cached list older than recordsLifetime.
+func (b *batcher) zoneIdentifier(ctx context.Context, key, domain string,
+	listZones func(ctx context.Context) (zones map[string]string, err error)) (
+	identifier string, found bool, err error) {
+	a := b.getAccount(key)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	age := b.timeNow().Sub(a.zonesFetchedAt)
+	identifier, found = a.zones[domain]
+	switch {
+	case a.zones != nil && age < zonesLifetime && found,
+		a.zones != nil && age < recordsLifetime:
+		return identifier, found, nil
+	}
+
+	zones, err := listZones(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	a.zones = zones
+	a.zonesFetchedAt = b.timeNow()
+	identifier, found = a.zones[domain]
+	return identifier, found, nil
+}
+
+// records returns the records of the zone, listing them
+// if they are not cached or if the cache is too old.
+func (b *batcher) records(ctx context.Context, key, zoneIdentifier string,
+	listRecords func(ctx context.Context) (records []record, err error)) (
+	records []record, err error) {
+	a := b.getAccount(key)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	cached, ok := a.records[zoneIdentifier]
+	if ok && b.timeNow().Sub(cached.fetchedAt) < recordsLifetime {
+		return cached.records, nil
+	}
+
+	records, err = listRecords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a.records[zoneIdentifier] = &zoneRecords{
+		records:   records,
+		fetchedAt: b.timeNow(),
+	}
+	return records, nil
+}
+
+// setRecord updates or adds the record in the cached records of
+// the zone, so records updated are not seen as out of date.
+func (b *batcher) setRecord(key, zoneIdentifier string, updated record) {
+	a := b.getAccount(key)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	cached, ok := a.records[zoneIdentifier]
+	if !ok {
+		return
+	}
+	records := make([]record, 0, len(cached.records)+1)
+	for _, r := range cached.records {
+		if r.ID != updated.ID {
+			records = append(records, r)
+		}
+	}
+	cached.records = append(records, updated)
+}
+
+// invalidate removes the cached records of the zone,
+// for example if a record was deleted outside of the program.
+func (b *batcher) invalidate(key, zoneIdentifier string) {
+	a := b.getAccount(key)
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	delete(a.records, zoneIdentifier)
+}
+
+// listZones lists all the zones visible with the credentials.
+// See https://api.cloudflare.com/#zone-list-zones.
+func (p *Provider) listZones(ctx context.Context, client *http.Client) (
+	zones map[string]string, err error) {
+	zones = make(map[string]string)
+	for page := 1; ; page++ {
+		values := url.Values{}
+		values.Set("page", strconv.Itoa(page))
+		values.Set("per_page", "50")
+		var results []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		info, err := p.doRequest(ctx, client, http.MethodGet, "/zones", values, nil, &results)
+		if err != nil {
+			return nil, fmt.Errorf("listing zones page %d: %w", page, err)
+		}
+		for _, result := range results {
+			zones[result.Name] = result.ID
+		}
+		if page >= info.TotalPages {
+			return zones, nil
+		}
+	}
+}
+
+// listRecords lists all the records of the zone.
+// See https://api.cloudflare.com/#dns-records-for-a-zone-list-dns-records.
+func (p *Provider) listRecords(ctx context.Context, client *http.Client,
+	zoneIdentifier string) (records []record, err error) {
+	path := fmt.Sprintf("/zones/%s/dns_records", zoneIdentifier)
+	for page := 1; ; page++ {
+		values := url.Values{}
+		values.Set("page", strconv.Itoa(page))
+		values.Set("per_page", "1000")
+		var results []record
+		info, err := p.doRequest(ctx, client, http.MethodGet, path, values, nil, &results)
+		if err != nil {
+			return nil, fmt.Errorf("listing records page %d: %w", page, err)
+		}
+		records = append(records, results...)
+		if page >= info.TotalPages {
+			return records, nil
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+)
+
+// ListRecords lists the A and AAAA records of the zone.
+func (p *Provider) ListRecords(ctx context.Context, client *http.Client) (
+	records []models.ZoneRecord, err error) {
+	zoneIdentifier, err := p.getZoneIdentifier(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errors.ErrGetZoneID, err)
+	}
+
+	results, err := p.listRecords(ctx, client, zoneIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		if result.Type != constants.A && result.Type != constants.AAAA {
+			continue
+		}
+		host := "@"
+		if result.Name != p.domain {
+			host = strings.TrimSuffix(result.Name, "."+p.domain)
+		}
+		records = append(records, models.ZoneRecord{
+			Host: host,
+			Type: result.Type,
+			IP:   net.ParseIP(result.Content),
+		})
+	}
+	return records, nil
+}
@@ -1,14 +1,11 @@
 package cloudflare
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
-	"net/url"
-	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/settings/common"
@@ -32,6 +29,7 @@ type Provider struct {
 	proxied        bool
 	ttl            uint
 	matcher        common.Matcher
+	batches        *batcher
 }
 
 func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersion,
@@ -48,6 +46,10 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 	if err := json.Unmarshal(data, &extraSettings); err != nil {
 		return nil, err
 	}
+	const automaticTTL = 1
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = automaticTTL
+	}
 	p = &Provider{
 		domain:         domain,
 		host:           host,
@@ -60,6 +62,7 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 		proxied:        extraSettings.Proxied,
 		ttl:            extraSettings.TTL,
 		matcher:        matcher,
+		batches:        batches,
 	}
 	if err := p.isValid(); err != nil {
 		return nil, err
@@ -77,16 +80,11 @@ func (p *Provider) isValid() error {
 			return errors.ErrMalformedEmail
 		}
 	case len(p.userServiceKey) > 0: // only user service key
-		if !p.matcher.CloudflareKey(p.key) {
+		if !p.matcher.CloudflareUserServiceKey(p.userServiceKey) {
 			return errors.ErrMalformedUserServiceKey
 		}
-	default: // constants.API token only
-	}
-	switch {
-	case len(p.zoneIdentifier) == 0:
-		return errors.ErrEmptyZoneIdentifier
-	case p.ttl == 0:
-		return errors.ErrEmptyTTL
+	case p.token == "":
+		return errors.ErrEmptyToken
 	}
 	return nil
 }
@@ -95,6 +93,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Cloudflare, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Cloudflare
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.cloudflare.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -111,6 +117,10 @@ func (p *Provider) Proxied() bool {
 	return p.proxied
 }
 
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -139,158 +149,115 @@ func (p *Provider) setHeaders(request *http.Request) {
 	}
 }
 
-// Obtain domain ID.
-// See https://api.cloudflare.com/#dns-records-for-a-zone-list-dns-records.
-func (p *Provider) getRecordID(ctx context.Context, client *http.Client, newIP net.IP) (
-	identifier string, upToDate bool, err error) {
-	recordType := constants.A
-	if newIP.To4() == nil {
-		recordType = constants.AAAA
-	}
+type record struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
 
-	u := url.URL{
-		Scheme: "https",
-		Host:   "api.cloudflare.com",
-		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records", p.zoneIdentifier),
+// getRecord finds the record matching the host and record type in the
+// records of the zone, which are listed once for all records of the zone
+// updated within a short time, with found set to false if the record
+// does not exist.
+func (p *Provider) getRecord(ctx context.Context, client *http.Client,
+	zoneIdentifier, recordType string) (result record, found bool, err error) {
+	listRecords := func(ctx context.Context) (records []record, err error) {
+		return p.listRecords(ctx, client, zoneIdentifier)
 	}
-	values := url.Values{}
-	values.Set("type", recordType)
-	values.Set("name", utils.BuildURLQueryHostname(p.host, p.domain))
-	values.Set("page", "1")
-	values.Set("per_page", "1")
-	u.RawQuery = values.Encode()
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	records, err := p.batches.records(ctx, p.credentialsKey(), zoneIdentifier, listRecords)
 	if err != nil {
-		return "", false, err
+		return result, false, err
 	}
-	p.setHeaders(request)
 
-	response, err := client.Do(request)
-	if err != nil {
-		return "", false, err
-	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		return "", false, fmt.Errorf("%w: %d: %s",
-			errors.ErrBadHTTPStatus, response.StatusCode, utils.BodyToSingleLine(response.Body))
-	}
-
-	decoder := json.NewDecoder(response.Body)
-	listRecordsResponse := struct {
-		Success bool     `json:"success"`
-		Errors  []string `json:"errors"`
-		Result  []struct {
-			ID      string `json:"id"`
-			Content string `json:"content"`
-		} `json:"result"`
-	}{}
-	if err := decoder.Decode(&listRecordsResponse); err != nil {
-		return "", false, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	name := utils.BuildURLQueryHostname(p.host, p.domain)
+	matches := 0
+	for _, r := range records {
+		if r.Type == recordType && r.Name == name {
+			result = r
+			matches++
+		}
 	}
-
-	switch {
-	case len(listRecordsResponse.Errors) > 0:
-		return "", false, fmt.Errorf("%w: %s",
-			errors.ErrUnsuccessfulResponse, strings.Join(listRecordsResponse.Errors, ","))
-	case !listRecordsResponse.Success:
-		return "", false, errors.ErrUnsuccessfulResponse
-	case len(listRecordsResponse.Result) == 0:
-		return "", false, errors.ErrNoResultReceived
-	case len(listRecordsResponse.Result) > 1:
-		return "", false, fmt.Errorf("%w: %d instead of 1",
-			errors.ErrNumberOfResultsReceived, len(listRecordsResponse.Result))
-	case listRecordsResponse.Result[0].Content == newIP.String(): // up to date
-		return "", true, nil
+	if matches > 1 {
+		return result, false, fmt.Errorf("%w: %d instead of 1",
+			errors.ErrNumberOfResultsReceived, matches)
 	}
-	return listRecordsResponse.Result[0].ID, false, nil
+	return result, matches == 1, nil
 }
 
+// Update finds the zone identifier if it is not configured, and
+// updates the record with a PATCH request, creating it if it does
+// not exist yet. The zones and records lists are shared by all the
+// records of the same Cloudflare account, to limit the API calls.
+// See https://api.cloudflare.com/#dns-records-for-a-zone-patch-dns-record
+// and https://api.cloudflare.com/#dns-records-for-a-zone-create-dns-record.
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
 	recordType := constants.A
 	if ip.To4() == nil {
 		recordType = constants.AAAA
 	}
-	identifier, upToDate, err := p.getRecordID(ctx, client, ip)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errors.ErrGetRecordID, err)
-	} else if upToDate {
-		return ip, nil
-	}
 
-	u := url.URL{
-		Scheme: "https",
-		Host:   "api.cloudflare.com",
-		Path:   fmt.Sprintf("/client/v4/zones/%s/dns_records/%s", p.zoneIdentifier, identifier),
-	}
-
-	requestData := struct {
-		Type    string `json:"type"`    // constants.A or constants.AAAA depending on ip address given
-		Name    string `json:"name"`    // DNS record name i.e. example.com
-		Content string `json:"content"` // ip address
-		Proxied bool   `json:"proxied"` // whether the record is receiving the performance and security benefits of Cloudflare
-		TTL     uint   `json:"ttl"`
-	}{
-		Type:    recordType,
-		Name:    utils.BuildURLQueryHostname(p.host, p.domain),
-		Content: ip.String(),
-		Proxied: p.proxied,
-		TTL:     p.ttl,
-	}
-
-	buffer := bytes.NewBuffer(nil)
-	encoder := json.NewEncoder(buffer)
-	if err := encoder.Encode(requestData); err != nil {
-		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	zoneIdentifier, err := p.getZoneIdentifier(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errors.ErrGetZoneID, err)
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), buffer)
+	existing, found, err := p.getRecord(ctx, client, zoneIdentifier, recordType)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s: %w", errors.ErrGetRecordID, err)
+	} else if found && existing.Content == ip.String() {
+		return ip, nil // up to date
 	}
 
-	p.setHeaders(request)
-
-	response, err := client.Do(request)
+	result, err := p.setRecord(ctx, client, zoneIdentifier, recordType, existing.ID, ip)
 	if err != nil {
+		// the cached records may be out of date, for example if
+		// the record was deleted outside of the program.
+		p.batches.invalidate(p.credentialsKey(), zoneIdentifier)
 		return nil, err
 	}
-	defer response.Body.Close()
+	p.batches.setRecord(p.credentialsKey(), zoneIdentifier, result)
 
-	if response.StatusCode > http.StatusUnsupportedMediaType {
-		return nil, fmt.Errorf("%w: %d: %s",
-			errors.ErrBadHTTPStatus, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	newIP = net.ParseIP(result.Content)
+	if newIP == nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, result.Content)
+	} else if !newIP.Equal(ip) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMismatch, newIP.String())
 	}
+	return newIP, nil
+}
 
-	decoder := json.NewDecoder(response.Body)
-	var parsedJSON struct {
-		Success bool `json:"success"`
-		Errors  []struct {
-			Code    int    `json:"code"`
-			Message string `json:"message"`
-		} `json:"errors"`
-		Result struct {
-			Content string `json:"content"`
-		} `json:"result"`
-	}
-	if err := decoder.Decode(&parsedJSON); err != nil {
-		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+// setRecord patches the record with the identifier given, or
+// creates the record if the identifier is empty.
+func (p *Provider) setRecord(ctx context.Context, client *http.Client,
+	zoneIdentifier, recordType, identifier string, ip net.IP) (result record, err error) {
+	requestData := struct {
+		Type    string `json:"type,omitempty"` // constants.A or constants.AAAA depending on ip address given
+		Name    string `json:"name,omitempty"` // DNS record name i.e. example.com
+		Content string `json:"content"`        // ip address
+		Proxied bool   `json:"proxied"`        // whether the record is receiving the performance and security benefits of Cloudflare
+		TTL     uint   `json:"ttl"`
+	}{
+		Content: ip.String(),
+		Proxied: p.proxied,
+		TTL:     p.ttl,
 	}
 
-	if !parsedJSON.Success {
-		var errStr string
-		for _, e := range parsedJSON.Errors {
-			errStr += fmt.Sprintf("error %d: %s; ", e.Code, e.Message)
+	path := fmt.Sprintf("/zones/%s/dns_records", zoneIdentifier)
+	if identifier != "" {
+		path += "/" + identifier
+		_, err = p.doRequest(ctx, client, http.MethodPatch, path, nil, requestData, &result)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", errors.ErrUpdateRecord, err)
 		}
-		return nil, fmt.Errorf("%w: %s", errors.ErrUnsuccessfulResponse, errStr)
+		return result, nil
 	}
 
-	newIP = net.ParseIP(parsedJSON.Result.Content)
-	if newIP == nil {
-		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, parsedJSON.Result.Content)
-	} else if !newIP.Equal(ip) {
-		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMismatch, newIP.String())
+	requestData.Type = recordType
+	requestData.Name = utils.BuildURLQueryHostname(p.host, p.domain)
+	_, err = p.doRequest(ctx, client, http.MethodPost, path, nil, requestData, &result)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", errors.ErrCreateRecord, err)
 	}
-	return newIP, nil
+	return result, nil
 }
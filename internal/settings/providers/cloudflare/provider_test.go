@@ -0,0 +1,187 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"token only": {
+			data: `{"token": "token"}`,
+		},
+		"no credentials": {
+			data:       `{"zone_identifier": "zone"}`,
+			errWrapped: errors.ErrEmptyToken,
+		},
+		"malformed email": {
+			data:       `{"key": "key", "email": "x"}`,
+			errWrapped: errors.ErrMalformedEmail,
+		},
+		"user service key": {
+			data: `{"user_service_key": "v1.0-key"}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.data), "domain.com", "@",
+				ipversion.IP4, regex.NewMatcher())
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uint(1), provider.ttl)
+		})
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, data string) *Provider {
+		t.Helper()
+		provider, err := New(json.RawMessage(data), "domain.com", "home",
+			ipversion.IP4, regex.NewMatcher())
+		require.NoError(t, err)
+		provider.batches = newBatcher(time.Now)
+		return provider
+	}
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newProvider(t, `{"token": "token", "zone_identifier": "zone"}`)
+	}
+	check := func(body string) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			for _, request := range requests {
+				assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+			}
+			if body != "" {
+				assert.JSONEq(t, body, requests[len(requests)-1].Body)
+			}
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"success": true, "result": [{"id": "record", "type": "A", "name": "home.domain.com", "content": "203.0.113.9"}]}`},
+				{Body: `{"success": true, "result": {"id": "record", "content": "` + reportedIP + `"}}`},
+			}
+		},
+		ReportsIP: true,
+		Scenarios: map[string]providertest.Scenario{
+			"zone discovered and record created": {
+				Updater: newProvider(t, `{"token": "token", "proxied": true, "ttl": 120}`),
+				Responses: []providertest.Response{
+					{Body: `{"success": true, "result": [{"id": "zone", "name": "domain.com"}]}`},
+					{Body: `{"success": true, "result": [{"id": "other", "type": "A", "name": "domain.com", "content": "203.0.113.1"}]}`},
+					{Body: `{"success": true, "result": {"content": "203.0.113.1"}}`},
+				},
+				Requests: []string{
+					"GET /client/v4/zones?page=1&per_page=50",
+					"GET /client/v4/zones/zone/dns_records?page=1&per_page=1000",
+					"POST /client/v4/zones/zone/dns_records",
+				},
+				Check: check(`{"type":"A","name":"home.domain.com","content":"203.0.113.1","proxied":true,"ttl":120}`),
+			},
+			"record updated": {
+				Responses: []providertest.Response{
+					{Body: `{"success": true, "result": [{"id": "record", "type": "A", "name": "home.domain.com", "content": "203.0.113.9"}]}`},
+					{Body: `{"success": true, "result": {"content": "203.0.113.1"}}`},
+				},
+				Requests: []string{
+					"GET /client/v4/zones/zone/dns_records?page=1&per_page=1000",
+					"PATCH /client/v4/zones/zone/dns_records/record",
+				},
+				Check: check(`{"content":"203.0.113.1","proxied":false,"ttl":1}`),
+			},
+			"up to date": {
+				Responses: []providertest.Response{
+					{Body: `{"success": true, "result": [{"id": "record", "type": "A", "name": "home.domain.com", "content": "203.0.113.1"}]}`},
+				},
+				Requests: []string{"GET /client/v4/zones/zone/dns_records?page=1&per_page=1000"},
+				Check:    check(""),
+			},
+			"zone not found": {
+				Updater: newProvider(t, `{"token": "token"}`),
+				Responses: []providertest.Response{
+					{Body: `{"success": true, "result": [{"id": "zone", "name": "other.com"}]}`},
+				},
+				Requests:   []string{"GET /client/v4/zones?page=1&per_page=50"},
+				ErrWrapped: errors.ErrZoneNotFound,
+				Check:      check(""),
+			},
+			"bad token": {
+				Updater: newProvider(t, `{"token": "token"}`),
+				Responses: []providertest.Response{
+					{Status: http.StatusForbidden, Body: `{"success": false, "errors": [{"code": 9109, "message": "Invalid access token"}]}`},
+				},
+				Requests:   []string{"GET /client/v4/zones?page=1&per_page=50"},
+				ErrWrapped: errors.ErrAuth,
+				Check:      check(""),
+			},
+		},
+	})
+}
+
+func Test_Provider_Update_batched(t *testing.T) {
+	t.Parallel()
+
+	registrar := providertest.NewRegistrar()
+	defer registrar.Close()
+	registrar.Script(
+		providertest.Response{Body: `{"success": true, "result": [{"id": "zone", "name": "domain.com"}], "result_info": {"total_pages": 1}}`},
+		providertest.Response{Body: `{"success": true, "result": [
+			{"id": "a", "type": "A", "name": "a.domain.com", "content": "203.0.113.9"},
+			{"id": "b", "type": "A", "name": "b.domain.com", "content": "203.0.113.9"}
+		], "result_info": {"total_pages": 1}}`},
+		providertest.Response{Body: `{"success": true, "result": {"id": "a", "type": "A", "name": "a.domain.com", "content": "203.0.113.1"}}`},
+		providertest.Response{Body: `{"success": true, "result": {"id": "b", "type": "A", "name": "b.domain.com", "content": "203.0.113.1"}}`},
+	)
+
+	batches := newBatcher(time.Now)
+	ip := net.IPv4(203, 0, 113, 1)
+	for _, host := range []string{"a", "b", "a"} {
+		provider, err := New(json.RawMessage(`{"token": "token"}`), "domain.com", host,
+			ipversion.IP4, regex.NewMatcher())
+		require.NoError(t, err)
+		provider.batches = batches
+
+		_, err = provider.Update(context.Background(), registrar.Client(), ip)
+		require.NoError(t, err)
+	}
+
+	requests := registrar.Requests()
+	lines := make([]string, len(requests))
+	for i, request := range requests {
+		lines[i] = request.Method + " " + request.URL.RequestURI()
+	}
+	assert.Equal(t, []string{
+		"GET /client/v4/zones?page=1&per_page=50",
+		"GET /client/v4/zones/zone/dns_records?page=1&per_page=1000",
+		"PATCH /client/v4/zones/zone/dns_records/a",
+		"PATCH /client/v4/zones/zone/dns_records/b",
+		// a is up to date in the cached records
+	}, lines)
+}
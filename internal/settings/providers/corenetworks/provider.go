@@ -0,0 +1,264 @@
+package corenetworks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// sessions is shared by all Core-Networks records, such that records
+// with the same credentials reuse the same token.
+var sessions = session.NewCache(time.Now) //nolint:gochecknoglobals
+
+// sessionLifetime is the duration a token is reused for since its
+// last use, which is below the token validity of one hour.
+const sessionLifetime = 30 * time.Minute
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	username  string
+	password  string
+	ttl       uint
+	sessions  *session.Cache
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TTL      uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const minTTL = 60
+	ttl := uint(minTTL)
+	if extraSettings.TTL > minTTL {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		username:  extraSettings.Username,
+		password:  extraSettings.Password,
+		ttl:       ttl,
+		sessions:  sessions,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.CoreNetworks, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.CoreNetworks
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"beta.api.core-networks.de"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.core-networks.de/\">Core-Networks</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request, token string) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	if token != "" {
+		headers.SetAuthBearer(request, token)
+	}
+}
+
+// Using https://beta.api.core-networks.de/doc/
+// Changes to a zone only take effect once committed.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	login := func(ctx context.Context) (token string, err error) {
+		return p.getToken(ctx, client)
+	}
+	update := func(token string) (err error) {
+		return p.replaceRecord(ctx, client, token, recordType, ip)
+	}
+	isExpired := func(err error) bool { return goerrors.Is(err, errors.ErrAuth) }
+
+	key := session.Key(p.username, p.password)
+	err = p.sessions.Do(ctx, key, sessionLifetime, login, update, isExpired)
+	if err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+// replaceRecord deletes the records of the host with the record type,
+// creates a record with the IP address and commits the zone changes.
+func (p *Provider) replaceRecord(ctx context.Context, client *http.Client,
+	token, recordType string, ip net.IP) (err error) {
+	zonePath := "/dnszones/" + p.domain + "/records"
+	record := struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		TTL  uint   `json:"ttl,omitempty"`
+		Data string `json:"data,omitempty"`
+	}{
+		Name: p.host,
+		Type: recordType,
+	}
+	err = p.post(ctx, client, token, zonePath+"/delete", record, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRemoveRecord, err)
+	}
+
+	record.TTL = p.ttl
+	record.Data = ip.String()
+	err = p.post(ctx, client, token, zonePath+"/", record, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+	}
+
+	err = p.post(ctx, client, token, zonePath+"/commit", nil, nil)
+	if err != nil {
+		return fmt.Errorf("%w: committing zone: %s", errors.ErrUpdateRecord, err)
+	}
+	return nil
+}
+
+func (p *Provider) getToken(ctx context.Context, client *http.Client) (token string, err error) {
+	credentials := struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}{
+		Login:    p.username,
+		Password: p.password,
+	}
+	var data struct {
+		Token string `json:"token"`
+	}
+	err = p.post(ctx, client, "", "/auth/token", credentials, &data)
+	if err != nil {
+		return "", err
+	} else if data.Token == "" {
+		return "", fmt.Errorf("%w: no token", errors.ErrUnknownResponse)
+	}
+	return data.Token, nil
+}
+
+// post sends the request data as JSON to the path, and decodes the
+// JSON response to the response data if it is not nil.
+func (p *Provider) post(ctx context.Context, client *http.Client, token, path string,
+	requestData, responseData interface{}) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "beta.api.core-networks.de",
+		Path:   path,
+	}
+
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request, token)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package corenetworks
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"username": "user", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		provider.sessions = session.NewCache(time.Now)
+		return provider
+	}
+	responses := []providertest.Response{
+		{Body: `{"token": "token", "expires": 3600}`},
+		{Status: http.StatusNoContent},
+	}
+	requests := []string{
+		"POST /auth/token",
+		"POST /dnszones/domain.com/records/delete",
+		"POST /dnszones/domain.com/records/",
+		"POST /dnszones/domain.com/records/commit",
+	}
+
+	// The session is shared by the updates of the same updater.
+	sessionUpdater := newUpdater(t)
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return responses
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"record replaced": {
+				Updater:   sessionUpdater,
+				Responses: responses,
+				Requests:  requests,
+				Check: func(t *testing.T, requests []providertest.Request) {
+					assert.Equal(t, "Bearer token", requests[1].Header.Get("Authorization"))
+					assert.JSONEq(t, `{"name":"home","type":"A"}`, requests[1].Body)
+					assert.JSONEq(t, `{"name":"home","type":"A","ttl":60,"data":"203.0.113.1"}`, requests[2].Body)
+				},
+			},
+		},
+	})
+
+	t.Run("token reused", func(t *testing.T) {
+		t.Parallel()
+		registrar := providertest.NewRegistrar()
+		defer registrar.Close()
+		registrar.Script(responses[1:]...)
+
+		ip := net.IPv4(203, 0, 113, 1)
+		_, err := sessionUpdater.Update(context.Background(), registrar.Client(), ip)
+		require.NoError(t, err)
+
+		paths := make([]string, 0, len(requests)-1)
+		for _, request := range registrar.Requests() {
+			paths = append(paths, request.Method+" "+request.URL.RequestURI())
+		}
+		assert.Equal(t, requests[1:], paths)
+	})
+}
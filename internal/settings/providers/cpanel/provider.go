@@ -0,0 +1,301 @@
+package cpanel
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	serverURL *url.URL
+	username  string
+	token     string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		ServerURL string `json:"server_url"`
+		Username  string `json:"username"`
+		Token     string `json:"token"`
+		TTL       uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	if extraSettings.ServerURL == "" {
+		return nil, errors.ErrEmptyURL
+	}
+	serverURL, err := url.Parse(extraSettings.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an http or https URL",
+			errors.ErrMalformedURL, extraSettings.ServerURL)
+	}
+
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		serverURL: serverURL,
+		username:  extraSettings.Username,
+		token:     extraSettings.Token,
+		ttl:       ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.token == "":
+		return errors.ErrEmptyToken
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.CPanel, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.CPanel
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.serverURL.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://cpanel.net/\">cPanel</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("Authorization", "cpanel "+p.username+":"+p.token)
+}
+
+type zoneRecord struct {
+	LineIndex  int      `json:"line_index"`
+	Type       string   `json:"type"`
+	RecordType string   `json:"record_type"`
+	NameBase64 string   `json:"dname_b64"`
+	DataBase64 []string `json:"data_b64"`
+}
+
+// Using https://api.docs.cpanel.net/openapi/cpanel/operation/dns-mass_edit_zone/
+// The zone serial is required to edit the zone, and is taken from
+// the SOA record of the zone.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	var records []zoneRecord
+	err = p.uapi(ctx, client, "parse_zone", url.Values{"zone": {p.domain}}, &records)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	serial := ""
+	lineIndex := -1
+	for _, record := range records {
+		if record.Type != "record" {
+			continue
+		}
+		switch record.RecordType {
+		case "SOA":
+			const serialIndex = 2
+			if len(record.DataBase64) > serialIndex {
+				serial = decodeBase64(record.DataBase64[serialIndex])
+			}
+		case recordType:
+			if lineIndex >= 0 || !p.matchesName(decodeBase64(record.NameBase64)) {
+				continue
+			}
+			if len(record.DataBase64) == 1 {
+				recordIP := net.ParseIP(decodeBase64(record.DataBase64[0]))
+				if recordIP != nil && recordIP.Equal(ip) {
+					return ip, nil
+				}
+			}
+			lineIndex = record.LineIndex
+		}
+	}
+	if serial == "" {
+		return nil, fmt.Errorf("%w: no SOA serial found for zone %s", errors.ErrUnknownResponse, p.domain)
+	}
+
+	type recordData struct {
+		LineIndex  *int     `json:"line_index,omitempty"`
+		Name       string   `json:"dname"`
+		TTL        uint     `json:"ttl"`
+		RecordType string   `json:"record_type"`
+		Data       []string `json:"data"`
+	}
+	data := recordData{
+		Name:       p.recordName(),
+		TTL:        p.ttl,
+		RecordType: recordType,
+		Data:       []string{ip.String()},
+	}
+	operation, intermediaryErr := "add", errors.ErrCreateRecord
+	if lineIndex >= 0 {
+		data.LineIndex = &lineIndex
+		operation, intermediaryErr = "edit", errors.ErrUpdateRecord
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestMarshal, err)
+	}
+
+	values := url.Values{
+		"zone":    {p.domain},
+		"serial":  {serial},
+		operation: {string(encoded)},
+	}
+	err = p.uapi(ctx, client, "mass_edit_zone", values, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", intermediaryErr, err)
+	}
+	return ip, nil
+}
+
+// recordName returns the name of the record relative to the zone,
+// or the fully qualified zone name for the root of the domain.
+func (p *Provider) recordName() string {
+	if p.host == "@" {
+		return p.domain + "."
+	}
+	return p.host
+}
+
+// matchesName returns true if the record name, which can be relative
+// to the zone or fully qualified, designates the host.
+func (p *Provider) matchesName(name string) bool {
+	if strings.HasSuffix(name, ".") {
+		return strings.EqualFold(strings.TrimSuffix(name, "."), p.BuildDomainName())
+	}
+	return strings.EqualFold(name, p.host)
+}
+
+func decodeBase64(s string) string {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// uapi calls the UAPI function of the DNS module with the values
+// given, and decodes the data of the response into the data given
+// if it is not nil.
+func (p *Provider) uapi(ctx context.Context, client *http.Client,
+	function string, values url.Values, data interface{}) (err error) {
+	u := *p.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/execute/DNS/" + function
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var result struct {
+		Status int             `json:"status"`
+		Errors []string        `json:"errors"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	if result.Status != 1 {
+		return fmt.Errorf("%w: %s", errors.ErrUnsuccessfulResponse, strings.Join(result.Errors, "; "))
+	}
+
+	if data == nil {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, data); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
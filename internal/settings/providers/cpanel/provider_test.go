@@ -0,0 +1,152 @@
+package cpanel
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func zoneBody(records ...string) string {
+	soa := fmt.Sprintf(`{"line_index": 1, "type": "record", "record_type": "SOA", "dname_b64": %q,`+
+		` "data_b64": [%q, %q, %q]}`, b64("domain.com."), b64("ns1.domain.com."),
+		b64("hostmaster.domain.com."), b64("2024010101"))
+	body := `{"status": 1, "errors": null, "data": [` + soa
+	for _, record := range records {
+		body += ", " + record
+	}
+	return body + `]}`
+}
+
+func aRecord(lineIndex int, name, ip string) string {
+	return fmt.Sprintf(`{"line_index": %d, "type": "record", "record_type": "A", "dname_b64": %q, "data_b64": [%q]}`,
+		lineIndex, b64(name), b64(ip))
+}
+
+const okBody = `{"status": 1, "errors": null, "data": {"new_serial": "2024010102"}}`
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"server_url": "https://cpanel.example.com:2083", "username": "user", "token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	check := func(expectedEdit url.Values) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			assert.Equal(t, "/execute/DNS/parse_zone", requests[0].URL.Path)
+			assert.Equal(t, "cpanel user:token", requests[0].Header.Get("Authorization"))
+			if expectedEdit == nil {
+				require.Len(t, requests, 1)
+				return
+			}
+			require.Len(t, requests, 2)
+			assert.Equal(t, "/execute/DNS/mass_edit_zone", requests[1].URL.Path)
+			assert.Equal(t, expectedEdit, requests[1].URL.Query())
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: zoneBody()},
+				{Body: okBody},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"record added": {
+				Responses: []providertest.Response{
+					{Body: zoneBody(aRecord(2, "other", "203.0.113.2"))},
+					{Body: okBody},
+				},
+				Check: check(url.Values{
+					"zone":   {"domain.com"},
+					"serial": {"2024010101"},
+					"add":    {`{"dname":"home","ttl":300,"record_type":"A","data":["203.0.113.1"]}`},
+				}),
+			},
+			"fully qualified record edited": {
+				Responses: []providertest.Response{
+					{Body: zoneBody(aRecord(5, "home.domain.com.", "203.0.113.2"))},
+					{Body: okBody},
+				},
+				Check: check(url.Values{
+					"zone":   {"domain.com"},
+					"serial": {"2024010101"},
+					"edit":   {`{"line_index":5,"dname":"home","ttl":300,"record_type":"A","data":["203.0.113.1"]}`},
+				}),
+			},
+			"record up to date": {
+				Responses: []providertest.Response{
+					{Body: zoneBody(aRecord(5, "home", "203.0.113.1"))},
+				},
+				Check: check(nil),
+			},
+			"edit failed": {
+				Responses: []providertest.Response{
+					{Body: zoneBody(aRecord(5, "home", "203.0.113.2"))},
+					{Body: `{"status": 0, "errors": ["The serial number does not match"], "data": null}`},
+				},
+				ErrWrapped: errors.ErrUpdateRecord,
+				Check: check(url.Values{
+					"zone":   {"domain.com"},
+					"serial": {"2024010101"},
+					"edit":   {`{"line_index":5,"dname":"home","ttl":300,"record_type":"A","data":["203.0.113.1"]}`},
+				}),
+			},
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server_url": "https://cpanel.example.com:2083", "username": "user", "token": "token"}`,
+		},
+		"empty URL": {
+			data:       `{"username": "user", "token": "token"}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"URL without scheme": {
+			data:       `{"server_url": "cpanel.example.com", "username": "user", "token": "token"}`,
+			errWrapped: errors.ErrMalformedURL,
+		},
+		"empty username": {
+			data:       `{"server_url": "https://cpanel.example.com:2083", "token": "token"}`,
+			errWrapped: errors.ErrEmptyUsername,
+		},
+		"empty token": {
+			data:       `{"server_url": "https://cpanel.example.com:2083", "username": "user"}`,
+			errWrapped: errors.ErrEmptyToken,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
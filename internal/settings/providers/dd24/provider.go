@@ -60,6 +60,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Dd24, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Dd24
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dynamicdns.key-systems.net"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/settings/constants"
@@ -24,8 +25,14 @@ type Provider struct {
 	ipVersion     ipversion.IPVersion
 	username      string
 	password      string
+	key           string
 	dualStack     bool
 	useProviderIP bool
+	// lastIPs holds the last IPv4 and IPv6 addresses updated, to send
+	// both of them in a single request for dual stack records.
+	lastIPsMutex sync.Mutex
+	lastIPv4     net.IP
+	lastIPv6     net.IP
 }
 
 func New(data json.RawMessage, domain, host string,
@@ -33,6 +40,7 @@ func New(data json.RawMessage, domain, host string,
 	extraSettings := struct {
 		Username      string `json:"username"`
 		Password      string `json:"password"`
+		Key           string `json:"key"`
 		DualStack     bool   `json:"dual_stack"`
 		UseProviderIP bool   `json:"provider_ip"`
 	}{}
@@ -45,6 +53,7 @@ func New(data json.RawMessage, domain, host string,
 		ipVersion:     ipVersion,
 		username:      extraSettings.Username,
 		password:      extraSettings.Password,
+		key:           extraSettings.Key,
 		dualStack:     extraSettings.DualStack,
 		useProviderIP: extraSettings.UseProviderIP,
 	}
@@ -56,12 +65,10 @@ func New(data json.RawMessage, domain, host string,
 
 func (p *Provider) isValid() error {
 	switch {
-	case len(p.username) == 0:
+	case p.key == "" && len(p.username) == 0:
 		return errors.ErrEmptyUsername
-	case len(p.password) == 0:
+	case p.key == "" && len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -70,6 +77,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.DdnssDe, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DdnssDe
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"www.ddnss.de"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -106,15 +121,25 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (
 		Path:   "/upd.php",
 	}
 	values := url.Values{}
-	values.Set("user", p.username)
-	values.Set("pwd", p.password)
+	if p.key != "" {
+		values.Set("key", p.key)
+	} else {
+		values.Set("user", p.username)
+		values.Set("pwd", p.password)
+	}
 	values.Set("host", utils.BuildURLQueryHostname(p.host, p.domain))
-	if !p.useProviderIP {
-		ipKey := "ip"
-		if p.dualStack && ip.To4() == nil { // ipv6 update for dual stack
-			ipKey = "ip6"
+	switch {
+	case p.useProviderIP:
+	case p.dualStack:
+		ipv4, ipv6 := p.dualStackIPs(ip)
+		if ipv4 != nil {
+			values.Set("ip", ipv4.String())
 		}
-		values.Set(ipKey, ip.String())
+		if ipv6 != nil {
+			values.Set("ip6", ipv6.String())
+		}
+	default:
+		values.Set("ip", ip.String())
 	}
 	u.RawQuery = values.Encode()
 
@@ -149,8 +174,33 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (
 	case strings.Contains(s, constants.Notfqdn):
 		return nil, errors.ErrHostnameNotExists
 	case strings.Contains(s, "Updated 1 hostname"):
+		if p.dualStack {
+			p.setLastIP(ip)
+		}
 		return ip, nil
 	default:
 		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
 	}
 }
+
+// dualStackIPs returns the IPv4 and IPv6 addresses to send for a dual
+// stack record, which are the IP address given and the last address
+// updated of the other IP family, if any.
+func (p *Provider) dualStackIPs(ip net.IP) (ipv4, ipv6 net.IP) {
+	p.lastIPsMutex.Lock()
+	defer p.lastIPsMutex.Unlock()
+	if ip.To4() != nil {
+		return ip, p.lastIPv6
+	}
+	return p.lastIPv4, ip
+}
+
+func (p *Provider) setLastIP(ip net.IP) {
+	p.lastIPsMutex.Lock()
+	defer p.lastIPsMutex.Unlock()
+	if ip.To4() != nil {
+		p.lastIPv4 = ip
+	} else {
+		p.lastIPv6 = ip
+	}
+}
@@ -0,0 +1,62 @@
+package ddnss
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"username": "user", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: "Updated 1 hostname."},
+			}
+		},
+	})
+}
+
+func Test_Provider_Update_dualStack(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"key": "updatekey", "dual_stack": true}`)
+	provider, err := New(data, "domain.com", "home", ipversion.IP4or6)
+	require.NoError(t, err)
+
+	registrar := providertest.NewRegistrar()
+	defer registrar.Close()
+	registrar.Script(providertest.Response{Body: "Updated 1 hostname."})
+
+	ipv4 := net.IPv4(203, 0, 113, 1)
+	_, err = provider.Update(context.Background(), registrar.Client(), ipv4)
+	require.NoError(t, err)
+	ipv6 := net.ParseIP("2001:db8::1")
+	_, err = provider.Update(context.Background(), registrar.Client(), ipv6)
+	require.NoError(t, err)
+
+	requests := registrar.Requests()
+	require.Len(t, requests, 2)
+	first := requests[0].URL.Query()
+	assert.Equal(t, "updatekey", first.Get("key"))
+	assert.Empty(t, first.Get("user"))
+	assert.Equal(t, "203.0.113.1", first.Get("ip"))
+	assert.False(t, first.Has("ip6"))
+	second := requests[1].URL.Query()
+	assert.Equal(t, "203.0.113.1", second.Get("ip"))
+	assert.Equal(t, "2001:db8::1", second.Get("ip6"))
+}
@@ -0,0 +1,91 @@
+package digitalocean
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+// ListRecords lists the A and AAAA records of the domain.
+// See https://docs.digitalocean.com/reference/api/api-reference/#operation/domains_list_records
+func (p *Provider) ListRecords(ctx context.Context, client *http.Client) (
+	records []models.ZoneRecord, err error) {
+	for page := 1; ; page++ {
+		pageRecords, lastPage, err := p.listRecordsPage(ctx, client, page)
+		if err != nil {
+			return nil, fmt.Errorf("listing records page %d: %w", page, err)
+		}
+		records = append(records, pageRecords...)
+		if lastPage {
+			return records, nil
+		}
+	}
+}
+
+func (p *Provider) listRecordsPage(ctx context.Context, client *http.Client, page int) (
+	records []models.ZoneRecord, lastPage bool, err error) {
+	values := url.Values{}
+	values.Set("page", strconv.Itoa(page))
+	values.Set("per_page", "200")
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.digitalocean.com",
+		Path:     "/v2/domains/" + p.domain + "/records",
+		RawQuery: values.Encode(),
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("%w: %d: %s",
+			errors.ErrBadHTTPStatus, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var result struct {
+		DomainRecords []struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+			Data string `json:"data"`
+		} `json:"domain_records"`
+		Links struct {
+			Pages struct {
+				Next string `json:"next"`
+			} `json:"pages"`
+		} `json:"links"`
+	}
+	if err = decoder.Decode(&result); err != nil {
+		return nil, false, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	for _, record := range result.DomainRecords {
+		if record.Type != constants.A && record.Type != constants.AAAA {
+			continue
+		}
+		records = append(records, models.ZoneRecord{
+			Host: record.Name, // relative name, "@" for the domain itself
+			Type: record.Type,
+			IP:   net.ParseIP(record.Data),
+		})
+	}
+	return records, result.Links.Pages.Next == "", nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -22,12 +23,14 @@ type Provider struct {
 	host      string
 	ipVersion ipversion.IPVersion
 	token     string
+	ttl       uint
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion) (p *Provider, err error) {
 	extraSettings := struct {
 		Token string `json:"token"`
+		TTL   uint   `json:"ttl"`
 	}{}
 	if err := json.Unmarshal(data, &extraSettings); err != nil {
 		return nil, err
@@ -37,6 +40,7 @@ func New(data json.RawMessage, domain, host string,
 		host:      host,
 		ipVersion: ipVersion,
 		token:     extraSettings.Token,
+		ttl:       extraSettings.TTL,
 	}
 	if err := p.isValid(); err != nil {
 		return nil, err
@@ -45,8 +49,13 @@ func New(data json.RawMessage, domain, host string,
 }
 
 func (p *Provider) isValid() error {
-	if len(p.token) == 0 {
+	// minTTL is the minimum TTL in seconds accepted by DigitalOcean.
+	const minTTL = 30
+	switch {
+	case len(p.token) == 0:
 		return errors.ErrEmptyToken
+	case p.ttl != 0 && p.ttl < minTTL:
+		return fmt.Errorf("%w: %d must be at least %d", errors.ErrTTLTooLow, p.ttl, minTTL)
 	}
 	return nil
 }
@@ -55,6 +64,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.DigitalOcean, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DigitalOcean
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.digitalocean.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -71,6 +88,10 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -145,15 +166,23 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (
 		recordType = constants.AAAA
 	}
 
-	recordID, err := p.getRecordID(ctx, recordType, client)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errors.ErrGetRecordID, err)
-	}
-
+	// Update the existing record, or create it if it does not exist.
+	method := http.MethodPut
+	expectedStatus := http.StatusOK
 	u := url.URL{
 		Scheme: "https",
 		Host:   "api.digitalocean.com",
-		Path:   fmt.Sprintf("/v2/domains/%s/records/%d", p.domain, recordID),
+	}
+	recordID, err := p.getRecordID(ctx, recordType, client)
+	switch {
+	case goerrors.Is(err, errors.ErrNotFound):
+		method = http.MethodPost
+		expectedStatus = http.StatusCreated
+		u.Path = fmt.Sprintf("/v2/domains/%s/records", p.domain)
+	case err != nil:
+		return nil, fmt.Errorf("%s: %w", errors.ErrGetRecordID, err)
+	default:
+		u.Path = fmt.Sprintf("/v2/domains/%s/records/%d", p.domain, recordID)
 	}
 
 	buffer := bytes.NewBuffer(nil)
@@ -162,16 +191,18 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (
 		Type string `json:"type"`
 		Name string `json:"name"`
 		Data string `json:"data"`
+		TTL  uint   `json:"ttl,omitempty"`
 	}{
 		Type: recordType,
 		Name: p.host,
 		Data: ip.String(),
+		TTL:  p.ttl,
 	}
 	if err := encoder.Encode(requestData); err != nil {
 		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), buffer)
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +214,7 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
+	if response.StatusCode != expectedStatus {
 		return nil, fmt.Errorf("%w: %d: %s",
 			errors.ErrBadHTTPStatus, response.StatusCode, utils.BodyToSingleLine(response.Body))
 	}
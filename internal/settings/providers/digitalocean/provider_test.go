@@ -0,0 +1,132 @@
+package digitalocean
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"empty token": {
+			data:       `{}`,
+			errWrapped: errors.ErrEmptyToken,
+		},
+		"TTL below minimum": {
+			data:       `{"token": "token", "ttl": 29}`,
+			errWrapped: errors.ErrTTLTooLow,
+		},
+		"TTL at minimum": {
+			data: `{"token": "token", "ttl": 30}`,
+		},
+		"TTL unset": {
+			data: `{"token": "token"}`,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "home", ipversion.IP4)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, data string) *Provider {
+		t.Helper()
+		provider, err := New(json.RawMessage(data), "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newProvider(t, `{"token": "token"}`)
+	}
+	check := func(bodies ...string) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			require.Len(t, requests, len(bodies))
+			for i, request := range requests {
+				assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+				if bodies[i] == "" {
+					assert.Empty(t, request.Body)
+				} else {
+					assert.JSONEq(t, bodies[i], request.Body)
+				}
+			}
+		}
+	}
+	const listRequest = "GET /v2/domains/domain.com/records?name=home.domain.com&type=A"
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"domain_records": [{"id": 1}]}`},
+				{Body: `{"domain_record": {"data": "` + reportedIP + `"}}`},
+			}
+		},
+		ReportsIP: true,
+		Scenarios: map[string]providertest.Scenario{
+			"replaced": {
+				Updater: newProvider(t, `{"token": "token", "ttl": 60}`),
+				Responses: []providertest.Response{
+					{Body: `{"domain_records": [{"id": 123}]}`},
+					{Body: `{"domain_record": {"data": "203.0.113.1"}}`},
+				},
+				Requests: []string{
+					listRequest,
+					"PUT /v2/domains/domain.com/records/123",
+				},
+				Check: check("", `{"type":"A","name":"home","data":"203.0.113.1","ttl":60}`),
+			},
+			"created": {
+				Responses: []providertest.Response{
+					{Body: `{"domain_records": []}`},
+					{Status: http.StatusCreated, Body: `{"domain_record": {"data": "203.0.113.1"}}`},
+				},
+				Requests: []string{
+					listRequest,
+					"POST /v2/domains/domain.com/records",
+				},
+				Check: check("", `{"type":"A","name":"home","data":"203.0.113.1"}`),
+			},
+			"created with unexpected status": {
+				Responses: []providertest.Response{
+					{Body: `{"domain_records": []}`},
+					{Body: `{"domain_record": {"data": "203.0.113.1"}}`},
+				},
+				Requests: []string{
+					listRequest,
+					"POST /v2/domains/domain.com/records",
+				},
+				ErrWrapped: errors.ErrBadHTTPStatus,
+				Check:      check("", `{"type":"A","name":"home","data":"203.0.113.1"}`),
+			},
+			"list error": {
+				Responses: []providertest.Response{
+					{Status: http.StatusUnauthorized, Body: `{"id": "unauthorized"}`},
+				},
+				Requests:   []string{listRequest},
+				ErrWrapped: errors.ErrBadHTTPStatus,
+				Check:      check(""),
+			},
+		},
+	})
+}
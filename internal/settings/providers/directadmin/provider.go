@@ -0,0 +1,272 @@
+package directadmin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	serverURL *url.URL
+	username  string
+	loginKey  string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		ServerURL string `json:"server_url"`
+		Username  string `json:"username"`
+		LoginKey  string `json:"login_key"`
+		TTL       uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	if extraSettings.ServerURL == "" {
+		return nil, errors.ErrEmptyURL
+	}
+	serverURL, err := url.Parse(extraSettings.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an http or https URL",
+			errors.ErrMalformedURL, extraSettings.ServerURL)
+	}
+
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		serverURL: serverURL,
+		username:  extraSettings.Username,
+		loginKey:  extraSettings.LoginKey,
+		ttl:       ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.loginKey == "":
+		return errors.ErrEmptyKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.DirectAdmin, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.DirectAdmin
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.serverURL.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.directadmin.com/\">DirectAdmin</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetAccept(request, "application/json")
+	request.SetBasicAuth(p.username, p.loginKey)
+}
+
+type dnsRecord struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Combined string `json:"combined"`
+}
+
+// Using https://docs.directadmin.com/developer/api/legacy-api.html
+// An existing record is designated with its name and value encoded
+// as a query string, in a field named after the record type.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	var data struct {
+		Records []dnsRecord `json:"records"`
+	}
+	err = p.doRequest(ctx, client, nil, &data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	values := url.Values{
+		"action": {"add"},
+		"type":   {recordType},
+		"name":   {p.recordName()},
+		"value":  {ip.String()},
+		"ttl":    {strconv.FormatUint(uint64(p.ttl), 10)},
+	}
+	intermediaryErr := errors.ErrCreateRecord
+	for _, record := range data.Records {
+		if record.Type != recordType || !p.matchesName(record.Name) {
+			continue
+		}
+		if recordIP := net.ParseIP(record.Value); recordIP != nil && recordIP.Equal(ip) {
+			return ip, nil
+		}
+		values.Set("action", "edit")
+		values.Set(strings.ToLower(recordType)+"recs0", record.Combined)
+		intermediaryErr = errors.ErrUpdateRecord
+		break
+	}
+
+	err = p.doRequest(ctx, client, values, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", intermediaryErr, err)
+	}
+	return ip, nil
+}
+
+// recordName returns the name of the record relative to the zone,
+// or the fully qualified zone name for the root of the domain.
+func (p *Provider) recordName() string {
+	if p.host == "@" {
+		return p.domain + "."
+	}
+	return p.host
+}
+
+// matchesName returns true if the record name, which can be relative
+// to the zone or fully qualified, designates the host.
+func (p *Provider) matchesName(name string) bool {
+	if strings.HasSuffix(name, ".") {
+		return strings.EqualFold(strings.TrimSuffix(name, "."), p.BuildDomainName())
+	}
+	return strings.EqualFold(name, p.host)
+}
+
+// doRequest lists the records of the domain if the form values are nil,
+// or posts the form values otherwise. The JSON response is decoded into
+// the response data if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	values url.Values, responseData interface{}) (err error) {
+	u := *p.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/CMD_API_DNS_CONTROL"
+	u.RawQuery = url.Values{"domain": {p.domain}, "json": {"yes"}}.Encode()
+
+	method := http.MethodGet
+	var body io.Reader
+	if values != nil {
+		method = http.MethodPost
+		body = strings.NewReader(values.Encode())
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+	if values != nil {
+		headers.SetContentType(request, "application/x-www-form-urlencoded")
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Error  string `json:"error"`
+		Result string `json:"result"`
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	if result.Error != "" {
+		return fmt.Errorf("%w: %s: %s", errors.ErrUnsuccessfulResponse, result.Error, result.Result)
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.Unmarshal(b, responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
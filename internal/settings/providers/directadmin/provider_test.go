@@ -0,0 +1,130 @@
+package directadmin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const successBody = `{"success": "Record Added", "result": ""}`
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, host string) *Provider {
+		t.Helper()
+		data := json.RawMessage(`{"server_url": "https://da.example.com:2222", "username": "user", "login_key": "key"}`)
+		provider, err := New(data, "domain.com", host, ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newProvider(t, "home")
+	}
+	responses := func(records, response string) []providertest.Response {
+		return []providertest.Response{
+			{Body: `{"records": ` + records + `}`},
+			{Body: response},
+		}
+	}
+	const listRequest = "GET /CMD_API_DNS_CONTROL?domain=domain.com&json=yes"
+	const changeRequest = "POST /CMD_API_DNS_CONTROL?domain=domain.com&json=yes"
+	check := func(form url.Values) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			username, password, ok := (&http.Request{Header: requests[0].Header}).BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "user", username)
+			assert.Equal(t, "key", password)
+			if form == nil {
+				return
+			}
+			actualForm, err := url.ParseQuery(requests[1].Body)
+			require.NoError(t, err)
+			assert.Equal(t, form, actualForm)
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return responses(`[]`, successBody)
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"record added": {
+				Updater: newProvider(t, "@"),
+				Responses: responses(`[{"name": "home", "type": "A", "value": "203.0.113.2",`+
+					` "combined": "name=home&value=203.0.113.2"}]`, successBody),
+				Requests: []string{listRequest, changeRequest},
+				Check: check(url.Values{
+					"action": {"add"}, "type": {"A"}, "name": {"domain.com."},
+					"value": {"203.0.113.1"}, "ttl": {"300"},
+				}),
+			},
+			"record edited": {
+				Responses: responses(`[{"name": "home", "type": "A", "value": "203.0.113.2",`+
+					` "combined": "name=home&value=203.0.113.2"}]`, successBody),
+				Requests: []string{listRequest, changeRequest},
+				Check: check(url.Values{
+					"action": {"edit"}, "type": {"A"}, "name": {"home"}, "value": {"203.0.113.1"},
+					"ttl": {"300"}, "arecs0": {"name=home&value=203.0.113.2"},
+				}),
+			},
+			"record up to date": {
+				Responses: responses(`[{"name": "home.domain.com.", "type": "A", "value": "203.0.113.1"}]`, ""),
+				Requests:  []string{listRequest},
+				Check:     check(nil),
+			},
+			"error response": {
+				Responses:  responses(`[]`, `{"error": "Cannot add record", "result": "Invalid value"}`),
+				Requests:   []string{listRequest, changeRequest},
+				ErrWrapped: errors.ErrCreateRecord,
+				Check: check(url.Values{
+					"action": {"add"}, "type": {"A"}, "name": {"home"},
+					"value": {"203.0.113.1"}, "ttl": {"300"},
+				}),
+			},
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server_url": "https://da.example.com:2222", "username": "user", "login_key": "key"}`,
+		},
+		"empty URL": {
+			data:       `{"username": "user", "login_key": "key"}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"empty username": {
+			data:       `{"server_url": "https://da.example.com:2222", "login_key": "key"}`,
+			errWrapped: errors.ErrEmptyUsername,
+		},
+		"empty login key": {
+			data:       `{"server_url": "https://da.example.com:2222", "username": "user"}`,
+			errWrapped: errors.ErrEmptyKey,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -73,6 +73,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.DNSOMatic, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DNSOMatic
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"updates.dnsomatic.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
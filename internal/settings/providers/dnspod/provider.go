@@ -56,6 +56,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.DNSPod, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DNSPod
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dnsapi.cn"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
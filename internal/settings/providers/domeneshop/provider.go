@@ -0,0 +1,279 @@
+package domeneshop
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	token     string
+	secret    string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Token  string `json:"token"`
+		Secret string `json:"secret"`
+		TTL    uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		token:     extraSettings.Token,
+		secret:    extraSettings.Secret,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.token == "":
+		return errors.ErrEmptyToken
+	case p.secret == "":
+		return errors.ErrEmptySecret
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Domeneshop, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Domeneshop
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.domeneshop.no"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://domene.shop/\">Domeneshop</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.SetBasicAuth(p.token, p.secret)
+}
+
+func makeURL(path string, values url.Values) string {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "api.domeneshop.no",
+		Path:     "/v0" + path,
+		RawQuery: values.Encode(),
+	}
+	return u.String()
+}
+
+type record struct {
+	ID   int    `json:"id,omitempty"`
+	Host string `json:"host"`
+	TTL  uint   `json:"ttl,omitempty"`
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+// Using https://api.domeneshop.no/docs/
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	domainID, err := p.getDomainID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetDomainID, err)
+	}
+
+	recordID, err := p.getRecordID(ctx, client, domainID, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetRecordID, err)
+	}
+
+	newRecord := record{
+		Host: p.host,
+		TTL:  p.ttl,
+		Type: recordType,
+		Data: ip.String(),
+	}
+	path := "/domains/" + strconv.Itoa(domainID) + "/dns"
+	method := http.MethodPost // create a record
+	if recordID != 0 {
+		path += "/" + strconv.Itoa(recordID)
+		method = http.MethodPut // update the existing record
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(newRecord); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, makeURL(path, nil), buffer)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		if recordID == 0 {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+func (p *Provider) getDomainID(ctx context.Context, client *http.Client) (
+	domainID int, err error) {
+	values := url.Values{}
+	values.Set("domain", p.domain)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, makeURL("/domains", values), nil)
+	if err != nil {
+		return 0, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		return 0, err
+	}
+
+	var domains []struct {
+		ID     int    `json:"id"`
+		Domain string `json:"domain"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&domains); err != nil {
+		return 0, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	for _, domain := range domains {
+		if strings.EqualFold(domain.Domain, p.domain) {
+			return domain.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", errors.ErrDomainIDNotFound, p.domain)
+}
+
+// getRecordID returns the ID of the record of the host and
+// record type given, or 0 if the record does not exist.
+func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
+	domainID int, recordType string) (recordID int, err error) {
+	values := url.Values{}
+	values.Set("host", p.host)
+	values.Set("type", recordType)
+	path := "/domains/" + strconv.Itoa(domainID) + "/dns"
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, makeURL(path, values), nil)
+	if err != nil {
+		return 0, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		return 0, err
+	}
+
+	var records []record
+	if err := json.NewDecoder(response.Body).Decode(&records); err != nil {
+		return 0, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	for _, existing := range records {
+		if existing.Host == p.host && existing.Type == recordType {
+			return existing.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+func checkStatus(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
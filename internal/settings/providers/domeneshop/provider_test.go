@@ -0,0 +1,32 @@
+package domeneshop
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"token": "token", "secret": "secret"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `[{"id":1,"domain":"domain.com"}]`},
+				{Body: `[{"id":2,"host":"home","ttl":3600,"type":"A","data":"1.2.3.4"}]`},
+				{Status: http.StatusNoContent},
+			}
+		},
+	})
+}
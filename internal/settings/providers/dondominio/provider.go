@@ -59,10 +59,11 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case len(p.name) == 0:
-		return errors.ErrEmptyName
-	case p.host != "@":
-		return errors.ErrHostOnlyAt
+	case len(p.name) > 0:
+		// glue records can only be set on the domain itself
+		capabilities := constants.ProviderHostCapabilities(constants.DonDominio)
+		capabilities.ApexOnly = true
+		return utils.ValidateHost(constants.DonDominio, capabilities, p.host)
 	}
 	return nil
 }
@@ -71,6 +72,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.DonDominio, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DonDominio
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"simple-api.dondominio.net"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -106,7 +115,17 @@ func (p *Provider) setHeaders(request *http.Request) {
 	headers.SetAccept(request, "application/json")
 }
 
+// Update updates the glue record of the name server if the name
+// setting is set, and otherwise updates the A or AAAA record of the
+// host in the DNS zone of the domain.
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	if p.name == "" {
+		return p.updateZone(ctx, client, ip)
+	}
+	return p.updateGlueRecord(ctx, client, ip)
+}
+
+func (p *Provider) updateGlueRecord(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "simple-api.dondominio.net",
@@ -0,0 +1,126 @@
+package dondominio
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{"username": "u", "password": "p", "name": "ns1"}`),
+		"domain.com", "home", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrHostOnlyAt)
+	assert.EqualError(t, err, `host can only be "@": provider dondominio does not support the host "home"`)
+
+	_, err = New(json.RawMessage(`{"username": "u", "password": "p", "name": "ns1"}`),
+		"domain.com", "@", ipversion.IP4)
+	assert.NoError(t, err)
+
+	_, err = New(json.RawMessage(`{"username": "u", "password": "p"}`),
+		"domain.com", "home", ipversion.IP4)
+	assert.NoError(t, err)
+}
+
+func Test_Provider_Update_zone(t *testing.T) {
+	t.Parallel()
+
+	const listResponse = `{"success": true, "responseData": {"dns": [
+		{"entityID": "1", "name": "domain.com", "type": "A", "value": "203.0.113.1"},
+		{"entityID": "2", "name": "home.domain.com", "type": "A", "value": "203.0.113.9"}
+	]}}`
+
+	testCases := map[string]struct {
+		host       string
+		responses  []providertest.Response
+		paths      []string
+		forms      []url.Values
+		errWrapped error
+	}{
+		"record updated": {
+			host: "home",
+			responses: []providertest.Response{
+				{Body: listResponse},
+				{Body: `{"success": true}`},
+			},
+			paths: []string{"/domain/dnslist/", "/domain/dnsupdate/"},
+			forms: []url.Values{
+				{"filterType": {"A"}},
+				{"entityID": {"2"}, "value": {"203.0.113.1"}},
+			},
+		},
+		"record created": {
+			host: "new",
+			responses: []providertest.Response{
+				{Body: listResponse},
+				{Body: `{"success": true}`},
+			},
+			paths: []string{"/domain/dnslist/", "/domain/dnscreate/"},
+			forms: []url.Values{
+				{"filterType": {"A"}},
+				{"name": {"new.domain.com"}, "type": {"A"}, "value": {"203.0.113.1"}},
+			},
+		},
+		"up to date": {
+			host:      "@",
+			responses: []providertest.Response{{Body: listResponse}},
+			paths:     []string{"/domain/dnslist/"},
+			forms:     []url.Values{{"filterType": {"A"}}},
+		},
+		"unsuccessful": {
+			host: "home",
+			responses: []providertest.Response{
+				{Body: `{"success": false, "errorCode": 1000, "errorCodeMsg": "Invalid credentials"}`},
+			},
+			paths:      []string{"/domain/dnslist/"},
+			forms:      []url.Values{{"filterType": {"A"}}},
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(testCase.responses...)
+
+			data := json.RawMessage(`{"username": "user", "password": "password"}`)
+			provider, err := New(data, "domain.com", testCase.host, ipversion.IP4)
+			require.NoError(t, err)
+
+			ip := net.IPv4(203, 0, 113, 1)
+			newIP, err := provider.Update(context.Background(), registrar.Client(), ip)
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, ip.Equal(newIP))
+			}
+
+			requests := registrar.Requests()
+			require.Len(t, requests, len(testCase.paths))
+			for i, request := range requests {
+				assert.Equal(t, testCase.paths[i], request.URL.Path)
+				form, err := url.ParseQuery(request.Body)
+				require.NoError(t, err)
+				expected := testCase.forms[i]
+				expected.Set("apiuser", "user")
+				expected.Set("apipasswd", "password")
+				expected.Set("domain", "domain.com")
+				assert.Equal(t, expected, form)
+			}
+		})
+	}
+}
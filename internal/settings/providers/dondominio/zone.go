@@ -0,0 +1,132 @@
+package dondominio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+type dnsRecord struct {
+	EntityID string `json:"entityID"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+}
+
+// updateZone updates the record of the host in the DNS zone of
+// the domain, creating it if it does not exist.
+// See https://dev.dondominio.com/api/docs/api/#dns-zone-list-domain-dnslist
+func (p *Provider) updateZone(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	fqdn := utils.BuildURLQueryHostname(p.host, p.domain)
+
+	values := url.Values{}
+	values.Set("filterType", recordType)
+	var listData struct {
+		DNS []dnsRecord `json:"dns"`
+	}
+	err = p.doRequest(ctx, client, "/domain/dnslist/", values, &listData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errors.ErrListRecords, err)
+	}
+
+	var existing *dnsRecord
+	for i, record := range listData.DNS {
+		if record.Type == recordType && strings.TrimSuffix(record.Name, ".") == fqdn {
+			existing = &listData.DNS[i]
+			break
+		}
+	}
+
+	switch {
+	case existing == nil:
+		values = url.Values{}
+		values.Set("name", fqdn)
+		values.Set("type", recordType)
+		values.Set("value", ip.String())
+		err = p.doRequest(ctx, client, "/domain/dnscreate/", values, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", errors.ErrCreateRecord, err)
+		}
+	case existing.Value != ip.String():
+		values = url.Values{}
+		values.Set("entityID", existing.EntityID)
+		values.Set("value", ip.String())
+		err = p.doRequest(ctx, client, "/domain/dnsupdate/", values, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", errors.ErrUpdateRecord, err)
+		}
+	}
+	return ip, nil
+}
+
+// doRequest sends the values given with the credentials and domain
+// to the API path given, and decodes the response data into
+// responseData if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	path string, values url.Values, responseData interface{}) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "simple-api.dondominio.net",
+		Path:   path,
+	}
+	values.Set("apiuser", p.username)
+	values.Set("apipasswd", p.password)
+	values.Set("domain", p.domain)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(),
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrBadHTTPStatus, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := json.NewDecoder(response.Body)
+	var data struct {
+		Success          bool            `json:"success"`
+		ErrorCode        int             `json:"errorCode"`
+		ErrorCodeMessage string          `json:"errorCodeMsg"`
+		ResponseData     json.RawMessage `json:"responseData"`
+	}
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	if !data.Success {
+		return fmt.Errorf("%w: %s (error code %d)",
+			errors.ErrUnsuccessfulResponse, data.ErrorCodeMessage, data.ErrorCode)
+	} else if responseData == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(data.ResponseData, responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
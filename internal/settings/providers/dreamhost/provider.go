@@ -62,6 +62,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Dreamhost, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Dreamhost
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.dreamhost.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
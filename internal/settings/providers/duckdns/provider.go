@@ -53,10 +53,6 @@ func (p *Provider) isValid() error {
 	if !p.matcher.DuckDNSToken(p.token) {
 		return errors.ErrMalformedToken
 	}
-	switch p.host {
-	case "@", "*":
-		return errors.ErrHostOnlySubdomain
-	}
 	return nil
 }
 
@@ -64,6 +60,14 @@ func (p *Provider) String() string {
 	return utils.ToString("duckdns.org", p.host, constants.DuckDNS, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DuckDNS
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"www.duckdns.org"}
+}
+
 func (p *Provider) Domain() string {
 	return "duckdns.org"
 }
@@ -0,0 +1,29 @@
+package duckdns
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"token": "00000000-0000-0000-0000-000000000000"}`)
+		provider, err := New(data, "duckdns.org", "home", ipversion.IP4, regex.NewMatcher())
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{{Body: "OK\n" + reportedIP + "\n\nUPDATED"}}
+		},
+		ReportsIP: true,
+	})
+}
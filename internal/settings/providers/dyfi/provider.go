@@ -0,0 +1,225 @@
+package dyfi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// refreshPeriod is the period to update hosts at, even if their IP
+// address did not change. dy.fi releases hosts not updated for 7 days
+// and asks to update them every 5 days, but not more often if the IP
+// address did not change.
+const refreshPeriod = 5 * 24 * time.Hour
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	username  string
+	password  string
+	// last holds the last IP address updated and its update
+	// time, to not update the same IP address too often.
+	lastMutex  sync.Mutex
+	lastIP     net.IP
+	lastUpdate time.Time
+	timeNow    func() time.Time
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	if ipVersion == ipversion.IP6 {
+		return p, errors.ErrIPv6NotSupported
+	}
+	extraSettings := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		username:  extraSettings.Username,
+		password:  extraSettings.Password,
+		timeNow:   time.Now,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.DyFi, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.DyFi
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"www.dy.fi"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.dy.fi/\">dy.fi</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// RefreshPeriod returns the maximum duration between two updates
+// of the host, for the host to not be released by dy.fi.
+func (p *Provider) RefreshPeriod() time.Duration {
+	return refreshPeriod
+}
+
+// Update updates the host to the IP address the request comes from,
+// since dy.fi does not accept an IP address parameter.
+// See https://www.dy.fi/page/clients
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	if ip.To4() == nil {
+		return nil, errors.ErrIPv6NotSupported
+	}
+
+	now := p.timeNow()
+	if p.updatedRecently(ip, now) {
+		// dy.fi considers updates of the same IP address
+		// more often than every 5 days as abuse.
+		return ip, nil
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "www.dy.fi",
+		Path:   "/nic/update",
+		User:   url.UserPassword(p.username, p.password),
+	}
+	values := url.Values{}
+	values.Set("hostname", p.BuildDomainName())
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	headers.SetUserAgent(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	s := strings.TrimSpace(string(b))
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, utils.ToSingleLine(s))
+	default:
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.ToSingleLine(s))
+	}
+
+	switch {
+	case s == "":
+		return nil, errors.ErrNoResultReceived
+	case strings.HasPrefix(s, constants.Badauth):
+		return nil, errors.ErrAuth
+	case strings.HasPrefix(s, constants.Nohost), strings.HasPrefix(s, constants.Notfqdn):
+		return nil, errors.ErrHostnameNotExists
+	case strings.HasPrefix(s, constants.Abuse):
+		return nil, errors.ErrAbuse
+	case strings.HasPrefix(s, "badrequest"):
+		return nil, errors.ErrBadRequest
+	case strings.HasPrefix(s, constants.Nineoneone), strings.HasPrefix(s, "dnserr"):
+		return nil, fmt.Errorf("%w: %s", errors.ErrDNSServerSide, s)
+	case strings.HasPrefix(s, "nochg"):
+		newIP = ip
+	case strings.HasPrefix(s, "good"):
+		// The response is in the form "good 1.2.3.4"
+		fields := strings.Fields(s)
+		const expectedFields = 2
+		if len(fields) < expectedFields {
+			return nil, errors.ErrNoIPInResponse
+		}
+		newIP = net.ParseIP(fields[1])
+		if newIP == nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, fields[1])
+		} else if !ip.Equal(newIP) {
+			return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMismatch, newIP)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
+	}
+
+	p.setLast(newIP, now)
+	return newIP, nil
+}
+
+func (p *Provider) updatedRecently(ip net.IP, now time.Time) bool {
+	p.lastMutex.Lock()
+	defer p.lastMutex.Unlock()
+	return ip.Equal(p.lastIP) && now.Sub(p.lastUpdate) < refreshPeriod
+}
+
+func (p *Provider) setLast(ip net.IP, now time.Time) {
+	p.lastMutex.Lock()
+	defer p.lastMutex.Unlock()
+	p.lastIP = ip
+	p.lastUpdate = now
+}
@@ -0,0 +1,79 @@
+package dyfi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"username": "user@example.com", "password": "password"}`)
+		provider, err := New(data, "dy.fi", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: "good " + reportedIP},
+			}
+		},
+		ReportsIP: true,
+	})
+}
+
+func Test_Provider_Update_recentlyUpdated(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"username": "user@example.com", "password": "password"}`)
+	provider, err := New(data, "dy.fi", "home", ipversion.IP4)
+	require.NoError(t, err)
+	now := time.Unix(0, 0)
+	provider.timeNow = func() time.Time { return now }
+
+	registrar := providertest.NewRegistrar()
+	defer registrar.Close()
+	registrar.Script(providertest.Response{Body: "nochg"})
+
+	ip := net.IPv4(203, 0, 113, 1)
+	update := func() {
+		t.Helper()
+		newIP, err := provider.Update(context.Background(), registrar.Client(), ip)
+		require.NoError(t, err)
+		assert.True(t, newIP.Equal(ip))
+	}
+
+	update()
+	now = now.Add(24 * time.Hour)
+	update() // same IP address updated a day ago, no request sent
+	assert.Len(t, registrar.Requests(), 1)
+
+	now = now.Add(refreshPeriod)
+	update()
+	assert.Len(t, registrar.Requests(), 2)
+
+	ip = net.IPv4(203, 0, 113, 2)
+	update() // IP address changed
+	assert.Len(t, registrar.Requests(), 3)
+}
+
+func Test_New_ipv6(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"username": "user", "password": "password"}`)
+	_, err := New(data, "dy.fi", "home", ipversion.IP6)
+	assert.ErrorIs(t, err, errors.ErrIPv6NotSupported)
+}
@@ -0,0 +1,105 @@
+package dyndns2
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"server": "dyndns.example.com", "username": "user", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	scenario := func(response providertest.Response, errWrapped error) providertest.Scenario {
+		return providertest.Scenario{
+			Responses:  []providertest.Response{response},
+			Requests:   []string{"GET /nic/update?hostname=home.domain.com&myip=203.0.113.1"},
+			ErrWrapped: errWrapped,
+			Check: func(t *testing.T, requests []providertest.Request) {
+				assert.Equal(t, "dyndns.example.com", requests[0].URL.Host)
+				username, password, ok := (&http.Request{Header: requests[0].Header}).BasicAuth()
+				assert.True(t, ok)
+				assert.Equal(t, "user", username)
+				assert.Equal(t, "password", password)
+			},
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{{Body: "good " + reportedIP}}
+		},
+		ReportsIP: true,
+		Scenarios: map[string]providertest.Scenario{
+			"good":                scenario(providertest.Response{Body: "good 203.0.113.1"}, nil),
+			"nochg without IP":    scenario(providertest.Response{Body: "nochg\n"}, nil),
+			"badauth":             scenario(providertest.Response{Body: "badauth"}, errors.ErrAuth),
+			"unauthorized status": scenario(providertest.Response{Status: http.StatusUnauthorized, Body: "badauth"}, errors.ErrAuth),
+			"nohost":              scenario(providertest.Response{Body: "nohost"}, errors.ErrHostnameNotExists),
+			"notfqdn":             scenario(providertest.Response{Body: "notfqdn"}, errors.ErrHostnameNotExists),
+			"abuse":               scenario(providertest.Response{Body: "abuse"}, errors.ErrAbuse),
+			"badagent":            scenario(providertest.Response{Body: "badagent"}, errors.ErrBannedUserAgent),
+			"numhost":             scenario(providertest.Response{Body: "numhost"}, errors.ErrBadRequest),
+			"911":                 scenario(providertest.Response{Body: "911"}, errors.ErrDNSServerSide),
+			"dnserr":              scenario(providertest.Response{Body: "dnserr"}, errors.ErrDNSServerSide),
+			"empty":               scenario(providertest.Response{Body: ""}, errors.ErrNoResultReceived),
+			"unknown":             scenario(providertest.Response{Body: "what"}, errors.ErrUnknownResponse),
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server": "router.lan:8080", "path": "update", "scheme": "http",` +
+				` "username": "user", "password": "password"}`,
+		},
+		"empty server": {
+			data:       `{"username": "user", "password": "password"}`,
+			errWrapped: errors.ErrEmptyHost,
+		},
+		"server with scheme": {
+			data:       `{"server": "https://dyndns.example.com", "username": "user", "password": "password"}`,
+			errWrapped: errors.ErrMalformedURL,
+		},
+		"bad scheme": {
+			data:       `{"server": "dyndns.example.com", "scheme": "ftp", "username": "user", "password": "password"}`,
+			errWrapped: errors.ErrMalformedURL,
+		},
+		"empty username": {
+			data:       `{"server": "dyndns.example.com", "password": "password"}`,
+			errWrapped: errors.ErrEmptyUsername,
+		},
+		"empty password": {
+			data:       `{"server": "dyndns.example.com", "username": "user"}`,
+			errWrapped: errors.ErrEmptyPassword,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -65,8 +65,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case p.password == "":
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -75,6 +73,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Dynu, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Dynu
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.dynu.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
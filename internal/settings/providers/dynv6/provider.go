@@ -9,6 +9,7 @@ import (
 	"net/url"
 
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
 	"github.com/qdm12/ddns-updater/internal/settings/errors"
 	"github.com/qdm12/ddns-updater/internal/settings/headers"
 	"github.com/qdm12/ddns-updater/internal/settings/utils"
@@ -49,8 +50,6 @@ func (p *Provider) isValid() error {
 	switch {
 	case len(p.token) == 0:
 		return errors.ErrEmptyToken
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -59,6 +58,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: DynV6]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.DynV6
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"ipv4.dynv6.com", "ipv6.dynv6.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -0,0 +1,230 @@
+package easydns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	token     string
+	key       string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Token string `json:"token"`
+		Key   string `json:"key"`
+		TTL   uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		token:     extraSettings.Token,
+		key:       extraSettings.Key,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.token == "":
+		return errors.ErrEmptyToken
+	case p.key == "":
+		return errors.ErrEmptyKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.EasyDNS, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.EasyDNS
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"rest.easydns.net"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://easydns.com/\">EasyDNS</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.SetBasicAuth(p.token, p.key)
+}
+
+// Using https://rest.easydns.net/ documented at https://docs.sandbox.rest.easydns.net/
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	recordID, found, err := p.getRecordID(ctx, client, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetRecordID, err)
+	}
+
+	if !found {
+		err = p.createRecord(ctx, client, recordType, ip)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+		return ip, nil
+	}
+
+	err = p.updateRecord(ctx, client, recordID, recordType, ip)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+func (p *Provider) makeURL(path string) string {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "rest.easydns.net",
+		Path:     path,
+		RawQuery: url.Values{"format": []string{"json"}}.Encode(),
+	}
+	return u.String()
+}
+
+func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
+	recordType string) (recordID string, found bool, err error) {
+	response, err := p.doRequest(ctx, client, http.MethodGet,
+		p.makeURL("/zones/records/all/"+p.domain), nil)
+	if err != nil {
+		return "", false, err
+	}
+	defer response.Body.Close()
+
+	var data struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Host string `json:"host"`
+			Type string `json:"type"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return "", false, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	for _, record := range data.Data {
+		if record.Host == p.host && record.Type == recordType {
+			return record.ID, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+type recordData struct {
+	Host  string `json:"host"`
+	Type  string `json:"type"`
+	Rdata string `json:"rdata"`
+	TTL   string `json:"ttl"`
+	Prio  string `json:"prio"`
+}
+
+func (p *Provider) makeRecordData(recordType string, ip net.IP) recordData {
+	const defaultTTL = 300
+	ttl := p.ttl
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	return recordData{
+		Host:  p.host,
+		Type:  recordType,
+		Rdata: ip.String(),
+		TTL:   fmt.Sprint(ttl),
+		Prio:  "0",
+	}
+}
+
+func (p *Provider) createRecord(ctx context.Context, client *http.Client,
+	recordType string, ip net.IP) (err error) {
+	body, err := json.Marshal(p.makeRecordData(recordType, ip))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestMarshal, err)
+	}
+
+	urlString := p.makeURL("/zones/records/add/" + p.domain + "/" + recordType)
+	response, err := p.doRequest(ctx, client, http.MethodPut, urlString, body)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
+	recordID, recordType string, ip net.IP) (err error) {
+	body, err := json.Marshal(p.makeRecordData(recordType, ip))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestMarshal, err)
+	}
+
+	urlString := p.makeURL("/zones/records/" + recordID)
+	response, err := p.doRequest(ctx, client, http.MethodPost, urlString, body)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
@@ -0,0 +1,51 @@
+package easydns
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"token": "token", "key": "key"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"data":[{"id":"123","host":"home","type":"A","rdata":"1.2.3.4"}]}`},
+				{Body: `{"msg":"OK","status":200}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"rate limit retried": {
+				Responses: []providertest.Response{
+					{
+						Status: http.StatusTooManyRequests,
+						Header: http.Header{"Retry-After": []string{"0"}},
+					},
+					{Body: `{"data":[]}`},
+					{Status: http.StatusCreated, Body: `{"msg":"OK","status":201}`},
+				},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					require.Len(t, requests, 3)
+					assert.Equal(t, http.MethodGet, requests[1].Method)
+					assert.Equal(t, http.MethodPut, requests[2].Method)
+					assert.Equal(t, "/zones/records/add/domain.com/A", requests[2].URL.Path)
+				},
+			},
+		},
+	})
+}
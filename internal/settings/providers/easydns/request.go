@@ -0,0 +1,78 @@
+package easydns
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+const (
+	// maxRetries is the maximum number of retries of a
+	// request rate limited by EasyDNS.
+	maxRetries = 3
+	// initialBackoff is the wait duration before the first
+	// retry, doubled on each retry, if EasyDNS gives no
+	// Retry-After header.
+	initialBackoff = time.Second
+	// maxBackoff is the longest wait before a retry. If EasyDNS
+	// asks to wait longer, the update fails as rate limited.
+	maxBackoff = 10 * time.Second
+)
+
+// doRequest sends the request and returns its response if it has
+// a success status code. Rate limited requests are retried with an
+// exponential backoff, since the EasyDNS API has strict rate limits.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	method, urlString string, body []byte) (response *http.Response, err error) {
+	backoff := initialBackoff
+	for retry := 0; ; retry++ {
+		request, err := http.NewRequestWithContext(ctx, method, urlString, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		p.setHeaders(request)
+
+		response, err = client.Do(request)
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode == http.StatusOK || response.StatusCode == http.StatusCreated {
+			return response, nil
+		}
+
+		message := utils.BodyToSingleLine(response.Body)
+		_ = response.Body.Close()
+		switch response.StatusCode {
+		case http.StatusTooManyRequests:
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return nil, fmt.Errorf("%w: %s", errors.ErrAuth, message)
+		default:
+			return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+				response.StatusCode, message)
+		}
+
+		wait := backoff
+		if retryAfter, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+			wait = time.Duration(retryAfter) * time.Second
+		}
+		if retry == maxRetries || wait > maxBackoff {
+			return nil, fmt.Errorf("%w: %s", errors.ErrAbuse, message)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,250 @@
+package epik
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	signature string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Signature string `json:"signature"`
+		TTL       uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		signature: extraSettings.Signature,
+		ttl:       ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.signature == "" {
+		return errors.ErrEmptyKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Epik, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Epik
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"usersapiv2.epik.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.epik.com/\">Epik</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+}
+
+type dnsRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+// Using https://docs-userapi.epik.com/v2/
+// A record with the IP address is created before deleting the previous
+// records, to not leave the host without a record in between.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	recordsPath := "/v2/domains/" + p.domain + "/records"
+
+	var data struct {
+		Data struct {
+			Records []dnsRecord `json:"records"`
+		} `json:"data"`
+	}
+	err = p.doRequest(ctx, client, http.MethodGet, recordsPath, nil, nil, &data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	var staleRecordIDs []string
+	upToDate := false
+	for _, record := range data.Data.Records {
+		if !p.matchesHost(record.Name) || record.Type != recordType {
+			continue
+		}
+		if recordIP := net.ParseIP(record.Data); recordIP != nil && recordIP.Equal(ip) {
+			upToDate = true
+			continue
+		}
+		staleRecordIDs = append(staleRecordIDs, record.ID)
+	}
+
+	if !upToDate {
+		type payload struct {
+			Host string `json:"HOST"`
+			Type string `json:"TYPE"`
+			Data string `json:"DATA"`
+			Aux  uint   `json:"AUX"`
+			TTL  uint   `json:"TTL"`
+		}
+		requestData := struct {
+			Payload payload `json:"create_host_records_payload"`
+		}{
+			Payload: payload{Host: p.host, Type: recordType, Data: ip.String(), TTL: p.ttl},
+		}
+		err = p.doRequest(ctx, client, http.MethodPost, recordsPath, nil, requestData, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+	}
+
+	for _, recordID := range staleRecordIDs {
+		query := url.Values{"ID": {recordID}}
+		err = p.doRequest(ctx, client, http.MethodDelete, recordsPath, query, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrRemoveRecord, err)
+		}
+	}
+	return ip, nil
+}
+
+// matchesHost returns true if the record name is the host,
+// where the root of the domain can be named "@" or left empty.
+func (p *Provider) matchesHost(name string) bool {
+	if p.host == "@" && name == "" {
+		return true
+	}
+	return strings.EqualFold(name, p.host)
+}
+
+// doRequest sends the request data encoded as JSON if it is not nil,
+// and decodes the JSON response into the response data if it is not nil.
+// The request is authenticated with the signature in its query parameters.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client, method, path string,
+	query url.Values, requestData, responseData interface{}) (err error) {
+	if query == nil {
+		query = make(url.Values, 1)
+	}
+	query.Set("SIGNATURE", p.signature)
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "usersapiv2.epik.com",
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package epik
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, host string) *Provider {
+		t.Helper()
+		data := json.RawMessage(`{"signature": "signature"}`)
+		provider, err := New(data, "domain.com", host, ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newProvider(t, "home")
+	}
+	responses := func(records string) []providertest.Response {
+		return []providertest.Response{
+			{Body: `{"data": {"records": ` + records + `}}`},
+			{Body: `{}`},
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return responses(`[]`)
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"record created": {
+				Responses: responses(`[{"id": "1", "name": "other", "type": "A", "data": "203.0.113.2"}]`),
+				Requests: []string{
+					"GET /v2/domains/domain.com/records?SIGNATURE=signature",
+					"POST /v2/domains/domain.com/records?SIGNATURE=signature",
+				},
+			},
+			"stale record replaced": {
+				Responses: responses(`[{"id": "1", "name": "home", "type": "A", "data": "203.0.113.2"},` +
+					`{"id": "2", "name": "home", "type": "AAAA", "data": "2001:db8::1"}]`),
+				Requests: []string{
+					"GET /v2/domains/domain.com/records?SIGNATURE=signature",
+					"POST /v2/domains/domain.com/records?SIGNATURE=signature",
+					"DELETE /v2/domains/domain.com/records?ID=1&SIGNATURE=signature",
+				},
+			},
+			"root record up to date": {
+				Updater:   newProvider(t, "@"),
+				Responses: responses(`[{"id": "1", "name": "", "type": "A", "data": "203.0.113.1"}]`),
+				Requests:  []string{"GET /v2/domains/domain.com/records?SIGNATURE=signature"},
+			},
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{}`), "domain.com", "@", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrEmptyKey)
+}
@@ -0,0 +1,164 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	// command is the program path followed by its arguments,
+	// in which placeholders are replaced on each update.
+	command []string
+	timeout time.Duration
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Command []string `json:"command"`
+		Timeout string   `json:"timeout"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	const defaultTimeout = 30 * time.Second
+	timeout := defaultTimeout
+	if extraSettings.Timeout != "" {
+		timeout, err = time.ParseDuration(extraSettings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: timeout: %s", errors.ErrMalformedDuration, err)
+		} else if timeout <= 0 {
+			return nil, fmt.Errorf("%w: timeout %s must be positive",
+				errors.ErrMalformedDuration, extraSettings.Timeout)
+		}
+	}
+
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		command:   extraSettings.Command,
+		timeout:   timeout,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if len(p.command) == 0 || p.command[0] == "" {
+		return errors.ErrEmptyCommand
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Exec, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Exec
+}
+
+func (p *Provider) APIHosts() []string {
+	return nil // the command does not use the program HTTP client
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "Command",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Update runs the command with the update information given as
+// placeholders in its arguments and as environment variables.
+// The update is successful if the command exits with the code 0.
+// The HTTP client is not used.
+func (p *Provider) Update(ctx context.Context, _ *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	variables := map[string]string{
+		"ip":          ip.String(),
+		"record_type": recordType,
+		"domain":      p.domain,
+		"host":        p.host,
+		"fqdn":        p.BuildDomainName(),
+	}
+
+	oldNew := make([]string, 0, 2*len(variables)) //nolint:gomnd
+	environment := os.Environ()
+	for key, value := range variables {
+		oldNew = append(oldNew, "{"+key+"}", value)
+		environment = append(environment, "DDNS_"+strings.ToUpper(key)+"="+value)
+	}
+	replacer := strings.NewReplacer(oldNew...)
+
+	args := make([]string, len(p.command)-1)
+	for i, arg := range p.command[1:] {
+		args[i] = replacer.Replace(arg)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.command[0], args...) //nolint:gosec
+	cmd.Env = environment
+	output := bytes.NewBuffer(nil)
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	err = cmd.Run()
+	switch {
+	case ctx.Err() != nil:
+		return nil, fmt.Errorf("%w: after %s: %s", ctx.Err(), p.timeout,
+			utils.ToSingleLine(output.String()))
+	case err != nil:
+		return nil, fmt.Errorf("%w: %s: %s", errors.ErrUnsuccessfulResponse, err,
+			utils.ToSingleLine(output.String()))
+	}
+	return ip, nil
+}
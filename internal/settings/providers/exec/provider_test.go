@@ -0,0 +1,125 @@
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		command    []string
+		timeout    string
+		ip         net.IP
+		output     string
+		errWrapped error
+		errMessage string
+	}{
+		"arguments": {
+			command: []string{"sh", "-c", `echo "$1 $2 $3" > "$OUTPUT_FILE"`, "sh",
+				"{ip}", "{record_type}", "{fqdn}"},
+			ip:     net.IPv4(203, 0, 113, 1),
+			output: "203.0.113.1 A home.domain.com\n",
+		},
+		"environment": {
+			command: []string{"sh", "-c", `echo "$DDNS_IP $DDNS_RECORD_TYPE $DDNS_DOMAIN $DDNS_HOST" > "$OUTPUT_FILE"`},
+			ip:      net.ParseIP("2001:db8::1"),
+			output:  "2001:db8::1 AAAA domain.com home\n",
+		},
+		"non zero exit code": {
+			command:    []string{"sh", "-c", "echo failed; exit 3"},
+			ip:         net.IPv4(203, 0, 113, 1),
+			errWrapped: errors.ErrUnsuccessfulResponse,
+			errMessage: "unsuccessful response: exit status 3: failed",
+		},
+		"timeout": {
+			command:    []string{"sleep", "5"},
+			timeout:    "50ms",
+			ip:         net.IPv4(203, 0, 113, 1),
+			errWrapped: context.DeadlineExceeded,
+			errMessage: "context deadline exceeded: after 50ms: ",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			outputFile := filepath.Join(t.TempDir(), "output")
+			command := make([]string, len(testCase.command))
+			for i, arg := range testCase.command {
+				command[i] = os.Expand(arg, func(key string) string {
+					if key == "OUTPUT_FILE" {
+						return outputFile
+					}
+					return "$" + key
+				})
+			}
+			data, err := json.Marshal(map[string]interface{}{
+				"command": command,
+				"timeout": testCase.timeout,
+			})
+			require.NoError(t, err)
+			provider, err := New(data, "domain.com", "home", ipversion.IP4or6)
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), nil, testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				assert.EqualError(t, err, testCase.errMessage)
+				return
+			}
+			assert.True(t, testCase.ip.Equal(newIP))
+			output, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.output, string(output))
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"command": ["/update.sh", "{ip}"], "timeout": "1m"}`,
+		},
+		"empty command": {
+			data:       `{"command": []}`,
+			errWrapped: errors.ErrEmptyCommand,
+		},
+		"malformed timeout": {
+			data:       `{"command": ["/update.sh"], "timeout": "1"}`,
+			errWrapped: errors.ErrMalformedDuration,
+		},
+		"negative timeout": {
+			data:       `{"command": ["/update.sh"], "timeout": "-1s"}`,
+			errWrapped: errors.ErrMalformedDuration,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
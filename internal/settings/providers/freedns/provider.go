@@ -56,6 +56,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.FreeDNS, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.FreeDNS
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"sync.afraid.org", "v6.sync.afraid.org"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -58,6 +58,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Gandi, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Gandi
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dns.api.gandi.net"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -74,6 +82,10 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+func (p *Provider) TTL() uint {
+	return uint(p.ttl)
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -0,0 +1,253 @@
+package gcore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	token     string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Token string `json:"token"`
+		TTL   uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		token:     extraSettings.Token,
+		ttl:       ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.token == "" {
+		return errors.ErrEmptyToken
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Gcore, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Gcore
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.gcore.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://gcore.com/dns\">Gcore</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("Authorization", "APIKey "+p.token)
+}
+
+type rrset struct {
+	TTL             uint             `json:"ttl"`
+	ResourceRecords []resourceRecord `json:"resource_records"`
+}
+
+type resourceRecord struct {
+	Content []string `json:"content"`
+	Enabled bool     `json:"enabled"`
+}
+
+// Using https://api.gcore.com/docs/dns
+// The RRset of the host is replaced with a single resource record
+// holding the IP address, and created if it does not exist.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	zone, err := p.getZone(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetZoneID, err)
+	}
+
+	rrsetPath := "/dns/v2/zones/" + zone + "/" + p.BuildDomainName() + "/" + recordType
+	var existing rrset
+	err = p.doRequest(ctx, client, http.MethodGet, rrsetPath, nil, &existing)
+	rrsetExists := true
+	switch {
+	case goerrors.Is(err, errors.ErrNotFound):
+		rrsetExists = false
+	case err != nil:
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetRecordInZone, err)
+	case existing.hasOnly(ip):
+		return ip, nil
+	}
+
+	data := rrset{
+		TTL: p.ttl,
+		ResourceRecords: []resourceRecord{
+			{Content: []string{ip.String()}, Enabled: true},
+		},
+	}
+	if !rrsetExists {
+		err = p.doRequest(ctx, client, http.MethodPost, rrsetPath, data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+		return ip, nil
+	}
+
+	err = p.doRequest(ctx, client, http.MethodPut, rrsetPath, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+// hasOnly returns true if the RRset contains a single
+// resource record with the IP address as content.
+func (r rrset) hasOnly(ip net.IP) bool {
+	if len(r.ResourceRecords) != 1 || len(r.ResourceRecords[0].Content) != 1 {
+		return false
+	}
+	recordIP := net.ParseIP(r.ResourceRecords[0].Content[0])
+	return recordIP != nil && recordIP.Equal(ip)
+}
+
+// getZone returns the name of the zone of the domain as known by Gcore.
+func (p *Provider) getZone(ctx context.Context, client *http.Client) (zone string, err error) {
+	var data struct {
+		Name string `json:"name"`
+	}
+	err = p.doRequest(ctx, client, http.MethodGet, "/dns/v2/zones/"+p.domain, nil, &data)
+	switch {
+	case goerrors.Is(err, errors.ErrNotFound):
+		return "", fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+	case err != nil:
+		return "", err
+	case data.Name == "":
+		return "", fmt.Errorf("%w: no zone name", errors.ErrUnknownResponse)
+	}
+	return strings.TrimSuffix(data.Name, "."), nil
+}
+
+// doRequest sends the request data encoded as JSON if it is not nil,
+// and decodes the JSON response into the response data if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	method, path string, requestData, responseData interface{}) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.gcore.com",
+		Path:   path,
+	}
+
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package gcore
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const zoneBody = `{"id": 1, "name": "domain.com"}`
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	const rrsetPath = "/dns/v2/zones/domain.com/home.domain.com/A"
+	checkAuthorization := func(t *testing.T, requests []providertest.Request) {
+		for _, request := range requests {
+			assert.Equal(t, "APIKey token", request.Header.Get("Authorization"))
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: zoneBody},
+				{Status: http.StatusNotFound, Body: `{"error": "record is not found"}`},
+				{Body: `{}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"zone not found": {
+				Responses: []providertest.Response{
+					{Status: http.StatusNotFound, Body: `{"error": "zone is not found"}`},
+				},
+				Requests:   []string{"GET /dns/v2/zones/domain.com"},
+				ErrWrapped: errors.ErrGetZoneID,
+				Check:      checkAuthorization,
+			},
+			"rrset created": {
+				Responses: []providertest.Response{
+					{Body: zoneBody},
+					{Status: http.StatusNotFound, Body: `{"error": "record is not found"}`},
+					{Body: `{}`},
+				},
+				Requests: []string{
+					"GET /dns/v2/zones/domain.com",
+					"GET " + rrsetPath,
+					"POST " + rrsetPath,
+				},
+				Check: checkAuthorization,
+			},
+			"rrset updated": {
+				Responses: []providertest.Response{
+					{Body: zoneBody},
+					{Body: `{"ttl": 300, "resource_records": [{"content": ["203.0.113.2"]}]}`},
+					{Body: `{}`},
+				},
+				Requests: []string{
+					"GET /dns/v2/zones/domain.com",
+					"GET " + rrsetPath,
+					"PUT " + rrsetPath,
+				},
+				Check: checkAuthorization,
+			},
+			"rrset up to date": {
+				Responses: []providertest.Response{
+					{Body: zoneBody},
+					{Body: `{"ttl": 300, "resource_records": [{"content": ["203.0.113.1"]}]}`},
+				},
+				Requests: []string{
+					"GET /dns/v2/zones/domain.com",
+					"GET " + rrsetPath,
+				},
+				Check: checkAuthorization,
+			},
+		},
+	})
+}
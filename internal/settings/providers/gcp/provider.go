@@ -70,6 +70,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.GCP, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.GCP
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dns.googleapis.com", "oauth2.googleapis.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net"
@@ -25,6 +26,7 @@ type Provider struct {
 	ipVersion ipversion.IPVersion
 	key       string
 	secret    string
+	ttl       uint
 	matcher   common.Matcher
 }
 
@@ -33,6 +35,7 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 	extraSettings := struct {
 		Key    string `json:"key"`
 		Secret string `json:"secret"`
+		TTL    uint   `json:"ttl"`
 	}{}
 	if err := json.Unmarshal(data, &extraSettings); err != nil {
 		return nil, err
@@ -43,6 +46,7 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 		ipVersion: ipVersion,
 		key:       extraSettings.Key,
 		secret:    extraSettings.Secret,
+		ttl:       extraSettings.TTL,
 		matcher:   matcher,
 	}
 	if err := p.isValid(); err != nil {
@@ -51,12 +55,17 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 	return p, nil
 }
 
+// minTTL is the minimum TTL in seconds accepted by GoDaddy.
+const minTTL = 600
+
 func (p *Provider) isValid() error {
 	switch {
 	case !p.matcher.GodaddyKey(p.key):
 		return errors.ErrMalformedKey
 	case len(p.secret) == 0:
 		return errors.ErrEmptySecret
+	case p.ttl != 0 && p.ttl < minTTL:
+		return fmt.Errorf("%w: %d must be at least %d", errors.ErrTTLTooLow, p.ttl, minTTL)
 	}
 	return nil
 }
@@ -65,6 +74,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.GoDaddy, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.GoDaddy
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.godaddy.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -81,6 +98,10 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -101,57 +122,98 @@ func (p *Provider) setHeaders(request *http.Request) {
 	headers.SetAccept(request, "application/json")
 }
 
+type record struct {
+	Type string `json:"type,omitempty"`
+	Name string `json:"name,omitempty"`
+	Data string `json:"data"` // IP address to update to
+	TTL  uint   `json:"ttl,omitempty"`
+}
+
+// Update replaces the record, and creates it if it does not exist.
+// See https://developer.godaddy.com/doc/endpoint/domains#/v1/recordReplaceTypeName
+// and https://developer.godaddy.com/doc/endpoint/domains#/v1/recordAdd
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
 	recordType := constants.A
 	if ip.To4() == nil {
 		recordType = constants.AAAA
 	}
-	type goDaddyPutBody struct {
-		Data string `json:"data"` // IP address to update to
+
+	path := fmt.Sprintf("/v1/domains/%s/records/%s/%s", p.domain, recordType, p.host)
+	requestData := []record{{Data: ip.String(), TTL: p.ttl}}
+	err = p.doRequest(ctx, client, http.MethodPut, path, requestData)
+	switch {
+	case err == nil:
+		return ip, nil
+	case !stderrors.Is(err, errors.ErrNotFound):
+		return nil, fmt.Errorf("%s: %w", errors.ErrUpdateRecord, err)
+	}
+
+	path = fmt.Sprintf("/v1/domains/%s/records", p.domain)
+	requestData = []record{{
+		Type: recordType,
+		Name: p.host,
+		Data: ip.String(),
+		TTL:  p.ttl,
+	}}
+	err = p.doRequest(ctx, client, http.MethodPatch, path, requestData)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errors.ErrCreateRecord, err)
 	}
+	return ip, nil
+}
+
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	method, path string, requestData interface{}) (err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "api.godaddy.com",
-		Path:   fmt.Sprintf("/v1/domains/%s/records/%s/%s", p.domain, recordType, p.host),
+		Path:   path,
 	}
 
 	buffer := bytes.NewBuffer(nil)
 	encoder := json.NewEncoder(buffer)
-	requestData := []goDaddyPutBody{
-		{Data: ip.String()},
-	}
 	if err := encoder.Encode(requestData); err != nil {
-		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), buffer)
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	p.setHeaders(request)
 
 	response, err := client.Do(request)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer response.Body.Close()
 
 	if response.StatusCode == http.StatusOK {
-		return ip, nil
+		return nil
 	}
 
 	b, err := io.ReadAll(response.Body)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
 	}
 
-	err = fmt.Errorf("%w: %d", errors.ErrBadHTTPStatus, response.StatusCode)
+	message := utils.ToSingleLine(string(b))
 	var parsedJSON struct {
 		Message string `json:"message"`
 	}
 	jsonErr := json.Unmarshal(b, &parsedJSON)
-	if jsonErr != nil || len(parsedJSON.Message) == 0 {
-		return nil, fmt.Errorf("%w: %s", err, utils.ToSingleLine(string(b)))
+	if jsonErr == nil && len(parsedJSON.Message) > 0 {
+		message = parsedJSON.Message
+	}
+
+	switch response.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, message)
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrNotFound, message)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, message)
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus, response.StatusCode, message)
 	}
-	return nil, fmt.Errorf("%w: %s", err, parsedJSON.Message)
 }
@@ -0,0 +1,87 @@
+package godaddy
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{"key": "dLP4WKz5PdkS_GuUDNigHcLQFpw4CWNwAQ5", "secret": "secret", "ttl": 300}`),
+		"domain.com", "home", ipversion.IP4, regex.NewMatcher())
+	assert.ErrorIs(t, err, errors.ErrTTLTooLow)
+
+	_, err = New(json.RawMessage(`{"key": "dLP4WKz5PdkS_GuUDNigHcLQFpw4CWNwAQ5", "secret": "secret", "ttl": 600}`),
+		"domain.com", "home", ipversion.IP4, regex.NewMatcher())
+	assert.NoError(t, err)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, data string) *Provider {
+		t.Helper()
+		provider, err := New(json.RawMessage(data), "domain.com", "home",
+			ipversion.IP4, regex.NewMatcher())
+		require.NoError(t, err)
+		return provider
+	}
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newProvider(t, `{"key": "dLP4WKz5PdkS_GuUDNigHcLQFpw4CWNwAQ5", "secret": "secret"}`)
+	}
+	check := func(bodies ...string) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			require.Len(t, requests, len(bodies))
+			for i, request := range requests {
+				assert.Equal(t, "sso-key dLP4WKz5PdkS_GuUDNigHcLQFpw4CWNwAQ5:secret", request.Header.Get("Authorization"))
+				assert.JSONEq(t, bodies[i], request.Body)
+			}
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{{}}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"replaced": {
+				Updater:   newProvider(t, `{"key": "dLP4WKz5PdkS_GuUDNigHcLQFpw4CWNwAQ5", "secret": "secret", "ttl": 3600}`),
+				Responses: []providertest.Response{{}},
+				Requests:  []string{"PUT /v1/domains/domain.com/records/A/home"},
+				Check:     check(`[{"data":"203.0.113.1","ttl":3600}]`),
+			},
+			"created": {
+				Responses: []providertest.Response{
+					{Status: http.StatusNotFound, Body: `{"code": "NOT_FOUND", "message": "record not found"}`},
+					{},
+				},
+				Requests: []string{
+					"PUT /v1/domains/domain.com/records/A/home",
+					"PATCH /v1/domains/domain.com/records",
+				},
+				Check: check(
+					`[{"data":"203.0.113.1"}]`,
+					`[{"type":"A","name":"home","data":"203.0.113.1"}]`,
+				),
+			},
+			"bad credentials": {
+				Responses: []providertest.Response{
+					{Status: http.StatusUnauthorized, Body: `{"code": "UNABLE_TO_AUTHENTICATE", "message": "bad key"}`},
+				},
+				Requests:   []string{"PUT /v1/domains/domain.com/records/A/home"},
+				ErrWrapped: errors.ErrAuth,
+				Check:      check(`[{"data":"203.0.113.1"}]`),
+			},
+		},
+	})
+}
@@ -66,6 +66,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Google, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Google
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"domains.google.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
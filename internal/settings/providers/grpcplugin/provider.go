@@ -0,0 +1,160 @@
+package grpcplugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/plugin"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	name      string
+	settings  map[string]interface{}
+	client    *plugin.Client
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Plugin   string                 `json:"plugin"`
+		Settings map[string]interface{} `json:"settings"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		name:      extraSettings.Plugin,
+		settings:  extraSettings.Settings,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.name == "" {
+		return fmt.Errorf("%w: plugin name is not set", errors.ErrEmptyName)
+	}
+	client, ok := plugins.get(p.name)
+	if !ok {
+		return fmt.Errorf("%w: %s", errors.ErrUnknownPlugin, p.name)
+	}
+	p.client = client
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Plugin, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Plugin
+}
+
+func (p *Provider) APIHosts() []string {
+	return nil // the plugin does not use the program HTTP client
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  models.HTML("Plugin " + p.name),
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Update calls the plugin over gRPC. The HTTP client is not used
+// since plugins do their own networking.
+func (p *Provider) Update(ctx context.Context, _ *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	request := plugin.UpdateRequest{
+		Domain:     p.domain,
+		Host:       p.host,
+		FQDN:       p.BuildDomainName(),
+		IP:         ip.String(),
+		RecordType: recordType,
+		Settings:   p.settings,
+	}
+
+	response, err := p.client.Update(ctx, request)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	if response.IP == "" {
+		return ip, nil
+	}
+	newIP = net.ParseIP(response.IP)
+	if newIP == nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, response.IP)
+	}
+	return newIP, nil
+}
+
+// convertError maps gRPC status codes returned by the plugin
+// to the errors used by the other providers.
+func convertError(err error) error {
+	grpcStatus, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	message := grpcStatus.Message()
+	switch grpcStatus.Code() {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, message)
+	case codes.ResourceExhausted:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, message)
+	case codes.NotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, message)
+	case codes.InvalidArgument:
+		return fmt.Errorf("%w: %s", errors.ErrBadRequest, message)
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, message)
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, message)
+	default:
+		return fmt.Errorf("%w: %s: %s", errors.ErrUnsuccessfulResponse,
+			grpcStatus.Code(), message)
+	}
+}
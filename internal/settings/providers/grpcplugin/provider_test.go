@@ -0,0 +1,185 @@
+package grpcplugin
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/plugin"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testServer struct {
+	name     string
+	response plugin.UpdateResponse
+	err      error
+	requests chan plugin.UpdateRequest
+}
+
+func (s *testServer) Describe(context.Context) (plugin.Description, error) {
+	return plugin.Description{Name: s.name}, nil
+}
+
+func (s *testServer) Update(_ context.Context, request plugin.UpdateRequest) (
+	plugin.UpdateResponse, error) {
+	s.requests <- request
+	return s.response, s.err
+}
+
+// startPlugin serves the test server on a unix socket in a
+// temporary directory, and returns that directory.
+func startPlugin(t *testing.T, server *testServer) (dir string) {
+	t.Helper()
+	dir = t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- plugin.Serve(ctx, filepath.Join(dir, server.name+".sock"), server)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	require.Eventually(t, func() bool {
+		connection, err := net.Dial("unix", filepath.Join(dir, server.name+".sock"))
+		if err != nil {
+			return false
+		}
+		_ = connection.Close()
+		return true
+	}, time.Second, time.Millisecond)
+	return dir
+}
+
+func Test_registry_discover(t *testing.T) {
+	t.Parallel()
+
+	server := &testServer{name: "discovered"}
+	dir := startPlugin(t, server)
+
+	registry := newRegistry()
+	names, warnings, err := registry.discover(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+	assert.Equal(t, []string{"discovered"}, names)
+	_, ok := registry.get("discovered")
+	assert.True(t, ok)
+
+	// Same plugin name from a second discovery
+	_, warnings, err = registry.discover(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "plugin name conflict")
+
+	err = registry.close()
+	require.NoError(t, err)
+	_, ok = registry.get("discovered")
+	assert.False(t, ok)
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{}`), "domain.com", "@", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrEmptyName)
+
+	_, err = New(json.RawMessage(`{"plugin": "not-loaded"}`), "domain.com", "@", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrUnknownPlugin)
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name     string
+		response plugin.UpdateResponse
+		err      error
+		ip       net.IP
+		newIP    net.IP
+		errWrap  error
+	}{
+		"success": {
+			name:  "update-success",
+			ip:    net.IPv4(203, 0, 113, 1),
+			newIP: net.IPv4(203, 0, 113, 1),
+		},
+		"ip returned": {
+			name:     "update-ip-returned",
+			response: plugin.UpdateResponse{IP: "203.0.113.2"},
+			ip:       net.IPv4(203, 0, 113, 1),
+			newIP:    net.IPv4(203, 0, 113, 2),
+		},
+		"malformed ip returned": {
+			name:     "update-malformed-ip",
+			response: plugin.UpdateResponse{IP: "x"},
+			ip:       net.IPv4(203, 0, 113, 1),
+			errWrap:  errors.ErrIPReceivedMalformed,
+		},
+		"unauthenticated": {
+			name:    "update-unauthenticated",
+			err:     status.Error(codes.Unauthenticated, "bad token"),
+			ip:      net.IPv4(203, 0, 113, 1),
+			errWrap: errors.ErrAuth,
+		},
+		"resource exhausted": {
+			name:    "update-resource-exhausted",
+			err:     status.Error(codes.ResourceExhausted, "slow down"),
+			ip:      net.IPv4(203, 0, 113, 1),
+			errWrap: errors.ErrAbuse,
+		},
+		"internal": {
+			name:    "update-internal",
+			err:     status.Error(codes.Internal, "oops"),
+			ip:      net.IPv4(203, 0, 113, 1),
+			errWrap: errors.ErrUnsuccessfulResponse,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &testServer{
+				name:     testCase.name,
+				response: testCase.response,
+				err:      testCase.err,
+				requests: make(chan plugin.UpdateRequest, 1),
+			}
+			dir := startPlugin(t, server)
+			_, _, err := Discover(context.Background(), dir)
+			require.NoError(t, err)
+
+			data := json.RawMessage(`{"plugin": "` + testCase.name + `", "settings": {"token": "abc"}}`)
+			provider, err := New(data, "domain.com", "home", ipversion.IP4)
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), nil, testCase.ip)
+			if testCase.errWrap != nil {
+				assert.ErrorIs(t, err, testCase.errWrap)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, testCase.newIP.Equal(newIP))
+			}
+
+			request := <-server.requests
+			assert.Equal(t, plugin.UpdateRequest{
+				Domain:     "domain.com",
+				Host:       "home",
+				FQDN:       "home.domain.com",
+				IP:         "203.0.113.1",
+				RecordType: "A",
+				Settings:   map[string]interface{}{"token": "abc"},
+			}, request)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package grpcplugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/pkg/plugin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var errPluginNameConflict = errors.New("plugin name conflict")
+
+// plugins is shared by all plugin providers, and is filled
+// at program start by Discover before settings are read.
+var plugins = newRegistry() //nolint:gochecknoglobals
+
+type registry struct {
+	clients     map[string]*plugin.Client
+	connections []*grpc.ClientConn
+	mutex       sync.RWMutex
+}
+
+func newRegistry() *registry {
+	return &registry{
+		clients: make(map[string]*plugin.Client),
+	}
+}
+
+func (r *registry) get(name string) (client *plugin.Client, ok bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	client, ok = r.clients[name]
+	return client, ok
+}
+
+// Discover connects to each unix socket file ending in .sock in
+// the directory given, and registers each plugin under the name it
+// describes itself with. Plugins failing to connect or describe
+// themselves are skipped and reported as warnings.
+func Discover(ctx context.Context, dir string) (names, warnings []string, err error) {
+	return plugins.discover(ctx, dir)
+}
+
+// Close closes the connections to all discovered plugins.
+func Close() (err error) {
+	return plugins.close()
+}
+
+func (r *registry) discover(ctx context.Context, dir string) (names, warnings []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading plugins directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".sock") ||
+			entry.Type()&os.ModeSocket == 0 {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		name, err := r.load(ctx, path)
+		if err != nil {
+			warnings = append(warnings, "plugin "+path+": "+err.Error())
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, warnings, nil
+}
+
+func (r *registry) load(ctx context.Context, socketPath string) (name string, err error) {
+	const timeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	connection, err := grpc.DialContext(ctx, "unix://"+socketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock())
+	if err != nil {
+		return "", fmt.Errorf("connecting: %w", err)
+	}
+
+	client := plugin.NewClient(connection)
+	description, err := client.Describe(ctx)
+	if err != nil {
+		_ = connection.Close()
+		return "", fmt.Errorf("describing: %w", err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.clients[description.Name]; exists {
+		_ = connection.Close()
+		return "", fmt.Errorf("%w: %s is already registered", errPluginNameConflict, description.Name)
+	}
+	r.clients[description.Name] = client
+	r.connections = append(r.connections, connection)
+	return description.Name, nil
+}
+
+func (r *registry) close() (err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, connection := range r.connections {
+		closeErr := connection.Close()
+		if err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}
+	r.connections = nil
+	r.clients = make(map[string]*plugin.Client)
+	return err
+}
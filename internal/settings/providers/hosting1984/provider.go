@@ -0,0 +1,308 @@
+package hosting1984
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// sessions is shared by all 1984 Hosting records, such that records
+// with the same credentials reuse the same session cookie.
+var sessions = session.NewCache(time.Now) //nolint:gochecknoglobals
+
+// sessionLifetime is the duration a session cookie is reused for
+// since its last use, which is below the account session timeout.
+const sessionLifetime = 15 * time.Minute
+
+// sessionCookieName is the name of the cookie set on login.
+const sessionCookieName = "sessionid"
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	username  string
+	password  string
+	ttl       uint
+	sessions  *session.Cache
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		TTL      uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 3600
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		username:  extraSettings.Username,
+		password:  extraSettings.Password,
+		ttl:       ttl,
+		sessions:  sessions,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Hosting1984, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Hosting1984
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"management.1984.is"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://1984.hosting/\">1984 Hosting</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request, sessionID string) {
+	headers.SetUserAgent(request)
+	headers.SetAccept(request, "application/json")
+	if sessionID != "" {
+		request.AddCookie(&http.Cookie{Name: sessionCookieName, Value: sessionID})
+	}
+}
+
+// Using the account API of https://management.1984.is/
+// The session cookie obtained on login authenticates the other calls.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	login := func(ctx context.Context) (sessionID string, err error) {
+		return p.login(ctx, client)
+	}
+	update := func(sessionID string) (err error) {
+		return p.setRecord(ctx, client, sessionID, recordType, ip)
+	}
+	isExpired := func(err error) bool { return goerrors.Is(err, errors.ErrAuth) }
+
+	key := session.Key(p.username, p.password)
+	err = p.sessions.Do(ctx, key, sessionLifetime, login, update, isExpired)
+	if err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+type dnsRecord struct {
+	ID      int    `json:"id"`
+	Host    string `json:"host"`
+	Type    string `json:"type"`
+	Content string `json:"content"`
+}
+
+// setRecord updates the record of the host with the record type,
+// or creates it if it does not exist.
+func (p *Provider) setRecord(ctx context.Context, client *http.Client,
+	sessionID, recordType string, ip net.IP) (err error) {
+	recordsPath := "/accountapi/domains/" + p.domain + "/records/"
+
+	var data struct {
+		Records []dnsRecord `json:"records"`
+	}
+	err = p.doRequest(ctx, client, sessionID, http.MethodGet, recordsPath, nil, &data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	values := url.Values{
+		"content": {ip.String()},
+		"ttl":     {strconv.FormatUint(uint64(p.ttl), 10)},
+	}
+	for _, record := range data.Records {
+		if !strings.EqualFold(record.Host, p.host) || record.Type != recordType {
+			continue
+		}
+		if recordIP := net.ParseIP(record.Content); recordIP != nil && recordIP.Equal(ip) {
+			return nil
+		}
+		recordPath := recordsPath + strconv.Itoa(record.ID) + "/"
+		err = p.doRequest(ctx, client, sessionID, http.MethodPost, recordPath, values, nil)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+		}
+		return nil
+	}
+
+	values.Set("host", p.host)
+	values.Set("type", recordType)
+	err = p.doRequest(ctx, client, sessionID, http.MethodPost, recordsPath, values, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+	}
+	return nil
+}
+
+func (p *Provider) login(ctx context.Context, client *http.Client) (sessionID string, err error) {
+	values := url.Values{
+		"username": {p.username},
+		"password": {p.password},
+	}
+	request, err := p.newRequest(ctx, "", http.MethodPost, "/accountapi/login/", values)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	err = checkResponse(response)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == sessionCookieName && cookie.Value != "" {
+			return cookie.Value, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no %s cookie", errors.ErrUnknownResponse, sessionCookieName)
+}
+
+// doRequest sends the form values given, if any, and decodes the
+// JSON response to the response data if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client, sessionID,
+	method, path string, values url.Values, responseData interface{}) (err error) {
+	request, err := p.newRequest(ctx, sessionID, method, path, values)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	err = checkResponse(response)
+	if err != nil {
+		return err
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
+
+func (p *Provider) newRequest(ctx context.Context, sessionID, method, path string,
+	values url.Values) (request *http.Request, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "management.1984.is",
+		Path:   path,
+	}
+
+	var body io.Reader
+	if values != nil {
+		body = strings.NewReader(values.Encode())
+	}
+
+	request, err = http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request, sessionID)
+	if values != nil {
+		headers.SetContentType(request, "application/x-www-form-urlencoded")
+	}
+	return request, nil
+}
+
+func checkResponse(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,79 @@
+package hosting1984
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loginResponse() providertest.Response {
+	return providertest.Response{
+		Header: http.Header{"Set-Cookie": {"sessionid=session; Path=/"}},
+		Body:   `{"ok": true}`,
+	}
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"username": "user", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		provider.sessions = session.NewCache(time.Now)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				loginResponse(),
+				{Body: `{"records": []}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"record updated": {
+				Responses: []providertest.Response{
+					loginResponse(),
+					{Body: `{"records": [{"id": 7, "host": "home", "type": "A", "content": "203.0.113.2"}]}`},
+				},
+				Requests: []string{
+					"POST /accountapi/login/",
+					"GET /accountapi/domains/domain.com/records/",
+					"POST /accountapi/domains/domain.com/records/7/",
+				},
+			},
+			"record created": {
+				Responses: []providertest.Response{
+					loginResponse(),
+					{Body: `{"records": [{"id": 7, "host": "home", "type": "AAAA", "content": "2001:db8::1"}]}`},
+				},
+				Requests: []string{
+					"POST /accountapi/login/",
+					"GET /accountapi/domains/domain.com/records/",
+					"POST /accountapi/domains/domain.com/records/",
+				},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					assert.Equal(t, "sessionid=session", requests[1].Header.Get("Cookie"))
+					assert.Equal(t, "content=203.0.113.1&host=home&ttl=3600&type=A", requests[2].Body)
+				},
+			},
+			"no session cookie": {
+				Responses: []providertest.Response{
+					{Body: `{"ok": true}`},
+				},
+				Requests:   []string{"POST /accountapi/login/"},
+				ErrWrapped: errors.ErrUnknownResponse,
+			},
+		},
+	})
+}
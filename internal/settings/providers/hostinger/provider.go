@@ -0,0 +1,234 @@
+package hostinger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	token     string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Token string `json:"token"`
+		TTL   uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		token:     extraSettings.Token,
+		ttl:       ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.token == "" {
+		return errors.ErrEmptyToken
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Hostinger, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Hostinger
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"developers.hostinger.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.hostinger.com/\">Hostinger</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	headers.SetAuthBearer(request, p.token)
+}
+
+type record struct {
+	Name    string    `json:"name"`
+	Type    string    `json:"type"`
+	TTL     uint      `json:"ttl"`
+	Records []content `json:"records"`
+}
+
+type content struct {
+	Content string `json:"content"`
+}
+
+// Using https://developers.hostinger.com/#tag/dns-zone
+// The records of the host with the record type are overwritten
+// with a single record holding the IP address.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	zonePath := "/api/dns/v1/zones/" + p.domain
+
+	var records []record
+	err = p.doRequest(ctx, client, http.MethodGet, zonePath, nil, &records)
+	if err != nil {
+		if goerrors.Is(err, errors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+		}
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	for _, existing := range records {
+		if strings.EqualFold(existing.Name, p.host) && existing.Type == recordType &&
+			existing.hasOnly(ip) {
+			return ip, nil
+		}
+	}
+
+	requestData := struct {
+		Overwrite bool     `json:"overwrite"`
+		Zone      []record `json:"zone"`
+	}{
+		Overwrite: true,
+		Zone: []record{{
+			Name:    p.host,
+			Type:    recordType,
+			TTL:     p.ttl,
+			Records: []content{{Content: ip.String()}},
+		}},
+	}
+	err = p.doRequest(ctx, client, http.MethodPut, zonePath, requestData, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+// hasOnly returns true if the record contains a single
+// content value being the IP address.
+func (r record) hasOnly(ip net.IP) bool {
+	if len(r.Records) != 1 {
+		return false
+	}
+	recordIP := net.ParseIP(r.Records[0].Content)
+	return recordIP != nil && recordIP.Equal(ip)
+}
+
+// doRequest sends the request data encoded as JSON if it is not nil,
+// and decodes the JSON response into the response data if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	method, path string, requestData, responseData interface{}) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "developers.hostinger.com",
+		Path:   path,
+	}
+
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package hostinger
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `[]`},
+				{Body: `{"message": "Request accepted"}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"zone not found": {
+				Responses: []providertest.Response{
+					{Status: http.StatusNotFound, Body: `{"message": "Not found"}`},
+				},
+				Requests:   []string{"GET /api/dns/v1/zones/domain.com"},
+				ErrWrapped: errors.ErrZoneNotFound,
+			},
+			"record created": {
+				Responses: []providertest.Response{
+					{Body: `[]`},
+					{Body: `{"message": "Request accepted"}`},
+				},
+				Requests: []string{
+					"GET /api/dns/v1/zones/domain.com",
+					"PUT /api/dns/v1/zones/domain.com",
+				},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					const expectedBody = `{"overwrite":true,"zone":[{"name":"home","type":"A","ttl":300,` +
+						`"records":[{"content":"203.0.113.1"}]}]}` + "\n"
+					assert.Equal(t, expectedBody, requests[1].Body)
+					assert.Equal(t, "Bearer token", requests[1].Header.Get("Authorization"))
+				},
+			},
+			"record overwritten": {
+				Responses: []providertest.Response{
+					{Body: `[{"name": "home", "type": "A", "ttl": 300, "records": [{"content": "203.0.113.2"}]}]`},
+					{Body: `{"message": "Request accepted"}`},
+				},
+				Requests: []string{
+					"GET /api/dns/v1/zones/domain.com",
+					"PUT /api/dns/v1/zones/domain.com",
+				},
+			},
+			"record up to date": {
+				Responses: []providertest.Response{
+					{Body: `[{"name": "home", "type": "A", "ttl": 300, "records": [{"content": "203.0.113.1"}]}]`},
+				},
+				Requests: []string{"GET /api/dns/v1/zones/domain.com"},
+			},
+		},
+	})
+}
@@ -0,0 +1,275 @@
+package huaweicloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain          string
+	host            string
+	ipVersion       ipversion.IPVersion
+	accessKeyID     string
+	secretAccessKey string
+	region          string
+	ttl             uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		AccessKeyID     string `json:"access_key_id"`
+		SecretAccessKey string `json:"secret_access_key"`
+		Region          string `json:"region"`
+		TTL             uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:          domain,
+		host:            host,
+		ipVersion:       ipVersion,
+		accessKeyID:     extraSettings.AccessKeyID,
+		secretAccessKey: extraSettings.SecretAccessKey,
+		region:          extraSettings.Region,
+		ttl:             ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.accessKeyID == "":
+		return errors.ErrEmptyAccessKeyID
+	case p.secretAccessKey == "":
+		return errors.ErrEmptyAccessKeySecret
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.HuaweiCloud, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.HuaweiCloud
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.apiHost()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.huaweicloud.com/intl/en-us/product/dns.html\">Huawei Cloud</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// apiHost returns the global DNS endpoint, or the
+// regional endpoint if a region is set.
+func (p *Provider) apiHost() string {
+	if p.region == "" {
+		return "dns.myhuaweicloud.com"
+	}
+	return "dns." + p.region + ".myhuaweicloud.com"
+}
+
+func (p *Provider) makeURL(path string, values url.Values) string {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     p.apiHost(),
+		Path:     path,
+		RawQuery: values.Encode(),
+	}
+	return u.String()
+}
+
+// doRequest signs and sends the request, and decodes the JSON
+// response body into the output if the output is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	method, urlString string, payload []byte, output interface{}) (err error) {
+	request, err := http.NewRequestWithContext(ctx, method, urlString, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	headers.SetUserAgent(request)
+	headers.SetAccept(request, "application/json")
+	headers.SetContentType(request, "application/json")
+	signRequest(request, payload, p.accessKeyID, p.secretAccessKey, time.Now())
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if output == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(output); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
+
+// Using https://support.huaweicloud.com/intl/en-us/api-dns/dns_api_64001.html
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	zoneID, err := p.getZoneID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetZoneID, err)
+	}
+
+	recordSetID, err := p.getRecordSetID(ctx, client, zoneID, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetRecordID, err)
+	}
+
+	payload, err := json.Marshal(recordSet{
+		Name:    p.BuildDomainName() + ".",
+		Type:    recordType,
+		TTL:     p.ttl,
+		Records: []string{ip.String()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestMarshal, err)
+	}
+
+	path := "/v2/zones/" + zoneID + "/recordsets"
+	if recordSetID == "" {
+		err = p.doRequest(ctx, client, http.MethodPost, p.makeURL(path, nil), payload, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+		return ip, nil
+	}
+
+	path += "/" + recordSetID
+	err = p.doRequest(ctx, client, http.MethodPut, p.makeURL(path, nil), payload, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+type recordSet struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     uint     `json:"ttl"`
+	Records []string `json:"records"`
+}
+
+func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (
+	zoneID string, err error) {
+	values := url.Values{}
+	values.Set("type", "public")
+	values.Set("name", p.domain+".")
+	var data struct {
+		Zones []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"zones"`
+	}
+	err = p.doRequest(ctx, client, http.MethodGet, p.makeURL("/v2/zones", values), nil, &data)
+	if err != nil {
+		return "", err
+	}
+
+	for _, zone := range data.Zones {
+		if zone.Name == p.domain+"." {
+			return zone.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+}
+
+// getRecordSetID returns the ID of the record set of the record
+// type given, or an empty string if the record set does not exist.
+func (p *Provider) getRecordSetID(ctx context.Context, client *http.Client,
+	zoneID, recordType string) (recordSetID string, err error) {
+	name := p.BuildDomainName() + "."
+	values := url.Values{}
+	values.Set("type", recordType)
+	values.Set("name", name)
+	var data struct {
+		RecordSets []recordSet `json:"recordsets"`
+	}
+	path := "/v2/zones/" + zoneID + "/recordsets"
+	err = p.doRequest(ctx, client, http.MethodGet, p.makeURL(path, values), nil, &data)
+	if err != nil {
+		return "", err
+	}
+
+	// The name filter matches record sets containing the name
+	for _, recordSet := range data.RecordSets {
+		if recordSet.Name == name && recordSet.Type == recordType {
+			return recordSet.ID, nil
+		}
+	}
+	return "", nil
+}
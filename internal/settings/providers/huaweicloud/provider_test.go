@@ -0,0 +1,32 @@
+package huaweicloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"access_key_id": "key", "secret_access_key": "secret"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"zones":[{"id":"zone","name":"domain.com."}]}`},
+				{Body: `{"recordsets":[{"id":"record","name":"home.domain.com.","type":"A","ttl":300,"records":["1.2.3.4"]}]}`},
+				{Status: http.StatusAccepted, Body: `{"id":"record"}`},
+			}
+		},
+	})
+}
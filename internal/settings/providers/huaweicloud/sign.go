@@ -0,0 +1,134 @@
+package huaweicloud
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signAlgorithm  = "SDK-HMAC-SHA256"
+	signTimeFormat = "20060102T150405Z"
+)
+
+// signRequest signs the request with the access key and secret access key
+// as documented on https://support.huaweicloud.com/intl/en-us/devg-apisign/api-sign-algorithm.html
+// The payload must be the request body, or nil if there is no body.
+func signRequest(request *http.Request, payload []byte,
+	accessKeyID, secretAccessKey string, now time.Time) {
+	sdkDate := now.UTC().Format(signTimeFormat)
+	request.Header.Set("X-Sdk-Date", sdkDate)
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI(request),
+		canonicalQuery(request),
+		canonicalHeaders,
+		signedHeaders,
+		hexSHA256(payload),
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		sdkDate,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, []byte(secretAccessKey))
+	_, _ = mac.Write([]byte(stringToSign))
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	request.Header.Set("Authorization", signAlgorithm+
+		" Access="+accessKeyID+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+// canonicalURI escapes each segment of the path and
+// adds a trailing slash to it, if it has none.
+func canonicalURI(request *http.Request) string {
+	segments := strings.Split(request.URL.Path, "/")
+	for i, segment := range segments {
+		segments[i] = escape(segment)
+	}
+	path := strings.Join(segments, "/")
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return path
+}
+
+func canonicalQuery(request *http.Request) string {
+	query := request.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, escape(key)+"="+escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// canonicalizeHeaders only signs the host, date and content type headers,
+// since other headers can be modified by intermediate round trippers.
+func canonicalizeHeaders(request *http.Request) (signedHeaders, canonicalHeaders string) {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	values := map[string]string{
+		"host":       host,
+		"x-sdk-date": request.Header.Get("X-Sdk-Date"),
+	}
+	if contentType := request.Header.Get("Content-Type"); contentType != "" {
+		values["content-type"] = contentType
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		builder.WriteString(key + ":" + strings.TrimSpace(values[key]) + "\n")
+	}
+	return strings.Join(keys, ";"), builder.String()
+}
+
+// escape percent encodes all the characters of s
+// except the unreserved characters of RFC 3986.
+func escape(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		isUnreserved := ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') ||
+			('0' <= c && c <= '9') || c == '-' || c == '_' || c == '.' || c == '~'
+		if isUnreserved {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,37 @@
+package huaweicloud
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signRequest(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"name":"www.example.com.","type":"A","ttl":300,"records":["1.2.3.4"]}`)
+	request, err := http.NewRequest(http.MethodPut,
+		"https://dns.myhuaweicloud.com/v2/zones/zone-id/recordsets/record-id?b=2&a=x%20y",
+		bytes.NewReader(payload))
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/json")
+	now := time.Date(2023, time.March, 1, 12, 30, 0, 0, time.UTC)
+
+	signRequest(request, payload, "AKEXAMPLE", "SKEXAMPLE", now)
+
+	const expectedAuthorization = "SDK-HMAC-SHA256 Access=AKEXAMPLE, " +
+		"SignedHeaders=content-type;host;x-sdk-date, " +
+		"Signature=092928d9e70e4d54daacbfea22a56f2cba205e27bb303f6fec8a17e45e532dcf"
+	assert.Equal(t, expectedAuthorization, request.Header.Get("Authorization"))
+	assert.Equal(t, "20230301T123000Z", request.Header.Get("X-Sdk-Date"))
+}
+
+func Test_escape(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a-b_c.d~e%20f%2Fg%2A", escape("a-b_c.d~e f/g*"))
+}
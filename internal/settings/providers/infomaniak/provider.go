@@ -57,8 +57,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -67,6 +65,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Infomaniak, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Infomaniak
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"infomaniak.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
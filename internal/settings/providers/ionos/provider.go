@@ -0,0 +1,230 @@
+package ionos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	apiKey    string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		APIKey string `json:"api_key"`
+		TTL    uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		apiKey:    extraSettings.APIKey,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.apiKey == "":
+		return errors.ErrEmptyAPIKey
+	case !isAPIKeyValid(p.apiKey):
+		return fmt.Errorf("%w: must be in the format prefix.secret", errors.ErrMalformedKey)
+	}
+	return nil
+}
+
+func isAPIKeyValid(apiKey string) bool {
+	prefix, secret, found := strings.Cut(apiKey, ".")
+	return found && prefix != "" && secret != ""
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Ionos, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Ionos
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.hosting.ionos.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.ionos.com/\">IONOS</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("X-API-Key", p.apiKey)
+}
+
+// Using https://developer.hosting.ionos.com/docs/dns
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	zoneID, err := p.getZoneID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetZoneID, err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.hosting.ionos.com",
+		Path:   "/dns/v1/zones/" + zoneID,
+	}
+
+	// The PATCH request replaces the records of the zone
+	// having the same name and type as the records sent.
+	type record struct {
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Content  string `json:"content"`
+		TTL      uint   `json:"ttl,omitempty"`
+		Disabled bool   `json:"disabled"`
+	}
+	requestData := []record{{
+		Name:    p.BuildDomainName(),
+		Type:    recordType,
+		Content: ip.String(),
+		TTL:     p.ttl,
+	}}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, u.String(), buffer)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+
+	// The response body is empty on success.
+	return ip, nil
+}
+
+func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (
+	zoneID string, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.hosting.ionos.com",
+		Path:   "/dns/v1/zones",
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		return "", err
+	}
+
+	var zones []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&zones); err != nil {
+		return "", fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	for _, zone := range zones {
+		if strings.EqualFold(zone.Name, p.domain) {
+			return zone.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+}
+
+func checkStatus(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,177 @@
+package joker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain        string
+	host          string
+	ipVersion     ipversion.IPVersion
+	username      string
+	password      string
+	useProviderIP bool
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Username      string `json:"username"`
+		Password      string `json:"password"`
+		UseProviderIP bool   `json:"provider_ip"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:        domain,
+		host:          host,
+		ipVersion:     ipVersion,
+		username:      extraSettings.Username,
+		password:      extraSettings.Password,
+		useProviderIP: extraSettings.UseProviderIP,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Joker, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Joker
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"svc.joker.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://joker.com/\">Joker.com</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Update uses the DynDNS2 protocol of Joker.com, with the dynamic
+// DNS credentials of the domain which are different for each domain.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "svc.joker.com",
+		Path:   "/nic/update",
+		User:   url.UserPassword(p.username, p.password),
+	}
+	values := url.Values{}
+	values.Set("hostname", utils.BuildURLQueryHostname(p.host, p.domain))
+	if !p.useProviderIP {
+		values.Set("myip", ip.String())
+	}
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	headers.SetUserAgent(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	s := strings.TrimSpace(string(b))
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, utils.ToSingleLine(s))
+	default:
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.ToSingleLine(s))
+	}
+
+	switch {
+	case s == "":
+		return nil, errors.ErrNoResultReceived
+	case strings.HasPrefix(s, constants.Badauth):
+		return nil, errors.ErrAuth
+	case strings.HasPrefix(s, constants.Nohost), strings.HasPrefix(s, constants.Notfqdn):
+		return nil, errors.ErrHostnameNotExists
+	case strings.HasPrefix(s, constants.Abuse):
+		return nil, errors.ErrAbuse
+	case strings.HasPrefix(s, constants.Nineoneone), strings.HasPrefix(s, "dnserr"):
+		return nil, fmt.Errorf("%w: %s", errors.ErrDNSServerSide, s)
+	case !strings.HasPrefix(s, "good") && !strings.HasPrefix(s, "nochg"):
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
+	}
+
+	// The response is in the form "good 1.2.3.4" or "nochg 1.2.3.4"
+	fields := strings.Fields(s)
+	const expectedFields = 2
+	if len(fields) < expectedFields {
+		return nil, errors.ErrNoIPInResponse
+	}
+	newIP = net.ParseIP(fields[1])
+	if newIP == nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, fields[1])
+	}
+	if !p.useProviderIP && !ip.Equal(newIP) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMismatch, newIP.String())
+	}
+	return newIP, nil
+}
@@ -0,0 +1,50 @@
+package joker
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"username": "user", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4or6)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: "good " + reportedIP},
+			}
+		},
+		ReportsIP: true,
+		Scenarios: map[string]providertest.Scenario{
+			"ipv6": {
+				IP:        net.ParseIP("2001:db8::1"),
+				Responses: []providertest.Response{{Body: "nochg 2001:db8::1"}},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					require.Len(t, requests, 1)
+					assert.Equal(t, "svc.joker.com", requests[0].URL.Host)
+					assert.Equal(t, "home.domain.com", requests[0].URL.Query().Get("hostname"))
+					assert.Equal(t, "2001:db8::1", requests[0].URL.Query().Get("myip"))
+					username, password, ok := (&http.Request{Header: requests[0].Header}).BasicAuth()
+					assert.True(t, ok)
+					assert.Equal(t, "user", username)
+					assert.Equal(t, "password", password)
+				},
+			},
+		},
+	})
+}
@@ -58,6 +58,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Linode, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Linode
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.linode.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
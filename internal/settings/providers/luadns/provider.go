@@ -63,6 +63,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.LuaDNS, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.LuaDNS
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.luadns.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
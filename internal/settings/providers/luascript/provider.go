@@ -0,0 +1,196 @@
+package luascript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	path      string
+	proto     *lua.FunctionProto
+	params    map[string]string
+	timeout   time.Duration
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Script  string            `json:"script"`
+		Params  map[string]string `json:"params"`
+		Timeout string            `json:"timeout"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	const defaultTimeout = 30 * time.Second
+	timeout := defaultTimeout
+	if extraSettings.Timeout != "" {
+		timeout, err = time.ParseDuration(extraSettings.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%w: timeout: %s", errors.ErrMalformedDuration, err)
+		} else if timeout <= 0 {
+			return nil, fmt.Errorf("%w: timeout %s must be positive",
+				errors.ErrMalformedDuration, extraSettings.Timeout)
+		}
+	}
+
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		path:      extraSettings.Script,
+		params:    extraSettings.Params,
+		timeout:   timeout,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+
+	p.proto, err = compile(p.path)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.path == "" {
+		return fmt.Errorf("%w: script path is not set", errors.ErrEmptyName)
+	}
+	return nil
+}
+
+// compile parses and compiles the Lua script file once,
+// such that each update only runs the compiled script.
+func compile(path string) (proto *lua.FunctionProto, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening script: %w", err)
+	}
+	defer file.Close()
+
+	chunk, err := parse.Parse(file, path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedScript, err)
+	}
+	proto, err = lua.Compile(chunk, path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedScript, err)
+	}
+	return proto, nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Lua, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Lua
+}
+
+func (p *Provider) APIHosts() []string {
+	return nil // the hosts requested by the script are unknown
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "Lua script",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Update runs the script in a new sandboxed Lua state, and calls
+// its global update function with a table describing the record.
+// The update fails if the function raises an error, or returns
+// false with an optional message.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	state := newSandbox(ctx, client)
+	defer state.Close()
+
+	state.Push(state.NewFunctionFromProto(p.proto))
+	err = state.PCall(0, 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("running script: %w", err)
+	}
+
+	update, ok := state.GetGlobal("update").(*lua.LFunction)
+	if !ok {
+		return nil, fmt.Errorf("%w: no global update function defined", errors.ErrMalformedScript)
+	}
+
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	record := state.NewTable()
+	record.RawSetString("ip", lua.LString(ip.String()))
+	record.RawSetString("record_type", lua.LString(recordType))
+	record.RawSetString("domain", lua.LString(p.domain))
+	record.RawSetString("host", lua.LString(p.host))
+	record.RawSetString("fqdn", lua.LString(p.BuildDomainName()))
+	params := state.NewTable()
+	for key, value := range p.params {
+		params.RawSetString(key, lua.LString(value))
+	}
+	record.RawSetString("params", params)
+
+	const returnValues = 2
+	err = state.CallByParam(lua.P{Fn: update, NRet: returnValues, Protect: true}, record)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%w: after %s", ctx.Err(), p.timeout)
+		}
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnsuccessfulResponse, err)
+	}
+
+	succeeded, message := state.Get(-2), state.Get(-1)
+	state.Pop(returnValues)
+	if succeeded == lua.LFalse {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnsuccessfulResponse,
+			strings.TrimSpace(lua.LVAsString(message)))
+	}
+	return ip, nil
+}
@@ -0,0 +1,153 @@
+package luascript
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, script string) (path string) {
+	t.Helper()
+	path = filepath.Join(t.TempDir(), "update.lua")
+	err := os.WriteFile(path, []byte(script), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+func newTestProvider(t *testing.T, script string) *Provider {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"script":  writeScript(t, script),
+		"params":  map[string]string{"token": "token"},
+		"timeout": "1s",
+	})
+	require.NoError(t, err)
+	provider, err := New(data, "domain.com", "home", ipversion.IP4or6)
+	require.NoError(t, err)
+	return provider
+}
+
+const httpScript = `
+function update(record)
+  local response, err = http.request{
+    method = "POST",
+    url = "https://api.example.com/records/" .. record.fqdn,
+    headers = {Authorization = "Bearer " .. record.params.token},
+    body = record.record_type .. "=" .. record.ip,
+  }
+  if response == nil then
+    error(err)
+  end
+  if response.status ~= 200 then
+    return false, "status " .. response.status .. ": " .. response.body
+  end
+end
+`
+
+func Test_Provider_Update_http(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newTestProvider(t, httpScript)
+	}
+	check := func(t *testing.T, requests []providertest.Request) {
+		require.Len(t, requests, 1)
+		assert.Equal(t, "api.example.com", requests[0].URL.Host)
+		assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+		assert.Equal(t, "AAAA=2001:db8::1", requests[0].Body)
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{{Body: "OK"}}
+		},
+		IP: net.ParseIP("2001:db8::1"),
+		Scenarios: map[string]providertest.Scenario{
+			"success": {
+				Responses: []providertest.Response{{Body: "OK"}},
+				Requests:  []string{"POST /records/home.domain.com"},
+				Check:     check,
+			},
+			"returned false": {
+				Responses:  []providertest.Response{{Status: http.StatusForbidden, Body: "denied"}},
+				Requests:   []string{"POST /records/home.domain.com"},
+				ErrWrapped: errors.ErrUnsuccessfulResponse,
+				ErrMessage: "unsuccessful response: status 403: denied",
+				Check:      check,
+			},
+		},
+	})
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		script     string
+		errWrapped error
+	}{
+		"no return value": {
+			script: `function update(record) end`,
+		},
+		"raised error": {
+			script:     `function update(record) error("cannot update") end`,
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+		"no update function": {
+			script:     `local x = 1`,
+			errWrapped: errors.ErrMalformedScript,
+		},
+		"file access removed": {
+			script:     `function update(record) dofile("/etc/passwd") end`,
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+		"os library not available": {
+			script:     `function update(record) os.execute("true") end`,
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+		"timeout": {
+			script:     `function update(record) while true do end end`,
+			errWrapped: context.DeadlineExceeded,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider := newTestProvider(t, testCase.script)
+			_, err := provider.Update(context.Background(), http.DefaultClient, net.IPv4(203, 0, 113, 1))
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{}`), "domain.com", "@", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrEmptyName)
+
+	data, err := json.Marshal(map[string]string{"script": writeScript(t, "function update(")})
+	require.NoError(t, err)
+	_, err = New(data, "domain.com", "@", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrMalformedScript)
+
+	data, err = json.Marshal(map[string]string{"script": writeScript(t, ""), "timeout": "1"})
+	require.NoError(t, err)
+	_, err = New(data, "domain.com", "@", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrMalformedDuration)
+}
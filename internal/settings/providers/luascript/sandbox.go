@@ -0,0 +1,111 @@
+package luascript
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// newSandbox returns a Lua state with only the base, string, table
+// and math libraries, without functions to load code from files,
+// and with an http module using the HTTP client given.
+func newSandbox(ctx context.Context, client *http.Client) *lua.LState {
+	state := lua.NewState(lua.Options{SkipOpenLibs: true})
+	state.SetContext(ctx)
+
+	libraries := []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{name: lua.BaseLibName, open: lua.OpenBase},
+		{name: lua.StringLibName, open: lua.OpenString},
+		{name: lua.TabLibName, open: lua.OpenTable},
+		{name: lua.MathLibName, open: lua.OpenMath},
+	}
+	for _, library := range libraries {
+		state.Push(state.NewFunction(library.open))
+		state.Push(lua.LString(library.name))
+		state.Call(1, 0)
+	}
+
+	for _, name := range []string{"dofile", "loadfile", "load", "loadstring", "require", "module"} {
+		state.SetGlobal(name, lua.LNil)
+	}
+
+	module := state.NewTable()
+	state.SetField(module, "request", state.NewFunction(func(state *lua.LState) int {
+		return httpRequest(ctx, state, client)
+	}))
+	state.SetGlobal("http", module)
+
+	return state
+}
+
+// maxResponseBodySize is the maximum size of a response body
+// read and given to a script.
+const maxResponseBodySize = 1 << 20
+
+// httpRequest sends the HTTP request described by the table argument,
+// with the fields method, url, headers and body, and returns a table
+// with the fields status, headers and body.
+// It returns nil and an error message if the request fails.
+func httpRequest(ctx context.Context, state *lua.LState, client *http.Client) int {
+	options := state.CheckTable(1)
+
+	method := strings.ToUpper(lua.LVAsString(options.RawGetString("method")))
+	if method == "" {
+		method = http.MethodGet
+	}
+	url := lua.LVAsString(options.RawGetString("url"))
+	if url == "" {
+		state.ArgError(1, "url field is required")
+	}
+
+	var body io.Reader
+	if s := lua.LVAsString(options.RawGetString("body")); s != "" {
+		body = strings.NewReader(s)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return pushError(state, err)
+	}
+	headers.SetUserAgent(request)
+	if requestHeaders, ok := options.RawGetString("headers").(*lua.LTable); ok {
+		requestHeaders.ForEach(func(key, value lua.LValue) {
+			request.Header.Set(lua.LVAsString(key), lua.LVAsString(value))
+		})
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return pushError(state, err)
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(io.LimitReader(response.Body, maxResponseBodySize))
+	if err != nil {
+		return pushError(state, err)
+	}
+
+	result := state.NewTable()
+	result.RawSetString("status", lua.LNumber(response.StatusCode))
+	result.RawSetString("body", lua.LString(b))
+	responseHeaders := state.NewTable()
+	for key := range response.Header {
+		responseHeaders.RawSetString(strings.ToLower(key), lua.LString(response.Header.Get(key)))
+	}
+	result.RawSetString("headers", responseHeaders)
+	state.Push(result)
+	return 1
+}
+
+func pushError(state *lua.LState, err error) int {
+	state.Push(lua.LNil)
+	state.Push(lua.LString(err.Error()))
+	return 2 //nolint:gomnd
+}
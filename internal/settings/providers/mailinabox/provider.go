@@ -0,0 +1,169 @@
+package mailinabox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain        string
+	host          string
+	ipVersion     ipversion.IPVersion
+	serverURL     *url.URL
+	email         string
+	password      string
+	useProviderIP bool
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		ServerURL     string `json:"server_url"`
+		Email         string `json:"email"`
+		Password      string `json:"password"`
+		UseProviderIP bool   `json:"provider_ip"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	if extraSettings.ServerURL == "" {
+		return nil, errors.ErrEmptyURL
+	}
+	serverURL, err := url.Parse(extraSettings.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an http or https URL",
+			errors.ErrMalformedURL, extraSettings.ServerURL)
+	}
+
+	p = &Provider{
+		domain:        domain,
+		host:          host,
+		ipVersion:     ipVersion,
+		serverURL:     serverURL,
+		email:         extraSettings.Email,
+		password:      extraSettings.Password,
+		useProviderIP: extraSettings.UseProviderIP,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.email == "":
+		return errors.ErrEmptyEmail
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.MailInABox, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.MailInABox
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.serverURL.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://mailinabox.email/\">Mail-in-a-Box</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "text/plain")
+	request.SetBasicAuth(p.email, p.password)
+}
+
+// Using https://mailinabox.email/api-docs.html#tag/DNS/operation/addDnsCustomRecord
+// The PUT method replaces all the records of the name and type with the
+// value given, and Mail-in-a-Box uses the IP address of the client if
+// no value is given.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	u := *p.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") +
+		"/admin/dns/custom/" + p.BuildDomainName() + "/" + recordType
+
+	value := ip.String()
+	if p.useProviderIP {
+		value = ""
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), strings.NewReader(value))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		return ip, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("%w: %s", errors.ErrBadRequest, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
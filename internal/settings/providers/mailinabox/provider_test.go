@@ -0,0 +1,125 @@
+package mailinabox
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"server_url": "https://box.example.com", "email": "me@example.com", "password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{{Body: "updated DNS: domain.com"}}
+		},
+	})
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		providerIP   bool
+		ip           net.IP
+		expectedPath string
+		expectedBody string
+	}{
+		"IPv4": {
+			ip:           net.IPv4(203, 0, 113, 1),
+			expectedPath: "/admin/dns/custom/home.domain.com/A",
+			expectedBody: "203.0.113.1",
+		},
+		"IPv6": {
+			ip:           net.ParseIP("2001:db8::1"),
+			expectedPath: "/admin/dns/custom/home.domain.com/AAAA",
+			expectedBody: "2001:db8::1",
+		},
+		"provider IP": {
+			providerIP:   true,
+			ip:           net.IPv4(203, 0, 113, 1),
+			expectedPath: "/admin/dns/custom/home.domain.com/A",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(map[string]interface{}{
+				"server_url":  "https://box.example.com/",
+				"email":       "me@example.com",
+				"password":    "password",
+				"provider_ip": testCase.providerIP,
+			})
+			require.NoError(t, err)
+			provider, err := New(data, "domain.com", "home", ipversion.IP4or6)
+			require.NoError(t, err)
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(providertest.Response{Body: "updated DNS: domain.com"})
+
+			newIP, err := provider.Update(context.Background(), registrar.Client(), testCase.ip)
+			require.NoError(t, err)
+			assert.True(t, testCase.ip.Equal(newIP))
+
+			requests := registrar.Requests()
+			require.Len(t, requests, 1)
+			assert.Equal(t, http.MethodPut, requests[0].Method)
+			assert.Equal(t, testCase.expectedPath, requests[0].URL.Path)
+			assert.Equal(t, testCase.expectedBody, requests[0].Body)
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server_url": "https://box.example.com", "email": "me@example.com", "password": "password"}`,
+		},
+		"empty URL": {
+			data:       `{"email": "me@example.com", "password": "password"}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"empty email": {
+			data:       `{"server_url": "https://box.example.com", "password": "password"}`,
+			errWrapped: errors.ErrEmptyEmail,
+		},
+		"empty password": {
+			data:       `{"server_url": "https://box.example.com", "email": "me@example.com"}`,
+			errWrapped: errors.ErrEmptyPassword,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
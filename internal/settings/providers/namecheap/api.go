@@ -0,0 +1,191 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+type apiHost struct {
+	Name    string `xml:"Name,attr"`
+	Type    string `xml:"Type,attr"`
+	Address string `xml:"Address,attr"`
+	MXPref  string `xml:"MXPref,attr"`
+	TTL     string `xml:"TTL,attr"`
+}
+
+// updateWithAPI gets all the hosts of the domain and sets them all
+// again with the record of the host changed or added, since the API
+// only allows replacing all the hosts at once.
+// See https://www.namecheap.com/support/api/methods/domains-dns/set-hosts/
+func (p *Provider) updateWithAPI(ctx context.Context, client *http.Client, ip net.IP) (
+	newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	var getResult struct {
+		EmailType string    `xml:"EmailType,attr"`
+		Hosts     []apiHost `xml:"host"`
+	}
+	err = p.doAPIRequest(ctx, client, "namecheap.domains.dns.getHosts",
+		url.Values{}, "DomainDNSGetHostsResult", &getResult)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errors.ErrListRecords, err)
+	}
+
+	hosts := getResult.Hosts
+	found := false
+	for i, host := range hosts {
+		if host.Name != p.host || host.Type != recordType {
+			continue
+		} else if host.Address == ip.String() {
+			return ip, nil // up to date
+		}
+		hosts[i].Address = ip.String()
+		found = true
+	}
+	if !found {
+		const defaultTTL = "1800"
+		hosts = append(hosts, apiHost{
+			Name:    p.host,
+			Type:    recordType,
+			Address: ip.String(),
+			TTL:     defaultTTL,
+		})
+	}
+
+	values := url.Values{}
+	if getResult.EmailType != "" {
+		values.Set("EmailType", getResult.EmailType)
+	}
+	for i, host := range hosts {
+		suffix := strconv.Itoa(i + 1)
+		values.Set("HostName"+suffix, host.Name)
+		values.Set("RecordType"+suffix, host.Type)
+		values.Set("Address"+suffix, host.Address)
+		if host.MXPref != "" {
+			values.Set("MXPref"+suffix, host.MXPref)
+		}
+		if host.TTL != "" {
+			values.Set("TTL"+suffix, host.TTL)
+		}
+	}
+
+	var setResult struct {
+		IsSuccess bool `xml:"IsSuccess,attr"`
+	}
+	err = p.doAPIRequest(ctx, client, "namecheap.domains.dns.setHosts",
+		values, "DomainDNSSetHostsResult", &setResult)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errors.ErrUpdateRecord, err)
+	} else if !setResult.IsSuccess {
+		return nil, fmt.Errorf("%s: %w", errors.ErrUpdateRecord, errors.ErrUnsuccessfulResponse)
+	}
+	return ip, nil
+}
+
+// doAPIRequest sends the command with the values given to the Namecheap
+// API, and decodes the command result element named resultName into result.
+// See https://www.namecheap.com/support/api/intro/
+func (p *Provider) doAPIRequest(ctx context.Context, client *http.Client,
+	command string, values url.Values, resultName string, result interface{}) (err error) {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.namecheap.com",
+		Path:   "/xml.response",
+	}
+
+	// The domain is split in its second level and top level domains,
+	// for example domain.co.uk gives domain and co.uk.
+	sld, tld := p.domain, ""
+	if i := strings.Index(p.domain, "."); i >= 0 {
+		sld, tld = p.domain[:i], p.domain[i+1:]
+	}
+	values.Set("ApiUser", p.apiUser)
+	values.Set("ApiKey", p.apiKey)
+	values.Set("UserName", p.apiUser)
+	values.Set("ClientIp", p.clientIP.String())
+	values.Set("Command", command)
+	values.Set("SLD", sld)
+	values.Set("TLD", tld)
+
+	// POST is used since setHosts can have many parameters.
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(),
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s",
+			errors.ErrBadHTTPStatus, response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	decoder := xml.NewDecoder(response.Body)
+	decoder.CharsetReader = func(encoding string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+	var parsedXML struct {
+		Status string `xml:"Status,attr"`
+		Errors []struct {
+			Number  string `xml:"Number,attr"`
+			Message string `xml:",chardata"`
+		} `xml:"Errors>Error"`
+		CommandResponse struct {
+			InnerXML []byte `xml:",innerxml"`
+		} `xml:"CommandResponse"`
+	}
+	if err := decoder.Decode(&parsedXML); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	if parsedXML.Status != "OK" {
+		messages := make([]string, len(parsedXML.Errors))
+		for i, e := range parsedXML.Errors {
+			messages[i] = fmt.Sprintf("error %s: %s", e.Number, strings.TrimSpace(e.Message))
+		}
+		return fmt.Errorf("%w: %s", errors.ErrUnsuccessfulResponse, strings.Join(messages, "; "))
+	}
+
+	inner := xml.NewDecoder(strings.NewReader(string(parsedXML.CommandResponse.InnerXML)))
+	for {
+		token, err := inner.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %s element not found: %s",
+				errors.ErrUnmarshalResponse, resultName, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != resultName {
+			continue
+		}
+		err = inner.DecodeElement(result, &start)
+		if err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+		}
+		return nil
+	}
+}
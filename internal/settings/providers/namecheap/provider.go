@@ -25,17 +25,20 @@ type Provider struct {
 	ipVersion     ipversion.IPVersion
 	password      string
 	useProviderIP bool
+	apiUser       string
+	apiKey        string
+	clientIP      net.IP
 	matcher       common.Matcher
 }
 
 func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersion,
 	matcher common.Matcher) (p *Provider, err error) {
-	if ipVersion == ipversion.IP6 {
-		return p, errors.ErrIPv6NotSupported
-	}
 	extraSettings := struct {
 		Password      string `json:"password"`
 		UseProviderIP bool   `json:"provider_ip"`
+		APIUser       string `json:"api_user"`
+		APIKey        string `json:"api_key"`
+		ClientIP      string `json:"client_ip"`
 	}{}
 	if err := json.Unmarshal(data, &extraSettings); err != nil {
 		return nil, err
@@ -46,8 +49,12 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 		ipVersion:     ipVersion,
 		password:      extraSettings.Password,
 		useProviderIP: extraSettings.UseProviderIP,
+		apiUser:       extraSettings.APIUser,
+		apiKey:        extraSettings.APIKey,
+		clientIP:      net.ParseIP(extraSettings.ClientIP),
 		matcher:       matcher,
 	}
+
 	if err := p.isValid(); err != nil {
 		return nil, err
 	}
@@ -55,7 +62,21 @@ func New(data json.RawMessage, domain, host string, ipVersion ipversion.IPVersio
 }
 
 func (p *Provider) isValid() error {
-	if !p.matcher.NamecheapPassword(p.password) {
+	if p.apiUser != "" {
+		switch {
+		case p.apiKey == "":
+			return errors.ErrEmptyKey
+		case p.clientIP == nil || p.clientIP.To4() == nil:
+			return fmt.Errorf("%w: client_ip must be an IPv4 address", errors.ErrMalformedIP)
+		}
+		return nil
+	}
+
+	switch {
+	case p.ipVersion == ipversion.IP6:
+		// the dynamic DNS endpoint only supports IPv4
+		return errors.ErrIPv6NotSupported
+	case !p.matcher.NamecheapPassword(p.password):
 		return errors.ErrMalformedPassword
 	}
 	return nil
@@ -65,6 +86,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.Namecheap, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Namecheap
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dynamicdns.park-your-domain.com", "api.namecheap.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -99,7 +128,17 @@ func (p *Provider) setHeaders(request *http.Request) {
 	headers.SetAccept(request, "application/xml")
 }
 
+// Update uses the Namecheap API if the api_user setting is set,
+// which supports IPv6, and the dynamic DNS endpoint otherwise.
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	if p.apiUser != "" {
+		return p.updateWithAPI(ctx, client, ip)
+	}
+	return p.updateWithDynamicDNS(ctx, client, ip)
+}
+
+func (p *Provider) updateWithDynamicDNS(ctx context.Context, client *http.Client,
+	ip net.IP) (newIP net.IP, err error) {
 	u := url.URL{
 		Scheme: "https",
 		Host:   "dynamicdns.park-your-domain.com",
@@ -0,0 +1,200 @@
+package namecheap
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/regex"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		ipVersion  ipversion.IPVersion
+		errWrapped error
+	}{
+		"dynamic DNS IPv6": {
+			data:       `{"password": "0123456789abcdef0123456789abcdef"}`,
+			ipVersion:  ipversion.IP6,
+			errWrapped: errors.ErrIPv6NotSupported,
+		},
+		"API IPv6": {
+			data:      `{"api_user": "user", "api_key": "key", "client_ip": "198.51.100.1"}`,
+			ipVersion: ipversion.IP6,
+		},
+		"API without key": {
+			data:       `{"api_user": "user", "client_ip": "198.51.100.1"}`,
+			ipVersion:  ipversion.IP4,
+			errWrapped: errors.ErrEmptyKey,
+		},
+		"API with IPv6 client IP": {
+			data:       `{"api_user": "user", "api_key": "key", "client_ip": "2001:db8::1"}`,
+			ipVersion:  ipversion.IP4,
+			errWrapped: errors.ErrMalformedIP,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@",
+				testCase.ipVersion, regex.NewMatcher())
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+const getHostsResponse = `<?xml version="1.0" encoding="utf-8"?>
+<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+  <Errors />
+  <CommandResponse Type="namecheap.domains.dns.getHosts">
+    <DomainDNSGetHostsResult Domain="domain.com" EmailType="MX" IsUsingOurDNS="true">
+      <host HostId="1" Name="@" Type="A" Address="203.0.113.9" MXPref="10" TTL="1800" />
+      <host HostId="2" Name="@" Type="MX" Address="mail.domain.com." MXPref="10" TTL="1800" />
+    </DomainDNSGetHostsResult>
+  </CommandResponse>
+</ApiResponse>`
+
+const setHostsResponse = `<?xml version="1.0" encoding="utf-8"?>
+<ApiResponse Status="OK" xmlns="http://api.namecheap.com/xml.response">
+  <Errors />
+  <CommandResponse Type="namecheap.domains.dns.setHosts">
+    <DomainDNSSetHostsResult Domain="domain.com" IsSuccess="true" />
+  </CommandResponse>
+</ApiResponse>`
+
+func Test_Provider_Update_api(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		host       string
+		ip         net.IP
+		responses  []providertest.Response
+		setValues  url.Values
+		errWrapped error
+	}{
+		"AAAA record added": {
+			host:      "@",
+			ip:        net.ParseIP("2001:db8::1"),
+			responses: []providertest.Response{{Body: getHostsResponse}, {Body: setHostsResponse}},
+			setValues: url.Values{
+				"EmailType":   {"MX"},
+				"HostName1":   {"@"},
+				"RecordType1": {"A"},
+				"Address1":    {"203.0.113.9"},
+				"MXPref1":     {"10"},
+				"TTL1":        {"1800"},
+				"HostName2":   {"@"},
+				"RecordType2": {"MX"},
+				"Address2":    {"mail.domain.com."},
+				"MXPref2":     {"10"},
+				"TTL2":        {"1800"},
+				"HostName3":   {"@"},
+				"RecordType3": {"AAAA"},
+				"Address3":    {"2001:db8::1"},
+				"TTL3":        {"1800"},
+			},
+		},
+		"A record changed": {
+			host:      "@",
+			ip:        net.IPv4(203, 0, 113, 1),
+			responses: []providertest.Response{{Body: getHostsResponse}, {Body: setHostsResponse}},
+			setValues: url.Values{
+				"EmailType":   {"MX"},
+				"HostName1":   {"@"},
+				"RecordType1": {"A"},
+				"Address1":    {"203.0.113.1"},
+				"MXPref1":     {"10"},
+				"TTL1":        {"1800"},
+				"HostName2":   {"@"},
+				"RecordType2": {"MX"},
+				"Address2":    {"mail.domain.com."},
+				"MXPref2":     {"10"},
+				"TTL2":        {"1800"},
+			},
+		},
+		"up to date": {
+			host:      "@",
+			ip:        net.IPv4(203, 0, 113, 9),
+			responses: []providertest.Response{{Body: getHostsResponse}},
+		},
+		"API error": {
+			host: "@",
+			ip:   net.IPv4(203, 0, 113, 1),
+			responses: []providertest.Response{{Body: `<ApiResponse Status="ERROR">
+				<Errors><Error Number="1011102">API Key is invalid</Error></Errors>
+				</ApiResponse>`}},
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(testCase.responses...)
+
+			data := json.RawMessage(`{"api_user": "user", "api_key": "key", "client_ip": "198.51.100.1"}`)
+			provider, err := New(data, "domain.com", testCase.host, ipversion.IP4or6, regex.NewMatcher())
+			require.NoError(t, err)
+
+			newIP, err := provider.Update(context.Background(), registrar.Client(), testCase.ip)
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, testCase.ip.Equal(newIP))
+
+			requests := registrar.Requests()
+			common := url.Values{
+				"ApiUser":  {"user"},
+				"ApiKey":   {"key"},
+				"UserName": {"user"},
+				"ClientIp": {"198.51.100.1"},
+				"SLD":      {"domain"},
+				"TLD":      {"com"},
+			}
+			getValues, err := url.ParseQuery(requests[0].Body)
+			require.NoError(t, err)
+			expected := url.Values{"Command": {"namecheap.domains.dns.getHosts"}}
+			for key, value := range common {
+				expected[key] = value
+			}
+			assert.Equal(t, expected, getValues)
+
+			if testCase.setValues == nil {
+				assert.Len(t, requests, 1)
+				return
+			}
+			require.Len(t, requests, 2)
+			setValues, err := url.ParseQuery(requests[1].Body)
+			require.NoError(t, err)
+			expected = testCase.setValues
+			expected.Set("Command", "namecheap.domains.dns.setHosts")
+			for key, value := range common {
+				expected[key] = value
+			}
+			assert.Equal(t, expected, setValues)
+		})
+	}
+}
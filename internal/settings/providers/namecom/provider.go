@@ -0,0 +1,264 @@
+package namecom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	username  string
+	token     string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Username string `json:"username"`
+		Token    string `json:"token"`
+		TTL      uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		username:  extraSettings.Username,
+		token:     extraSettings.Token,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.token == "":
+		return errors.ErrEmptyToken
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.NameCom, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.NameCom
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.name.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.name.com/\">Name.com</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.SetBasicAuth(p.username, p.token)
+}
+
+// recordHost returns the host as expected by Name.com,
+// where the root of the domain is the empty string.
+func (p *Provider) recordHost() string {
+	if p.host == "@" {
+		return ""
+	}
+	return p.host
+}
+
+// Using https://www.name.com/api-docs/DNS#UpdateRecord
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	recordID, err := p.getRecordID(ctx, client, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetRecordID, err)
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.name.com",
+		Path:   fmt.Sprintf("/v4/domains/%s/records/%d", p.domain, recordID),
+	}
+	requestData := struct {
+		Host   string `json:"host"`
+		Type   string `json:"type"`
+		Answer string `json:"answer"`
+		TTL    uint   `json:"ttl,omitempty"`
+	}{
+		Host:   p.recordHost(),
+		Type:   recordType,
+		Answer: ip.String(),
+		TTL:    p.ttl,
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), buffer)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	err = checkStatus(response)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseData struct {
+		Answer string `json:"answer"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&responseData); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	newIP = net.ParseIP(responseData.Answer)
+	if newIP == nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, responseData.Answer)
+	} else if !newIP.Equal(ip) {
+		return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMismatch, newIP)
+	}
+	return newIP, nil
+}
+
+// getRecordID returns the ID of the record matching the host and
+// record type, going through all the pages of records of the domain.
+// See https://www.name.com/api-docs/DNS#ListRecords
+func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
+	recordType string) (recordID int, err error) {
+	const perPage = 1000
+	page := 1
+	for page != 0 {
+		u := url.URL{
+			Scheme: "https",
+			Host:   "api.name.com",
+			Path:   fmt.Sprintf("/v4/domains/%s/records", p.domain),
+		}
+		values := url.Values{}
+		values.Set("perPage", strconv.Itoa(perPage))
+		values.Set("page", strconv.Itoa(page))
+		u.RawQuery = values.Encode()
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return 0, err
+		}
+		p.setHeaders(request)
+
+		response, err := client.Do(request)
+		if err != nil {
+			return 0, err
+		}
+
+		err = checkStatus(response)
+		if err != nil {
+			_ = response.Body.Close()
+			return 0, err
+		}
+
+		var data struct {
+			Records []struct {
+				ID   int    `json:"id"`
+				Host string `json:"host"`
+				Type string `json:"type"`
+			} `json:"records"`
+			NextPage int `json:"nextPage"`
+		}
+		err = json.NewDecoder(response.Body).Decode(&data)
+		_ = response.Body.Close()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+		}
+
+		for _, record := range data.Records {
+			if record.Host == p.recordHost() && record.Type == recordType {
+				return record.ID, nil
+			}
+		}
+		page = data.NextPage
+	}
+
+	return 0, errors.ErrRecordNotFound
+}
+
+func checkStatus(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
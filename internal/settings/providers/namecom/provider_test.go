@@ -0,0 +1,31 @@
+package namecom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"username": "user", "token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"records":[{"id":1,"host":"www","type":"A"},{"id":2,"host":"home","type":"A"}]}`},
+				{Body: `{"id":2,"host":"home","type":"A","answer":"` + reportedIP + `"}`},
+			}
+		},
+		ReportsIP: true,
+	})
+}
@@ -0,0 +1,164 @@
+package netcup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+const endpoint = "https://ccp.netcup.net/run/webservice/servers/endpoint.php?JSON"
+
+// doJSONRequest sends the action with its parameters to the netcup CCP API,
+// and decodes the response data into the responseData pointer given.
+// See https://ccp.netcup.net/run/webservice/servers/endpoint.php
+func doJSONRequest(ctx context.Context, client *http.Client,
+	action string, param, responseData interface{}) (err error) {
+	requestData := struct {
+		Action string      `json:"action"`
+		Param  interface{} `json:"param"`
+	}{
+		Action: action,
+		Param:  param,
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, buffer)
+	if err != nil {
+		return err
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		Status       string          `json:"status"`
+		StatusCode   int             `json:"statuscode"`
+		ShortMessage string          `json:"shortmessage"`
+		LongMessage  string          `json:"longmessage"`
+		ResponseData json.RawMessage `json:"responsedata"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	const statusCodeSuccess = 2000
+	if data.StatusCode != statusCodeSuccess {
+		return fmt.Errorf("%w: %s: %d: %s: %s", errors.ErrUnsuccessfulResponse,
+			data.Status, data.StatusCode, data.ShortMessage, data.LongMessage)
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data.ResponseData, responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
+
+func (p *Provider) login(ctx context.Context, client *http.Client) (
+	sessionID string, err error) {
+	param := struct {
+		CustomerNumber string `json:"customernumber"`
+		APIKey         string `json:"apikey"`
+		APIPassword    string `json:"apipassword"`
+	}{
+		CustomerNumber: p.customerNumber,
+		APIKey:         p.apiKey,
+		APIPassword:    p.apiPassword,
+	}
+	var responseData struct {
+		SessionID string `json:"apisessionid"`
+	}
+	err = doJSONRequest(ctx, client, "login", param, &responseData)
+	if goerrors.Is(err, errors.ErrUnsuccessfulResponse) {
+		return "", fmt.Errorf("%w: %s", errors.ErrAuth, err)
+	} else if err != nil {
+		return "", err
+	}
+	return responseData.SessionID, nil
+}
+
+type sessionParam struct {
+	CustomerNumber string `json:"customernumber"`
+	APIKey         string `json:"apikey"`
+	SessionID      string `json:"apisessionid"`
+}
+
+type dnsRecord struct {
+	ID           string `json:"id,omitempty"`
+	Hostname     string `json:"hostname"`
+	Type         string `json:"type"`
+	Destination  string `json:"destination"`
+	DeleteRecord bool   `json:"deleterecord"`
+}
+
+func (p *Provider) infoDNSRecords(ctx context.Context, client *http.Client,
+	sessionID string) (records []dnsRecord, err error) {
+	param := struct {
+		sessionParam
+		DomainName string `json:"domainname"`
+	}{
+		sessionParam: sessionParam{
+			CustomerNumber: p.customerNumber,
+			APIKey:         p.apiKey,
+			SessionID:      sessionID,
+		},
+		DomainName: p.domain,
+	}
+	var responseData struct {
+		Records []dnsRecord `json:"dnsrecords"`
+	}
+	err = doJSONRequest(ctx, client, "infoDnsRecords", param, &responseData)
+	if err != nil {
+		return nil, err
+	}
+	return responseData.Records, nil
+}
+
+func (p *Provider) updateDNSRecords(ctx context.Context, client *http.Client,
+	sessionID string, record dnsRecord) (records []dnsRecord, err error) {
+	type recordSet struct {
+		Records []dnsRecord `json:"dnsrecords"`
+	}
+	param := struct {
+		sessionParam
+		DomainName string    `json:"domainname"`
+		RecordSet  recordSet `json:"dnsrecordset"`
+	}{
+		sessionParam: sessionParam{
+			CustomerNumber: p.customerNumber,
+			APIKey:         p.apiKey,
+			SessionID:      sessionID,
+		},
+		DomainName: p.domain,
+		RecordSet:  recordSet{Records: []dnsRecord{record}},
+	}
+	var responseData recordSet
+	err = doJSONRequest(ctx, client, "updateDnsRecords", param, &responseData)
+	if err != nil {
+		return nil, err
+	}
+	return responseData.Records, nil
+}
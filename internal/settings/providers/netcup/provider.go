@@ -0,0 +1,186 @@
+package netcup
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// sessions is shared by all netcup records, such that records
+// of the same account reuse the same API session.
+var sessions = session.NewCache(time.Now) //nolint:gochecknoglobals
+
+// sessionLifetime is the duration a session is reused for since its
+// last use, below the 15 minutes after which netcup expires it.
+const sessionLifetime = 10 * time.Minute
+
+type Provider struct {
+	domain         string
+	host           string
+	ipVersion      ipversion.IPVersion
+	customerNumber string
+	apiKey         string
+	apiPassword    string
+	sessions       *session.Cache
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		CustomerNumber string `json:"customer_number"`
+		APIKey         string `json:"api_key"`
+		APIPassword    string `json:"api_password"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:         domain,
+		host:           host,
+		ipVersion:      ipVersion,
+		customerNumber: extraSettings.CustomerNumber,
+		apiKey:         extraSettings.APIKey,
+		apiPassword:    extraSettings.APIPassword,
+		sessions:       sessions,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.customerNumber == "":
+		return errors.ErrEmptyCustomerNumber
+	case p.apiKey == "":
+		return errors.ErrEmptyAPIKey
+	case p.apiPassword == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Netcup, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Netcup
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"ccp.netcup.net"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.netcup.eu/\">netcup</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Using https://ccp.netcup.net/run/webservice/servers/endpoint.php
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	login := func(ctx context.Context) (sessionID string, err error) {
+		return p.login(ctx, client)
+	}
+	// sessionRejected is set if the first call using the
+	// session fails, which happens if the session expired.
+	var sessionRejected bool
+	update := func(sessionID string) (err error) {
+		newIP, sessionRejected, err = p.updateRecord(ctx, client, sessionID, recordType, ip)
+		return err
+	}
+	isExpired := func(error) bool { return sessionRejected }
+
+	key := session.Key(p.customerNumber, p.apiKey, p.apiPassword)
+	err = p.sessions.Do(ctx, key, sessionLifetime, login, update, isExpired)
+	if err != nil {
+		return nil, err
+	}
+	return newIP, nil
+}
+
+func (p *Provider) updateRecord(ctx context.Context, client *http.Client,
+	sessionID, recordType string, ip net.IP) (newIP net.IP, sessionRejected bool, err error) {
+	records, err := p.infoDNSRecords(ctx, client, sessionID)
+	if err != nil {
+		sessionRejected = goerrors.Is(err, errors.ErrUnsuccessfulResponse)
+		return nil, sessionRejected, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	// The record is created if it does not exist, since it then has no ID.
+	record := dnsRecord{
+		Hostname: p.host,
+		Type:     recordType,
+	}
+	for _, existing := range records {
+		if existing.Hostname == p.host && existing.Type == recordType {
+			record = existing
+			break
+		}
+	}
+
+	if record.Destination == ip.String() {
+		return ip, false, nil // already up to date
+	}
+	record.Destination = ip.String()
+
+	records, err = p.updateDNSRecords(ctx, client, sessionID, record)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+
+	for _, updated := range records {
+		if updated.Hostname != p.host || updated.Type != recordType {
+			continue
+		}
+		newIP = net.ParseIP(updated.Destination)
+		if newIP == nil {
+			return nil, false, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, updated.Destination)
+		} else if !newIP.Equal(ip) {
+			return nil, false, fmt.Errorf("%w: %s", errors.ErrIPReceivedMismatch, newIP)
+		}
+		return newIP, false, nil
+	}
+	return nil, false, fmt.Errorf("%w: in update response", errors.ErrRecordNotFound)
+}
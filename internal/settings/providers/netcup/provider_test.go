@@ -0,0 +1,83 @@
+package netcup
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/session"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"customer_number": "123456", "api_key": "key", "api_password": "password"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		provider.sessions = session.NewCache(time.Now)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"statuscode":2000,"responsedata":{"apisessionid":"session"}}`},
+				{Body: `{"statuscode":2000,"responsedata":{"dnsrecords":[` +
+					`{"id":"1","hostname":"home","type":"A","destination":"192.0.2.1"}]}}`},
+				{Body: `{"statuscode":2000,"responsedata":{"dnsrecords":[` +
+					`{"id":"1","hostname":"home","type":"A","destination":"` + reportedIP + `"}]}}`},
+			}
+		},
+		ReportsIP: true,
+	})
+}
+
+func Test_Provider_Update_sessionReuse(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"customer_number": "123456", "api_key": "key", "api_password": "password"}`)
+	provider, err := New(data, "domain.com", "home", ipversion.IP4)
+	require.NoError(t, err)
+	provider.sessions = session.NewCache(time.Now)
+
+	registrar := providertest.NewRegistrar()
+	defer registrar.Close()
+	const upToDateRecords = `{"statuscode":2000,"responsedata":{"dnsrecords":[` +
+		`{"id":"1","hostname":"home","type":"A","destination":"203.0.113.1"}]}}`
+	registrar.Script(
+		providertest.Response{Body: `{"statuscode":2000,"responsedata":{"apisessionid":"session1"}}`},
+		providertest.Response{Body: upToDateRecords},
+		// second update with the cached session, rejected as expired
+		providertest.Response{Body: `{"statuscode":4001,"shortmessage":"session expired"}`},
+		providertest.Response{Body: `{"statuscode":2000,"responsedata":{"apisessionid":"session2"}}`},
+		providertest.Response{Body: upToDateRecords},
+	)
+
+	ip := net.IPv4(203, 0, 113, 1)
+	for i := 0; i < 2; i++ {
+		newIP, err := provider.Update(context.Background(), registrar.Client(), ip)
+		require.NoError(t, err)
+		assert.True(t, newIP.Equal(ip))
+	}
+
+	sessionIDs := make([]string, 0, len(registrar.Requests()))
+	for _, request := range registrar.Requests() {
+		var body struct {
+			Action string `json:"action"`
+			Param  struct {
+				SessionID string `json:"apisessionid"`
+			} `json:"param"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(request.Body), &body))
+		sessionIDs = append(sessionIDs, body.Action+":"+body.Param.SessionID)
+	}
+	assert.Equal(t, []string{"login:", "infoDnsRecords:session1",
+		"infoDnsRecords:session1", "login:", "infoDnsRecords:session2"}, sessionIDs)
+}
@@ -0,0 +1,234 @@
+package netlify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	token     string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Token string `json:"token"`
+		TTL   uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		token:     extraSettings.Token,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.token == "" {
+		return errors.ErrEmptyToken
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Netlify, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Netlify
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.netlify.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.netlify.com/\">Netlify</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	headers.SetAuthBearer(request, p.token)
+}
+
+type dnsRecord struct {
+	ID       string `json:"id,omitempty"`
+	Hostname string `json:"hostname"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	TTL      uint   `json:"ttl,omitempty"`
+}
+
+// Using https://open-api.netlify.com/#tag/dnsZone
+// Netlify has no call to modify a record, so a record with the
+// IP address is created before deleting the previous records, to
+// not leave the host without a record in between.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	zoneID, err := p.getZoneID(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetZoneID, err)
+	}
+	recordsPath := "/dns_zones/" + zoneID + "/dns_records"
+
+	var records []dnsRecord
+	err = p.doRequest(ctx, client, http.MethodGet, recordsPath, nil, &records)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	hostname := p.BuildDomainName()
+	var staleRecordIDs []string
+	upToDate := false
+	for _, record := range records {
+		if !strings.EqualFold(record.Hostname, hostname) || record.Type != recordType {
+			continue
+		}
+		if recordIP := net.ParseIP(record.Value); recordIP != nil && recordIP.Equal(ip) {
+			upToDate = true
+			continue
+		}
+		staleRecordIDs = append(staleRecordIDs, record.ID)
+	}
+
+	if !upToDate {
+		record := dnsRecord{Hostname: hostname, Type: recordType, Value: ip.String(), TTL: p.ttl}
+		err = p.doRequest(ctx, client, http.MethodPost, recordsPath, record, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+	}
+
+	for _, recordID := range staleRecordIDs {
+		err = p.doRequest(ctx, client, http.MethodDelete, recordsPath+"/"+recordID, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrRemoveRecord, err)
+		}
+	}
+	return ip, nil
+}
+
+func (p *Provider) getZoneID(ctx context.Context, client *http.Client) (zoneID string, err error) {
+	var zones []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	err = p.doRequest(ctx, client, http.MethodGet, "/dns_zones", nil, &zones)
+	if err != nil {
+		return "", err
+	}
+	for _, zone := range zones {
+		if strings.EqualFold(zone.Name, p.domain) {
+			return zone.ID, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.domain)
+}
+
+// doRequest sends the request data encoded as JSON if it is not nil,
+// and decodes the JSON response into the response data if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	method, path string, requestData, responseData interface{}) (err error) {
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method,
+		"https://api.netlify.com/api/v1"+path, body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package netlify
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/require"
+)
+
+const zonesBody = `[{"id": "zone_1", "name": "other.com"}, {"id": "zone_2", "name": "domain.com"}]`
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: zonesBody},
+				{Body: `[]`},
+				{Status: http.StatusCreated, Body: `{}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"zone not found": {
+				Responses:  []providertest.Response{{Body: `[]`}},
+				Requests:   []string{"GET /api/v1/dns_zones"},
+				ErrWrapped: errors.ErrGetZoneID,
+			},
+			"stale record replaced": {
+				Responses: []providertest.Response{
+					{Body: zonesBody},
+					{Body: `[{"id": "rec_1", "hostname": "home.domain.com", "type": "A", "value": "203.0.113.2"},` +
+						`{"id": "rec_2", "hostname": "home.domain.com", "type": "AAAA", "value": "2001:db8::1"},` +
+						`{"id": "rec_3", "hostname": "other.domain.com", "type": "A", "value": "203.0.113.2"}]`},
+					{Status: http.StatusCreated, Body: `{}`},
+					{Status: http.StatusNoContent},
+				},
+				Requests: []string{
+					"GET /api/v1/dns_zones",
+					"GET /api/v1/dns_zones/zone_2/dns_records",
+					"POST /api/v1/dns_zones/zone_2/dns_records",
+					"DELETE /api/v1/dns_zones/zone_2/dns_records/rec_1",
+				},
+			},
+			"duplicate record removed": {
+				Responses: []providertest.Response{
+					{Body: zonesBody},
+					{Body: `[{"id": "rec_1", "hostname": "home.domain.com", "type": "A", "value": "203.0.113.1"},` +
+						`{"id": "rec_2", "hostname": "home.domain.com", "type": "A", "value": "203.0.113.2"}]`},
+					{Status: http.StatusNoContent},
+				},
+				Requests: []string{
+					"GET /api/v1/dns_zones",
+					"GET /api/v1/dns_zones/zone_2/dns_records",
+					"DELETE /api/v1/dns_zones/zone_2/dns_records/rec_2",
+				},
+			},
+		},
+	})
+}
@@ -61,8 +61,6 @@ func (p *Provider) isValid() error {
 		return fmt.Errorf("%w: longer than 50 characters", errors.ErrMalformedUsername)
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -71,6 +69,14 @@ func (p *Provider) String() string {
 	return utils.ToString(p.domain, p.host, constants.NoIP, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.NoIP
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dynupdate.no-ip.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -0,0 +1,234 @@
+package ns1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	apiKey    string
+	ttl       uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		APIKey string `json:"api_key"`
+		TTL    uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		apiKey:    extraSettings.APIKey,
+		ttl:       extraSettings.TTL,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.apiKey == "" {
+		return errors.ErrEmptyAPIKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.NS1, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.NS1
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.nsone.net"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://ns1.com/\">NS1</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("X-NSONE-Key", p.apiKey)
+}
+
+type answer struct {
+	Answer []string `json:"answer"`
+}
+
+// Using https://developer.ibm.com/apis/catalog/ns1--ibm-ns1-connect-api/api/API--ns1--ibm-ns1-connect-api#getRecord
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	recordDomain := utils.BuildURLQueryHostname(p.host, p.domain)
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.nsone.net",
+		Path:   fmt.Sprintf("/v1/zones/%s/%s/%s", p.domain, recordDomain, recordType),
+	}
+
+	answers, found, err := p.getAnswers(ctx, client, u)
+	if err != nil {
+		return nil, fmt.Errorf("getting record answers: %w", err)
+	}
+
+	if found && len(answers) == 1 && len(answers[0].Answer) == 1 &&
+		answers[0].Answer[0] == ip.String() {
+		return ip, nil // already up to date
+	}
+
+	// The record is updated with a POST request and created with a PUT request.
+	method := http.MethodPost
+	if !found {
+		method = http.MethodPut
+	}
+	requestData := struct {
+		Zone    string   `json:"zone"`
+		Domain  string   `json:"domain"`
+		Type    string   `json:"type"`
+		TTL     uint     `json:"ttl,omitempty"`
+		Answers []answer `json:"answers"`
+	}{
+		Zone:    p.domain,
+		Domain:  recordDomain,
+		Type:    recordType,
+		TTL:     p.ttl,
+		Answers: []answer{{Answer: []string{ip.String()}}},
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), buffer)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var responseData struct {
+		Answers []answer `json:"answers"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&responseData); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	for _, answer := range responseData.Answers {
+		for _, value := range answer.Answer {
+			newIP = net.ParseIP(value)
+			if newIP == nil {
+				return nil, fmt.Errorf("%w: %s", errors.ErrIPReceivedMalformed, value)
+			} else if newIP.Equal(ip) {
+				return newIP, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s not found in answers received", errors.ErrIPReceivedMismatch, ip)
+}
+
+func (p *Provider) getAnswers(ctx context.Context, client *http.Client, u url.URL) (
+	answers []answer, found bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, false, nil
+	case http.StatusUnauthorized:
+		return nil, false, fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	default:
+		return nil, false, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		Answers []answer `json:"answers"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, false, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return data.Answers, true, nil
+}
@@ -0,0 +1,110 @@
+package ns1
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(json.RawMessage(`{}`), "domain.com", "home", ipversion.IP4)
+	assert.ErrorIs(t, err, errors.ErrEmptyAPIKey)
+
+	provider, err := New(json.RawMessage(`{"api_key": "key", "ttl": 60}`), "domain.com", "home", ipversion.IP4)
+	require.NoError(t, err)
+	assert.Equal(t, uint(60), provider.TTL())
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"api_key": "key"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	const recordPath = "/v1/zones/domain.com/home.domain.com/A"
+	check := func(body string) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			for _, request := range requests {
+				assert.Equal(t, "api.nsone.net", request.URL.Host)
+				assert.Equal(t, "key", request.Header.Get("X-NSONE-Key"))
+			}
+			if body != "" {
+				assert.JSONEq(t, body, requests[len(requests)-1].Body)
+			}
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"answers": [{"answer": ["203.0.113.2"]}]}`},
+				{Body: `{"answers": [{"answer": ["` + reportedIP + `"]}]}`},
+			}
+		},
+		ReportsIP: true,
+		Scenarios: map[string]providertest.Scenario{
+			"record updated": {
+				Responses: []providertest.Response{
+					{Body: `{"answers": [{"answer": ["203.0.113.2"]}]}`},
+					{Body: `{"answers": [{"answer": ["203.0.113.1"]}]}`},
+				},
+				Requests: []string{
+					"GET " + recordPath,
+					"POST " + recordPath,
+				},
+				Check: check(`{"zone":"domain.com","domain":"home.domain.com","type":"A",` +
+					`"answers":[{"answer":["203.0.113.1"]}]}`),
+			},
+			"record not found": {
+				Responses: []providertest.Response{
+					{Status: http.StatusNotFound, Body: `{"message": "record not found"}`},
+					{Body: `{"answers": [{"answer": ["203.0.113.1"]}]}`},
+				},
+				Requests: []string{
+					"GET " + recordPath,
+					"PUT " + recordPath,
+				},
+				Check: check(`{"zone":"domain.com","domain":"home.domain.com","type":"A",` +
+					`"answers":[{"answer":["203.0.113.1"]}]}`),
+			},
+			"record up to date": {
+				Responses: []providertest.Response{
+					{Body: `{"answers": [{"answer": ["203.0.113.1"]}]}`},
+				},
+				Requests: []string{"GET " + recordPath},
+				Check:    check(""),
+			},
+			"bad API key": {
+				Responses: []providertest.Response{
+					{Status: http.StatusUnauthorized, Body: `{"message": "Unauthorized"}`},
+				},
+				Requests:   []string{"GET " + recordPath},
+				ErrWrapped: errors.ErrAuth,
+				Check:      check(""),
+			},
+			"bad API key on update": {
+				Responses: []providertest.Response{
+					{Status: http.StatusNotFound, Body: `{"message": "record not found"}`},
+					{Status: http.StatusUnauthorized, Body: `{"message": "Unauthorized"}`},
+				},
+				Requests: []string{
+					"GET " + recordPath,
+					"PUT " + recordPath,
+				},
+				ErrWrapped: errors.ErrAuth,
+			},
+		},
+	})
+}
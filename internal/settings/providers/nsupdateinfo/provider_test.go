@@ -0,0 +1,48 @@
+package nsupdateinfo
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"secret": "secret"}`)
+		provider, err := New(data, "nsupdate.info", "home", ipversion.IP4or6)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{
+				{Body: "good " + reportedIP},
+			}
+		},
+		ReportsIP: true,
+		Scenarios: map[string]providertest.Scenario{
+			"ipv6": {
+				IP:        net.ParseIP("2001:db8::1"),
+				Responses: []providertest.Response{{Body: "nochg 2001:db8::1"}},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					require.Len(t, requests, 1)
+					assert.Equal(t, "ipv6.nsupdate.info", requests[0].URL.Host)
+					username, password, ok := (&http.Request{Header: requests[0].Header}).BasicAuth()
+					assert.True(t, ok)
+					assert.Equal(t, "home.nsupdate.info", username)
+					assert.Equal(t, "secret", password)
+				},
+			},
+		},
+	})
+}
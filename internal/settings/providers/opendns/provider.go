@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
 	"github.com/qdm12/ddns-updater/internal/settings/errors"
 	"github.com/qdm12/ddns-updater/internal/settings/headers"
 	"github.com/qdm12/ddns-updater/internal/settings/utils"
@@ -56,8 +57,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -66,6 +65,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: Opendns]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.OpenDNS
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"updates.opendns.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
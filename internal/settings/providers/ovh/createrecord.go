@@ -22,10 +22,12 @@ func (p *Provider) createRecord(ctx context.Context, client *http.Client,
 		FieldType string `json:"fieldType"`
 		SubDomain string `json:"subDomain"`
 		Target    string `json:"target"`
+		TTL       uint   `json:"ttl,omitempty"`
 	}{
 		FieldType: recordType,
 		SubDomain: subdomain,
 		Target:    ipStr,
+		TTL:       p.ttl,
 	}
 	bodyBytes, err := json.Marshal(postRecordsParams)
 	if err != nil {
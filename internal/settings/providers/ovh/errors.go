@@ -2,13 +2,19 @@ package ovh
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/settings/errors"
 )
 
+// errQueryOutOfTime is returned if the timestamp of a signed
+// query is too far from the OVH server time.
+var errQueryOutOfTime = stderrors.New("query out of time")
+
 func extractAPIError(response *http.Response) (err error) {
 	decoder := json.NewDecoder(response.Body)
 	var apiError struct {
@@ -26,6 +32,10 @@ func extractAPIError(response *http.Response) (err error) {
 
 	_ = response.Body.Close()
 
+	if strings.Contains(strings.ToLower(apiError.Message), "query out of time") {
+		return fmt.Errorf("%w: %s: for query ID: %s", errQueryOutOfTime, apiError.Message, queryID)
+	}
+
 	return fmt.Errorf("%w: %s: %s: for query ID: %s",
 		errors.ErrBadHTTPStatus, response.Status, apiError.Message, queryID)
 }
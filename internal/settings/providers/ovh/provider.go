@@ -32,8 +32,12 @@ type Provider struct {
 	appKey        string
 	appSecret     string
 	consumerKey   string
+	ttl           uint
 	timeNow       func() time.Time
 	serverDelta   time.Duration
+	// serverDeltaTime is the time the server delta was last
+	// calculated, and is the zero time if it was never calculated.
+	serverDeltaTime time.Time
 }
 
 func New(data json.RawMessage, domain, host string,
@@ -47,6 +51,7 @@ func New(data json.RawMessage, domain, host string,
 		AppKey        string `json:"app_key"`
 		AppSecret     string `json:"app_secret"`
 		ConsumerKey   string `json:"consumer_key"`
+		TTL           uint   `json:"ttl"`
 	}{}
 	if err := json.Unmarshal(data, &extraSettings); err != nil {
 		return nil, err
@@ -69,6 +74,7 @@ func New(data json.RawMessage, domain, host string,
 		appKey:        extraSettings.AppKey,
 		appSecret:     extraSettings.AppSecret,
 		consumerKey:   extraSettings.ConsumerKey,
+		ttl:           extraSettings.TTL,
 		timeNow:       time.Now,
 	}
 	if err := p.isValid(); err != nil {
@@ -104,6 +110,17 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: OVH]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.OVH
+}
+
+func (p *Provider) APIHosts() []string {
+	if p.mode == "api" {
+		return []string{p.apiURL.Hostname()}
+	}
+	return []string{"www.ovh.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -120,6 +137,10 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -205,33 +226,49 @@ func (p *Provider) updateWithZoneDNS(ctx context.Context, client *http.Client, i
 		subDomain = ""
 	}
 
+	err = p.updateZoneRecords(ctx, client, recordType, subDomain, ipStr)
+	if stderrors.Is(err, errQueryOutOfTime) {
+		// the clock of this machine or of the OVH server drifted since the
+		// server time delta was calculated, so calculate it again and retry.
+		p.serverDeltaTime = time.Time{}
+		err = p.updateZoneRecords(ctx, client, recordType, subDomain, ipStr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ip, nil
+}
+
+func (p *Provider) updateZoneRecords(ctx context.Context, client *http.Client,
+	recordType, subDomain, ipStr string) (err error) {
 	timestamp, err := p.getAdjustedUnixTimestamp(ctx, client)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrGetAdjustedTime, err)
+		return fmt.Errorf("%w: %s", ErrGetAdjustedTime, err)
 	}
 
 	recordIDs, err := p.getRecords(ctx, client, recordType, subDomain, timestamp)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+		return fmt.Errorf("%s: %w", errors.ErrListRecords, err)
 	}
 
 	if len(recordIDs) == 0 {
 		if err := p.createRecord(ctx, client, recordType, subDomain, ipStr, timestamp); err != nil {
-			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+			return fmt.Errorf("%s: %w", errors.ErrCreateRecord, err)
 		}
 	} else {
 		for _, recordID := range recordIDs {
 			if err := p.updateRecord(ctx, client, recordID, ipStr, timestamp); err != nil {
-				return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+				return fmt.Errorf("%s: %w", errors.ErrUpdateRecord, err)
 			}
 		}
 	}
 
 	if err := p.refresh(ctx, client, timestamp); err != nil {
-		return nil, fmt.Errorf("%w: %s", ErrRefresh, err)
+		return fmt.Errorf("%s: %w", ErrRefresh, err)
 	}
 
-	return ip, nil
+	return nil
 }
 
 func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
@@ -0,0 +1,101 @@
+package ovh
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update_api(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		responses []providertest.Response
+		requests  []string
+		bodies    []string
+	}{
+		"record created": {
+			responses: []providertest.Response{
+				{Body: `1700000000`},
+				{Body: `[]`},
+				{Body: `{}`},
+				{Body: `null`},
+			},
+			requests: []string{
+				"GET /1.0/auth/time",
+				"GET /1.0/domain/zone/domain.com/record?fieldType=A&subDomain=home",
+				"POST /1.0/domain/zone/domain.com/record",
+				"POST /1.0/domain/zone/domain.com/refresh",
+			},
+			bodies: []string{
+				"",
+				"",
+				`{"fieldType":"A","subDomain":"home","target":"203.0.113.1","ttl":60}`,
+				"",
+			},
+		},
+		"records updated": {
+			responses: []providertest.Response{
+				{Body: `1700000000`},
+				{Body: `[1, 2]`},
+				{Body: `null`},
+			},
+			requests: []string{
+				"GET /1.0/auth/time",
+				"GET /1.0/domain/zone/domain.com/record?fieldType=A&subDomain=home",
+				"PUT /1.0/domain/zone/domain.com/record/1",
+				"PUT /1.0/domain/zone/domain.com/record/2",
+				"POST /1.0/domain/zone/domain.com/refresh",
+			},
+			bodies: []string{
+				"",
+				"",
+				`{"target":"203.0.113.1","ttl":60}`,
+				`{"target":"203.0.113.1","ttl":60}`,
+				"",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(testCase.responses...)
+
+			data := json.RawMessage(`{"mode": "api", "app_key": "ak", "app_secret": "as",
+				"consumer_key": "ck", "ttl": 60}`)
+			provider, err := New(data, "domain.com", "home", ipversion.IP4)
+			require.NoError(t, err)
+
+			ip := net.IPv4(203, 0, 113, 1)
+			newIP, err := provider.Update(context.Background(), registrar.Client(), ip)
+			require.NoError(t, err)
+			assert.True(t, ip.Equal(newIP))
+
+			requests := registrar.Requests()
+			require.Len(t, requests, len(testCase.requests))
+			for i, request := range requests {
+				assert.Equal(t, testCase.requests[i], request.Method+" "+request.URL.RequestURI())
+				if testCase.bodies[i] == "" {
+					assert.Empty(t, request.Body)
+				} else {
+					assert.JSONEq(t, testCase.bodies[i], request.Body)
+				}
+				if i > 0 {
+					assert.Equal(t, "ck", request.Header.Get("X-Ovh-Consumer"))
+					assert.NotEmpty(t, request.Header.Get("X-Ovh-Signature"))
+				}
+			}
+		})
+	}
+}
@@ -23,11 +23,17 @@ func (p *Provider) getAdjustedUnixTimestamp(ctx context.Context, client *http.Cl
 	return adjustedTime.Unix(), nil
 }
 
+// timeDeltaMaxAge is the maximum age of the time delta calculated,
+// after which it is calculated again in case the clocks drifted.
+const timeDeltaMaxAge = time.Hour
+
 // getTimeDelta obtains the delta between the OVH server time and this machine time.
-// If it is the first time executing, it fetches the time from OVH servers to calculate the
-// delta. Otherwise, it uses the delta calculated previously.
+// If it is the first time executing or if the delta is older than timeDeltaMaxAge,
+// it fetches the time from OVH servers to calculate the delta. Otherwise, it uses
+// the delta calculated previously.
 func (p *Provider) getTimeDelta(ctx context.Context, client *http.Client) (delta time.Duration, err error) {
-	if p.serverDelta > 0 {
+	now := p.timeNow()
+	if !p.serverDeltaTime.IsZero() && now.Sub(p.serverDeltaTime) < timeDeltaMaxAge {
 		return p.serverDelta, nil
 	}
 
@@ -36,8 +42,9 @@ func (p *Provider) getTimeDelta(ctx context.Context, client *http.Client) (delta
 		return 0, err
 	}
 
-	now := p.timeNow()
-	p.serverDelta = now.Sub(ovhTime) // server delta should not change
+	now = p.timeNow()
+	p.serverDelta = now.Sub(ovhTime)
+	p.serverDeltaTime = now
 	return p.serverDelta, nil
 }
 
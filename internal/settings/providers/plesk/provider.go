@@ -0,0 +1,243 @@
+package plesk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	serverURL *url.URL
+	apiKey    string
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		ServerURL string `json:"server_url"`
+		APIKey    string `json:"api_key"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	if extraSettings.ServerURL == "" {
+		return nil, errors.ErrEmptyURL
+	}
+	serverURL, err := url.Parse(extraSettings.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an http or https URL",
+			errors.ErrMalformedURL, extraSettings.ServerURL)
+	}
+
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		serverURL: serverURL,
+		apiKey:    extraSettings.APIKey,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.apiKey == "" {
+		return errors.ErrEmptyAPIKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Plesk, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Plesk
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.serverURL.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.plesk.com/\">Plesk</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("X-API-Key", p.apiKey)
+}
+
+type dnsRecord struct {
+	ID    int    `json:"id"`
+	Type  string `json:"type"`
+	Host  string `json:"host"`
+	Value string `json:"value"`
+}
+
+// Using https://docs.plesk.com/en-US/obsidian/api-rpc/about-rest-api.79359/
+// Plesk has no API call to modify a record, so a record with the IP
+// address is created before deleting the previous records.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+	domainQuery := url.Values{"domain": {p.domain}}
+
+	var records []dnsRecord
+	err = p.doRequest(ctx, client, http.MethodGet, "/api/v2/dns/records", domainQuery, nil, &records)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrListRecords, err)
+	}
+
+	var staleRecordIDs []int
+	upToDate := false
+	for _, record := range records {
+		if record.Type != recordType ||
+			!strings.EqualFold(strings.TrimSuffix(record.Host, "."), p.BuildDomainName()) {
+			continue
+		}
+		if recordIP := net.ParseIP(record.Value); recordIP != nil && recordIP.Equal(ip) {
+			upToDate = true
+			continue
+		}
+		staleRecordIDs = append(staleRecordIDs, record.ID)
+	}
+
+	if !upToDate {
+		requestData := struct {
+			Type  string `json:"type"`
+			Host  string `json:"host"`
+			Value string `json:"value"`
+		}{
+			Type:  recordType,
+			Host:  p.recordHost(),
+			Value: ip.String(),
+		}
+		err = p.doRequest(ctx, client, http.MethodPost, "/api/v2/dns/records", domainQuery, requestData, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+	}
+
+	for _, recordID := range staleRecordIDs {
+		path := "/api/v2/dns/records/" + strconv.Itoa(recordID)
+		err = p.doRequest(ctx, client, http.MethodDelete, path, nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrRemoveRecord, err)
+		}
+	}
+	return ip, nil
+}
+
+// recordHost returns the host of the record as expected by Plesk,
+// which is empty for the root of the domain.
+func (p *Provider) recordHost() string {
+	if p.host == "@" {
+		return ""
+	}
+	return p.host
+}
+
+// doRequest sends the request data encoded as JSON if it is not nil,
+// and decodes the JSON response into the response data if it is not nil.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client, method, path string,
+	query url.Values, requestData, responseData interface{}) (err error) {
+	u := *p.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawQuery = query.Encode()
+
+	var body io.Reader
+	if requestData != nil {
+		buffer := bytes.NewBuffer(nil)
+		if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+			return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusBadRequest:
+		return fmt.Errorf("%w: %s", errors.ErrBadRequest, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	if responseData == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(responseData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	return nil
+}
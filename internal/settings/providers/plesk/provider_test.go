@@ -0,0 +1,110 @@
+package plesk
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newProvider := func(t *testing.T, host string) *Provider {
+		t.Helper()
+		data := json.RawMessage(`{"server_url": "https://plesk.example.com:8443", "api_key": "key"}`)
+		provider, err := New(data, "domain.com", host, ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		return newProvider(t, "home")
+	}
+	responses := func(records string) []providertest.Response {
+		return []providertest.Response{
+			{Body: records},
+			{Body: `{"id": 3}`},
+		}
+	}
+	check := func(createdBody string) func(t *testing.T, requests []providertest.Request) {
+		return func(t *testing.T, requests []providertest.Request) {
+			for _, request := range requests {
+				assert.Equal(t, "key", request.Header.Get("X-API-Key"))
+			}
+			if createdBody != "" {
+				assert.Equal(t, createdBody, requests[1].Body)
+			}
+		}
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `[]`},
+				{Body: `{"id": 1}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"stale record replaced": {
+				Responses: responses(`[{"id": 1, "type": "A", "host": "home.domain.com.", "value": "203.0.113.2"},` +
+					`{"id": 2, "type": "A", "host": "other.domain.com.", "value": "203.0.113.2"}]`),
+				Requests: []string{
+					"GET /api/v2/dns/records?domain=domain.com",
+					"POST /api/v2/dns/records?domain=domain.com",
+					"DELETE /api/v2/dns/records/1",
+				},
+				Check: check(`{"type":"A","host":"home","value":"203.0.113.1"}` + "\n"),
+			},
+			"root record created": {
+				Updater:   newProvider(t, "@"),
+				Responses: responses(`[{"id": 1, "type": "AAAA", "host": "domain.com.", "value": "2001:db8::1"}]`),
+				Requests: []string{
+					"GET /api/v2/dns/records?domain=domain.com",
+					"POST /api/v2/dns/records?domain=domain.com",
+				},
+				Check: check(`{"type":"A","host":"","value":"203.0.113.1"}` + "\n"),
+			},
+			"record up to date": {
+				Responses: responses(`[{"id": 1, "type": "A", "host": "home.domain.com.", "value": "203.0.113.1"}]`),
+				Requests:  []string{"GET /api/v2/dns/records?domain=domain.com"},
+				Check:     check(""),
+			},
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server_url": "https://plesk.example.com:8443", "api_key": "key"}`,
+		},
+		"empty URL": {
+			data:       `{"api_key": "key"}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"empty API key": {
+			data:       `{"server_url": "https://plesk.example.com:8443"}`,
+			errWrapped: errors.ErrEmptyAPIKey,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
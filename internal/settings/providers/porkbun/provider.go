@@ -64,6 +64,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: Porkbun]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Porkbun
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"porkbun.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -80,6 +88,10 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -0,0 +1,308 @@
+package powerdns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain      string
+	host        string
+	ipVersion   ipversion.IPVersion
+	serverURL   *url.URL
+	serverID    string
+	apiKey      string
+	ttl         uint
+	rrSetMember bool
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		ServerURL   string `json:"server_url"`
+		ServerID    string `json:"server_id"`
+		APIKey      string `json:"api_key"`
+		TTL         uint   `json:"ttl"`
+		RRSetMember bool   `json:"rrset_member"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	if extraSettings.ServerURL == "" {
+		return nil, errors.ErrEmptyURL
+	}
+	serverURL, err := url.Parse(extraSettings.ServerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (serverURL.Scheme != "http" && serverURL.Scheme != "https") || serverURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an http or https URL",
+			errors.ErrMalformedURL, extraSettings.ServerURL)
+	}
+
+	serverID := extraSettings.ServerID
+	if serverID == "" {
+		serverID = "localhost"
+	}
+	const defaultTTL = 300
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+
+	p = &Provider{
+		domain:      domain,
+		host:        host,
+		ipVersion:   ipVersion,
+		serverURL:   serverURL,
+		serverID:    serverID,
+		apiKey:      extraSettings.APIKey,
+		ttl:         ttl,
+		rrSetMember: extraSettings.RRSetMember,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.apiKey == "" {
+		return errors.ErrEmptyAPIKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.PowerDNS, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.PowerDNS
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{p.serverURL.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) IsRRSetMember() bool {
+	return p.rrSetMember
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.powerdns.com/\">PowerDNS</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.Header.Set("X-API-Key", p.apiKey)
+}
+
+type rrSet struct {
+	Name       string   `json:"name"`
+	Type       string   `json:"type"`
+	TTL        uint     `json:"ttl"`
+	ChangeType string   `json:"changetype,omitempty"`
+	Records    []record `json:"records"`
+}
+
+type record struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	return p.UpdateMember(ctx, client, nil, ip)
+}
+
+// UpdateMember replaces the record set of the host with the IP address,
+// or only replaces the previous IP address in the record set if the
+// record is configured as a member of the record set.
+func (p *Provider) UpdateMember(ctx context.Context, client *http.Client,
+	previousIP, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	records := []record{{Content: ip.String()}}
+	if p.rrSetMember {
+		records, err = p.getMembers(ctx, client, recordType)
+		if err != nil {
+			return nil, fmt.Errorf("getting record set: %w", err)
+		}
+		records = replaceMember(records, previousIP, ip)
+	}
+
+	err = p.patchRRSet(ctx, client, recordType, records)
+	if err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+// replaceMember returns the records of the record set with the previous
+// IP address replaced by the IP address, keeping the disabled state of
+// the other records.
+func replaceMember(records []record, previousIP, ip net.IP) (newRecords []record) {
+	contents := make([]string, len(records))
+	contentToDisabled := make(map[string]bool, len(records))
+	for i, existing := range records {
+		contents[i] = existing.Content
+		contentToDisabled[existing.Content] = existing.Disabled
+	}
+	contents = utils.ReplaceMember(contents, previousIP, ip)
+	newRecords = make([]record, len(contents))
+	for i, content := range contents {
+		newRecords[i] = record{Content: content, Disabled: contentToDisabled[content]}
+	}
+	newRecords[len(newRecords)-1].Disabled = false
+	return newRecords
+}
+
+func (p *Provider) zoneURL() *url.URL {
+	// Zone IDs and RRset names are canonical, with a trailing dot.
+	zoneID := strings.TrimSuffix(p.domain, ".") + "."
+	u := *p.serverURL
+	u.Path = strings.TrimSuffix(u.Path, "/") +
+		"/api/v1/servers/" + p.serverID + "/zones/" + zoneID
+	return &u
+}
+
+// Using https://doc.powerdns.com/authoritative/http-api/zone.html#get--servers-server_id-zones-zone_id
+func (p *Provider) getMembers(ctx context.Context, client *http.Client,
+	recordType string) (records []record, err error) {
+	name := p.BuildDomainName() + "."
+	u := p.zoneURL()
+	values := url.Values{}
+	values.Set("rrset_name", name)
+	values.Set("rrset_type", recordType)
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, checkResponse(response)
+	}
+
+	var zone struct {
+		RRSets []rrSet `json:"rrsets"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&zone); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+	// Older servers ignore the rrset filters and return all the record sets.
+	for _, rrSet := range zone.RRSets {
+		if rrSet.Name == name && rrSet.Type == recordType {
+			return rrSet.Records, nil
+		}
+	}
+	return nil, nil
+}
+
+// Using https://doc.powerdns.com/authoritative/http-api/zone.html#patch--servers-server_id-zones-zone_id
+func (p *Provider) patchRRSet(ctx context.Context, client *http.Client,
+	recordType string, records []record) (err error) {
+	payload := struct {
+		RRSets []rrSet `json:"rrsets"`
+	}{
+		RRSets: []rrSet{{
+			Name:       p.BuildDomainName() + ".",
+			Type:       recordType,
+			TTL:        p.ttl,
+			ChangeType: "REPLACE",
+			Records:    records,
+		}},
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(payload); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPatch, p.zoneURL().String(), buffer)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusNoContent, http.StatusOK:
+		return nil
+	default:
+		return checkResponse(response)
+	}
+}
+
+func checkResponse(response *http.Response) error {
+	switch response.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusUnprocessableEntity:
+		return fmt.Errorf("%w: %s", errors.ErrBadRequest, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,158 @@
+package powerdns
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"server_url": "http://pdns:8081", "api_key": "key"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Status: http.StatusNoContent},
+			}
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server_url": "https://pdns.example.com/prefix/", "api_key": "key"}`,
+		},
+		"empty URL": {
+			data:       `{"api_key": "key"}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"URL without scheme": {
+			data:       `{"server_url": "pdns:8081", "api_key": "key"}`,
+			errWrapped: errors.ErrMalformedURL,
+		},
+		"empty API key": {
+			data:       `{"server_url": "http://pdns:8081"}`,
+			errWrapped: errors.ErrEmptyAPIKey,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
+
+func Test_Provider_UpdateMember(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		previousIP net.IP
+		responses  []providertest.Response
+		requests   []string
+		patchBody  string
+		errWrapped error
+	}{
+		"replace record set": {
+			data:       `{"server_url": "http://pdns:8081", "api_key": "key"}`,
+			previousIP: net.IPv4(198, 51, 100, 1),
+			responses:  []providertest.Response{{Status: http.StatusNoContent}},
+			requests:   []string{"PATCH /api/v1/servers/localhost/zones/domain.com."},
+			patchBody: `{"rrsets":[{"name":"home.domain.com.","type":"A","ttl":300,"changetype":"REPLACE",` +
+				`"records":[{"content":"203.0.113.1","disabled":false}]}]}`,
+		},
+		"replace member": {
+			data:       `{"server_url": "http://pdns:8081", "api_key": "key", "rrset_member": true}`,
+			previousIP: net.IPv4(198, 51, 100, 1),
+			responses: []providertest.Response{
+				{Body: `{"rrsets":[{"name":"home.domain.com.","type":"A","ttl":300,"records":[` +
+					`{"content":"198.51.100.1","disabled":false},{"content":"192.0.2.1","disabled":true}]}]}`},
+				{Status: http.StatusNoContent},
+			},
+			requests: []string{
+				"GET /api/v1/servers/localhost/zones/domain.com.?rrset_name=home.domain.com.&rrset_type=A",
+				"PATCH /api/v1/servers/localhost/zones/domain.com.",
+			},
+			patchBody: `{"rrsets":[{"name":"home.domain.com.","type":"A","ttl":300,"changetype":"REPLACE",` +
+				`"records":[{"content":"192.0.2.1","disabled":true},{"content":"203.0.113.1","disabled":false}]}]}`,
+		},
+		"new member": {
+			data: `{"server_url": "http://pdns:8081", "api_key": "key", "rrset_member": true}`,
+			responses: []providertest.Response{
+				{Body: `{"rrsets":[]}`},
+				{Status: http.StatusNoContent},
+			},
+			requests: []string{
+				"GET /api/v1/servers/localhost/zones/domain.com.?rrset_name=home.domain.com.&rrset_type=A",
+				"PATCH /api/v1/servers/localhost/zones/domain.com.",
+			},
+			patchBody: `{"rrsets":[{"name":"home.domain.com.","type":"A","ttl":300,"changetype":"REPLACE",` +
+				`"records":[{"content":"203.0.113.1","disabled":false}]}]}`,
+		},
+		"zone not found": {
+			data:       `{"server_url": "http://pdns:8081", "api_key": "key", "rrset_member": true}`,
+			responses:  []providertest.Response{{Status: http.StatusNotFound}},
+			requests:   []string{"GET /api/v1/servers/localhost/zones/domain.com.?rrset_name=home.domain.com.&rrset_type=A"},
+			errWrapped: errors.ErrZoneNotFound,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(testCase.responses...)
+
+			provider, err := New(json.RawMessage(testCase.data), "domain.com", "home", ipversion.IP4)
+			require.NoError(t, err)
+			ip := net.IPv4(203, 0, 113, 1)
+			newIP, err := provider.UpdateMember(context.Background(), registrar.Client(),
+				testCase.previousIP, ip)
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+			} else {
+				require.NoError(t, err)
+				assert.True(t, ip.Equal(newIP))
+			}
+
+			requests := registrar.Requests()
+			require.Len(t, requests, len(testCase.requests))
+			for i, request := range requests {
+				assert.Equal(t, testCase.requests[i], request.Method+" "+request.URL.RequestURI())
+				assert.Equal(t, "key", request.Header.Get("X-API-Key"))
+				if request.Method == http.MethodPatch {
+					assert.JSONEq(t, testCase.patchBody, request.Body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package regru
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	username  string
+	password  string
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		username:  extraSettings.Username,
+		password:  extraSettings.Password,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.username == "":
+		return errors.ErrEmptyUsername
+	case p.password == "":
+		return errors.ErrEmptyPassword
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Regru, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Regru
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.reg.ru"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.reg.ru/\">Reg.ru</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+type action struct {
+	Action     string `json:"action"`
+	Subdomain  string `json:"subdomain"`
+	RecordType string `json:"record_type,omitempty"`
+	IPAddress  string `json:"ipaddr,omitempty"`
+}
+
+type domain struct {
+	DName string `json:"dname"`
+}
+
+type apiError struct {
+	Result    string `json:"result"`
+	ErrorCode string `json:"error_code"`
+	ErrorText string `json:"error_text"`
+}
+
+// Using https://www.reg.ru/reseller/api2doc#zone_update_records
+// The records of the host are removed and added again in a single
+// call, since there is no action to modify a record.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType, addAction := constants.A, "add_alias"
+	if ip.To4() == nil {
+		recordType, addAction = constants.AAAA, "add_aaaa"
+	}
+
+	inputData := struct {
+		Username          string   `json:"username"`
+		Password          string   `json:"password"`
+		Domains           []domain `json:"domains"`
+		ActionList        []action `json:"action_list"`
+		OutputContentType string   `json:"output_content_type"`
+	}{
+		Username: p.username,
+		Password: p.password,
+		Domains:  []domain{{DName: p.domain}},
+		ActionList: []action{
+			{Action: "remove_record", Subdomain: p.host, RecordType: recordType},
+			{Action: addAction, Subdomain: p.host, IPAddress: ip.String()},
+		},
+		OutputContentType: "plain",
+	}
+	encodedInputData, err := json.Marshal(inputData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrRequestMarshal, err)
+	}
+	values := url.Values{
+		"input_format": {"json"},
+		"input_data":   {string(encodedInputData)},
+	}
+
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.reg.ru",
+		Path:   "/api/regru2/zone/update_records",
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(),
+		strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/x-www-form-urlencoded")
+	headers.SetAccept(request, "application/json")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		apiError
+		Answer struct {
+			Domains []struct {
+				DName string `json:"dname"`
+				apiError
+			} `json:"domains"`
+		} `json:"answer"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	if err := data.apiError.toError(); err != nil {
+		return nil, err
+	}
+	if len(data.Answer.Domains) != 1 {
+		return nil, fmt.Errorf("%w: %d domains in answer",
+			errors.ErrNumberOfResultsReceived, len(data.Answer.Domains))
+	}
+	if err := data.Answer.Domains[0].apiError.toError(); err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+func (e apiError) toError() error {
+	switch e.Result {
+	case "success":
+		return nil
+	case "error":
+	default:
+		return fmt.Errorf("%w: result %q", errors.ErrUnknownResponse, e.Result)
+	}
+
+	message := e.ErrorCode + ": " + e.ErrorText
+	switch e.ErrorCode {
+	case "NO_AUTH", "PASSWORD_AUTH_FAILED", "INVALID_AUTH", "ACCESS_DENIED",
+		"ACCESS_DENIED_FROM_IP", "NO_SUCH_USER":
+		return fmt.Errorf("%w: %s", errors.ErrAuth, message)
+	case "DOMAIN_NOT_FOUND", "NO_DOMAIN", "DOMAIN_IS_NOT_USE_REGRU_NSS":
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, message)
+	case "IP_EXCEEDED_ALLOWED_CONNECTION_RATE", "ACCOUNT_EXCEEDED_ALLOWED_CONNECTION_RATE":
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, message)
+	default:
+		return fmt.Errorf("%w: %s", errors.ErrBadRequest, message)
+	}
+}
@@ -0,0 +1,65 @@
+package regru
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const successBody = `{"result": "success", "answer": {"domains": [{"dname": "domain.ru", "result": "success"}]}}`
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"username": "user", "password": "password"}`)
+		provider, err := New(data, "domain.ru", "home", ipversion.IP4or6)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{{Body: successBody}}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"IPv6 address": {
+				IP:        net.ParseIP("2001:db8::1"),
+				Responses: []providertest.Response{{Body: successBody}},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					require.Len(t, requests, 1)
+					values, err := url.ParseQuery(requests[0].Body)
+					require.NoError(t, err)
+					var inputData struct {
+						ActionList json.RawMessage `json:"action_list"`
+					}
+					require.NoError(t, json.Unmarshal([]byte(values.Get("input_data")), &inputData))
+					const expected = `[{"action":"remove_record","subdomain":"home","record_type":"AAAA"},` +
+						`{"action":"add_aaaa","subdomain":"home","ipaddr":"2001:db8::1"}]`
+					assert.JSONEq(t, expected, string(inputData.ActionList))
+				},
+			},
+			"authentication error": {
+				Responses: []providertest.Response{
+					{Body: `{"result": "error", "error_code": "PASSWORD_AUTH_FAILED", "error_text": "bad password"}`},
+				},
+				ErrWrapped: errors.ErrAuth,
+			},
+			"domain error": {
+				Responses: []providertest.Response{
+					{Body: `{"result": "success", "answer": {"domains": [{"dname": "domain.ru", ` +
+						`"result": "error", "error_code": "DOMAIN_NOT_FOUND"}]}}`},
+				},
+				ErrWrapped: errors.ErrZoneNotFound,
+			},
+		},
+	})
+}
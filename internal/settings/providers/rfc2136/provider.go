@@ -0,0 +1,212 @@
+package rfc2136
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain     string
+	host       string
+	ipVersion  ipversion.IPVersion
+	nameserver string
+	zone       string
+	keyName    string
+	secret     string
+	algorithm  string
+	transport  string
+	ttl        uint32
+}
+
+//nolint:gochecknoglobals
+var algorithms = map[string]string{
+	"hmac-sha1":   dns.HmacSHA1,
+	"hmac-sha224": dns.HmacSHA224,
+	"hmac-sha256": dns.HmacSHA256,
+	"hmac-sha384": dns.HmacSHA384,
+	"hmac-sha512": dns.HmacSHA512,
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		Nameserver string `json:"nameserver"`
+		Zone       string `json:"zone"`
+		KeyName    string `json:"key_name"`
+		Secret     string `json:"secret"`
+		Algorithm  string `json:"algorithm"`
+		Transport  string `json:"transport"`
+		TTL        uint32 `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	nameserver := extraSettings.Nameserver
+	if _, _, err := net.SplitHostPort(nameserver); err != nil && nameserver != "" {
+		nameserver = net.JoinHostPort(nameserver, "53")
+	}
+	zone := extraSettings.Zone
+	if zone == "" {
+		zone = domain
+	}
+	algorithm := extraSettings.Algorithm
+	if algorithm == "" {
+		algorithm = "hmac-sha256"
+	}
+	transport := extraSettings.Transport
+	if transport == "" {
+		transport = "udp"
+	}
+	const defaultTTL = 300
+	ttl := uint32(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+
+	p = &Provider{
+		domain:     domain,
+		host:       host,
+		ipVersion:  ipVersion,
+		nameserver: nameserver,
+		zone:       dns.Fqdn(strings.ToLower(zone)),
+		keyName:    dns.Fqdn(strings.ToLower(extraSettings.KeyName)),
+		secret:     extraSettings.Secret,
+		algorithm:  strings.ToLower(strings.TrimSuffix(algorithm, ".")),
+		transport:  transport,
+		ttl:        ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.nameserver == "":
+		return fmt.Errorf("%w: nameserver is not set", errors.ErrEmptyHost)
+	case p.keyName == ".":
+		return errors.ErrEmptyName
+	case p.secret == "":
+		return errors.ErrEmptySecret
+	}
+	if _, err := base64.StdEncoding.DecodeString(p.secret); err != nil {
+		return fmt.Errorf("%w: secret is not base64 encoded", errors.ErrMalformedKey)
+	}
+	if _, ok := algorithms[p.algorithm]; !ok {
+		return fmt.Errorf("%w: algorithm %q is not supported", errors.ErrMalformedKey, p.algorithm)
+	}
+	if p.transport != "udp" && p.transport != "tcp" {
+		return fmt.Errorf("%w: transport %q must be udp or tcp", errors.ErrBadRequest, p.transport)
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.RFC2136, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.RFC2136
+}
+
+func (p *Provider) APIHosts() []string {
+	return nil // DNS messages are not sent through the program HTTP client
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return uint(p.ttl)
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.rfc-editor.org/rfc/rfc2136\">RFC 2136</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Update replaces the A or AAAA record set of the host with the IP address
+// using a DNS UPDATE message signed with TSIG, as described in RFC 2136
+// and RFC 8945. The HTTP client is not used.
+func (p *Provider) Update(ctx context.Context, _ *http.Client, ip net.IP) (newIP net.IP, err error) {
+	header := dns.RR_Header{
+		Name:   dns.Fqdn(p.BuildDomainName()),
+		Class:  dns.ClassINET,
+		Ttl:    p.ttl,
+		Rrtype: dns.TypeA,
+	}
+	var rr dns.RR = &dns.A{Hdr: header, A: ip.To4()}
+	if ip.To4() == nil {
+		header.Rrtype = dns.TypeAAAA
+		rr = &dns.AAAA{Hdr: header, AAAA: ip}
+	}
+
+	message := new(dns.Msg)
+	message.SetUpdate(p.zone)
+	message.RemoveRRset([]dns.RR{rr})
+	message.Insert([]dns.RR{rr})
+	const fudge = 300
+	message.SetTsig(p.keyName, algorithms[p.algorithm], fudge, time.Now().Unix())
+
+	client := &dns.Client{
+		Net:        p.transport,
+		TsigSecret: map[string]string{p.keyName: p.secret},
+	}
+	response, _, err := client.ExchangeContext(ctx, message, p.nameserver)
+	if err != nil {
+		if goerrors.Is(err, dns.ErrSig) || goerrors.Is(err, dns.ErrSecret) || goerrors.Is(err, dns.ErrTime) {
+			return nil, fmt.Errorf("%w: %s", errors.ErrAuth, err)
+		}
+		return nil, err
+	}
+
+	switch response.Rcode {
+	case dns.RcodeSuccess:
+		return ip, nil
+	case dns.RcodeNotAuth, dns.RcodeRefused, dns.RcodeBadSig, dns.RcodeBadKey, dns.RcodeBadTime:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, dns.RcodeToString[response.Rcode])
+	case dns.RcodeNotZone:
+		return nil, fmt.Errorf("%w: %s", errors.ErrZoneNotFound, p.zone)
+	case dns.RcodeServerFailure:
+		return nil, fmt.Errorf("%w: %s", errors.ErrDNSServerSide, dns.RcodeToString[response.Rcode])
+	default:
+		return nil, fmt.Errorf("%w: %s", errors.ErrBadRequest, dns.RcodeToString[response.Rcode])
+	}
+}
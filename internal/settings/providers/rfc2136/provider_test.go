@@ -0,0 +1,147 @@
+package rfc2136
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0c2VjcmV0MTI=" // base64 of 32 bytes
+
+// startServer starts a DNS server accepting updates signed with the
+// test key, and sending the update messages received to the channel.
+func startServer(t *testing.T, updates chan<- *dns.Msg) (address string) {
+	t.Helper()
+
+	packetConn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	server := &dns.Server{
+		PacketConn:        packetConn,
+		TsigSecret:        map[string]string{"ddns-key.": testSecret},
+		NotifyStartedFunc: func() { close(started) },
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction {
+			return dns.MsgAccept // default function rejects updates
+		},
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, request *dns.Msg) {
+			response := new(dns.Msg)
+			response.SetReply(request)
+			if request.IsTsig() == nil || w.TsigStatus() != nil {
+				response.SetRcode(request, dns.RcodeNotAuth)
+			} else {
+				updates <- request
+				tsig := request.IsTsig()
+				response.SetTsig(tsig.Hdr.Name, tsig.Algorithm, tsig.Fudge, time.Now().Unix())
+			}
+			_ = w.WriteMsg(response)
+		}),
+	}
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+	<-started
+	return packetConn.LocalAddr().String()
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	updates := make(chan *dns.Msg, 1)
+	address := startServer(t, updates)
+
+	testCases := map[string]struct {
+		secret     string
+		ip         net.IP
+		rr         string
+		errWrapped error
+	}{
+		"IPv4 address": {
+			secret: testSecret,
+			ip:     net.IPv4(1, 2, 3, 4),
+			rr:     "home.example.com.\t300\tIN\tA\t1.2.3.4",
+		},
+		"IPv6 address": {
+			secret: testSecret,
+			ip:     net.ParseIP("2001:db8::1"),
+			rr:     "home.example.com.\t300\tIN\tAAAA\t2001:db8::1",
+		},
+		"wrong secret": {
+			secret:     "b3RoZXJzZWNyZXQ=",
+			ip:         net.IPv4(1, 2, 3, 4),
+			errWrapped: errors.ErrAuth,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) { // not parallel since sharing the server
+			data := json.RawMessage(`{"nameserver": "` + address +
+				`", "key_name": "ddns-key", "secret": "` + testCase.secret + `"}`)
+			provider, err := New(data, "example.com", "home", ipversion.IP4or6)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			newIP, err := provider.Update(ctx, nil, testCase.ip)
+
+			require.ErrorIs(t, err, testCase.errWrapped)
+			if testCase.errWrapped != nil {
+				return
+			}
+			assert.True(t, testCase.ip.Equal(newIP))
+			update := <-updates
+			assert.Equal(t, "example.com.", update.Question[0].Name)
+			require.Len(t, update.Ns, 2)
+			assert.Equal(t, uint16(dns.ClassANY), update.Ns[0].Header().Class) // delete RRset
+			assert.Equal(t, testCase.rr, update.Ns[1].String())
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"nameserver": "ns.example.com", "key_name": "key", "secret": "` + testSecret + `"}`,
+		},
+		"empty nameserver": {
+			data:       `{"key_name": "key", "secret": "` + testSecret + `"}`,
+			errWrapped: errors.ErrEmptyHost,
+		},
+		"secret not base64": {
+			data:       `{"nameserver": "ns.example.com", "key_name": "key", "secret": "not base64!"}`,
+			errWrapped: errors.ErrMalformedKey,
+		},
+		"unsupported algorithm": {
+			data: `{"nameserver": "ns.example.com", "key_name": "key", "secret": "` + testSecret +
+				`", "algorithm": "hmac-md5"}`,
+			errWrapped: errors.ErrMalformedKey,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "example.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
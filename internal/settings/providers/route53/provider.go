@@ -0,0 +1,361 @@
+package route53
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain      string
+	host        string
+	ipVersion   ipversion.IPVersion
+	credentials credentials
+	roleARN     string
+	zoneID      string
+	ttl         uint32
+	rrSetMember bool
+	// clockDelta is the time difference between the AWS servers
+	// and this machine, detected when a request is rejected
+	// because its signature time is too skewed.
+	clockDelta time.Duration
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		AccessKey    string `json:"access_key"`
+		SecretKey    string `json:"secret_key"`
+		SessionToken string `json:"session_token"`
+		RoleARN      string `json:"role_arn"`
+		ZoneID       string `json:"zone_id"`
+		TTL          uint32 `json:"ttl"`
+		RRSetMember  bool   `json:"rrset_member"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 300
+	if extraSettings.TTL == 0 {
+		extraSettings.TTL = defaultTTL
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		credentials: credentials{
+			accessKeyID:     extraSettings.AccessKey,
+			secretAccessKey: extraSettings.SecretKey,
+			sessionToken:    extraSettings.SessionToken,
+		},
+		roleARN:     extraSettings.RoleARN,
+		zoneID:      strings.TrimPrefix(extraSettings.ZoneID, "/hostedzone/"),
+		ttl:         extraSettings.TTL,
+		rrSetMember: extraSettings.RRSetMember,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.credentials.accessKeyID == "":
+		return errors.ErrEmptyAccessKeyID
+	case p.credentials.secretAccessKey == "":
+		return errors.ErrEmptyAccessKeySecret
+	case p.zoneID == "":
+		return errors.ErrEmptyZoneIdentifier
+	case p.roleARN != "" && !strings.HasPrefix(p.roleARN, "arn:"):
+		return fmt.Errorf("%w: %s", errors.ErrMalformedRoleARN, p.roleARN)
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Route53, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Route53
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"route53.amazonaws.com", "sts.amazonaws.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return uint(p.ttl)
+}
+
+func (p *Provider) IsRRSetMember() bool {
+	return p.rrSetMember
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://aws.amazon.com/route53/\">Route53</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+type (
+	changeResourceRecordSetsRequest struct {
+		XMLName     xml.Name    `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+		ChangeBatch changeBatch `xml:"ChangeBatch"`
+	}
+	changeBatch struct {
+		Changes []change `xml:"Changes>Change"`
+	}
+	change struct {
+		Action            string            `xml:"Action"`
+		ResourceRecordSet resourceRecordSet `xml:"ResourceRecordSet"`
+	}
+	resourceRecordSet struct {
+		Name            string           `xml:"Name"`
+		Type            string           `xml:"Type"`
+		TTL             uint32           `xml:"TTL"`
+		ResourceRecords []resourceRecord `xml:"ResourceRecords>ResourceRecord"`
+	}
+	resourceRecord struct {
+		Value string `xml:"Value"`
+	}
+	listResourceRecordSetsResponse struct {
+		ResourceRecordSets []resourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+	}
+	errorResponse struct {
+		Errors []struct {
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+	}
+)
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	return p.UpdateMember(ctx, client, nil, ip)
+}
+
+// UpdateMember upserts the record set of the host with the IP address,
+// or only replaces the previous IP address in the record set if the
+// record is configured as a member of the record set.
+func (p *Provider) UpdateMember(ctx context.Context, client *http.Client,
+	previousIP, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	signingCredentials := p.credentials
+	if p.roleARN != "" {
+		now := time.Now().Add(p.clockDelta)
+		signingCredentials, err = assumeRole(ctx, client, p.credentials, p.roleARN, now)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrAssumeRole, err)
+		}
+	}
+
+	name := utils.BuildURLQueryHostname(p.host, p.domain) + "."
+	values := []string{ip.String()}
+	if p.rrSetMember {
+		members, err := p.listMembers(ctx, client, signingCredentials, name, recordType)
+		if err != nil {
+			return nil, fmt.Errorf("listing record set: %w", err)
+		}
+		values = utils.ReplaceMember(members, previousIP, ip)
+	}
+
+	err = p.changeResourceRecordSet(ctx, client, signingCredentials, name, recordType, values)
+	if err != nil {
+		return nil, err
+	}
+	return ip, nil
+}
+
+// Using https://docs.aws.amazon.com/Route53/latest/APIReference/API_ListResourceRecordSets.html
+func (p *Provider) listMembers(ctx context.Context, client *http.Client,
+	signingCredentials credentials, name, recordType string) (members []string, err error) {
+	query := url.Values{}
+	query.Set("name", name)
+	query.Set("type", recordType)
+	query.Set("maxitems", "1")
+	var response listResourceRecordSetsResponse
+	err = p.request(ctx, client, signingCredentials, http.MethodGet, query, nil, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record sets are listed starting from the name and type given,
+	// so the first record set returned can be of another host.
+	for _, recordSet := range response.ResourceRecordSets {
+		recordSetName := strings.ReplaceAll(recordSet.Name, `\052`, "*")
+		if strings.EqualFold(recordSetName, name) && recordSet.Type == recordType {
+			members = make([]string, len(recordSet.ResourceRecords))
+			for i, resourceRecord := range recordSet.ResourceRecords {
+				members[i] = resourceRecord.Value
+			}
+			return members, nil
+		}
+	}
+	return nil, nil
+}
+
+// Using https://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html
+func (p *Provider) changeResourceRecordSet(ctx context.Context, client *http.Client,
+	signingCredentials credentials, name, recordType string, values []string) (err error) {
+	resourceRecords := make([]resourceRecord, len(values))
+	for i, value := range values {
+		resourceRecords[i].Value = value
+	}
+	requestData := changeResourceRecordSetsRequest{
+		ChangeBatch: changeBatch{
+			Changes: []change{{
+				Action: "UPSERT",
+				ResourceRecordSet: resourceRecordSet{
+					Name:            name,
+					Type:            recordType,
+					TTL:             p.ttl,
+					ResourceRecords: resourceRecords,
+				},
+			}},
+		},
+	}
+	body := bytes.NewBufferString(xml.Header)
+	if err := xml.NewEncoder(body).Encode(requestData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	return p.request(ctx, client, signingCredentials, http.MethodPost, nil, body.Bytes(), nil)
+}
+
+// request sends a signed request to the resource record sets of the hosted
+// zone, and decodes the response into result if it is not nil. If the request
+// is rejected because the time of this machine is too skewed, the clock delta
+// is adjusted using the date of the response and the request is sent again.
+func (p *Provider) request(ctx context.Context, client *http.Client, signingCredentials credentials,
+	method string, query url.Values, payload []byte, result interface{}) (err error) {
+	now := time.Now().Add(p.clockDelta)
+	skewed, err := p.doRequest(ctx, client, signingCredentials, method, query, payload, result, now)
+	if skewed {
+		// the clock delta got adjusted, so retry once with the adjusted time.
+		now = time.Now().Add(p.clockDelta)
+		_, err = p.doRequest(ctx, client, signingCredentials, method, query, payload, result, now)
+	}
+	return err
+}
+
+// doRequest sends the signed request with the payload given.
+// If the request is rejected because the time of this machine is too skewed,
+// skewed is returned as true and the clock delta is adjusted using the
+// date of the response.
+func (p *Provider) doRequest(ctx context.Context, client *http.Client,
+	signingCredentials credentials, method string, query url.Values, payload []byte,
+	result interface{}, now time.Time) (skewed bool, err error) {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "route53.amazonaws.com",
+		Path:     "/2013-04-01/hostedzone/" + p.zoneID + "/rrset",
+		RawQuery: query.Encode(),
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return false, err
+	}
+	headers.SetUserAgent(request)
+	if payload != nil {
+		headers.SetContentType(request, "application/xml")
+	}
+	// Route53 is a global service signed for the us-east-1 region.
+	signRequest(request, payload, signingCredentials, "us-east-1", "route53", now)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		if result == nil {
+			return false, nil
+		}
+		if err := xml.NewDecoder(response.Body).Decode(result); err != nil {
+			return false, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+		}
+		return false, nil
+	}
+
+	err = fmt.Errorf("%w: %d", errors.ErrBadHTTPStatus, response.StatusCode)
+	var errResponse errorResponse
+	if decodeErr := xml.NewDecoder(response.Body).Decode(&errResponse); decodeErr != nil ||
+		len(errResponse.Errors) == 0 {
+		return false, err
+	}
+	messages := make([]string, len(errResponse.Errors))
+	for i, e := range errResponse.Errors {
+		messages[i] = e.Code + ": " + e.Message
+	}
+	firstError := errResponse.Errors[0]
+	switch {
+	case isClockSkewError(firstError.Code, firstError.Message):
+		serverTime, parseErr := http.ParseTime(response.Header.Get("Date"))
+		if parseErr == nil {
+			p.clockDelta = serverTime.Sub(time.Now())
+			skewed = true
+		}
+		err = fmt.Errorf("%w: %s", errors.ErrAuth, err)
+	case firstError.Code == "Throttling", firstError.Code == "PriorRequestNotComplete":
+		err = fmt.Errorf("%w: %s", errors.ErrAbuse, err)
+	case firstError.Code == "InvalidClientTokenId", firstError.Code == "SignatureDoesNotMatch",
+		firstError.Code == "AccessDenied":
+		err = fmt.Errorf("%w: %s", errors.ErrAuth, err)
+	case firstError.Code == "NoSuchHostedZone":
+		err = fmt.Errorf("%w: %s", errors.ErrZoneNotFound, err)
+	}
+	return skewed, fmt.Errorf("%w: %s", err, strings.Join(messages, "; "))
+}
+
+func isClockSkewError(code, message string) bool {
+	return code == "RequestTimeTooSkewed" || code == "RequestExpired" ||
+		strings.Contains(message, "Signature expired")
+}
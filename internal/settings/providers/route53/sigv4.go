@@ -0,0 +1,123 @@
+package route53
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+type credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string // optional
+}
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4DateFormat = "20060102"
+	sigV4TimeFormat = "20060102T150405Z"
+)
+
+// signRequest signs the request with the AWS signature version 4, see
+// https://docs.aws.amazon.com/general/latest/gr/sigv4_signing.html
+// The payload must be the request body, or nil if there is no body.
+func signRequest(request *http.Request, payload []byte, credentials credentials,
+	region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	date := now.Format(sigV4DateFormat)
+
+	request.Header.Set("X-Amz-Date", amzDate)
+	if credentials.sessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", credentials.sessionToken)
+	}
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		canonicalURI(request),
+		canonicalQuery(request),
+		canonicalHeaders,
+		signedHeaders,
+		hexSHA256(payload),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	request.Header.Set("Authorization", sigV4Algorithm+
+		" Credential="+credentials.accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+}
+
+func canonicalURI(request *http.Request) string {
+	path := request.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func canonicalQuery(request *http.Request) string {
+	// url.Values.Encode sorts by key but encodes spaces as '+'
+	return strings.ReplaceAll(request.URL.Query().Encode(), "+", "%20")
+}
+
+func canonicalizeHeaders(request *http.Request) (signedHeaders, canonicalHeaders string) {
+	host := request.Host
+	if host == "" {
+		host = request.URL.Host
+	}
+	values := map[string]string{"host": host}
+	for key, headerValues := range request.Header {
+		key = strings.ToLower(key)
+		if key == "user-agent" || key == "authorization" {
+			// can be modified by intermediate round trippers
+			continue
+		}
+		trimmed := make([]string, len(headerValues))
+		for i, value := range headerValues {
+			trimmed[i] = strings.Join(strings.Fields(value), " ")
+		}
+		values[key] = strings.Join(trimmed, ",")
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var builder strings.Builder
+	for _, key := range keys {
+		builder.WriteString(key + ":" + values[key] + "\n")
+	}
+	return strings.Join(keys, ";"), builder.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,34 @@
+package route53
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_signRequest(t *testing.T) {
+	t.Parallel()
+
+	// Example from https://docs.aws.amazon.com/general/latest/gr/sigv4-create-canonical-request.html
+	request, err := http.NewRequest(http.MethodGet,
+		"https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	credentials := credentials{
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, time.August, 30, 12, 36, 0, 0, time.UTC)
+
+	signRequest(request, nil, credentials, "us-east-1", "iam", now)
+
+	const expectedAuthorization = "AWS4-HMAC-SHA256 " +
+		"Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	assert.Equal(t, expectedAuthorization, request.Header.Get("Authorization"))
+	assert.Equal(t, "20150830T123600Z", request.Header.Get("X-Amz-Date"))
+}
@@ -0,0 +1,71 @@
+package route53
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+)
+
+// assumeRole obtains temporary credentials for the role given, see
+// https://docs.aws.amazon.com/STS/latest/APIReference/API_AssumeRole.html
+func assumeRole(ctx context.Context, client *http.Client, userCredentials credentials,
+	roleARN string, now time.Time) (assumed credentials, err error) {
+	values := url.Values{
+		"Action":          {"AssumeRole"},
+		"Version":         {"2011-06-15"},
+		"RoleArn":         {roleARN},
+		"RoleSessionName": {"ddns-updater"},
+		"DurationSeconds": {"900"},
+	}
+	payload := []byte(values.Encode())
+
+	const stsURL = "https://sts.amazonaws.com/"
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, stsURL,
+		strings.NewReader(string(payload)))
+	if err != nil {
+		return assumed, err
+	}
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/x-www-form-urlencoded; charset=utf-8")
+	signRequest(request, payload, userCredentials, "us-east-1", "sts", now)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return assumed, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return assumed, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+
+	var data struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleResult>Credentials"`
+	}
+	if err := xml.NewDecoder(response.Body).Decode(&data); err != nil {
+		return assumed, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	assumed = credentials{
+		accessKeyID:     data.Credentials.AccessKeyID,
+		secretAccessKey: data.Credentials.SecretAccessKey,
+		sessionToken:    data.Credentials.SessionToken,
+	}
+	if assumed.accessKeyID == "" || assumed.secretAccessKey == "" {
+		return assumed, fmt.Errorf("%w: no credentials in response", errors.ErrNoResultReceived)
+	}
+	return assumed, nil
+}
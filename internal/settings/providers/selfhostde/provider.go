@@ -57,8 +57,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -67,6 +65,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: Selfhost.de]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.SelfhostDe
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"carol.selfhost.de"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -8,7 +8,6 @@ import (
 	"net"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/settings/constants"
@@ -63,9 +62,6 @@ func (p *Provider) isValid() error {
 	case p.password == "":
 		return errors.ErrEmptyPassword
 	}
-	if strings.Contains(p.host, "*") {
-		return errors.ErrHostWildcard
-	}
 	return nil
 }
 
@@ -73,6 +69,14 @@ func (p *Provider) String() string {
 	return utils.ToString("servercow.de", p.host, constants.Servercow, p.ipVersion)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Servercow
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.servercow.de"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -89,6 +93,10 @@ func (p *Provider) Proxied() bool {
 	return false
 }
 
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
 func (p *Provider) BuildDomainName() string {
 	return utils.BuildDomainName(p.host, p.domain)
 }
@@ -0,0 +1,241 @@
+package simply
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain      string
+	host        string
+	ipVersion   ipversion.IPVersion
+	accountName string
+	apiKey      string
+	ttl         uint
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		AccountName string `json:"account_name"`
+		APIKey      string `json:"api_key"`
+		TTL         uint   `json:"ttl"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	const defaultTTL = 3600
+	ttl := uint(defaultTTL)
+	if extraSettings.TTL > 0 {
+		ttl = extraSettings.TTL
+	}
+	p = &Provider{
+		domain:      domain,
+		host:        host,
+		ipVersion:   ipVersion,
+		accountName: extraSettings.AccountName,
+		apiKey:      extraSettings.APIKey,
+		ttl:         ttl,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	switch {
+	case p.accountName == "":
+		return errors.ErrEmptyUsername
+	case p.apiKey == "":
+		return errors.ErrEmptyAPIKey
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Simply, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Simply
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"api.simply.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) TTL() uint {
+	return p.ttl
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://www.simply.com/\">Simply.com</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+func (p *Provider) setHeaders(request *http.Request) {
+	headers.SetUserAgent(request)
+	headers.SetContentType(request, "application/json")
+	headers.SetAccept(request, "application/json")
+	request.SetBasicAuth(p.accountName, p.apiKey)
+}
+
+// Using https://www.simply.com/en/docs/api/
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	recordType := constants.A
+	if ip.To4() == nil {
+		recordType = constants.AAAA
+	}
+
+	recordID, found, err := p.getRecordID(ctx, client, recordType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrGetRecordID, err)
+	}
+
+	if !found {
+		err = p.sendRecord(ctx, client, http.MethodPost, "", recordType, ip)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", errors.ErrCreateRecord, err)
+		}
+		return ip, nil
+	}
+
+	err = p.sendRecord(ctx, client, http.MethodPut, "/"+strconv.Itoa(recordID), recordType, ip)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUpdateRecord, err)
+	}
+	return ip, nil
+}
+
+func (p *Provider) makeURL(path string) string {
+	u := url.URL{
+		Scheme: "https",
+		Host:   "api.simply.com",
+		Path:   "/2/my/products/" + p.domain + "/dns/records" + path,
+	}
+	return u.String()
+}
+
+func (p *Provider) getRecordID(ctx context.Context, client *http.Client,
+	recordType string) (recordID int, found bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.makeURL(""), nil)
+	if err != nil {
+		return 0, false, err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, false, err
+	}
+	defer response.Body.Close()
+
+	if err := checkStatus(response); err != nil {
+		return 0, false, err
+	}
+
+	var data struct {
+		Records []struct {
+			ID   int    `json:"record_id"`
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"records"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&data); err != nil {
+		return 0, false, fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	for _, record := range data.Records {
+		if record.Name == p.host && record.Type == recordType {
+			return record.ID, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func (p *Provider) sendRecord(ctx context.Context, client *http.Client,
+	method, path, recordType string, ip net.IP) (err error) {
+	requestData := struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Data string `json:"data"`
+		TTL  uint   `json:"ttl"`
+	}{
+		Name: p.host,
+		Type: recordType,
+		Data: ip.String(),
+		TTL:  p.ttl,
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := json.NewEncoder(buffer).Encode(requestData); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrRequestEncode, err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, p.makeURL(path), buffer)
+	if err != nil {
+		return err
+	}
+	p.setHeaders(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	return checkStatus(response)
+}
+
+func checkStatus(response *http.Response) (err error) {
+	switch response.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.BodyToSingleLine(response.Body))
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", errors.ErrZoneNotFound, utils.BodyToSingleLine(response.Body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.BodyToSingleLine(response.Body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus,
+			response.StatusCode, utils.BodyToSingleLine(response.Body))
+	}
+}
@@ -0,0 +1,49 @@
+package simply
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		t.Helper()
+		data := json.RawMessage(`{"account_name": "S123456", "api_key": "key"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"records": [], "status": 200}`},
+				{Body: `{"status": 200, "message": "OK"}`},
+			}
+		},
+		Scenarios: map[string]providertest.Scenario{
+			"existing record": {
+				Responses: []providertest.Response{
+					{Body: `{"records": [` +
+						`{"record_id": 1, "name": "home", "type": "AAAA", "data": "2001:db8::1"},` +
+						`{"record_id": 2, "name": "home", "type": "A", "data": "203.0.113.2"}]}`},
+					{Body: `{"status": 200, "message": "OK"}`},
+				},
+				Check: func(t *testing.T, requests []providertest.Request) {
+					require.Len(t, requests, 2)
+					assert.Equal(t, http.MethodPut, requests[1].Method)
+					assert.Equal(t, "/2/my/products/domain.com/dns/records/2", requests[1].URL.Path)
+					assert.JSONEq(t, `{"name":"home","type":"A","data":"203.0.113.1","ttl":3600}`, requests[1].Body)
+				},
+			},
+		},
+	})
+}
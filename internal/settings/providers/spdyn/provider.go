@@ -26,27 +26,32 @@ type Provider struct {
 	password      string
 	token         string
 	useProviderIP bool
+	// ipv6PrefixLength is the length of the IPv6 prefix to send
+	// instead of the IPv6 address, and is 0 to send the address.
+	ipv6PrefixLength uint8
 }
 
 func New(data json.RawMessage, domain, host string,
 	ipVersion ipversion.IPVersion) (p *Provider, err error) {
 	extraSettings := struct {
-		User          string `json:"user"`
-		Password      string `json:"password"`
-		Token         string `json:"token"`
-		UseProviderIP bool   `json:"provider_ip"`
+		User             string `json:"user"`
+		Password         string `json:"password"`
+		Token            string `json:"token"`
+		UseProviderIP    bool   `json:"provider_ip"`
+		IPv6PrefixLength uint8  `json:"ipv6_prefix_length"`
 	}{}
 	if err := json.Unmarshal(data, &extraSettings); err != nil {
 		return nil, err
 	}
 	p = &Provider{
-		domain:        domain,
-		host:          host,
-		ipVersion:     ipVersion,
-		user:          extraSettings.User,
-		password:      extraSettings.Password,
-		token:         extraSettings.Token,
-		useProviderIP: extraSettings.UseProviderIP,
+		domain:           domain,
+		host:             host,
+		ipVersion:        ipVersion,
+		user:             extraSettings.User,
+		password:         extraSettings.Password,
+		token:            extraSettings.Token,
+		useProviderIP:    extraSettings.UseProviderIP,
+		ipv6PrefixLength: extraSettings.IPv6PrefixLength,
 	}
 	if err := p.isValid(); err != nil {
 		return nil, err
@@ -55,6 +60,11 @@ func New(data json.RawMessage, domain, host string,
 }
 
 func (p *Provider) isValid() error {
+	const maxPrefixLength = 128
+	if p.ipv6PrefixLength > maxPrefixLength {
+		return fmt.Errorf("%w: %d must be between 0 and %d",
+			errors.ErrMalformedIPv6Prefix, p.ipv6PrefixLength, maxPrefixLength)
+	}
 	if len(p.token) > 0 {
 		return nil
 	}
@@ -63,8 +73,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyUsername
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -73,6 +81,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: Spdyn]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Spdyn
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"update.spdyn.de"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -112,9 +128,17 @@ func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (
 	hostname := utils.BuildURLQueryHostname(p.host, p.domain)
 	values := url.Values{}
 	values.Set("hostname", hostname)
-	if p.useProviderIP {
+	switch {
+	case p.useProviderIP:
 		values.Set("myip", "10.0.0.1")
-	} else {
+	case ip.To4() == nil && p.ipv6PrefixLength > 0:
+		// In prefix mode, SPDYN combines the prefix with the interface
+		// identifiers configured for the host.
+		const ipv6Bits = 128
+		mask := net.CIDRMask(int(p.ipv6PrefixLength), ipv6Bits)
+		prefix := net.IPNet{IP: ip.Mask(mask), Mask: mask}
+		values.Set("myip", prefix.String())
+	default:
 		values.Set("myip", ip.String())
 	}
 	if len(p.token) > 0 {
@@ -0,0 +1,120 @@
+package spdyn
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(reportedIP string) []providertest.Response {
+			return []providertest.Response{{Body: "good " + reportedIP}}
+		},
+	})
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data          string
+		ip            net.IP
+		expectedQuery string
+	}{
+		"user and password": {
+			data:          `{"user": "user", "password": "password"}`,
+			ip:            net.IPv4(203, 0, 113, 1),
+			expectedQuery: "hostname=home.domain.com&myip=203.0.113.1&pass=password&user=user",
+		},
+		"host update token": {
+			data:          `{"token": "token"}`,
+			ip:            net.IPv4(203, 0, 113, 1),
+			expectedQuery: "hostname=home.domain.com&myip=203.0.113.1&pass=token&user=home.domain.com",
+		},
+		"IPv6 address": {
+			data:          `{"token": "token"}`,
+			ip:            net.ParseIP("2001:db8:1:2::1"),
+			expectedQuery: "hostname=home.domain.com&myip=2001%3Adb8%3A1%3A2%3A%3A1&pass=token&user=home.domain.com",
+		},
+		"IPv6 prefix": {
+			data:          `{"token": "token", "ipv6_prefix_length": 56}`,
+			ip:            net.ParseIP("2001:db8:1:2::1"),
+			expectedQuery: "hostname=home.domain.com&myip=2001%3Adb8%3A1%3A%3A%2F56&pass=token&user=home.domain.com",
+		},
+		"IPv6 prefix ignored for IPv4": {
+			data:          `{"token": "token", "ipv6_prefix_length": 56}`,
+			ip:            net.IPv4(203, 0, 113, 1),
+			expectedQuery: "hostname=home.domain.com&myip=203.0.113.1&pass=token&user=home.domain.com",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.data), "domain.com", "home", ipversion.IP4or6)
+			require.NoError(t, err)
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(providertest.Response{Body: "good"})
+
+			newIP, err := provider.Update(context.Background(), registrar.Client(), testCase.ip)
+			require.NoError(t, err)
+			assert.True(t, testCase.ip.Equal(newIP))
+
+			requests := registrar.Requests()
+			require.Len(t, requests, 1)
+			assert.Equal(t, testCase.expectedQuery, requests[0].URL.RawQuery)
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"token": {
+			data: `{"token": "token"}`,
+		},
+		"empty user": {
+			data:       `{"password": "password"}`,
+			errWrapped: errors.ErrEmptyUsername,
+		},
+		"prefix length too long": {
+			data:       `{"token": "token", "ipv6_prefix_length": 129}`,
+			errWrapped: errors.ErrMalformedIPv6Prefix,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "home", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
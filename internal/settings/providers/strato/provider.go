@@ -52,8 +52,6 @@ func (p *Provider) isValid() error {
 	switch {
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -62,6 +60,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: Strato]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Strato
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dyndns.strato.com"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -0,0 +1,67 @@
+package technitium
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"server_url": "http://dns.lan:5380", "token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Status: http.StatusOK, Body: `{"status": "ok", "response": {}}`},
+			}
+		},
+	})
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"server_url": "https://dns.example.com/", "token": "token"}`,
+		},
+		"empty URL": {
+			data:       `{"token": "token"}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"URL without scheme": {
+			data:       `{"server_url": "dns.lan:5380", "token": "token"}`,
+			errWrapped: errors.ErrMalformedURL,
+		},
+		"empty token": {
+			data:       `{"server_url": "http://dns.lan:5380"}`,
+			errWrapped: errors.ErrEmptyToken,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
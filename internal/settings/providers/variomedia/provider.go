@@ -58,8 +58,6 @@ func (p *Provider) isValid() error {
 		return errors.ErrEmptyEmail
 	case len(p.password) == 0:
 		return errors.ErrEmptyPassword
-	case p.host == "*":
-		return errors.ErrHostWildcard
 	}
 	return nil
 }
@@ -68,6 +66,14 @@ func (p *Provider) String() string {
 	return fmt.Sprintf("[domain: %s | host: %s | provider: Variomedia]", p.domain, p.host)
 }
 
+func (p *Provider) Provider() models.Provider {
+	return constants.Variomedia
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"dyndns.variomedia.de", "dyndns4.variomedia.de", "dyndns6.variomedia.de"}
+}
+
 func (p *Provider) Domain() string {
 	return p.domain
 }
@@ -0,0 +1,65 @@
+package vercel
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"token": "token"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{
+				{Body: `{"records": []}`},
+				{Body: `{"uid": "rec_1"}`},
+			}
+		},
+	})
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"token": "token", "team_id": "team_1", "ttl": 120}`)
+	provider, err := New(data, "domain.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+
+	registrar := providertest.NewRegistrar()
+	defer registrar.Close()
+	registrar.Script(
+		providertest.Response{Body: `{"records": [` +
+			`{"id": "rec_1", "name": "home", "type": "A"},` +
+			`{"id": "rec_2", "name": "", "type": "A"}]}`},
+		providertest.Response{Body: `{}`},
+	)
+
+	ip := net.IPv4(203, 0, 113, 1)
+	newIP, err := provider.Update(context.Background(), registrar.Client(), ip)
+
+	require.NoError(t, err)
+	assert.True(t, newIP.Equal(ip))
+	requests := registrar.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "team_1", requests[0].URL.Query().Get("teamId"))
+	assert.Equal(t, "Bearer token", requests[0].Header.Get("Authorization"))
+	assert.Equal(t, http.MethodPatch, requests[1].Method)
+	assert.Equal(t, "/v1/domains/records/rec_2", requests[1].URL.Path)
+	assert.Equal(t, "team_1", requests[1].URL.Query().Get("teamId"))
+	assert.JSONEq(t, `{"value":"203.0.113.1","ttl":120}`, requests[1].Body)
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+)
+
+// jsonPath is a parsed JSON path supporting the subset
+// of JSONPath made of the root `$`, `.key` and `[index]`.
+type jsonPath []pathStep
+
+type pathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func parseJSONPath(s string) (path jsonPath, err error) {
+	rest := strings.TrimPrefix(s, "$")
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("%w: %q: empty key", errors.ErrMalformedJSONPath, s)
+			}
+			path = append(path, pathStep{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("%w: %q: missing ]", errors.ErrMalformedJSONPath, s)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("%w: %q: index %q is not a positive integer",
+					errors.ErrMalformedJSONPath, s, rest[1:end])
+			}
+			path = append(path, pathStep{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%w: %q: unexpected character %q",
+				errors.ErrMalformedJSONPath, s, rest[0])
+		}
+	}
+	return path, nil
+}
+
+// lookup returns the value at the path in the decoded JSON value,
+// and false if the path does not exist in the value.
+func (j jsonPath) lookup(value interface{}) (result interface{}, ok bool) {
+	result = value
+	for _, step := range j {
+		if step.isIndex {
+			array, isArray := result.([]interface{})
+			if !isArray || step.index >= len(array) {
+				return nil, false
+			}
+			result = array[step.index]
+			continue
+		}
+		object, isObject := result.(map[string]interface{})
+		if !isObject {
+			return nil, false
+		}
+		result, ok = object[step.key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return result, true
+}
@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseJSONPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		path       string
+		jsonPath   jsonPath
+		errWrapped error
+	}{
+		"root": {
+			path: "$",
+		},
+		"keys and index": {
+			path: "$.result[1].status",
+			jsonPath: jsonPath{
+				{key: "result"},
+				{index: 1, isIndex: true},
+				{key: "status"},
+			},
+		},
+		"without root": {
+			path:     ".status",
+			jsonPath: jsonPath{{key: "status"}},
+		},
+		"empty key": {
+			path:       "$..status",
+			errWrapped: errors.ErrMalformedJSONPath,
+		},
+		"unclosed index": {
+			path:       "$.result[1",
+			errWrapped: errors.ErrMalformedJSONPath,
+		},
+		"negative index": {
+			path:       "$.result[-1]",
+			errWrapped: errors.ErrMalformedJSONPath,
+		},
+		"unexpected character": {
+			path:       "$status",
+			errWrapped: errors.ErrMalformedJSONPath,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			jsonPath, err := parseJSONPath(testCase.path)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			assert.Equal(t, testCase.jsonPath, jsonPath)
+		})
+	}
+}
+
+func Test_jsonPath_lookup(t *testing.T) {
+	t.Parallel()
+
+	var value interface{}
+	err := json.Unmarshal([]byte(`{"result": [{"status": "a"}, {"status": "b"}]}`), &value)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		path   string
+		result interface{}
+		ok     bool
+	}{
+		"found": {
+			path:   "$.result[1].status",
+			result: "b",
+			ok:     true,
+		},
+		"index out of range": {
+			path: "$.result[2].status",
+		},
+		"missing key": {
+			path: "$.result[0].other",
+		},
+		"key on array": {
+			path: "$.result.status",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path, err := parseJSONPath(testCase.path)
+			require.NoError(t, err)
+
+			result, ok := path.lookup(value)
+
+			assert.Equal(t, testCase.result, result)
+			assert.Equal(t, testCase.ok, ok)
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	// url, headers and body are templates in which
+	// placeholders are replaced on each update.
+	url     string
+	method  string
+	headers map[string]string
+	body    string
+	// success matchers, where zero values are not checked.
+	successStatuses  []int
+	successRegex     *regexp.Regexp
+	successJSONPath  jsonPath
+	successJSONValue *string
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		URL              string            `json:"url"`
+		Method           string            `json:"method"`
+		Headers          map[string]string `json:"headers"`
+		Body             string            `json:"body"`
+		SuccessStatuses  []int             `json:"success_status_codes"`
+		SuccessRegex     string            `json:"success_regex"`
+		SuccessJSONPath  string            `json:"success_json_path"`
+		SuccessJSONValue *string           `json:"success_json_value"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+
+	method := strings.ToUpper(extraSettings.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	p = &Provider{
+		domain:           domain,
+		host:             host,
+		ipVersion:        ipVersion,
+		url:              extraSettings.URL,
+		method:           method,
+		headers:          extraSettings.Headers,
+		body:             extraSettings.Body,
+		successStatuses:  extraSettings.SuccessStatuses,
+		successJSONValue: extraSettings.SuccessJSONValue,
+	}
+
+	if extraSettings.SuccessRegex != "" {
+		p.successRegex, err = regexp.Compile(extraSettings.SuccessRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: success_regex: %s", errors.ErrMalformedRegex, err)
+		}
+	}
+	if extraSettings.SuccessJSONPath != "" {
+		p.successJSONPath, err = parseJSONPath(extraSettings.SuccessJSONPath)
+		if err != nil {
+			return nil, fmt.Errorf("success_json_path: %w", err)
+		}
+	}
+
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.url == "" {
+		return errors.ErrEmptyURL
+	}
+	u, err := url.Parse(p.replacePlaceholders(p.url, net.IPv4zero))
+	if err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrMalformedURL, err)
+	} else if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: %q must be an http or https URL", errors.ErrMalformedURL, p.url)
+	}
+	if p.successJSONValue != nil && p.successJSONPath == nil {
+		return fmt.Errorf("%w: success_json_value is set without success_json_path",
+			errors.ErrMalformedJSONPath)
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Webhook, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Webhook
+}
+
+func (p *Provider) APIHosts() []string {
+	u, err := url.Parse(p.replacePlaceholders(p.url, net.IPv4zero))
+	if err != nil { // already validated
+		return nil
+	}
+	return []string{u.Hostname()}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "Webhook",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// replacePlaceholders replaces the placeholders {ip}, {ipv4}, {ipv6},
+// {host} and {domain} in the template given. The {ipv4} and {ipv6}
+// placeholders are replaced with the IP address if it is of that
+// family, and with an empty string otherwise.
+func (p *Provider) replacePlaceholders(template string, ip net.IP) string {
+	ipv4, ipv6 := "", ""
+	if ip.To4() != nil {
+		ipv4 = ip.String()
+	} else {
+		ipv6 = ip.String()
+	}
+	replacer := strings.NewReplacer(
+		"{ip}", ip.String(),
+		"{ipv4}", ipv4,
+		"{ipv6}", ipv6,
+		"{host}", p.host,
+		"{domain}", p.domain,
+	)
+	return replacer.Replace(template)
+}
+
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	var body io.Reader
+	if p.body != "" {
+		body = strings.NewReader(p.replacePlaceholders(p.body, ip))
+	}
+
+	request, err := http.NewRequestWithContext(ctx, p.method, p.replacePlaceholders(p.url, ip), body)
+	if err != nil {
+		return nil, err
+	}
+	headers.SetUserAgent(request)
+	for key, value := range p.headers {
+		request.Header.Set(key, p.replacePlaceholders(value, ip))
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.checkStatus(response.StatusCode, string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if p.successRegex != nil && !p.successRegex.Match(b) {
+		return nil, fmt.Errorf("%w: response does not match %s: %s",
+			errors.ErrUnsuccessfulResponse, p.successRegex, utils.ToSingleLine(string(b)))
+	}
+
+	if p.successJSONPath != nil {
+		err = p.checkJSON(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ip, nil
+}
+
+func (p *Provider) checkStatus(status int, body string) (err error) {
+	if len(p.successStatuses) > 0 {
+		for _, successStatus := range p.successStatuses {
+			if status == successStatus {
+				return nil
+			}
+		}
+	} else if status >= http.StatusOK && status < http.StatusMultipleChoices {
+		return nil
+	}
+
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s", errors.ErrAuth, utils.ToSingleLine(body))
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s", errors.ErrAbuse, utils.ToSingleLine(body))
+	default:
+		return fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus, status, utils.ToSingleLine(body))
+	}
+}
+
+// checkJSON checks the value at the success JSON path in the response
+// is the success JSON value if it is set, or is not a zero value
+// such as null, false, 0 or an empty string otherwise.
+func (p *Provider) checkJSON(b []byte) (err error) {
+	var decoded interface{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return fmt.Errorf("%w: %s", errors.ErrUnmarshalResponse, err)
+	}
+
+	value, ok := p.successJSONPath.lookup(decoded)
+	if !ok {
+		return fmt.Errorf("%w: no value at JSON path: %s",
+			errors.ErrUnsuccessfulResponse, utils.ToSingleLine(string(b)))
+	}
+
+	if p.successJSONValue != nil {
+		if fmt.Sprint(value) != *p.successJSONValue {
+			return fmt.Errorf("%w: value %v at JSON path is not %q",
+				errors.ErrUnsuccessfulResponse, value, *p.successJSONValue)
+		}
+		return nil
+	}
+
+	switch value {
+	case nil, false, float64(0), "":
+		return fmt.Errorf("%w: value at JSON path is %v",
+			errors.ErrUnsuccessfulResponse, value)
+	}
+	return nil
+}
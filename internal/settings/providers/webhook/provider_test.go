@@ -0,0 +1,180 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"url": "https://api.example.com/update?ip={ip}"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{{Body: "OK"}}
+		},
+	})
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		ip         net.IP
+		response   providertest.Response
+		check      func(t *testing.T, request providertest.Request)
+		errWrapped error
+	}{
+		"templated GET request": {
+			data: `{"url": "https://api.example.com/{domain}/{host}?ip={ip}&v4={ipv4}&v6={ipv6}",` +
+				` "headers": {"Authorization": "Bearer token", "X-Host": "{host}.{domain}"}}`,
+			ip:       net.IPv4(203, 0, 113, 1),
+			response: providertest.Response{Body: "OK"},
+			check: func(t *testing.T, request providertest.Request) {
+				assert.Equal(t, http.MethodGet, request.Method)
+				assert.Equal(t, "/domain.com/home", request.URL.Path)
+				assert.Equal(t, "ip=203.0.113.1&v4=203.0.113.1&v6=", request.URL.RawQuery)
+				assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+				assert.Equal(t, "home.domain.com", request.Header.Get("X-Host"))
+			},
+		},
+		"templated POST body": {
+			data:     `{"url": "https://api.example.com/update", "method": "post", "body": "{\"ip\": \"{ipv6}\"}"}`,
+			ip:       net.ParseIP("2001:db8::1"),
+			response: providertest.Response{Body: "OK"},
+			check: func(t *testing.T, request providertest.Request) {
+				assert.Equal(t, http.MethodPost, request.Method)
+				assert.Equal(t, `{"ip": "2001:db8::1"}`, request.Body)
+			},
+		},
+		"unexpected status": {
+			data:       `{"url": "https://api.example.com/update", "success_status_codes": [204]}`,
+			ip:         net.IPv4(203, 0, 113, 1),
+			response:   providertest.Response{Body: "OK"},
+			errWrapped: errors.ErrBadHTTPStatus,
+		},
+		"regex matching": {
+			data:     `{"url": "https://api.example.com/update", "success_regex": "^(good|nochg)"}`,
+			ip:       net.IPv4(203, 0, 113, 1),
+			response: providertest.Response{Body: "nochg 203.0.113.1"},
+		},
+		"regex not matching": {
+			data:       `{"url": "https://api.example.com/update", "success_regex": "^(good|nochg)"}`,
+			ip:         net.IPv4(203, 0, 113, 1),
+			response:   providertest.Response{Body: "badauth"},
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+		"JSON path truthy": {
+			data:     `{"url": "https://api.example.com/update", "success_json_path": "$.success"}`,
+			ip:       net.IPv4(203, 0, 113, 1),
+			response: providertest.Response{Body: `{"success": true}`},
+		},
+		"JSON path falsy": {
+			data:       `{"url": "https://api.example.com/update", "success_json_path": "$.success"}`,
+			ip:         net.IPv4(203, 0, 113, 1),
+			response:   providertest.Response{Body: `{"success": false}`},
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+		"JSON path value": {
+			data: `{"url": "https://api.example.com/update",` +
+				` "success_json_path": "$.result[0].status", "success_json_value": "ok"}`,
+			ip:       net.IPv4(203, 0, 113, 1),
+			response: providertest.Response{Body: `{"result": [{"status": "ok"}]}`},
+		},
+		"JSON path wrong value": {
+			data: `{"url": "https://api.example.com/update",` +
+				` "success_json_path": "$.code", "success_json_value": "0"}`,
+			ip:         net.IPv4(203, 0, 113, 1),
+			response:   providertest.Response{Body: `{"code": 1}`},
+			errWrapped: errors.ErrUnsuccessfulResponse,
+		},
+		"JSON path on malformed JSON": {
+			data:       `{"url": "https://api.example.com/update", "success_json_path": "$.success"}`,
+			ip:         net.IPv4(203, 0, 113, 1),
+			response:   providertest.Response{Body: `OK`},
+			errWrapped: errors.ErrUnmarshalResponse,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.data), "domain.com", "home", ipversion.IP4or6)
+			require.NoError(t, err)
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(testCase.response)
+
+			_, err = provider.Update(context.Background(), registrar.Client(), testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			requests := registrar.Requests()
+			require.Len(t, requests, 1)
+			if testCase.check != nil {
+				testCase.check(t, requests[0])
+			}
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"url": "https://{domain}/update?ip={ip}"}`,
+		},
+		"empty URL": {
+			data:       `{}`,
+			errWrapped: errors.ErrEmptyURL,
+		},
+		"URL without scheme": {
+			data:       `{"url": "api.example.com/update"}`,
+			errWrapped: errors.ErrMalformedURL,
+		},
+		"malformed regex": {
+			data:       `{"url": "https://api.example.com", "success_regex": "("}`,
+			errWrapped: errors.ErrMalformedRegex,
+		},
+		"malformed JSON path": {
+			data:       `{"url": "https://api.example.com", "success_json_path": "$["}`,
+			errWrapped: errors.ErrMalformedJSONPath,
+		},
+		"JSON value without path": {
+			data:       `{"url": "https://api.example.com", "success_json_value": "ok"}`,
+			errWrapped: errors.ErrMalformedJSONPath,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package zonomi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/headers"
+	"github.com/qdm12/ddns-updater/internal/settings/utils"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// serviceURLs maps the services exposing the Zonomi API
+// to the URL of their dynamic DNS endpoint.
+//
+//nolint:gochecknoglobals
+var serviceURLs = map[string]string{
+	"zonomi":      "https://zonomi.com/app/dns/dyndns.jsp",
+	"rimuhosting": "https://rimuhosting.com/dns/dyndns.jsp",
+}
+
+type Provider struct {
+	domain    string
+	host      string
+	ipVersion ipversion.IPVersion
+	apiKey    string
+	service   string
+}
+
+func New(data json.RawMessage, domain, host string,
+	ipVersion ipversion.IPVersion) (p *Provider, err error) {
+	extraSettings := struct {
+		APIKey  string `json:"api_key"`
+		Service string `json:"service"`
+	}{}
+	if err := json.Unmarshal(data, &extraSettings); err != nil {
+		return nil, err
+	}
+	service := strings.ToLower(extraSettings.Service)
+	if service == "" {
+		service = "zonomi"
+	}
+	p = &Provider{
+		domain:    domain,
+		host:      host,
+		ipVersion: ipVersion,
+		apiKey:    extraSettings.APIKey,
+		service:   service,
+	}
+	if err := p.isValid(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Provider) isValid() error {
+	if p.apiKey == "" {
+		return errors.ErrEmptyAPIKey
+	}
+	if _, ok := serviceURLs[p.service]; !ok {
+		return fmt.Errorf("%w: service %q must be zonomi or rimuhosting",
+			errors.ErrBadRequest, p.service)
+	}
+	return nil
+}
+
+func (p *Provider) String() string {
+	return utils.ToString(p.domain, p.host, constants.Zonomi, p.ipVersion)
+}
+
+func (p *Provider) Provider() models.Provider {
+	return constants.Zonomi
+}
+
+func (p *Provider) APIHosts() []string {
+	return []string{"zonomi.com", "rimuhosting.com"}
+}
+
+func (p *Provider) Domain() string {
+	return p.domain
+}
+
+func (p *Provider) Host() string {
+	return p.host
+}
+
+func (p *Provider) IPVersion() ipversion.IPVersion {
+	return p.ipVersion
+}
+
+func (p *Provider) Proxied() bool {
+	return false
+}
+
+func (p *Provider) BuildDomainName() string {
+	return utils.BuildDomainName(p.host, p.domain)
+}
+
+func (p *Provider) HTML() models.HTMLRow {
+	return models.HTMLRow{
+		Domain:    models.HTML(fmt.Sprintf("<a href=\"http://%s\">%s</a>", p.BuildDomainName(), p.BuildDomainName())),
+		Host:      models.HTML(p.Host()),
+		Provider:  "<a href=\"https://zonomi.com/\">Zonomi</a>",
+		IPVersion: models.HTML(p.ipVersion.String()),
+	}
+}
+
+// Using https://zonomi.com/app/dns/dyndns.jsp
+// The record is created if it does not exist.
+func (p *Provider) Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error) {
+	u, err := url.Parse(serviceURLs[p.service])
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	values.Set("action", "SET")
+	values.Set("name", p.BuildDomainName())
+	values.Set("value", ip.String())
+	if ip.To4() == nil {
+		values.Set("type", constants.AAAA)
+	}
+	values.Set("api_key", p.apiKey)
+	u.RawQuery = values.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	headers.SetUserAgent(request)
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	s := utils.BodyToSingleLine(response.Body)
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAuth, s)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", errors.ErrZoneNotFound, s)
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: %s", errors.ErrAbuse, s)
+	default:
+		return nil, fmt.Errorf("%w: %d: %s", errors.ErrBadHTTPStatus, response.StatusCode, s)
+	}
+
+	if !strings.Contains(s, "<is_ok>OK") {
+		return nil, fmt.Errorf("%w: %s", errors.ErrUnknownResponse, s)
+	}
+	return ip, nil
+}
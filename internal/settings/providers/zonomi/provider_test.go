@@ -0,0 +1,127 @@
+package zonomi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/providertest"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const okBody = `<?xml version="1.0" encoding="UTF-8"?><dnsapi_result><is_ok>OK:</is_ok>` +
+	`<results_count>1</results_count></dnsapi_result>`
+
+func Test_Provider_Conformance(t *testing.T) {
+	t.Parallel()
+
+	newUpdater := func(t *testing.T) providertest.Updater {
+		data := json.RawMessage(`{"api_key": "key"}`)
+		provider, err := New(data, "domain.com", "home", ipversion.IP4)
+		require.NoError(t, err)
+		return provider
+	}
+
+	providertest.Run(t, newUpdater, providertest.Behaviors{
+		Success: func(string) []providertest.Response {
+			return []providertest.Response{{Body: okBody}}
+		},
+	})
+}
+
+func Test_Provider_Update(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data        string
+		ip          net.IP
+		response    providertest.Response
+		expectedURL string
+		errWrapped  error
+	}{
+		"zonomi IPv4": {
+			data:        `{"api_key": "key"}`,
+			ip:          net.IPv4(203, 0, 113, 1),
+			response:    providertest.Response{Body: okBody},
+			expectedURL: "zonomi.com/app/dns/dyndns.jsp?action=SET&api_key=key&name=home.domain.com&value=203.0.113.1",
+		},
+		"rimuhosting IPv6": {
+			data:     `{"api_key": "key", "service": "rimuhosting"}`,
+			ip:       net.ParseIP("2001:db8::1"),
+			response: providertest.Response{Body: okBody},
+			expectedURL: "rimuhosting.com/dns/dyndns.jsp?action=SET&api_key=key" +
+				"&name=home.domain.com&type=AAAA&value=2001%3Adb8%3A%3A1",
+		},
+		"bad API key": {
+			data:        `{"api_key": "key"}`,
+			ip:          net.IPv4(203, 0, 113, 1),
+			response:    providertest.Response{Status: http.StatusUnauthorized, Body: "ERROR: Invalid API key"},
+			expectedURL: "zonomi.com/app/dns/dyndns.jsp?action=SET&api_key=key&name=home.domain.com&value=203.0.113.1",
+			errWrapped:  errors.ErrAuth,
+		},
+		"unknown response": {
+			data:        `{"api_key": "key"}`,
+			ip:          net.IPv4(203, 0, 113, 1),
+			response:    providertest.Response{Body: "<dnsapi_result></dnsapi_result>"},
+			expectedURL: "zonomi.com/app/dns/dyndns.jsp?action=SET&api_key=key&name=home.domain.com&value=203.0.113.1",
+			errWrapped:  errors.ErrUnknownResponse,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := New(json.RawMessage(testCase.data), "domain.com", "home", ipversion.IP4or6)
+			require.NoError(t, err)
+
+			registrar := providertest.NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(testCase.response)
+
+			_, err = provider.Update(context.Background(), registrar.Client(), testCase.ip)
+
+			assert.ErrorIs(t, err, testCase.errWrapped)
+			requests := registrar.Requests()
+			require.Len(t, requests, 1)
+			assert.Equal(t, testCase.expectedURL, requests[0].URL.Host+requests[0].URL.RequestURI())
+		})
+	}
+}
+
+func Test_New(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		data       string
+		errWrapped error
+	}{
+		"valid": {
+			data: `{"api_key": "key", "service": "RimuHosting"}`,
+		},
+		"empty API key": {
+			data:       `{}`,
+			errWrapped: errors.ErrEmptyAPIKey,
+		},
+		"unknown service": {
+			data:       `{"api_key": "key", "service": "other"}`,
+			errWrapped: errors.ErrBadRequest,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := New(json.RawMessage(testCase.data), "domain.com", "@", ipversion.IP4)
+			assert.ErrorIs(t, err, testCase.errWrapped)
+		})
+	}
+}
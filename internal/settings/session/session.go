@@ -0,0 +1,93 @@
+// Package session caches the sessions of providers requiring a login
+// step, such that records sharing the same credentials reuse a session
+// instead of logging in on every update.
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache caches session tokens by credentials key, each token
+// expiring after a lifetime since it was last used.
+type Cache struct {
+	sessions map[string]*session
+	mutex    sync.Mutex
+	timeNow  func() time.Time
+}
+
+type session struct {
+	token     string
+	expiresAt time.Time
+	// mutex is locked while using or creating the session,
+	// to prevent concurrent logins with the same credentials.
+	mutex sync.Mutex
+}
+
+func NewCache(timeNow func() time.Time) *Cache {
+	return &Cache{
+		sessions: make(map[string]*session),
+		timeNow:  timeNow,
+	}
+}
+
+// Key returns a cache key for the credentials given,
+// such that the credentials are not kept in the cache.
+func Key(credentials ...string) string {
+	digest := sha256.Sum256([]byte(strings.Join(credentials, "\x00")))
+	return hex.EncodeToString(digest[:])
+}
+
+// Do runs the function use with the cached session token for the key,
+// logging in with login if there is no cached session or if it expired.
+// If use fails with an error for which isExpired returns true and the
+// session was cached, the session is discarded and use is run once more
+// with a new session. The session expires after the lifetime given
+// since it was last used successfully.
+func (c *Cache) Do(ctx context.Context, key string, lifetime time.Duration,
+	login func(ctx context.Context) (token string, err error),
+	use func(token string) error, isExpired func(err error) bool) (err error) {
+	s := c.getSession(key)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cached := s.token != "" && c.timeNow().Before(s.expiresAt)
+	if !cached {
+		s.token, err = login(ctx)
+		if err != nil {
+			s.token = ""
+			return err
+		}
+	}
+
+	err = use(s.token)
+	if err != nil && cached && isExpired(err) {
+		s.token, err = login(ctx)
+		if err != nil {
+			s.token = ""
+			return err
+		}
+		err = use(s.token)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.expiresAt = c.timeNow().Add(lifetime)
+	return nil
+}
+
+func (c *Cache) getSession(key string) *session {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	s, ok := c.sessions[key]
+	if !ok {
+		s = new(session)
+		c.sessions[key] = s
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Cache_Do(t *testing.T) {
+	t.Parallel()
+
+	errExpired := errors.New("session expired")
+	now := time.Unix(0, 0)
+	cache := NewCache(func() time.Time { return now })
+
+	logins := 0
+	login := func(context.Context) (string, error) {
+		logins++
+		return "token" + string(rune('0'+logins)), nil
+	}
+	isExpired := func(err error) bool { return errors.Is(err, errExpired) }
+	var usedTokens []string
+	use := func(token string) error {
+		usedTokens = append(usedTokens, token)
+		return nil
+	}
+
+	const lifetime = time.Minute
+	ctx := context.Background()
+
+	// First use logs in, second use reuses the session.
+	assert.NoError(t, cache.Do(ctx, "key", lifetime, login, use, isExpired))
+	now = now.Add(lifetime / 2)
+	assert.NoError(t, cache.Do(ctx, "key", lifetime, login, use, isExpired))
+	assert.Equal(t, []string{"token1", "token1"}, usedTokens)
+
+	// The session lifetime is extended on each use.
+	now = now.Add(lifetime - time.Second)
+	assert.NoError(t, cache.Do(ctx, "key", lifetime, login, use, isExpired))
+	assert.Equal(t, 1, logins)
+
+	// An expired session logs in again.
+	now = now.Add(lifetime)
+	assert.NoError(t, cache.Do(ctx, "key", lifetime, login, use, isExpired))
+	assert.Equal(t, 2, logins)
+
+	// A session rejected by the provider is replaced once.
+	usedTokens = nil
+	rejectOnce := func(token string) error {
+		usedTokens = append(usedTokens, token)
+		if len(usedTokens) == 1 {
+			return errExpired
+		}
+		return nil
+	}
+	assert.NoError(t, cache.Do(ctx, "key", lifetime, login, rejectOnce, isExpired))
+	assert.Equal(t, []string{"token2", "token3"}, usedTokens)
+
+	// Other errors are returned as is.
+	errOther := errors.New("other")
+	err := cache.Do(ctx, "key", lifetime, login,
+		func(string) error { return errOther }, isExpired)
+	assert.ErrorIs(t, err, errOther)
+	assert.Equal(t, 3, logins)
+}
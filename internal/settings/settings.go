@@ -7,49 +7,89 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/qdm12/ddns-updater/internal/models"
 	"github.com/qdm12/ddns-updater/internal/settings/common"
 	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/adguardhome"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/aliyun"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/allinkl"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/bunny"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/cloudflare"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/corenetworks"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/cpanel"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dd24"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/ddnss"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/digitalocean"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/directadmin"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dnsomatic"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dnspod"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/domeneshop"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dondominio"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dreamhost"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/duckdns"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/dyfi"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dyn"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/dyndns2"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dynu"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/dynv6"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/easydns"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/epik"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/exec"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/freedns"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/gandi"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/gcore"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/gcp"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/godaddy"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/google"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/grpcplugin"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/he"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/hosting1984"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/hostinger"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/huaweicloud"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/infomaniak"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ionos"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/joker"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/linode"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/luadns"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/luascript"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/mailinabox"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/namecheap"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/namecom"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/netcup"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/netlify"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/njalla"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/noip"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/nsupdateinfo"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/opendns"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/ovh"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/plesk"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/porkbun"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/powerdns"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/regru"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/rfc2136"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/route53"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/selfhostde"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/servercow"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/simply"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/spdyn"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/strato"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/technitium"
 	"github.com/qdm12/ddns-updater/internal/settings/providers/variomedia"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/vercel"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/webhook"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/zonomi"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
 type Settings interface {
 	String() string
+	Provider() models.Provider
+	// APIHosts returns the hostnames the provider is reached at
+	// with the program HTTP client, for the outbound requests guard.
+	APIHosts() []string
 	Domain() string
 	Host() string
 	BuildDomainName() string
@@ -59,45 +99,116 @@ type Settings interface {
 	Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error)
 }
 
+// ZoneLister is implemented by providers able to list
+// the existing A and AAAA records of the zone of their domain.
+type ZoneLister interface {
+	ListRecords(ctx context.Context, client *http.Client) (records []models.ZoneRecord, err error)
+}
+
+// CNAMERemover is implemented by providers able to remove a CNAME
+// record on their host, such that it can be replaced by an A or AAAA
+// record. It returns no error if there is no CNAME record to remove.
+type CNAMERemover interface {
+	RemoveCNAME(ctx context.Context, client *http.Client) (err error)
+}
+
+// Refresher is implemented by providers requiring their records to be
+// updated periodically even if their IP address did not change, for
+// example to keep them from expiring. RefreshPeriod returns the duration
+// after which a record updated successfully should be updated again.
+type Refresher interface {
+	RefreshPeriod() time.Duration
+}
+
+// TTLer is implemented by providers setting the TTL of their records.
+// TTL returns the TTL in seconds of the record, and 0 if the record
+// uses the default TTL of the provider.
+type TTLer interface {
+	TTL() uint
+}
+
+// RRSetMember is implemented by providers able to publish the IP address
+// as one member of the record set of the host, for example for a round-robin
+// record shared with other machines or WAN links, instead of replacing
+// the whole record set.
+type RRSetMember interface {
+	// IsRRSetMember returns true if the record is configured
+	// as a member of the record set of its host.
+	IsRRSetMember() bool
+	// UpdateMember replaces the previous IP address published, which can
+	// be nil, by the IP address in the record set of the host, leaving its
+	// other members untouched.
+	UpdateMember(ctx context.Context, client *http.Client,
+		previousIP, ip net.IP) (newIP net.IP, err error)
+}
+
 var ErrProviderUnknown = errors.New("unknown provider")
 
 //nolint:gocyclo
 func New(provider models.Provider, data json.RawMessage, domain, host string, //nolint:ireturn
 	ipVersion ipversion.IPVersion, matcher common.Matcher) (
 	settings Settings, err error) {
+	if err := validateHost(provider, host); err != nil {
+		return nil, err
+	}
+
 	switch provider {
+	case constants.AdGuardHome:
+		return adguardhome.New(data, domain, host, ipVersion)
 	case constants.Aliyun:
 		return aliyun.New(data, domain, host, ipVersion)
 	case constants.AllInkl:
 		return allinkl.New(data, domain, host, ipVersion)
+	case constants.Bunny:
+		return bunny.New(data, domain, host, ipVersion)
 	case constants.Cloudflare:
 		return cloudflare.New(data, domain, host, ipVersion, matcher)
+	case constants.CoreNetworks:
+		return corenetworks.New(data, domain, host, ipVersion)
+	case constants.CPanel:
+		return cpanel.New(data, domain, host, ipVersion)
 	case constants.Dd24:
 		return dd24.New(data, domain, host, ipVersion)
 	case constants.DdnssDe:
 		return ddnss.New(data, domain, host, ipVersion)
 	case constants.DigitalOcean:
 		return digitalocean.New(data, domain, host, ipVersion)
+	case constants.DirectAdmin:
+		return directadmin.New(data, domain, host, ipVersion)
 	case constants.DNSOMatic:
 		return dnsomatic.New(data, domain, host, ipVersion, matcher)
 	case constants.DNSPod:
 		return dnspod.New(data, domain, host, ipVersion)
+	case constants.Domeneshop:
+		return domeneshop.New(data, domain, host, ipVersion)
 	case constants.DonDominio:
 		return dondominio.New(data, domain, host, ipVersion)
 	case constants.Dreamhost:
 		return dreamhost.New(data, domain, host, ipVersion, matcher)
 	case constants.DuckDNS:
 		return duckdns.New(data, domain, host, ipVersion, matcher)
+	case constants.DyFi:
+		return dyfi.New(data, domain, host, ipVersion)
 	case constants.Dyn:
 		return dyn.New(data, domain, host, ipVersion)
+	case constants.DynDNS2:
+		return dyndns2.New(data, domain, host, ipVersion)
 	case constants.Dynu:
 		return dynu.New(data, domain, host, ipVersion)
 	case constants.DynV6:
 		return dynv6.New(data, domain, host, ipVersion)
+	case constants.EasyDNS:
+		return easydns.New(data, domain, host, ipVersion)
+	case constants.Epik:
+		return epik.New(data, domain, host, ipVersion)
+	case constants.Exec:
+		return exec.New(data, domain, host, ipVersion)
 	case constants.FreeDNS:
 		return freedns.New(data, domain, host, ipVersion)
 	case constants.Gandi:
 		return gandi.New(data, domain, host, ipVersion)
+	case constants.Gcore:
+		return gcore.New(data, domain, host, ipVersion)
 	case constants.GCP:
 		return gcp.New(data, domain, host, ipVersion)
 	case constants.GoDaddy:
@@ -106,34 +217,80 @@ func New(provider models.Provider, data json.RawMessage, domain, host string, //
 		return google.New(data, domain, host, ipVersion)
 	case constants.HE:
 		return he.New(data, domain, host, ipVersion)
+	case constants.Hosting1984:
+		return hosting1984.New(data, domain, host, ipVersion)
+	case constants.Hostinger:
+		return hostinger.New(data, domain, host, ipVersion)
+	case constants.HuaweiCloud:
+		return huaweicloud.New(data, domain, host, ipVersion)
 	case constants.Infomaniak:
 		return infomaniak.New(data, domain, host, ipVersion)
+	case constants.Ionos:
+		return ionos.New(data, domain, host, ipVersion)
+	case constants.Joker:
+		return joker.New(data, domain, host, ipVersion)
 	case constants.Linode:
 		return linode.New(data, domain, host, ipVersion)
+	case constants.Lua:
+		return luascript.New(data, domain, host, ipVersion)
 	case constants.LuaDNS:
 		return luadns.New(data, domain, host, ipVersion)
+	case constants.MailInABox:
+		return mailinabox.New(data, domain, host, ipVersion)
 	case constants.Namecheap:
 		return namecheap.New(data, domain, host, ipVersion, matcher)
+	case constants.NameCom:
+		return namecom.New(data, domain, host, ipVersion)
+	case constants.Netcup:
+		return netcup.New(data, domain, host, ipVersion)
+	case constants.Netlify:
+		return netlify.New(data, domain, host, ipVersion)
 	case constants.Njalla:
 		return njalla.New(data, domain, host, ipVersion)
 	case constants.NoIP:
 		return noip.New(data, domain, host, ipVersion)
+	case constants.NS1:
+		return ns1.New(data, domain, host, ipVersion)
+	case constants.NsupdateInfo:
+		return nsupdateinfo.New(data, domain, host, ipVersion)
 	case constants.OpenDNS:
 		return opendns.New(data, domain, host, ipVersion)
 	case constants.OVH:
 		return ovh.New(data, domain, host, ipVersion)
+	case constants.Plesk:
+		return plesk.New(data, domain, host, ipVersion)
+	case constants.Plugin:
+		return grpcplugin.New(data, domain, host, ipVersion)
 	case constants.Porkbun:
 		return porkbun.New(data, domain, host, ipVersion)
+	case constants.PowerDNS:
+		return powerdns.New(data, domain, host, ipVersion)
+	case constants.Regru:
+		return regru.New(data, domain, host, ipVersion)
+	case constants.RFC2136:
+		return rfc2136.New(data, domain, host, ipVersion)
+	case constants.Route53:
+		return route53.New(data, domain, host, ipVersion)
 	case constants.SelfhostDe:
 		return selfhostde.New(data, domain, host, ipVersion)
 	case constants.Servercow:
 		return servercow.New(data, domain, host, ipVersion)
+	case constants.Simply:
+		return simply.New(data, domain, host, ipVersion)
 	case constants.Spdyn:
 		return spdyn.New(data, domain, host, ipVersion)
 	case constants.Strato:
 		return strato.New(data, domain, host, ipVersion)
+	case constants.Technitium:
+		return technitium.New(data, domain, host, ipVersion)
 	case constants.Variomedia:
 		return variomedia.New(data, domain, host, ipVersion, matcher)
+	case constants.Vercel:
+		return vercel.New(data, domain, host, ipVersion)
+	case constants.Webhook:
+		return webhook.New(data, domain, host, ipVersion)
+	case constants.Zonomi:
+		return zonomi.New(data, domain, host, ipVersion)
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrProviderUnknown, provider)
 	}
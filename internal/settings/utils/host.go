@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/ddns-updater/internal/settings/errors"
+)
+
+// ValidateHost checks the host is supported by the provider,
+// according to the host capabilities given.
+func ValidateHost(provider models.Provider, capabilities constants.HostCapabilities,
+	host string) error {
+	switch {
+	case host == "@":
+		if !capabilities.Apex {
+			return fmt.Errorf("%w: provider %s does not support the \"@\" host",
+				errors.ErrHostOnlySubdomain, provider)
+		}
+		return nil
+	case capabilities.ApexOnly:
+		return fmt.Errorf("%w: provider %s does not support the host %q",
+			errors.ErrHostOnlyAt, provider, host)
+	case host == "*" || strings.HasPrefix(host, "*."):
+		if !capabilities.Wildcard {
+			return fmt.Errorf("%w: provider %s does not support the wildcard host %q",
+				errors.ErrHostWildcard, provider, host)
+		}
+	}
+
+	if !capabilities.MultiLevel && strings.Contains(strings.TrimPrefix(host, "*."), ".") {
+		return fmt.Errorf("%w: provider %s does not support the host %q",
+			errors.ErrHostMultiLevel, provider, host)
+	}
+	return nil
+}
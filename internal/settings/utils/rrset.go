@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"net"
+)
+
+// ReplaceMember returns the IP addresses of a record set with the previous
+// IP address removed and the IP address added, keeping the other members
+// in their order. Members which are not valid IP addresses are kept as is.
+func ReplaceMember(members []string, previousIP, ip net.IP) (newMembers []string) {
+	newMembers = make([]string, 0, len(members)+1)
+	for _, member := range members {
+		memberIP := net.ParseIP(member)
+		if memberIP != nil && (memberIP.Equal(ip) ||
+			(previousIP != nil && memberIP.Equal(previousIP))) {
+			continue
+		}
+		newMembers = append(newMembers, member)
+	}
+	return append(newMembers, ip.String())
+}
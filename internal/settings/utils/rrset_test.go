@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ReplaceMember(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		members    []string
+		previousIP net.IP
+		ip         net.IP
+		newMembers []string
+	}{
+		"empty record set": {
+			ip:         net.IPv4(1, 2, 3, 4),
+			newMembers: []string{"1.2.3.4"},
+		},
+		"add member": {
+			members:    []string{"5.6.7.8"},
+			ip:         net.IPv4(1, 2, 3, 4),
+			newMembers: []string{"5.6.7.8", "1.2.3.4"},
+		},
+		"replace previous IP": {
+			members:    []string{"9.9.9.9", "5.6.7.8", "8.8.8.8"},
+			previousIP: net.IPv4(5, 6, 7, 8),
+			ip:         net.IPv4(1, 2, 3, 4),
+			newMembers: []string{"9.9.9.9", "8.8.8.8", "1.2.3.4"},
+		},
+		"IP already member": {
+			members:    []string{"1.2.3.4", "8.8.8.8"},
+			previousIP: net.IPv4(5, 6, 7, 8),
+			ip:         net.IPv4(1, 2, 3, 4),
+			newMembers: []string{"8.8.8.8", "1.2.3.4"},
+		},
+		"IPv6 members": {
+			members:    []string{"2001:db8::1", "2001:0db8::2"},
+			previousIP: net.ParseIP("2001:db8::2"),
+			ip:         net.ParseIP("2001:db8::3"),
+			newMembers: []string{"2001:db8::1", "2001:db8::3"},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			newMembers := ReplaceMember(testCase.members, testCase.previousIP, testCase.ip)
+
+			assert.Equal(t, testCase.newMembers, newMembers)
+		})
+	}
+}
@@ -6,7 +6,9 @@ import (
 )
 
 func BodyToSingleLine(body io.Reader) (s string) {
-	b, err := io.ReadAll(body)
+	// the body is only used in error messages, so only read its beginning
+	const maxBytes = 1024
+	b, err := io.ReadAll(io.LimitReader(body, maxBytes))
 	if err != nil {
 		return ""
 	}
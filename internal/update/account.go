@@ -0,0 +1,155 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+)
+
+var (
+	ErrAccountBanned      = errors.New("account is banned")
+	ErrAccountCircuitOpen = errors.New("account has too many consecutive failures")
+)
+
+const (
+	accountBanDuration      = time.Hour
+	accountFailureThreshold = 3
+	accountCircuitDuration  = 5 * time.Minute
+)
+
+// accounts tracks the ban and circuit breaker states of named accounts,
+// independently of each other, so a misbehaving account does not
+// affect records of other accounts of the same provider.
+type accounts struct {
+	states map[string]*accountState
+	mutex  sync.Mutex
+}
+
+type accountState struct {
+	bannedUntil         time.Time
+	consecutiveFailures uint
+	circuitOpenUntil    time.Time
+}
+
+func newAccounts() *accounts {
+	return &accounts{
+		states: make(map[string]*accountState),
+	}
+}
+
+func accountKey(record records.Record) (key string) {
+	if record.Options.Account == "" {
+		return ""
+	}
+	return string(record.Settings.Provider()) + "/" + record.Options.Account
+}
+
+// check returns an error if the record account is banned or
+// if its circuit breaker is open.
+func (a *accounts) check(record records.Record, now time.Time) (err error) {
+	key := accountKey(record)
+	if key == "" {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	state, ok := a.states[key]
+	switch {
+	case !ok:
+		return nil
+	case now.Before(state.bannedUntil):
+		return fmt.Errorf("%w: %s until %s", ErrAccountBanned,
+			record.Options.Account, state.bannedUntil.Format(time.RFC3339))
+	case now.Before(state.circuitOpenUntil):
+		return fmt.Errorf("%w: %s, no update until %s", ErrAccountCircuitOpen,
+			record.Options.Account, state.circuitOpenUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// report records the result of an update for the record account.
+func (a *accounts) report(record records.Record, updateErr error, now time.Time) {
+	key := accountKey(record)
+	if key == "" {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	state, ok := a.states[key]
+	if !ok {
+		state = new(accountState)
+		a.states[key] = state
+	}
+
+	switch {
+	case updateErr == nil:
+		state.consecutiveFailures = 0
+	case errors.Is(updateErr, settingserrors.ErrAbuse):
+		state.bannedUntil = now.Add(accountBanDuration)
+	default:
+		state.consecutiveFailures++
+		if state.consecutiveFailures >= accountFailureThreshold {
+			state.circuitOpenUntil = now.Add(accountCircuitDuration)
+			state.consecutiveFailures = 0
+		}
+	}
+}
+
+// state returns whether the record account is banned and
+// whether its circuit breaker is open.
+func (a *accounts) state(record records.Record, now time.Time) (
+	banned, circuitOpen bool) {
+	key := accountKey(record)
+	if key == "" {
+		return false, false
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	state, ok := a.states[key]
+	if !ok {
+		return false, false
+	}
+	return now.Before(state.bannedUntil), now.Before(state.circuitOpenUntil)
+}
+
+// AccountState is the ban and circuit breaker state of an account,
+// used to carry the state over to another instance.
+type AccountState struct {
+	BannedUntil      time.Time `json:"banned_until"`
+	CircuitOpenUntil time.Time `json:"circuit_open_until"`
+}
+
+// AccountStates returns the ban and circuit breaker states
+// of the accounts, keyed by provider and account name.
+func (u *Updater) AccountStates() (states map[string]AccountState) {
+	u.accounts.mutex.Lock()
+	defer u.accounts.mutex.Unlock()
+	states = make(map[string]AccountState, len(u.accounts.states))
+	for key, state := range u.accounts.states {
+		states[key] = AccountState{
+			BannedUntil:      state.bannedUntil,
+			CircuitOpenUntil: state.circuitOpenUntil,
+		}
+	}
+	return states
+}
+
+// RestoreAccountStates sets the ban and circuit breaker states
+// of the accounts, for example from a snapshot.
+func (u *Updater) RestoreAccountStates(states map[string]AccountState) {
+	u.accounts.mutex.Lock()
+	defer u.accounts.mutex.Unlock()
+	for key, state := range states {
+		u.accounts.states[key] = &accountState{
+			bannedUntil:      state.BannedUntil,
+			circuitOpenUntil: state.CircuitOpenUntil,
+		}
+	}
+}
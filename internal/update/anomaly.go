@@ -0,0 +1,149 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// AnomalySettings contains settings to detect anomalies
+// in the public IP addresses fetched.
+type AnomalySettings struct {
+	// MaxChangesPerHour is the maximum number of changes of a public
+	// IP address in the last hour before it is considered flapping.
+	// It is disabled if set to 0.
+	MaxChangesPerHour uint
+	// Block prevents publishing anomalous IP addresses until the
+	// update is confirmed with ForceUpdateConfirmed.
+	Block bool
+}
+
+var (
+	ErrIPBogon    = errors.New("public IP address is in a bogon range")
+	ErrIPFlapping = errors.New("public IP address changes too often")
+)
+
+type anomalyDetector struct {
+	settings AnomalySettings
+	// lastIPs maps an IP kind (ip, ipv4, ipv6) to the last IP address fetched.
+	lastIPs map[string]net.IP
+	// changes maps an IP kind to the times of its changes in the last hour.
+	changes map[string][]time.Time
+	// mutex protects the maps above since each IP version
+	// pipeline checks its IP addresses concurrently.
+	mutex sync.Mutex
+}
+
+func newAnomalyDetector(settings AnomalySettings) *anomalyDetector {
+	return &anomalyDetector{
+		settings: settings,
+		lastIPs:  make(map[string]net.IP),
+		changes:  make(map[string][]time.Time),
+	}
+}
+
+// check returns an error if the IP address fetched for the kind given
+// is anomalous. It must be called once per fetch to track changes.
+func (a *anomalyDetector) check(kind string, ip net.IP, now time.Time) (err error) {
+	if ip == nil {
+		return nil
+	}
+
+	if isBogon(ip) {
+		return fmt.Errorf("%w: %s", ErrIPBogon, ip)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	lastIP, ok := a.lastIPs[kind]
+	a.lastIPs[kind] = ip
+	if ok && !ip.Equal(lastIP) {
+		a.changes[kind] = append(a.changes[kind], now)
+	}
+
+	// Only keep changes of the last hour
+	changes := a.changes[kind]
+	for len(changes) > 0 && now.Sub(changes[0]) > time.Hour {
+		changes = changes[1:]
+	}
+	a.changes[kind] = changes
+
+	if a.settings.MaxChangesPerHour > 0 &&
+		uint(len(changes)) > a.settings.MaxChangesPerHour {
+		return fmt.Errorf("%w: %s changed %d times in the last hour",
+			ErrIPFlapping, ip, len(changes))
+	}
+	return nil
+}
+
+// bogonNetworks are reserved networks which should never be
+// seen as public IP addresses, see https://ipinfo.io/bogon
+// IPv6 link-local and unique local addresses are checked
+// per record by checkPublishable.
+func bogonNetworks() (networks []*net.IPNet) {
+	cidrs := []string{
+		"0.0.0.0/8",
+		"10.0.0.0/8",
+		"100.64.0.0/10",
+		"127.0.0.0/8",
+		"169.254.0.0/16",
+		"172.16.0.0/12",
+		"192.0.0.0/24",
+		"192.0.2.0/24",
+		"192.168.0.0/16",
+		"198.18.0.0/15",
+		"198.51.100.0/24",
+		"203.0.113.0/24",
+		"224.0.0.0/4",
+		"240.0.0.0/4",
+		"::/128",
+		"::1/128",
+		"100::/64",
+		"2001:db8::/32",
+		"ff00::/8",
+	}
+	networks = make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, networks[i], _ = net.ParseCIDR(cidr)
+	}
+	return networks
+}
+
+func isBogon(ip net.IP) bool {
+	for _, network := range bogonNetworks() {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAnomalies checks the public IP addresses fetched for anomalies,
+// logging a warning for each anomaly found. If anomalies are blocked
+// and not confirmed, anomalous IP addresses are returned as nil
+// so they are not published, and errors are returned for them.
+func (r *Runner) checkAnomalies(ip, ipv4, ipv6 net.IP, now time.Time, confirmed bool) (
+	checkedIP, checkedIPv4, checkedIPv6 net.IP, errs []error) {
+	checked := [3]net.IP{ip, ipv4, ipv6}
+	kinds := [3]string{"ip", "ipv4", "ipv6"}
+	for i, kind := range kinds {
+		err := r.anomalies.check(kind, checked[i], now)
+		switch {
+		case err == nil:
+			continue
+		case !r.anomalies.settings.Block:
+			r.logger.Warn(err.Error())
+		case confirmed:
+			r.logger.Warn(err.Error() + ", publishing it as confirmed")
+		default:
+			checked[i] = nil
+			err = fmt.Errorf("%w, not publishing it until confirmed", err)
+			r.logger.Error(err.Error())
+			errs = append(errs, err)
+		}
+	}
+	return checked[0], checked[1], checked[2], errs
+}
@@ -0,0 +1,92 @@
+package update
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_anomalyDetector_check(t *testing.T) {
+	t.Parallel()
+
+	type fetch struct {
+		ip      net.IP
+		elapsed time.Duration
+		err     error
+		errMsg  string
+	}
+
+	testCases := map[string]struct {
+		settings AnomalySettings
+		fetches  []fetch
+	}{
+		"nil IP": {
+			fetches: []fetch{{}},
+		},
+		"public IPv4": {
+			fetches: []fetch{{ip: net.IPv4(1, 2, 3, 4)}},
+		},
+		"bogon IPv4": {
+			fetches: []fetch{{
+				ip:     net.IPv4(100, 64, 1, 1),
+				err:    ErrIPBogon,
+				errMsg: "public IP address is in a bogon range: 100.64.1.1",
+			}},
+		},
+		"bogon IPv6": {
+			fetches: []fetch{{
+				ip:     net.ParseIP("2001:db8::1"),
+				err:    ErrIPBogon,
+				errMsg: "public IP address is in a bogon range: 2001:db8::1",
+			}},
+		},
+		"flapping disabled": {
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2)},
+				{ip: net.IPv4(1, 1, 1, 1)},
+			},
+		},
+		"flapping": {
+			settings: AnomalySettings{MaxChangesPerHour: 1},
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), elapsed: time.Minute},
+				{
+					ip:      net.IPv4(1, 1, 1, 1),
+					elapsed: 2 * time.Minute,
+					err:     ErrIPFlapping,
+					errMsg:  "public IP address changes too often: 1.1.1.1 changed 2 times in the last hour",
+				},
+			},
+		},
+		"changes older than an hour": {
+			settings: AnomalySettings{MaxChangesPerHour: 1},
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), elapsed: time.Minute},
+				{ip: net.IPv4(1, 1, 1, 1), elapsed: 2 * time.Hour},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			detector := newAnomalyDetector(testCase.settings)
+			start := time.Unix(0, 0)
+			for _, fetch := range testCase.fetches {
+				err := detector.check("ipv4", fetch.ip, start.Add(fetch.elapsed))
+
+				assert.ErrorIs(t, err, fetch.err)
+				if fetch.err != nil {
+					assert.EqualError(t, err, fetch.errMsg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package update
+
+import (
+	"math/rand"
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// BackoffSettings contains settings to space out the update attempts
+// of a record after failed updates, such that a misconfigured record
+// does not call its provider each period and trip its abuse detection.
+type BackoffSettings struct {
+	// Initial is the backoff duration after the first failed update,
+	// and is doubled after each consecutive failed update.
+	Initial time.Duration
+	// Max is the maximum backoff duration, and
+	// the backoff is disabled if it is 0.
+	Max time.Duration
+}
+
+// backoffDuration returns the duration to wait before the next update
+// attempt after the number of consecutive failures given, with a random
+// jitter in [0.5, 1) of the exponential backoff duration so records
+// failing at the same time do not retry at the same time.
+// The random argument is a float in [0, 1).
+func backoffDuration(settings BackoffSettings, failures uint, random float64) time.Duration {
+	if settings.Max == 0 || failures == 0 {
+		return 0
+	}
+
+	duration := settings.Initial
+	for i := uint(1); i < failures && duration < settings.Max; i++ {
+		duration *= 2
+	}
+	if duration > settings.Max {
+		duration = settings.Max
+	}
+
+	const jitterRatio = 0.5
+	return duration - time.Duration(jitterRatio*random*float64(duration))
+}
+
+// setBackoff sets the backoff of the record after an update
+// failure, or clears it if the update succeeded.
+func (u *Updater) setBackoff(record *librecords.Record, updateErr error, now time.Time) {
+	if updateErr == nil {
+		record.Failures = 0
+		record.BackoffUntil = time.Time{}
+		return
+	}
+	record.Failures++
+	duration := backoffDuration(u.backoff, record.Failures, rand.Float64()) //nolint:gosec
+	if duration == 0 {
+		return
+	}
+	record.BackoffUntil = now.Add(duration)
+}
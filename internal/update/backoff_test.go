@@ -0,0 +1,61 @@
+package update
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_backoffDuration(t *testing.T) {
+	t.Parallel()
+
+	settings := BackoffSettings{Initial: time.Minute, Max: time.Hour}
+
+	testCases := map[string]struct {
+		settings BackoffSettings
+		failures uint
+		random   float64
+		duration time.Duration
+	}{
+		"disabled": {
+			settings: BackoffSettings{Initial: time.Minute},
+			failures: 3,
+		},
+		"no failure": {
+			settings: settings,
+		},
+		"first failure": {
+			settings: settings,
+			failures: 1,
+			duration: time.Minute,
+		},
+		"third failure": {
+			settings: settings,
+			failures: 3,
+			duration: 4 * time.Minute,
+		},
+		"third failure with jitter": {
+			settings: settings,
+			failures: 3,
+			random:   0.5,
+			duration: 3 * time.Minute,
+		},
+		"capped to max": {
+			settings: settings,
+			failures: 100,
+			duration: time.Hour,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			duration := backoffDuration(testCase.settings, testCase.failures, testCase.random)
+
+			assert.Equal(t, testCase.duration, duration)
+		})
+	}
+}
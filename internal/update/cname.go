@@ -0,0 +1,75 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings"
+)
+
+var ErrHostIsCNAME = errors.New("host is a CNAME record")
+
+// checkCNAME returns an error if the host of the record currently is a
+// CNAME record, which providers fail to update with confusing errors.
+// If the record is set to replace CNAME records and its provider
+// supports it, the CNAME record is instead removed, for the A or AAAA
+// record to be created. Note ALIAS records cannot be detected since
+// they are resolved by the provider.
+func (u *Updater) checkCNAME(ctx context.Context, client *http.Client,
+	record records.Record) (err error) {
+	hostname := record.Settings.BuildDomainName()
+	if strings.HasPrefix(hostname, "*.") {
+		return nil
+	}
+
+	resolver := record.Options.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	target, isCNAME := lookupCNAME(ctx, resolver, hostname)
+	if !isCNAME {
+		return nil
+	}
+
+	if !record.Options.ReplaceCNAME {
+		return fmt.Errorf("%w: %s points to %s, remove it at your provider "+
+			"or set \"replace_cname\" to true", ErrHostIsCNAME, hostname, target)
+	}
+
+	remover, ok := record.Settings.(settings.CNAMERemover)
+	if !ok {
+		return fmt.Errorf("%w: %s points to %s and provider %s cannot replace it",
+			ErrHostIsCNAME, hostname, target, record.Settings.Provider())
+	}
+	err = remover.RemoveCNAME(ctx, client)
+	if err != nil {
+		return fmt.Errorf("removing CNAME record of %s: %w", hostname, err)
+	}
+	u.logger.Info("removed CNAME record of " + hostname + " pointing to " + target)
+	return nil
+}
+
+// lookupCNAME returns the target of the hostname if it is a CNAME record.
+// Lookup errors, for example if the hostname does not exist yet, are
+// considered as the hostname not being a CNAME record.
+func lookupCNAME(ctx context.Context, resolver *net.Resolver,
+	hostname string) (target string, isCNAME bool) {
+	const timeout = 5 * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	canonical, err := resolver.LookupCNAME(ctx, hostname)
+	if err != nil {
+		return "", false
+	}
+	canonical = strings.TrimSuffix(canonical, ".")
+	if strings.EqualFold(canonical, strings.TrimSuffix(hostname, ".")) {
+		return "", false
+	}
+	return canonical, true
+}
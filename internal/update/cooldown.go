@@ -0,0 +1,96 @@
+package update
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+)
+
+// defaultCooldown is the duration without update attempts of a record
+// after its provider reported an abuse or rate limited it, if neither
+// the provider response nor the provider cooldowns give a duration.
+const defaultCooldown = time.Hour
+
+// providerCooldowns are the durations without update attempts after
+// a provider rate limited a record, for providers documenting them.
+var providerCooldowns = map[models.Provider]time.Duration{ //nolint:gochecknoglobals
+	constants.Cloudflare: 5 * time.Minute,
+	constants.GoDaddy:    time.Minute,
+}
+
+// cooldownDuration returns the duration without update attempts of a
+// record after an abuse or rate limit response of its provider. The
+// Retry-After duration of the response is used if it is set.
+func cooldownDuration(provider models.Provider, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	} else if duration, ok := providerCooldowns[provider]; ok {
+		return duration
+	}
+	return defaultCooldown
+}
+
+// retryAfterRecorder wraps an HTTP round tripper to record the
+// longest Retry-After duration of the responses rate limiting requests.
+type retryAfterRecorder struct {
+	proxied http.RoundTripper
+	// now is the time used to convert Retry-After dates to durations.
+	now        time.Time
+	retryAfter time.Duration
+	mutex      sync.Mutex
+}
+
+func newRetryAfterRecorder(proxied http.RoundTripper, now time.Time) *retryAfterRecorder {
+	if proxied == nil {
+		proxied = http.DefaultTransport
+	}
+	return &retryAfterRecorder{
+		proxied: proxied,
+		now:     now,
+	}
+}
+
+func (r *retryAfterRecorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := r.proxied.RoundTrip(request)
+	if err != nil || (response.StatusCode != http.StatusTooManyRequests &&
+		response.StatusCode != http.StatusServiceUnavailable) {
+		return response, err
+	}
+
+	retryAfter := parseRetryAfter(response.Header.Get("Retry-After"), r.now)
+	r.mutex.Lock()
+	if retryAfter > r.retryAfter {
+		r.retryAfter = retryAfter
+	}
+	r.mutex.Unlock()
+	return response, nil
+}
+
+// duration returns the longest Retry-After duration recorded,
+// or 0 if no response had a Retry-After header.
+func (r *retryAfterRecorder) duration() time.Duration {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.retryAfter
+}
+
+// parseRetryAfter parses a Retry-After header value, which is either
+// a number of seconds or an HTTP date, and returns 0 if it is malformed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseUint(value, 10, 32) //nolint:gomnd
+	if err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err != nil || !date.After(now) {
+		return 0
+	}
+	return date.Sub(now)
+}
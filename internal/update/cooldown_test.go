@@ -0,0 +1,56 @@
+package update
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_cooldownDuration(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 30*time.Second, cooldownDuration(constants.Cloudflare, 30*time.Second))
+	assert.Equal(t, 5*time.Minute, cooldownDuration(constants.Cloudflare, 0))
+	assert.Equal(t, time.Hour, cooldownDuration(constants.DuckDNS, 0))
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := map[string]struct {
+		value      string
+		retryAfter time.Duration
+	}{
+		"empty": {},
+		"seconds": {
+			value:      "120",
+			retryAfter: 2 * time.Minute,
+		},
+		"date": {
+			value:      now.Add(time.Hour).Format(http.TimeFormat),
+			retryAfter: time.Hour,
+		},
+		"past date": {
+			value: now.Add(-time.Hour).Format(http.TimeFormat),
+		},
+		"malformed": {
+			value: "soon",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			retryAfter := parseRetryAfter(testCase.value, now)
+
+			assert.Equal(t, testCase.retryAfter, retryAfter)
+		})
+	}
+}
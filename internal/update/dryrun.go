@@ -0,0 +1,162 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/constants"
+	"github.com/qdm12/golibs/logging"
+)
+
+var ErrDryRun = errors.New("request not sent in dry run mode")
+
+const redacted = "[redacted]"
+
+// dryRunUpdate logs the provider call the update of the record would
+// make, without sending it. Only the first HTTP request is logged for
+// providers making several requests, since the following requests
+// depend on the response of the first one.
+func (u *Updater) dryRunUpdate(ctx context.Context, record librecords.Record, ip net.IP) {
+	prefix := "dry run: " + record.Settings.String() + ": "
+	switch record.Settings.Provider() {
+	case constants.Exec, constants.RFC2136, constants.Plugin:
+		u.logger.Info(prefix + "would update to " + ip.String() +
+			" without HTTP request, skipping provider call")
+		return
+	}
+
+	client := &http.Client{
+		Timeout:   u.client.Timeout,
+		Transport: &dryRunTransport{logger: u.logger, prefix: prefix},
+	}
+	_, err := updateProvider(ctx, client, record, ip)
+	if err != nil && !errors.Is(err, ErrDryRun) {
+		u.logger.Warn(prefix + "preparing provider call: " + err.Error())
+	}
+}
+
+// dryRunTransport logs the HTTP requests with their secrets redacted,
+// and returns ErrDryRun instead of sending them.
+type dryRunTransport struct {
+	logger logging.Logger
+	prefix string
+}
+
+func (d *dryRunTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	message := d.prefix + "would send " + request.Method + " " + redactURL(request.URL)
+	if headers := redactHeaders(request.Header); headers != "" {
+		message += " with headers " + headers
+	}
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		_ = request.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > 0 {
+			message += " with body " + redactBody(body, request.Header.Get("Content-Type"))
+		}
+	}
+	d.logger.Info(message)
+	return nil, ErrDryRun
+}
+
+// isSecretKey returns true if the name given, such as a query
+// parameter, a header or a JSON field, likely holds a secret.
+func isSecretKey(name string) bool {
+	name = strings.ToLower(name)
+	for _, word := range [...]string{"token", "key", "pass", "pwd", "secret", "auth", "signature"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	if redactedURL.User != nil {
+		redactedURL.User = url.User(redacted)
+	}
+	redactedURL.RawQuery = redactValues(u.Query()).Encode()
+	return redactedURL.String()
+}
+
+func redactValues(values url.Values) url.Values {
+	for key := range values {
+		if isSecretKey(key) {
+			values[key] = []string{redacted}
+		}
+	}
+	return values
+}
+
+func redactHeaders(header http.Header) string {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		if key == "User-Agent" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	elements := make([]string, len(keys))
+	for i, key := range keys {
+		value := strings.Join(header.Values(key), ", ")
+		if isSecretKey(key) {
+			value = redacted
+		}
+		elements[i] = key + ": " + value
+	}
+	return strings.Join(elements, "; ")
+}
+
+func redactBody(body []byte, contentType string) string {
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err == nil {
+			return redactValues(values).Encode()
+		}
+	}
+
+	var data interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err == nil {
+		redactedBody, err := json.Marshal(redactJSON(data))
+		if err == nil {
+			return string(redactedBody)
+		}
+	}
+
+	// The body format is unknown, such as XML, and may contain secrets.
+	return "of " + strconv.Itoa(len(body)) + " bytes " + redacted
+}
+
+func redactJSON(data interface{}) interface{} {
+	switch typed := data.(type) {
+	case map[string]interface{}:
+		for key, value := range typed {
+			if isSecretKey(key) {
+				typed[key] = redacted
+			} else {
+				typed[key] = redactJSON(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range typed {
+			typed[i] = redactJSON(value)
+		}
+	}
+	return data
+}
@@ -0,0 +1,42 @@
+package update
+
+import (
+	"errors"
+
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/usage"
+)
+
+const (
+	CodeAccountCircuitOpen  settingserrors.Code = "ACCOUNT_CIRCUIT_OPEN"
+	CodeDailyBudgetExceeded settingserrors.Code = "DAILY_BUDGET_EXCEEDED"
+	CodeHostIsCNAME         settingserrors.Code = "HOST_IS_CNAME"
+	CodeIPAnomaly           settingserrors.Code = "IP_ANOMALY"
+	CodeIPNotPublishable    settingserrors.Code = "IP_NOT_PUBLISHABLE"
+	CodeNotLeader           settingserrors.Code = "NOT_LEADER"
+)
+
+// ErrorCode returns the machine readable code of an update error,
+// matching errors of the updater before errors of the providers.
+func ErrorCode(err error) settingserrors.Code {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrAccountBanned):
+		return settingserrors.CodeRateLimited
+	case errors.Is(err, ErrAccountCircuitOpen):
+		return CodeAccountCircuitOpen
+	case errors.Is(err, usage.ErrDailyBudgetExceeded):
+		return CodeDailyBudgetExceeded
+	case errors.Is(err, ErrIPBogon), errors.Is(err, ErrIPFlapping):
+		return CodeIPAnomaly
+	case errors.Is(err, ErrIPLinkLocal), errors.Is(err, ErrIPUniqueLocal):
+		return CodeIPNotPublishable
+	case errors.Is(err, ErrHostIsCNAME):
+		return CodeHostIsCNAME
+	case errors.Is(err, ErrNotLeader):
+		return CodeNotLeader
+	default:
+		return settingserrors.CodeOf(err)
+	}
+}
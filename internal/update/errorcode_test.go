@@ -0,0 +1,54 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/usage"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ErrorCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err  error
+		code settingserrors.Code
+	}{
+		"nil error": {},
+		"provider error": {
+			err:  fmt.Errorf("%w: invalid token", settingserrors.ErrAuth),
+			code: settingserrors.CodeProviderAuthFailed,
+		},
+		"specific provider error first": {
+			err: fmt.Errorf("%w: %s", settingserrors.ErrIPReceivedMismatch,
+				settingserrors.ErrBadHTTPStatus),
+			code: settingserrors.CodeIPMismatch,
+		},
+		"updater error": {
+			err:  fmt.Errorf("%w: for 5m", ErrAccountCircuitOpen),
+			code: CodeAccountCircuitOpen,
+		},
+		"usage error": {
+			err:  fmt.Errorf("doing request: %w", usage.ErrDailyBudgetExceeded),
+			code: CodeDailyBudgetExceeded,
+		},
+		"unknown error": {
+			err:  errors.New("dial tcp: connection refused"),
+			code: settingserrors.CodeUnknown,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			code := ErrorCode(testCase.err)
+
+			assert.Equal(t, testCase.code, code)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package update
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// fallbackFailureThreshold is the number of consecutive update
+// failures of a record before its fallback record is updated.
+const fallbackFailureThreshold = 3
+
+// fallbacks tracks the consecutive failures of records,
+// and the IP address last published to their fallback record.
+type fallbacks struct {
+	states map[uint]*fallbackState
+	mutex  sync.Mutex
+}
+
+type fallbackState struct {
+	consecutiveFailures uint
+	publishedIP         net.IP
+}
+
+func newFallbacks() *fallbacks {
+	return &fallbacks{
+		states: make(map[uint]*fallbackState),
+	}
+}
+
+// report records the result of an update for the record ID given,
+// and returns true if its fallback record should be updated with
+// the IP address given.
+func (f *fallbacks) report(id uint, ip net.IP, updateErr error) (updateFallback bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	state, ok := f.states[id]
+	if !ok {
+		state = new(fallbackState)
+		f.states[id] = state
+	}
+
+	if updateErr == nil {
+		state.consecutiveFailures = 0
+		return false
+	}
+	state.consecutiveFailures++
+	return state.consecutiveFailures >= fallbackFailureThreshold &&
+		!ip.Equal(state.publishedIP)
+}
+
+func (f *fallbacks) setPublished(id uint, ip net.IP) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.states[id].publishedIP = ip
+}
+
+// updateFallback updates the fallback record of the record given,
+// if any, when the record failed to update too many consecutive times.
+func (u *Updater) updateFallback(ctx context.Context, id uint,
+	record records.Record, ip net.IP, updateErr error) {
+	fallback := record.Options.Fallback
+	if fallback == nil || !u.fallbacks.report(id, ip, updateErr) {
+		return
+	}
+
+	u.logger.Info("updating fallback record " + fallback.String() + " to use " + ip.String() +
+		" since record " + record.Settings.BuildDomainName() + " failed to update " +
+		"too many consecutive times")
+	client := u.usageClient(string(fallback.Provider()), "")
+	_, err := fallback.Update(ctx, client, ip)
+	if err != nil {
+		u.logger.Error("updating fallback record " + fallback.BuildDomainName() + ": " + err.Error())
+		return
+	}
+	u.fallbacks.setPublished(id, ip)
+	message := record.Settings.BuildDomainName() + " failing, fallback " +
+		fallback.BuildDomainName() + " changed to " + ip.String()
+	u.notifyRecord(record, newNotificationData(record, ip, updateErr), message)
+}
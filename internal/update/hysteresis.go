@@ -0,0 +1,115 @@
+package update
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HysteresisSettings contains settings to require a new public
+// IP address to be observed consistently before publishing it,
+// for example to filter out transient IP addresses seen during
+// PPPoE reconnections.
+type HysteresisSettings struct {
+	// Fetches is the number of consecutive fetches a new public IP
+	// address must be obtained from before being published.
+	// It is disabled if set to 0 or 1.
+	Fetches uint
+	// Duration is the minimum duration a new public IP address must
+	// be obtained for before being published. It is disabled if 0.
+	Duration time.Duration
+}
+
+type hysteresis struct {
+	settings HysteresisSettings
+	// states maps an IP kind (ip, ipv4, ipv6) to its state.
+	states map[string]*hysteresisState
+	// mutex protects the states map since each IP version
+	// pipeline confirms its IP addresses concurrently.
+	mutex sync.Mutex
+}
+
+type hysteresisState struct {
+	// confirmedIP is the last IP address confirmed.
+	confirmedIP net.IP
+	// candidateIP is the new IP address being observed, and
+	// differing from the confirmed IP address.
+	candidateIP        net.IP
+	candidateFetches   uint
+	candidateFirstSeen time.Time
+}
+
+func newHysteresis(settings HysteresisSettings) *hysteresis {
+	return &hysteresis{
+		settings: settings,
+		states:   make(map[string]*hysteresisState),
+	}
+}
+
+func (h *hysteresis) enabled() bool {
+	return h.settings.Fetches > 1 || h.settings.Duration > 0
+}
+
+// confirm returns the IP address to publish for the kind given,
+// which is the last confirmed IP address until the new IP address
+// given has been observed consistently enough. The message returned
+// is not empty if the new IP address is not confirmed yet.
+func (h *hysteresis) confirm(kind string, ip net.IP, now time.Time) (
+	confirmedIP net.IP, message string) {
+	if ip == nil || !h.enabled() {
+		return ip, ""
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	state, ok := h.states[kind]
+	if !ok {
+		// Do not delay the first update after launch
+		h.states[kind] = &hysteresisState{confirmedIP: ip}
+		return ip, ""
+	}
+
+	if ip.Equal(state.confirmedIP) {
+		state.candidateIP = nil
+		return ip, ""
+	}
+
+	if !ip.Equal(state.candidateIP) {
+		state.candidateIP = ip
+		state.candidateFetches = 0
+		state.candidateFirstSeen = now
+	}
+	state.candidateFetches++
+
+	observedFor := now.Sub(state.candidateFirstSeen)
+	if state.candidateFetches >= h.settings.Fetches &&
+		observedFor >= h.settings.Duration {
+		state.confirmedIP = ip
+		state.candidateIP = nil
+		return ip, ""
+	}
+
+	message = "new public IP address " + ip.String() + " observed " +
+		strconv.Itoa(int(state.candidateFetches)) + " consecutive times for " +
+		observedFor.Round(time.Second).String() + ", keeping " +
+		state.confirmedIP.String() + " until it is confirmed"
+	return state.confirmedIP, message
+}
+
+// confirmIPs returns the public IP addresses to publish, keeping
+// the previously confirmed IP addresses until the new IP addresses
+// are observed consistently according to the hysteresis settings.
+func (r *Runner) confirmIPs(ip, ipv4, ipv6 net.IP, now time.Time) (
+	confirmedIP, confirmedIPv4, confirmedIPv6 net.IP) {
+	confirmed := [3]net.IP{ip, ipv4, ipv6}
+	kinds := [3]string{"ip", "ipv4", "ipv6"}
+	for i, kind := range kinds {
+		var message string
+		confirmed[i], message = r.hysteresis.confirm(kind, confirmed[i], now)
+		if message != "" {
+			r.logger.Info(message)
+		}
+	}
+	return confirmed[0], confirmed[1], confirmed[2]
+}
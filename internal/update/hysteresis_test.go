@@ -0,0 +1,76 @@
+package update
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_hysteresis_confirm(t *testing.T) {
+	t.Parallel()
+
+	type fetch struct {
+		ip          net.IP
+		elapsed     time.Duration
+		confirmedIP net.IP
+	}
+
+	testCases := map[string]struct {
+		settings HysteresisSettings
+		fetches  []fetch
+	}{
+		"disabled": {
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), confirmedIP: net.IPv4(2, 2, 2, 2)},
+			},
+		},
+		"nil IP": {
+			settings: HysteresisSettings{Fetches: 2},
+			fetches:  []fetch{{}},
+		},
+		"consecutive fetches": {
+			settings: HysteresisSettings{Fetches: 3},
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), confirmedIP: net.IPv4(2, 2, 2, 2)},
+			},
+		},
+		"transient IP": {
+			settings: HysteresisSettings{Fetches: 2},
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(3, 3, 3, 3), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(3, 3, 3, 3), confirmedIP: net.IPv4(3, 3, 3, 3)},
+			},
+		},
+		"duration": {
+			settings: HysteresisSettings{Duration: time.Minute},
+			fetches: []fetch{
+				{ip: net.IPv4(1, 1, 1, 1), confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), elapsed: time.Minute, confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), elapsed: 90 * time.Second, confirmedIP: net.IPv4(1, 1, 1, 1)},
+				{ip: net.IPv4(2, 2, 2, 2), elapsed: 2 * time.Minute, confirmedIP: net.IPv4(2, 2, 2, 2)},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			hysteresis := newHysteresis(testCase.settings)
+			start := time.Unix(0, 0)
+			for _, fetch := range testCase.fetches {
+				confirmedIP, _ := hysteresis.confirm("ipv4", fetch.ip, start.Add(fetch.elapsed))
+				assert.Equal(t, fetch.confirmedIP, confirmedIP)
+			}
+		})
+	}
+}
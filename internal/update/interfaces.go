@@ -3,6 +3,7 @@ package update
 import (
 	"context"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/records"
@@ -22,4 +23,22 @@ type Database interface {
 	Select(recordID uint) (record records.Record, err error)
 	SelectAll() (records []records.Record)
 	Update(recordID uint, record records.Record) (err error)
+	Modify(recordID uint, modify func(record *records.Record) (changed bool)) (err error)
+}
+
+type UsageTracker interface {
+	RoundTripper(proxied http.RoundTripper, key string) http.RoundTripper
+}
+
+type RateLimiter interface {
+	RoundTripper(proxied http.RoundTripper, provider, account string) http.RoundTripper
+}
+
+type PropagationChecker interface {
+	Check(ctx context.Context, zone, hostname string, ip net.IP,
+		recordResolver *net.Resolver) (err error)
+}
+
+type Leader interface {
+	IsLeader() bool
 }
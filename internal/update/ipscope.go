@@ -0,0 +1,15 @@
+package update
+
+import "net"
+
+// sameIP returns true if the two IP addresses are equal, comparing
+// IPv6 addresses only within the IPv6 comparison prefix, such that a
+// change of the interface identifier, for example due to privacy
+// extensions, is not considered as an IP address change.
+func (r *Runner) sameIP(a, b net.IP) bool {
+	if a == nil || b == nil || a.To4() != nil || b.To4() != nil ||
+		r.ipv6CompareMask == nil {
+		return a.Equal(b)
+	}
+	return a.Mask(r.ipv6CompareMask).Equal(b.Mask(r.ipv6CompareMask))
+}
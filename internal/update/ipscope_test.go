@@ -0,0 +1,54 @@
+package update
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Runner_sameIP(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		compareMask net.IPMask
+		a, b        net.IP
+		same        bool
+	}{
+		"no mask different IPv6": {
+			a: net.ParseIP("2001:db8::1"),
+			b: net.ParseIP("2001:db8::2"),
+		},
+		"same prefix": {
+			compareMask: net.CIDRMask(64, 128),
+			a:           net.ParseIP("2001:db8:0:1::1"),
+			b:           net.ParseIP("2001:db8:0:1:abcd::2"),
+			same:        true,
+		},
+		"different prefix": {
+			compareMask: net.CIDRMask(64, 128),
+			a:           net.ParseIP("2001:db8:0:1::1"),
+			b:           net.ParseIP("2001:db8:0:2::1"),
+		},
+		"IPv4 not masked": {
+			compareMask: net.CIDRMask(64, 128),
+			a:           net.IPv4(1, 2, 3, 4),
+			b:           net.IPv4(1, 2, 3, 5),
+		},
+		"nil IP": {
+			compareMask: net.CIDRMask(64, 128),
+			a:           net.ParseIP("2001:db8::1"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			runner := &Runner{ipv6CompareMask: testCase.compareMask}
+			same := runner.sameIP(testCase.a, testCase.b)
+			assert.Equal(t, testCase.same, same)
+		})
+	}
+}
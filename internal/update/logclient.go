@@ -2,7 +2,6 @@ package update
 
 import (
 	"bytes"
-	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -26,15 +25,13 @@ func makeLogClient(client *http.Client, logger Logger) (newClient *http.Client)
 		originalTransport = http.DefaultTransport
 	}
 
-	transport, ok := originalTransport.(*http.Transport)
-	if !ok {
-		panic(fmt.Sprintf("transport %T is not *http.Transport", originalTransport))
+	proxied := originalTransport
+	if transport, ok := originalTransport.(*http.Transport); ok {
+		proxied = transport.Clone()
 	}
 
-	clonedTransport := transport.Clone()
-
 	newClient.Transport = &loggingRoundTripper{
-		proxied: clonedTransport,
+		proxied: proxied,
 		logger:  logger,
 	}
 
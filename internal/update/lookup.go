@@ -0,0 +1,40 @@
+package update
+
+import (
+	"context"
+	"net"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/resolver"
+)
+
+// LookupSettings are the settings of the DNS lookup of records done
+// before updating them, to skip updates of records already having
+// the IP address to publish without calling the provider API.
+type LookupSettings struct {
+	// Resolver is the resolver to use, and
+	// is nil to use the system resolver.
+	Resolver *net.Resolver
+	// Authoritative is true to query the authoritative
+	// nameservers of the domain of each record instead.
+	Authoritative bool
+}
+
+// recordResolver returns the resolver to look up the record with,
+// which is the resolver of the record if it has one.
+func (r *Runner) recordResolver(ctx context.Context, record librecords.Record) *net.Resolver {
+	if record.Options.Resolver != nil {
+		return record.Options.Resolver
+	} else if !r.lookup.Authoritative {
+		return r.resolver
+	}
+
+	domain := record.Settings.Domain()
+	authoritative, err := resolver.NewAuthoritative(ctx, r.resolver, domain)
+	if err != nil {
+		r.logger.Warn("cannot use the authoritative nameservers of " + domain +
+			", using the resolver instead: " + err.Error())
+		return r.resolver
+	}
+	return authoritative
+}
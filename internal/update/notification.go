@@ -0,0 +1,75 @@
+package update
+
+import (
+	"bytes"
+	"net"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// NotificationData is the data available to notification templates,
+// for example {{.Domain}} changed from {{.OldIP}} to {{.NewIP}}.
+type NotificationData struct {
+	Domain string
+	OldIP  string
+	NewIP  string
+	Labels map[string]string
+	Error  string
+	// ErrorCode is the machine readable code of the error,
+	// for example PROVIDER_AUTH_FAILED or RATE_LIMITED.
+	ErrorCode string
+}
+
+func newNotificationData(record records.Record, newIP net.IP, err error) (data NotificationData) {
+	data = NotificationData{
+		Domain: record.Settings.BuildDomainName(),
+		Labels: record.Options.Labels,
+	}
+	if oldIP := record.History.GetCurrentIP(); oldIP != nil {
+		data.OldIP = oldIP.String()
+	}
+	if newIP != nil {
+		data.NewIP = newIP.String()
+	}
+	if err != nil {
+		data.Error = err.Error()
+		data.ErrorCode = string(ErrorCode(err))
+	}
+	return data
+}
+
+// notifyRecord sends a notification for the record using the record
+// template, or the global template if the record has none. If no template
+// is set or if the template execution fails, the default message is sent.
+func (u *Updater) notifyRecord(record records.Record, data NotificationData,
+	defaultMessage string) {
+	tmpl := record.Options.NotificationTemplate
+	if tmpl == nil {
+		tmpl = u.template
+	}
+	if tmpl == nil {
+		u.notifyTargets(record, defaultMessage)
+		return
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	err := tmpl.Execute(buffer, data)
+	if err != nil {
+		u.logger.Error("executing notification template: " + err.Error())
+		u.notifyTargets(record, defaultMessage)
+		return
+	}
+	u.notifyTargets(record, buffer.String())
+}
+
+// notifyTargets sends the message to the global notification
+// targets and to the notification targets of the record tenant.
+func (u *Updater) notifyTargets(record records.Record, message string) {
+	u.notify(message)
+	if record.Options.Tenant == nil {
+		return
+	}
+	if notify, ok := u.tenantNotify[record.Options.Tenant.Name]; ok {
+		notify(message)
+	}
+}
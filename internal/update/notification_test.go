@@ -0,0 +1,168 @@
+package update
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Updater_notifyTargets(t *testing.T) {
+	t.Parallel()
+
+	var global, acme, other []string
+	updater := &Updater{
+		notify: func(message string) { global = append(global, message) },
+		tenantNotify: map[string]func(message string){
+			"acme":  func(message string) { acme = append(acme, message) },
+			"other": func(message string) { other = append(other, message) },
+		},
+	}
+
+	updater.notifyTargets(records.Record{}, "no tenant")
+	updater.notifyTargets(records.Record{Options: records.Options{
+		Tenant: &records.Tenant{Name: "acme"},
+	}}, "acme record")
+	updater.notifyTargets(records.Record{Options: records.Options{
+		Tenant: &records.Tenant{Name: "silent"},
+	}}, "silent record")
+
+	assert.Equal(t, []string{"no tenant", "acme record", "silent record"}, global)
+	assert.Equal(t, []string{"acme record"}, acme)
+	assert.Empty(t, other)
+}
+
+func Test_newNotificationData(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		history models.History
+		labels  map[string]string
+		newIP   net.IP
+		err     error
+		data    NotificationData
+	}{
+		"first update": {
+			newIP: net.IPv4(1, 2, 3, 4),
+			data: NotificationData{
+				Domain: "home.example.com",
+				NewIP:  "1.2.3.4",
+			},
+		},
+		"IP change with labels": {
+			history: models.History{{IP: net.IPv4(1, 2, 3, 4), Time: time.Unix(0, 0)}},
+			labels:  map[string]string{"site": "paris"},
+			newIP:   net.IPv4(5, 6, 7, 8),
+			data: NotificationData{
+				Domain: "home.example.com",
+				OldIP:  "1.2.3.4",
+				NewIP:  "5.6.7.8",
+				Labels: map[string]string{"site": "paris"},
+			},
+		},
+		"updater error": {
+			history: models.History{{IP: net.IPv4(1, 2, 3, 4), Time: time.Unix(0, 0)}},
+			err:     fmt.Errorf("%w: standing by", ErrNotLeader),
+			data: NotificationData{
+				Domain:    "home.example.com",
+				OldIP:     "1.2.3.4",
+				Error:     "instance is not the leader: standing by",
+				ErrorCode: string(CodeNotLeader),
+			},
+		},
+		"provider error": {
+			err: fmt.Errorf("%w: bad key", settingserrors.ErrAuth),
+			data: NotificationData{
+				Domain:    "home.example.com",
+				Error:     "bad authentication: bad key",
+				ErrorCode: "PROVIDER_AUTH_FAILED",
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			record := newNS1Record(t, "example.com", "home", ipversion.IP4)
+			record.History = testCase.history
+			record.Options.Labels = testCase.labels
+
+			data := newNotificationData(record, testCase.newIP, testCase.err)
+
+			assert.Equal(t, testCase.data, data)
+		})
+	}
+}
+
+func Test_Updater_notifyRecord(t *testing.T) {
+	t.Parallel()
+
+	global := template.Must(template.New("").Parse("global: {{.Domain}} {{.NewIP}}"))
+	recordTemplate := template.Must(template.New("").Parse(
+		"record: {{.OldIP}} to {{.NewIP}} at {{index .Labels \"site\"}}"))
+	failing := template.Must(template.New("").Parse("{{.Unknown}}"))
+
+	testCases := map[string]struct {
+		global  *template.Template
+		record  *template.Template
+		message string
+	}{
+		"default message": {
+			message: "default",
+		},
+		"global template": {
+			global:  global,
+			message: "global: home.example.com 5.6.7.8",
+		},
+		"record template over global template": {
+			global:  global,
+			record:  recordTemplate,
+			message: "record: 1.2.3.4 to 5.6.7.8 at paris",
+		},
+		"record template without global template": {
+			record:  recordTemplate,
+			message: "record: 1.2.3.4 to 5.6.7.8 at paris",
+		},
+		"failing global template": {
+			global:  failing,
+			message: "default",
+		},
+		"failing record template": {
+			global:  global,
+			record:  failing,
+			message: "default",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var messages []string
+			updater := &Updater{
+				notify:   func(message string) { messages = append(messages, message) },
+				template: testCase.global,
+				logger:   noopLogger{},
+			}
+			record := newNS1Record(t, "example.com", "home", ipversion.IP4)
+			record.History = models.History{{IP: net.IPv4(1, 2, 3, 4), Time: time.Unix(0, 0)}}
+			record.Options.Labels = map[string]string{"site": "paris"}
+			record.Options.NotificationTemplate = testCase.record
+			data := newNotificationData(record, net.IPv4(5, 6, 7, 8), nil)
+
+			updater.notifyRecord(record, data, "default")
+
+			assert.Equal(t, []string{testCase.message}, messages)
+		})
+	}
+}
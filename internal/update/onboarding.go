@@ -0,0 +1,140 @@
+package update
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// onboardingMinWait is the minimum wait between two updates triggered
+// to onboard records, such that the public IP address is not fetched
+// too often when many records are due at close times.
+const onboardingMinWait = 5 * time.Second
+
+// onboarding spreads the first update of records without history over
+// a ramp-up window, pacing the records of each provider evenly, instead
+// of updating hundreds of newly added records at once at launch.
+type onboarding struct {
+	// pending maps the ID of a record to onboard to its onboarding state.
+	pending map[uint]*onboardingRecord
+	// providerInterval maps a provider to the interval between
+	// the first updates of its records.
+	providerInterval map[models.Provider]time.Duration
+	mutex            sync.Mutex
+}
+
+type onboardingRecord struct {
+	provider  models.Provider
+	ipVersion ipversion.IPVersion
+	notBefore time.Time
+}
+
+func newOnboarding(records []librecords.Record, rampUp time.Duration,
+	now time.Time) *onboarding {
+	o := &onboarding{
+		pending:          make(map[uint]*onboardingRecord),
+		providerInterval: make(map[models.Provider]time.Duration),
+	}
+	if rampUp == 0 {
+		return o
+	}
+
+	providerToIDs := make(map[models.Provider][]uint)
+	for i, record := range records {
+		if len(record.History) > 0 {
+			continue
+		}
+		provider := record.Settings.Provider()
+		providerToIDs[provider] = append(providerToIDs[provider], uint(i))
+	}
+
+	for provider, ids := range providerToIDs {
+		interval := rampUp / time.Duration(len(ids))
+		o.providerInterval[provider] = interval
+		for i, id := range ids {
+			o.pending[id] = &onboardingRecord{
+				provider:  provider,
+				ipVersion: records[id].Settings.IPVersion(),
+				notBefore: now.Add(time.Duration(i) * interval),
+			}
+		}
+	}
+	return o
+}
+
+// isHeld returns true if the record is waiting for its onboarding time.
+func (o *onboarding) isHeld(id uint, now time.Time) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	record, ok := o.pending[id]
+	return ok && now.Before(record.notBefore)
+}
+
+// isPending returns true if the record is still to be onboarded,
+// whether it is waiting for its onboarding time or not.
+func (o *onboarding) isPending(id uint) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	_, ok := o.pending[id]
+	return ok
+}
+
+// done reports the result of an update of a record. If the provider
+// rate limited a record being onboarded, the record is retried after the
+// provider interval and the records of the provider still waiting are
+// postponed by the provider interval.
+func (o *onboarding) done(id uint, err error, now time.Time) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	record, ok := o.pending[id]
+	if !ok {
+		return
+	}
+	if !errors.Is(err, settingserrors.ErrAbuse) {
+		delete(o.pending, id)
+		return
+	}
+
+	interval := o.providerInterval[record.provider]
+	for otherID, other := range o.pending {
+		if otherID != id && other.provider == record.provider {
+			other.notBefore = other.notBefore.Add(interval)
+		}
+	}
+	record.notBefore = now.Add(interval)
+}
+
+// wakeup returns a channel receiving when the next record of the IP
+// version given is due to be onboarded, or nil if no record is pending.
+func (o *onboarding) wakeup(ipVersion ipversion.IPVersion, now time.Time) <-chan time.Time {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	var next time.Time
+	for id, record := range o.pending {
+		switch {
+		case record.ipVersion != ipVersion:
+			continue
+		case !now.Before(record.notBefore):
+			// due and already checked, for example
+			// without requiring an update.
+			delete(o.pending, id)
+			continue
+		case next.IsZero() || record.notBefore.Before(next):
+			next = record.notBefore
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+
+	wait := next.Sub(now)
+	if wait < onboardingMinWait {
+		wait = onboardingMinWait
+	}
+	return time.After(wait)
+}
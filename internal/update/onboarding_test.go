@@ -0,0 +1,75 @@
+package update
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/domeneshop"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_onboarding(t *testing.T) {
+	t.Parallel()
+
+	ns1Settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	domeneshopSettings, err := domeneshop.New(json.RawMessage(`{"token": "token", "secret": "secret"}`),
+		"example.com", "home", ipversion.IP4)
+	require.NoError(t, err)
+
+	records := []librecords.Record{
+		{Settings: ns1Settings},
+		{Settings: ns1Settings, History: models.History{{IP: net.IPv4(1, 2, 3, 4)}}},
+		{Settings: ns1Settings},
+		{Settings: domeneshopSettings},
+		{Settings: ns1Settings},
+	}
+	start := time.Unix(0, 0)
+	o := newOnboarding(records, time.Hour, start)
+
+	// ns1 records without history are spread every 20 minutes,
+	// and the single domeneshop record is onboarded right away.
+	assert.False(t, o.isHeld(0, start))
+	assert.False(t, o.isHeld(1, start))
+	assert.True(t, o.isHeld(2, start))
+	assert.False(t, o.isHeld(2, start.Add(20*time.Minute)))
+	assert.False(t, o.isHeld(3, start))
+	assert.True(t, o.isHeld(4, start.Add(20*time.Minute)))
+	assert.False(t, o.isHeld(4, start.Add(40*time.Minute)))
+
+	// rate limited first update postpones the remaining ns1 records
+	o.done(0, settingserrors.ErrAbuse, start)
+	assert.True(t, o.isHeld(0, start.Add(19*time.Minute)))
+	assert.True(t, o.isHeld(2, start.Add(39*time.Minute)))
+	assert.False(t, o.isHeld(2, start.Add(40*time.Minute)))
+	assert.False(t, o.isHeld(3, start))
+
+	o.done(0, nil, start.Add(20*time.Minute))
+	assert.False(t, o.isHeld(0, start))
+
+	assert.Nil(t, o.wakeup(ipversion.IP6, start))
+	assert.NotNil(t, o.wakeup(ipversion.IP4, start))
+}
+
+func Test_onboarding_disabled(t *testing.T) {
+	t.Parallel()
+
+	settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	records := []librecords.Record{{Settings: settings}, {Settings: settings}}
+
+	o := newOnboarding(records, 0, time.Unix(0, 0))
+
+	assert.False(t, o.isHeld(1, time.Unix(0, 0)))
+	assert.Nil(t, o.wakeup(ipversion.IP4, time.Unix(0, 0)))
+}
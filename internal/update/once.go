@@ -0,0 +1,63 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+)
+
+var ErrOnceFailed = errors.New("update cycle failed")
+
+// RunOnce runs a single update cycle of all the pipelines concurrently,
+// checking all the records regardless of their update period, writes a
+// summary of the cycles and of the records to the writer given, and
+// returns an error if the cycle had any error, such as a public IP
+// address detection failure, or if any record failed to be updated.
+func (r *Runner) RunOnce(ctx context.Context, w io.Writer) (err error) {
+	results := make([][]error, len(r.pipelines))
+	var wg sync.WaitGroup
+	for i, p := range r.pipelines {
+		wg.Add(1)
+		go func(i int, p *pipeline) {
+			defer wg.Done()
+			results[i] = r.updateNecessary(ctx, p.ipVersion, r.ipv6Mask, true, false)
+		}(i, p)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, pipelineErrs := range results {
+		errs = append(errs, pipelineErrs...)
+	}
+
+	for _, summary := range r.CycleSummaries() {
+		fmt.Fprintln(w, summary.String())
+	}
+
+	var failed []string
+	for _, record := range r.db.SelectAll() {
+		fmt.Fprintln(w, record.String())
+		if record.Status == constants.FAIL || record.Status == constants.COOLDOWN {
+			failed = append(failed, record.Settings.BuildDomainName())
+		}
+	}
+
+	for _, err := range errs {
+		fmt.Fprintln(w, "error: "+err.Error())
+	}
+
+	switch {
+	case len(failed) > 0:
+		return fmt.Errorf("%w: %d error(s), records failed: %s",
+			ErrOnceFailed, len(errs), strings.Join(failed, ", "))
+	case len(errs) > 0:
+		return fmt.Errorf("%w: %d error(s), first error: %s",
+			ErrOnceFailed, len(errs), errs[0])
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/qdm12/golibs/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDatabase struct {
+	records []librecords.Record
+	mutex   sync.Mutex
+}
+
+func (db *testDatabase) Select(id uint) (record librecords.Record, err error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	return db.records[id], nil
+}
+
+func (db *testDatabase) SelectAll() (records []librecords.Record) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	return append(records, db.records...)
+}
+
+func (db *testDatabase) Update(id uint, record librecords.Record) (err error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	db.records[id] = record
+	return nil
+}
+
+func (db *testDatabase) Modify(id uint, modify func(record *librecords.Record) (changed bool)) (err error) {
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	modify(&db.records[id])
+	return nil
+}
+
+// testUpdater sets the status of records as the updater would.
+type testUpdater struct {
+	db  *testDatabase
+	err error
+}
+
+func (u *testUpdater) Update(_ context.Context, id uint, ip net.IP, now time.Time) (err error) {
+	record, _ := u.db.Select(id)
+	record.Time = now
+	record.Status = constants.SUCCESS
+	if u.err != nil {
+		record.Status = constants.FAIL
+		record.Message = u.err.Error()
+	}
+	_ = u.db.Update(id, record)
+	return u.err
+}
+
+type testIPFetcher struct {
+	ip  net.IP
+	err error
+}
+
+func (f *testIPFetcher) IP(context.Context) (net.IP, error)  { return f.ip, f.err }
+func (f *testIPFetcher) IP4(context.Context) (net.IP, error) { return f.ip, f.err }
+func (f *testIPFetcher) IP6(context.Context) (net.IP, error) { return nil, f.err }
+
+type testLeader bool
+
+func (l testLeader) IsLeader() bool { return bool(l) }
+
+type noopLogger struct{}
+
+func (noopLogger) Debug(string)              {}
+func (noopLogger) Info(string)               {}
+func (noopLogger) Warn(string)               {}
+func (noopLogger) Error(string)              {}
+func (noopLogger) PatchLevel(logging.Level)  {}
+func (noopLogger) PatchPrefix(prefix string) {}
+
+// failingResolver fails all DNS lookups without network access,
+// such that records are updated regardless of their IP address.
+func failingResolver() *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(context.Context, string, string) (net.Conn, error) {
+			return nil, errors.New("no network in tests")
+		},
+	}
+}
+
+func Test_Runner_RunOnce(t *testing.T) {
+	t.Parallel()
+
+	errProvider := errors.New("provider error")
+	errDetection := errors.New("detection error")
+
+	testCases := map[string]struct {
+		ipFetcher  *testIPFetcher
+		updateErr  error
+		leader     bool
+		errWrapped error
+		errMessage string
+		status     string
+	}{
+		"success": {
+			ipFetcher: &testIPFetcher{ip: net.IPv4(1, 2, 3, 4)},
+			leader:    true,
+			status:    string(constants.SUCCESS),
+		},
+		"detection failure": {
+			ipFetcher:  &testIPFetcher{err: errDetection},
+			leader:     true,
+			errWrapped: ErrOnceFailed,
+			errMessage: "update cycle failed: 1 error(s), first error: detection error",
+			status:     string(constants.UPTODATE),
+		},
+		"provider failure": {
+			ipFetcher:  &testIPFetcher{ip: net.IPv4(1, 2, 3, 4)},
+			updateErr:  errProvider,
+			leader:     true,
+			errWrapped: ErrOnceFailed,
+			errMessage: "update cycle failed: 1 error(s), records failed: example.com",
+			status:     string(constants.FAIL),
+		},
+		"not leader": {
+			ipFetcher:  &testIPFetcher{ip: net.IPv4(1, 2, 3, 4)},
+			errWrapped: ErrOnceFailed,
+			errMessage: "update cycle failed: 1 error(s), first error: instance is not the leader: standing by",
+			status:     string(constants.UNSET),
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+				"example.com", "@", ipversion.IP4)
+			require.NoError(t, err)
+			db := &testDatabase{records: []librecords.Record{
+				librecords.New(settings, librecords.Options{}, nil),
+			}}
+			updater := &testUpdater{db: db, err: testCase.updateErr}
+			lookup := LookupSettings{Resolver: failingResolver()}
+			runner := NewRunner(db, updater, testCase.ipFetcher, Periods{}, nil, nil,
+				0, 0, AnomalySettings{}, HysteresisSettings{}, 0, lookup, 1,
+				testLeader(testCase.leader), noopLogger{}, time.Now)
+			var output bytes.Buffer
+
+			err = runner.RunOnce(context.Background(), &output)
+
+			if testCase.errWrapped != nil {
+				assert.ErrorIs(t, err, testCase.errWrapped)
+				assert.EqualError(t, err, testCase.errMessage)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, testCase.status, string(db.records[0].Status))
+			assert.NotEmpty(t, output.String())
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package update
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/cron"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// Periods contains the periods at which the public IP address
+// of each IP version is fetched and records are updated.
+type Periods struct {
+	// IP is the period for records with the IP version "ipv4 or ipv6".
+	IP   time.Duration
+	IPv4 time.Duration
+	IPv6 time.Duration
+	// Cron, if not nil, schedules the checks of the records
+	// without their own period instead of the periods above.
+	Cron *cron.Schedule
+}
+
+// pipeline fetches the public IP address of a single IP version
+// and updates records configured with this IP version, such that
+// an outage of one IP version does not delay updates of the others.
+type pipeline struct {
+	ipVersion   ipversion.IPVersion
+	force       chan bool // true to confirm anomalous IP addresses
+	forceResult chan []error
+}
+
+func newPipelines() (pipelines []*pipeline) {
+	ipVersions := [...]ipversion.IPVersion{ipversion.IP4or6, ipversion.IP4, ipversion.IP6}
+	pipelines = make([]*pipeline, len(ipVersions))
+	for i, ipVersion := range ipVersions {
+		pipelines[i] = &pipeline{
+			ipVersion:   ipVersion,
+			force:       make(chan bool),
+			forceResult: make(chan []error),
+		}
+	}
+	return pipelines
+}
+
+func (r *Runner) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	var wg sync.WaitGroup
+	for _, p := range r.pipelines {
+		wg.Add(1)
+		go func(p *pipeline) {
+			defer wg.Done()
+			r.runPipeline(ctx, p)
+		}(p)
+	}
+	wg.Wait()
+}
+
+// runPipeline runs an update cycle each time a record of the IP version
+// of the pipeline is due, for its onboarding or according to its update
+// period, and each time an update is forced.
+func (r *Runner) runPipeline(ctx context.Context, p *pipeline) {
+	for {
+		now := r.timeNow()
+		scheduleWakeup := r.schedule.wakeup(r.db.SelectAll(), p.ipVersion, now)
+		onboardingWakeup := r.onboarding.wakeup(p.ipVersion, now)
+		select {
+		case <-scheduleWakeup:
+			r.updateNecessary(ctx, p.ipVersion, r.ipv6Mask, false, false)
+		case <-onboardingWakeup:
+			r.updateNecessary(ctx, p.ipVersion, r.ipv6Mask, false, false)
+		case confirmAnomalies := <-p.force:
+			p.forceResult <- r.updateNecessary(ctx, p.ipVersion, r.ipv6Mask, true, confirmAnomalies)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) ForceUpdate(ctx context.Context) (errs []error) {
+	return r.forceUpdate(ctx, false)
+}
+
+// ForceUpdateConfirmed forces an update, publishing public IP
+// addresses detected as anomalous and blocked otherwise.
+func (r *Runner) ForceUpdateConfirmed(ctx context.Context) (errs []error) {
+	return r.forceUpdate(ctx, true)
+}
+
+// forceUpdate forces an update on all the pipelines concurrently,
+// and combines their errors once they all finished.
+func (r *Runner) forceUpdate(ctx context.Context, confirmAnomalies bool) (errs []error) {
+	results := make([][]error, len(r.pipelines))
+	var wg sync.WaitGroup
+	for i, p := range r.pipelines {
+		wg.Add(1)
+		go func(i int, p *pipeline) {
+			defer wg.Done()
+			select {
+			case p.force <- confirmAnomalies:
+			case <-ctx.Done():
+				results[i] = []error{ctx.Err()}
+				return
+			}
+
+			select {
+			case results[i] = <-p.forceResult:
+			case <-ctx.Done():
+				results[i] = []error{ctx.Err()}
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, pipelineErrs := range results {
+		errs = append(errs, pipelineErrs...)
+	}
+	return errs
+}
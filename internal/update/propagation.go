@@ -0,0 +1,71 @@
+package update
+
+import (
+	"context"
+	"net"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// Run waits for the context to be canceled, and then cancels the
+// propagation checks running and waits for them to complete.
+func (u *Updater) Run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+	<-ctx.Done()
+	u.propagationMutex.Lock()
+	u.propagationCancel()
+	u.propagationMutex.Unlock()
+	u.propagationChecks.Wait()
+}
+
+// startPropagationCheck verifies the propagation of the IP address
+// in a goroutine tracked until Run stops. The check is not started
+// if Run already stopped.
+func (u *Updater) startPropagationCheck(id uint, record librecords.Record, ip net.IP) {
+	u.propagationMutex.Lock()
+	defer u.propagationMutex.Unlock()
+	if u.propagationCtx.Err() != nil {
+		return
+	}
+	u.propagationChecks.Add(1)
+	go func() {
+		defer u.propagationChecks.Done()
+		u.verifyPropagation(u.propagationCtx, id, record, ip)
+	}()
+}
+
+// verifyPropagation verifies the IP address is served by the DNS servers
+// checked, and sets the record status to success once it is. The record
+// is left untouched if it got updated again or confirmed by the webhook
+// in the meantime, or if the check is canceled. The record resolver is
+// used for the check if it is set.
+func (u *Updater) verifyPropagation(ctx context.Context, id uint,
+	record librecords.Record, ip net.IP) {
+	hostname := record.Settings.BuildDomainName()
+	// The check is bounded by its maximum number of tries, and uses
+	// the updater context since the update context can be a request one.
+	checkErr := u.propagation.Check(ctx, record.Settings.Domain(), hostname, ip,
+		record.Options.Resolver)
+	if checkErr != nil && ctx.Err() != nil {
+		return // canceled at shutdown
+	}
+
+	err := u.db.Modify(id, func(record *librecords.Record) (changed bool) {
+		if record.Status != constants.NOTPROPAGATED || !ip.Equal(record.History.GetCurrentIP()) {
+			// updated again or confirmed by webhook in the meantime
+			return false
+		}
+		if checkErr != nil {
+			u.logger.Warn("IP address " + ip.String() + " of " + hostname + " is not propagated: " + checkErr.Error())
+			record.Message = "changed to " + ip.String() + ", not propagated: " + checkErr.Error()
+		} else {
+			u.logger.Info("IP address " + ip.String() + " of " + hostname + " is propagated")
+			record.Status = constants.SUCCESS
+		}
+		return true
+	})
+	if err != nil {
+		u.logger.Error(err.Error())
+	}
+}
@@ -0,0 +1,108 @@
+package update
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/constants"
+	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingChecker reports the IP address as propagated once
+// released, or returns the context error if canceled before.
+// It records the record resolver it is called with.
+type blockingChecker struct {
+	started  chan struct{}
+	release  chan struct{}
+	resolver *net.Resolver
+}
+
+func (c *blockingChecker) Check(ctx context.Context, _, _ string, _ net.IP,
+	recordResolver *net.Resolver) error {
+	c.resolver = recordResolver
+	close(c.started)
+	select {
+	case <-c.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func Test_Updater_propagationChecks(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		stopBeforeStart bool
+		release         bool
+		status          models.Status
+	}{
+		"propagated": {
+			release: true,
+			status:  constants.SUCCESS,
+		},
+		"canceled at shutdown": {
+			status: constants.NOTPROPAGATED,
+		},
+		"not started after shutdown": {
+			stopBeforeStart: true,
+			status:          constants.NOTPROPAGATED,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ip := net.IPv4(1, 2, 3, 4)
+			record := newNS1Record(t, "example.com", "@", ipversion.IP4)
+			record.Status = constants.NOTPROPAGATED
+			record.History = models.History{{IP: ip, Time: time.Unix(0, 0)}}
+			record.Options.Resolver = &net.Resolver{}
+			db := &testDatabase{records: []librecords.Record{record}}
+			checker := &blockingChecker{
+				started: make(chan struct{}),
+				release: make(chan struct{}),
+			}
+			updater := NewUpdater(db, &http.Client{}, nil, nil, nil, nil, nil,
+				BackoffSettings{}, false, checker, testLeader(true), noopLogger{})
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go updater.Run(ctx, done)
+
+			if testCase.stopBeforeStart {
+				cancel()
+				<-done
+				updater.startPropagationCheck(0, record, ip)
+			} else {
+				updater.startPropagationCheck(0, record, ip)
+				<-checker.started
+				assert.Same(t, record.Options.Resolver, checker.resolver)
+				if testCase.release {
+					close(checker.release)
+					assert.Eventually(t, func() bool {
+						records := db.SelectAll()
+						return records[0].Status == constants.SUCCESS
+					}, time.Second, time.Millisecond)
+				}
+				cancel()
+				<-done
+			}
+
+			assert.Equal(t, testCase.status, db.records[0].Status)
+			select {
+			case <-checker.started:
+				assert.False(t, testCase.stopBeforeStart, "check started after shutdown")
+			default:
+				assert.True(t, testCase.stopBeforeStart, "check not started")
+			}
+		})
+	}
+}
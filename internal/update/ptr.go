@@ -0,0 +1,25 @@
+package update
+
+import (
+	"context"
+	"net"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// updatePTR points the PTR record of the new IP address to the
+// hostname of the record, if the record has PTR settings. A failure
+// is only logged since the record itself is updated successfully.
+func (u *Updater) updatePTR(ctx context.Context, record records.Record, newIP net.IP) {
+	if record.Options.PTR == nil {
+		return
+	}
+	hostname := record.Settings.BuildDomainName()
+	err := record.Options.PTR.UpdatePTR(ctx, u.client, newIP, hostname)
+	if err != nil {
+		u.logger.Error("updating PTR record of " + newIP.String() +
+			" to " + hostname + ": " + err.Error())
+		return
+	}
+	u.logger.Info("PTR record of " + newIP.String() + " set to " + hostname)
+}
@@ -0,0 +1,25 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+var (
+	ErrIPLinkLocal   = errors.New("link-local IP address cannot be published")
+	ErrIPUniqueLocal = errors.New("unique local IPv6 address cannot be published")
+)
+
+// checkPublishable returns an error if the IP address should not be
+// published in DNS records. Unique local IPv6 addresses (fc00::/7) can
+// be allowed for records of DNS providers serving an internal network.
+func checkPublishable(ip net.IP, allowULA bool) (err error) {
+	switch {
+	case ip.IsLinkLocalUnicast():
+		return fmt.Errorf("%w: %s", ErrIPLinkLocal, ip)
+	case ip.To4() == nil && ip.IsPrivate() && !allowULA:
+		return fmt.Errorf("%w: %s (see the allow_ula option)", ErrIPUniqueLocal, ip)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package update
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkPublishable(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ip       net.IP
+		allowULA bool
+		err      string
+	}{
+		"public IPv4": {
+			ip: net.IP{1, 2, 3, 4},
+		},
+		"public IPv6": {
+			ip: net.ParseIP("2001:db8::1"),
+		},
+		"link-local IPv4": {
+			ip:  net.IP{169, 254, 1, 1},
+			err: "link-local IP address cannot be published: 169.254.1.1",
+		},
+		"link-local IPv6": {
+			ip:       net.ParseIP("fe80::1"),
+			allowULA: true,
+			err:      "link-local IP address cannot be published: fe80::1",
+		},
+		"ULA IPv6": {
+			ip:  net.ParseIP("fd00::1"),
+			err: "unique local IPv6 address cannot be published: fd00::1 (see the allow_ula option)",
+		},
+		"ULA IPv6 allowed": {
+			ip:       net.ParseIP("fd00::1"),
+			allowULA: true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkPublishable(testCase.ip, testCase.allowULA)
+
+			if testCase.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, testCase.err, err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package update
+
+import (
+	"net"
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings"
+)
+
+// shouldRefreshRecord returns true if the record must be updated even if
+// its IP address did not change, because its last successful update is
+// older than the force update period given or than the refresh period
+// of its provider if it requires records to be updated periodically.
+// The force update period is disabled if 0.
+func shouldRefreshRecord(record librecords.Record, ip net.IP,
+	forceUpdatePeriod time.Duration, now time.Time) bool {
+	period := forceUpdatePeriod
+	refresher, ok := record.Settings.(settings.Refresher)
+	if ok && (period == 0 || refresher.RefreshPeriod() < period) {
+		period = refresher.RefreshPeriod()
+	}
+	if period == 0 || ip == nil {
+		return false
+	}
+	successTime := record.LastSuccessTime()
+	return !successTime.IsZero() && now.Sub(successTime) >= period
+}
@@ -0,0 +1,88 @@
+package update
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/dyfi"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_shouldRefreshRecord(t *testing.T) {
+	t.Parallel()
+
+	dyfiSettings, err := dyfi.New(json.RawMessage(`{"username": "user", "password": "password"}`),
+		"dy.fi", "home", ipversion.IP4)
+	require.NoError(t, err)
+	ns1Settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+
+	now := time.Unix(10*24*3600, 0)
+	ip := net.IPv4(1, 2, 3, 4)
+	history := func(age time.Duration) models.History {
+		return models.History{{IP: ip, Time: now.Add(-age)}}
+	}
+
+	testCases := map[string]struct {
+		record            librecords.Record
+		ip                net.IP
+		forceUpdatePeriod time.Duration
+		refresh           bool
+	}{
+		"provider without refresh": {
+			record: librecords.Record{Settings: ns1Settings, History: history(7 * 24 * time.Hour)},
+			ip:     ip,
+		},
+		"no history": {
+			record: librecords.Record{Settings: dyfiSettings},
+			ip:     ip,
+		},
+		"no IP address": {
+			record: librecords.Record{Settings: dyfiSettings, History: history(7 * 24 * time.Hour)},
+		},
+		"within refresh period": {
+			record: librecords.Record{Settings: dyfiSettings, History: history(4 * 24 * time.Hour)},
+			ip:     ip,
+		},
+		"refresh period elapsed": {
+			record:  librecords.Record{Settings: dyfiSettings, History: history(5 * 24 * time.Hour)},
+			ip:      ip,
+			refresh: true,
+		},
+		"refreshed recently with same IP address": {
+			record: librecords.Record{Settings: dyfiSettings, History: history(5 * 24 * time.Hour),
+				LastUpdate: now.Add(-24 * time.Hour)},
+			ip: ip,
+		},
+		"force update period elapsed": {
+			record:            librecords.Record{Settings: ns1Settings, History: history(30 * 24 * time.Hour)},
+			ip:                ip,
+			forceUpdatePeriod: 25 * 24 * time.Hour,
+			refresh:           true,
+		},
+		"shorter refresh period of provider": {
+			record:            librecords.Record{Settings: dyfiSettings, History: history(5 * 24 * time.Hour)},
+			ip:                ip,
+			forceUpdatePeriod: 25 * 24 * time.Hour,
+			refresh:           true,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			refresh := shouldRefreshRecord(testCase.record, testCase.ip, testCase.forceUpdatePeriod, now)
+			assert.Equal(t, testCase.refresh, refresh)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
@@ -14,41 +15,68 @@ import (
 )
 
 type Runner struct {
-	period      time.Duration
-	db          Database
-	updater     UpdaterInterface
-	force       chan struct{}
-	forceResult chan []error
-	ipv6Mask    net.IPMask
-	cooldown    time.Duration
-	resolver    *net.Resolver
-	ipGetter    PublicIPFetcher
-	logger      logging.Logger
-	timeNow     func() time.Time
+	pipelines []*pipeline
+	db        Database
+	updater   UpdaterInterface
+	ipv6Mask  net.IPMask
+	// ipv6CompareMask is the mask applied to IPv6 addresses to compare
+	// them, such that only a change of the prefix triggers an update.
+	ipv6CompareMask net.IPMask
+	cooldown        time.Duration
+	// forceUpdatePeriod is the period after which a record is updated
+	// even if its IP address did not change, and is disabled if 0.
+	forceUpdatePeriod time.Duration
+	anomalies         *anomalyDetector
+	hysteresis        *hysteresis
+	resolver          *net.Resolver
+	lookup            LookupSettings
+	// workers is the maximum number of providers updated concurrently.
+	workers    uint
+	ipGetter   PublicIPFetcher
+	leader     Leader
+	summaries  *cycleSummaries
+	onboarding *onboarding
+	schedule   *schedule
+	logger     logging.Logger
+	timeNow    func() time.Time
 }
 
 func NewRunner(db Database, updater UpdaterInterface, ipGetter PublicIPFetcher,
-	period time.Duration, ipv6Mask net.IPMask, cooldown time.Duration,
+	periods Periods, ipv6Mask, ipv6CompareMask net.IPMask, cooldown, forceUpdatePeriod time.Duration,
+	anomalies AnomalySettings, hysteresis HysteresisSettings,
+	onboardingRampUp time.Duration, lookup LookupSettings, workers uint, leader Leader,
 	logger logging.Logger, timeNow func() time.Time) *Runner {
+	resolver := net.DefaultResolver
+	if lookup.Resolver != nil {
+		resolver = lookup.Resolver
+	}
 	return &Runner{
-		period:      period,
-		db:          db,
-		updater:     updater,
-		force:       make(chan struct{}),
-		forceResult: make(chan []error),
-		ipv6Mask:    ipv6Mask,
-		cooldown:    cooldown,
-		resolver:    net.DefaultResolver,
-		ipGetter:    ipGetter,
-		logger:      logger,
-		timeNow:     timeNow,
+		pipelines:         newPipelines(),
+		db:                db,
+		updater:           updater,
+		ipv6Mask:          ipv6Mask,
+		ipv6CompareMask:   ipv6CompareMask,
+		cooldown:          cooldown,
+		forceUpdatePeriod: forceUpdatePeriod,
+		anomalies:         newAnomalyDetector(anomalies),
+		hysteresis:        newHysteresis(hysteresis),
+		resolver:          resolver,
+		lookup:            lookup,
+		workers:           workers,
+		ipGetter:          newSharedIPFetcher(ipGetter),
+		leader:            leader,
+		summaries:         newCycleSummaries(),
+		onboarding:        newOnboarding(db.SelectAll(), onboardingRampUp, timeNow()),
+		schedule:          newSchedule(db.SelectAll(), periods, timeNow()),
+		logger:            logger,
+		timeNow:           timeNow,
 	}
 }
 
-func (r *Runner) lookupIPsResilient(ctx context.Context, hostname string, tries int) (
-	ipv4 net.IP, ipv6 net.IP, err error) {
+func (r *Runner) lookupIPsResilient(ctx context.Context, resolver *net.Resolver,
+	hostname string, tries int) (ipv4 net.IP, ipv6 net.IP, err error) {
 	for i := 0; i < tries; i++ {
-		ipv4, ipv6, err = r.lookupIPs(ctx, hostname)
+		ipv4, ipv6, err = lookupIPs(ctx, resolver, hostname)
 		if err == nil {
 			return ipv4, ipv6, nil
 		}
@@ -56,8 +84,9 @@ func (r *Runner) lookupIPsResilient(ctx context.Context, hostname string, tries
 	return nil, nil, err
 }
 
-func (r *Runner) lookupIPs(ctx context.Context, hostname string) (ipv4 net.IP, ipv6 net.IP, err error) {
-	ips, err := r.resolver.LookupIP(ctx, "ip", hostname)
+func lookupIPs(ctx context.Context, resolver *net.Resolver, hostname string) (
+	ipv4 net.IP, ipv6 net.IP, err error) {
+	ips, err := resolver.LookupIP(ctx, "ip", hostname)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -71,23 +100,6 @@ func (r *Runner) lookupIPs(ctx context.Context, hostname string) (ipv4 net.IP, i
 	return ipv4, ipv6, nil
 }
 
-func doIPVersion(records []librecords.Record) (doIP, doIPv4, doIPv6 bool) {
-	for _, record := range records {
-		switch record.Settings.IPVersion() {
-		case ipversion.IP4or6:
-			doIP = true
-		case ipversion.IP4:
-			doIPv4 = true
-		case ipversion.IP6:
-			doIPv6 = true
-		}
-		if doIP && doIPv4 && doIPv6 {
-			return true, true, true
-		}
-	}
-	return doIP, doIPv4, doIPv6
-}
-
 func (r *Runner) getNewIPs(ctx context.Context, doIP, doIPv4, doIPv6 bool, ipv6Mask net.IPMask) (
 	ip, ipv4, ipv6 net.IP, errors []error) {
 	var err error
@@ -116,11 +128,42 @@ func (r *Runner) getNewIPs(ctx context.Context, doIP, doIPv4, doIPv6 bool, ipv6M
 	return ip, ipv4, ipv6, errors
 }
 
+// dueRecordIDs returns the IDs of the records of the IP version given
+// to check, which are the records due according to their update period
+// or their onboarding, or all the records not held for their onboarding
+// if force is true. The next check of each of these records is scheduled.
+func (r *Runner) dueRecordIDs(records []librecords.Record, ipVersion ipversion.IPVersion,
+	now time.Time, force bool) (recordIDs map[uint]struct{}) {
+	recordIDs = make(map[uint]struct{})
+	for i, record := range records {
+		id := uint(i)
+		switch {
+		case record.Settings.IPVersion() != ipVersion:
+		case r.onboarding.isHeld(id, now):
+			r.logger.Debug("record " + record.Settings.BuildDomainName() +
+				" is waiting for its onboarding time, skipping update")
+			// the onboarding wakes up the pipeline once the record is due
+			r.schedule.checked(id, record, now)
+		case force || r.schedule.isDue(id, now) || r.onboarding.isPending(id):
+			recordIDs[id] = struct{}{}
+			r.schedule.checked(id, record, now)
+		}
+	}
+	return recordIDs
+}
+
 func (r *Runner) getRecordIDsToUpdate(ctx context.Context, records []librecords.Record,
-	ip, ipv4, ipv6 net.IP, now time.Time, ipv6Mask net.IPMask) (recordIDs map[uint]struct{}) {
+	dueIDs map[uint]struct{}, ip, ipv4, ipv6 net.IP, now time.Time,
+	ipv6Mask net.IPMask) (recordIDs map[uint]struct{}) {
 	recordIDs = make(map[uint]struct{})
 	for i, record := range records {
-		if shouldUpdate := r.shouldUpdateRecord(ctx, record, ip, ipv4, ipv6, now, ipv6Mask); shouldUpdate {
+		if _, due := dueIDs[uint(i)]; !due {
+			continue
+		}
+		transforms := record.Options.Transforms
+		shouldUpdate := r.shouldUpdateRecord(ctx, record, transforms.Apply(ip),
+			transforms.Apply(ipv4), transforms.Apply(ipv6), now, ipv6Mask)
+		if shouldUpdate {
 			id := uint(i)
 			recordIDs[id] = struct{}{}
 		}
@@ -130,28 +173,51 @@ func (r *Runner) getRecordIDsToUpdate(ctx context.Context, records []librecords.
 
 func (r *Runner) shouldUpdateRecord(ctx context.Context, record librecords.Record,
 	ip, ipv4, ipv6 net.IP, now time.Time, ipv6Mask net.IPMask) (update bool) {
-	isWithinBanPeriod := record.LastBan != nil && now.Sub(*record.LastBan) < time.Hour
-	isWithinCooldown := now.Sub(record.History.GetSuccessTime()) < r.cooldown
-	if isWithinBanPeriod || isWithinCooldown {
-		domain := record.Settings.BuildDomainName()
-		r.logger.Debug("record " + domain + " is within ban period or cooldown period, skipping update")
+	if r.isHeldBack(record, now) {
 		return false
 	}
 
 	hostname := record.Settings.BuildDomainName()
 	ipVersion := record.Settings.IPVersion()
-	if record.Settings.Proxied() {
+	if shouldRefreshRecord(record, getIPMatchingVersion(ip, ipv4, ipv6, ipVersion), r.forceUpdatePeriod, now) {
+		r.logger.Info("record " + hostname + " was not updated since " +
+			record.LastSuccessTime().String() + ", refreshing it")
+		return true
+	}
+	// The IP addresses of a proxied record or of the other members
+	// of its record set are resolved instead of its own IP address.
+	if record.Settings.Proxied() || isRRSetMember(record.Settings) {
 		lastIP := record.History.GetCurrentIP() // can be nil
 		return r.shouldUpdateRecordNoLookup(hostname, ipVersion, lastIP, ip, ipv4, ipv6)
 	}
-	return r.shouldUpdateRecordWithLookup(ctx, hostname, ipVersion, ip, ipv4, ipv6, ipv6Mask)
+	resolver := r.recordResolver(ctx, record)
+	return r.shouldUpdateRecordWithLookup(ctx, resolver, hostname, ipVersion, ip, ipv4, ipv6, ipv6Mask)
+}
+
+// isHeldBack returns true if the record cannot be updated yet, because
+// it is within its ban period, its cooldown period or its backoff period.
+func (r *Runner) isHeldBack(record librecords.Record, now time.Time) (held bool) {
+	isWithinBanPeriod := now.Before(record.CooldownUntil)
+	isWithinCooldown := now.Sub(record.LastSuccessTime()) < r.cooldown
+	if isWithinBanPeriod || isWithinCooldown {
+		domain := record.Settings.BuildDomainName()
+		r.logger.Debug("record " + domain + " is within ban period or cooldown period, skipping update")
+		return true
+	}
+	if now.Before(record.BackoffUntil) {
+		r.logger.Debug("record " + record.Settings.BuildDomainName() + " is backing off after " +
+			strconv.FormatUint(uint64(record.Failures), 10) + " failed updates until " +
+			record.BackoffUntil.Format(time.RFC3339) + ", skipping update")
+		return true
+	}
+	return false
 }
 
 func (r *Runner) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion.IPVersion,
 	lastIP, ip, ipv4, ipv6 net.IP) (update bool) {
 	switch ipVersion {
 	case ipversion.IP4or6:
-		if ip != nil && !ip.Equal(lastIP) {
+		if ip != nil && !r.sameIP(ip, lastIP) {
 			r.logger.Info("Last IP address stored for " + hostname +
 				" is " + lastIP.String() + " and your IP address is " + ip.String())
 			return true
@@ -159,7 +225,7 @@ func (r *Runner) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion
 		r.logger.Debug("Last IP address stored for " + hostname + " is " +
 			lastIP.String() + " and your IP address is " + ip.String() + ", skipping update")
 	case ipversion.IP4:
-		if ipv4 != nil && !ipv4.Equal(lastIP) {
+		if ipv4 != nil && !r.sameIP(ipv4, lastIP) {
 			r.logger.Info("Last IPv4 address stored for " + hostname +
 				" is " + lastIP.String() + " and your IPv4 address is " + ip.String())
 			return true
@@ -167,7 +233,7 @@ func (r *Runner) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion
 		r.logger.Debug("Last IPv4 address stored for " + hostname + " is " +
 			lastIP.String() + " and your IPv4 address is " + ip.String() + ", skipping update")
 	case ipversion.IP6:
-		if ipv6 != nil && !ipv6.Equal(lastIP) {
+		if ipv6 != nil && !r.sameIP(ipv6, lastIP) {
 			r.logger.Info("Last IPv6 address stored for " + hostname +
 				" is " + lastIP.String() + " and your IPv6 address is " + ip.String())
 			return true
@@ -178,10 +244,11 @@ func (r *Runner) shouldUpdateRecordNoLookup(hostname string, ipVersion ipversion
 	return false
 }
 
-func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, hostname string, ipVersion ipversion.IPVersion,
-	ip, ipv4, ipv6 net.IP, ipv6Mask net.IPMask) (update bool) {
+func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, resolver *net.Resolver,
+	hostname string, ipVersion ipversion.IPVersion, ip, ipv4, ipv6 net.IP,
+	ipv6Mask net.IPMask) (update bool) {
 	const tries = 5
-	recordIPv4, recordIPv6, err := r.lookupIPsResilient(ctx, hostname, tries)
+	recordIPv4, recordIPv6, err := r.lookupIPsResilient(ctx, resolver, hostname, tries)
 	if err != nil {
 		if err := ctx.Err(); err != nil {
 			r.logger.Warn("DNS resolution of " + hostname + ": " + err.Error())
@@ -201,7 +268,7 @@ func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, hostname stri
 		if ip.To4() == nil {
 			recordIP = recordIPv6
 		}
-		if ip != nil && !ip.Equal(recordIPv4) && !ip.Equal(recordIPv6) {
+		if ip != nil && !r.sameIP(ip, recordIPv4) && !r.sameIP(ip, recordIPv6) {
 			r.logger.Info("IP address of " + hostname + " is " + recordIP.String() +
 				" and your IP address is " + ip.String())
 			return true
@@ -209,7 +276,7 @@ func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, hostname stri
 		r.logger.Debug("IP address of " + hostname + " is " + recordIP.String() +
 			" and your IP address is " + ip.String() + ", skipping update")
 	case ipversion.IP4:
-		if ipv4 != nil && !ipv4.Equal(recordIPv4) {
+		if ipv4 != nil && !r.sameIP(ipv4, recordIPv4) {
 			r.logger.Info("IPv4 address of " + hostname + " is " + recordIPv4.String() +
 				" and your IPv4 address is " + ipv4.String())
 			return true
@@ -217,7 +284,7 @@ func (r *Runner) shouldUpdateRecordWithLookup(ctx context.Context, hostname stri
 		r.logger.Debug("IPv4 address of " + hostname + " is " + recordIPv4.String() +
 			" and your IPv4 address is " + ipv4.String() + ", skipping update")
 	case ipversion.IP6:
-		if ipv6 != nil && !ipv6.Equal(recordIPv6) {
+		if ipv6 != nil && !r.sameIP(ipv6, recordIPv6) {
 			r.logger.Info("IPv6 address of " + hostname + " is " + recordIPv6.String() +
 				" and your IPv6 address is " + ipv6.String())
 			return true
@@ -256,67 +323,75 @@ func setInitialUpToDateStatus(db Database, id uint, updateIP net.IP, now time.Ti
 	return db.Update(id, record)
 }
 
-func (r *Runner) updateNecessary(ctx context.Context, ipv6Mask net.IPMask) (errors []error) {
+// updateNecessary fetches the public IP address of the IP version given
+// and updates the records configured with this IP version if needed.
+// Only the records due are checked, unless force is true.
+func (r *Runner) updateNecessary(ctx context.Context, ipVersion ipversion.IPVersion,
+	ipv6Mask net.IPMask, force, confirmAnomalies bool) (errors []error) {
 	records := r.db.SelectAll()
-	doIP, doIPv4, doIPv6 := doIPVersion(records)
+	dueIDs := r.dueRecordIDs(records, ipVersion, r.timeNow(), force)
+	if len(dueIDs) == 0 {
+		return nil
+	}
+	doIP := ipVersion == ipversion.IP4or6
+	doIPv4 := ipVersion == ipversion.IP4
+	doIPv6 := ipVersion == ipversion.IP6
+
+	if !r.leader.IsLeader() {
+		r.logger.Debug("instance is not the leader, skipping " + ipVersion.String() + " update")
+		return []error{fmt.Errorf("%w: standing by", ErrNotLeader)}
+	}
+
+	start := r.timeNow()
 	r.logger.Debug(fmt.Sprintf("configured to fetch IP: v4 or v6: %t, v4: %t, v6: %t", doIP, doIPv4, doIPv6))
 	ip, ipv4, ipv6, errors := r.getNewIPs(ctx, doIP, doIPv4, doIPv6, ipv6Mask)
+	detectedIPs := []net.IP{ip, ipv4, ipv6}
 	r.logger.Debug(fmt.Sprintf("your public IP address are: v4 or v6: %s, v4: %s, v6: %s", ip, ipv4, ipv6))
 	for _, err := range errors {
 		r.logger.Error(err.Error())
 	}
 
 	now := r.timeNow()
-	recordIDs := r.getRecordIDsToUpdate(ctx, records, ip, ipv4, ipv6, now, ipv6Mask)
+	ip, ipv4, ipv6, anomalyErrors := r.checkAnomalies(ip, ipv4, ipv6, now, confirmAnomalies)
+	errors = append(errors, anomalyErrors...)
+	ip, ipv4, ipv6 = r.confirmIPs(ip, ipv4, ipv6, now)
+	recordIDs := r.getRecordIDsToUpdate(ctx, records, dueIDs, ip, ipv4, ipv6, now, ipv6Mask)
+	wildcardGroups := makeWildcardGroups(records)
+	r.addWildcardSiblings(wildcardGroups, records, dueIDs, recordIDs, now)
 
 	for i, record := range records {
 		id := uint(i)
 		_, requireUpdate := recordIDs[id]
-		if requireUpdate || record.Status != constants.UNSET {
+		_, due := dueIDs[id]
+		if requireUpdate || !due || record.Status != constants.UNSET {
 			continue
 		}
 		updateIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Settings.IPVersion())
+		updateIP = record.Options.Transforms.Apply(updateIP)
 		if err := setInitialUpToDateStatus(r.db, id, updateIP, now); err != nil {
 			errors = append(errors, err)
 			r.logger.Error(err.Error())
 		}
 	}
-	for id := range recordIDs {
-		record := records[id]
-		updateIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Settings.IPVersion())
-		r.logger.Info("Updating record " + record.Settings.String() + " to use " + updateIP.String())
-		if err := r.updater.Update(ctx, id, updateIP, r.timeNow()); err != nil {
-			errors = append(errors, err)
-			r.logger.Error(err.Error())
+	recordsCount := 0
+	for _, record := range records {
+		if record.Settings.IPVersion() == ipVersion {
+			recordsCount++
 		}
 	}
-
-	return errors
-}
-
-func (r *Runner) Run(ctx context.Context, done chan<- struct{}) {
-	defer close(done)
-	ticker := time.NewTicker(r.period)
-	for {
-		select {
-		case <-ticker.C:
-			r.updateNecessary(ctx, r.ipv6Mask)
-		case <-r.force:
-			r.forceResult <- r.updateNecessary(ctx, r.ipv6Mask)
-		case <-ctx.Done():
-			ticker.Stop()
-			return
+	updateErrors := r.updateRecords(ctx, records, recordIDs, ip, ipv4, ipv6)
+	for i := range records {
+		if err, failed := updateErrors[uint(i)]; failed {
+			errors = append(errors, err)
 		}
 	}
-}
 
-func (r *Runner) ForceUpdate(ctx context.Context) (errs []error) {
-	r.force <- struct{}{}
+	errors = append(errors, r.logWildcardGroupsStatus(wildcardGroups, recordIDs, updateErrors)...)
 
-	select {
-	case errs = <-r.forceResult:
-	case <-ctx.Done():
-		errs = []error{ctx.Err()}
-	}
-	return errs
+	summary := newCycleSummary(ipVersion, start, r.timeNow(), recordsCount,
+		recordIDs, updateErrors, detectedIPs...)
+	r.summaries.set(summary)
+	r.logger.Info(summary.String())
+
+	return errors
 }
@@ -0,0 +1,99 @@
+package update
+
+import (
+	"sync"
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// scheduleMinWait is the minimum wait between two update cycles
+// triggered by the schedule, such that the public IP address is not
+// fetched too often when records are due at close times.
+const scheduleMinWait = time.Second
+
+// schedule keeps the time at which each record is next due to be
+// checked, such that each record is checked at its own update period
+// if it has one, at the times of the cron schedule if it is set, and
+// at the period of its IP version otherwise.
+type schedule struct {
+	periods Periods
+	// next maps the ID of a record to the time it is next due.
+	next  map[uint]time.Time
+	mutex sync.Mutex
+}
+
+func newSchedule(records []librecords.Record, periods Periods, now time.Time) *schedule {
+	s := &schedule{
+		periods: periods,
+		next:    make(map[uint]time.Time, len(records)),
+	}
+	for i, record := range records {
+		s.next[uint(i)] = s.nextTime(record, now)
+	}
+	return s
+}
+
+// nextTime returns the time the record is next due to be checked,
+// after it was checked at the time given.
+func (s *schedule) nextTime(record librecords.Record, now time.Time) time.Time {
+	if record.Options.Period > 0 {
+		return now.Add(record.Options.Period)
+	} else if s.periods.Cron != nil {
+		return s.periods.Cron.Next(now)
+	}
+	switch record.Settings.IPVersion() {
+	case ipversion.IP4:
+		return now.Add(s.periods.IPv4)
+	case ipversion.IP6:
+		return now.Add(s.periods.IPv6)
+	default:
+		return now.Add(s.periods.IP)
+	}
+}
+
+// isDue returns true if the record is due to be checked.
+func (s *schedule) isDue(id uint, now time.Time) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	next, ok := s.next[id]
+	return !ok || !now.Before(next)
+}
+
+// checked schedules the next check of the record.
+func (s *schedule) checked(id uint, record librecords.Record, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.next[id] = s.nextTime(record, now)
+}
+
+// wakeup returns a channel receiving when the next record of the IP
+// version given is due to be checked, or nil if there is no such record.
+func (s *schedule) wakeup(records []librecords.Record, ipVersion ipversion.IPVersion,
+	now time.Time) <-chan time.Time {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var next time.Time
+	for i, record := range records {
+		if record.Settings.IPVersion() != ipVersion {
+			continue
+		}
+		recordNext, ok := s.next[uint(i)]
+		if !ok {
+			recordNext = now
+		}
+		if next.IsZero() || recordNext.Before(next) {
+			next = recordNext
+		}
+	}
+	if next.IsZero() {
+		return nil
+	}
+
+	wait := next.Sub(now)
+	if wait < scheduleMinWait {
+		wait = scheduleMinWait
+	}
+	return time.After(wait)
+}
@@ -0,0 +1,73 @@
+package update
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/cron"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_schedule(t *testing.T) {
+	t.Parallel()
+
+	ipv4Settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "vpn", ipversion.IP4)
+	require.NoError(t, err)
+	ipv6Settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "blog", ipversion.IP6)
+	require.NoError(t, err)
+
+	records := []librecords.Record{
+		{Settings: ipv4Settings, Options: librecords.Options{Period: time.Minute}},
+		{Settings: ipv4Settings},
+		{Settings: ipv6Settings, Options: librecords.Options{Period: time.Hour}},
+	}
+	periods := Periods{IP: 5 * time.Minute, IPv4: 10 * time.Minute, IPv6: 10 * time.Minute}
+	start := time.Unix(0, 0)
+	s := newSchedule(records, periods, start)
+
+	assert.False(t, s.isDue(0, start))
+	assert.True(t, s.isDue(0, start.Add(time.Minute)))
+	assert.False(t, s.isDue(1, start.Add(time.Minute)))
+	assert.True(t, s.isDue(1, start.Add(10*time.Minute)))
+	assert.False(t, s.isDue(2, start.Add(10*time.Minute)))
+	assert.True(t, s.isDue(2, start.Add(time.Hour)))
+	// records added after the schedule creation are due right away
+	assert.True(t, s.isDue(3, start))
+
+	s.checked(0, records[0], start.Add(time.Minute))
+	assert.False(t, s.isDue(0, start.Add(time.Minute)))
+	assert.True(t, s.isDue(0, start.Add(2*time.Minute)))
+
+	assert.Nil(t, s.wakeup(records, ipversion.IP4or6, start))
+	assert.NotNil(t, s.wakeup(records, ipversion.IP4, start))
+	assert.NotNil(t, s.wakeup(records, ipversion.IP6, start))
+}
+
+func Test_schedule_cron(t *testing.T) {
+	t.Parallel()
+
+	settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	records := []librecords.Record{
+		{Settings: settings},
+		{Settings: settings, Options: librecords.Options{Period: time.Minute}},
+	}
+	everyHourAtHalf, err := cron.Parse("30 * * * *")
+	require.NoError(t, err)
+	periods := Periods{IP: time.Minute, IPv4: time.Minute, IPv6: time.Minute, Cron: everyHourAtHalf}
+	start := time.Date(2022, time.March, 1, 10, 0, 0, 0, time.UTC)
+	s := newSchedule(records, periods, start)
+
+	assert.False(t, s.isDue(0, start.Add(29*time.Minute)))
+	assert.True(t, s.isDue(0, start.Add(30*time.Minute)))
+	// records with their own period are not affected by the cron schedule
+	assert.True(t, s.isDue(1, start.Add(time.Minute)))
+}
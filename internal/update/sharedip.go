@@ -0,0 +1,81 @@
+package update
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// sharedIPFetcher collapses concurrent fetches of the public IP address
+// of the same IP version into a single in-flight fetch, whose result
+// is shared with all the callers waiting for it.
+type sharedIPFetcher struct {
+	fetcher PublicIPFetcher
+	calls   map[ipversion.IPVersion]*ipFetchCall
+	mutex   sync.Mutex
+}
+
+type ipFetchCall struct {
+	done chan struct{}
+	ip   net.IP
+	err  error
+}
+
+func newSharedIPFetcher(fetcher PublicIPFetcher) *sharedIPFetcher {
+	return &sharedIPFetcher{
+		fetcher: fetcher,
+		calls:   make(map[ipversion.IPVersion]*ipFetchCall),
+	}
+}
+
+func (s *sharedIPFetcher) IP(ctx context.Context) (ip net.IP, err error) {
+	return s.fetch(ctx, ipversion.IP4or6, s.fetcher.IP)
+}
+
+func (s *sharedIPFetcher) IP4(ctx context.Context) (ip net.IP, err error) {
+	return s.fetch(ctx, ipversion.IP4, s.fetcher.IP4)
+}
+
+func (s *sharedIPFetcher) IP6(ctx context.Context) (ip net.IP, err error) {
+	return s.fetch(ctx, ipversion.IP6, s.fetcher.IP6)
+}
+
+// fetch runs the fetch function given if no fetch is in flight for
+// the IP version given, and otherwise waits for the in-flight fetch.
+// Note the in-flight fetch uses the context of the caller starting it.
+func (s *sharedIPFetcher) fetch(ctx context.Context, version ipversion.IPVersion,
+	fetch getIPFunc) (ip net.IP, err error) {
+	s.mutex.Lock()
+	call, inFlight := s.calls[version]
+	if inFlight {
+		s.mutex.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-call.done:
+			return copyIP(call.ip), call.err
+		}
+	}
+
+	call = &ipFetchCall{done: make(chan struct{})}
+	s.calls[version] = call
+	s.mutex.Unlock()
+
+	call.ip, call.err = fetch(ctx)
+
+	s.mutex.Lock()
+	delete(s.calls, version)
+	s.mutex.Unlock()
+	close(call.done)
+
+	return copyIP(call.ip), call.err
+}
+
+func copyIP(ip net.IP) net.IP {
+	if ip == nil {
+		return nil
+	}
+	return append(net.IP(nil), ip...)
+}
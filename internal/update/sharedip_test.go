@@ -0,0 +1,64 @@
+package update
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingFetcher struct {
+	calls   int32
+	release chan struct{}
+}
+
+func (f *blockingFetcher) fetch(context.Context) (net.IP, error) {
+	atomic.AddInt32(&f.calls, 1)
+	<-f.release
+	return net.IPv4(1, 2, 3, 4), nil
+}
+
+func (f *blockingFetcher) IP(ctx context.Context) (net.IP, error)  { return f.fetch(ctx) }
+func (f *blockingFetcher) IP4(ctx context.Context) (net.IP, error) { return f.fetch(ctx) }
+func (f *blockingFetcher) IP6(ctx context.Context) (net.IP, error) { return f.fetch(ctx) }
+
+func Test_sharedIPFetcher(t *testing.T) {
+	t.Parallel()
+
+	fetcher := &blockingFetcher{release: make(chan struct{})}
+	shared := newSharedIPFetcher(fetcher)
+
+	const callers = 10
+	ips := make([]net.IP, callers)
+	var ready, done sync.WaitGroup
+	ready.Add(callers)
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			ready.Done()
+			ips[i], _ = shared.IP4(context.Background())
+		}(i)
+	}
+	ready.Wait()
+	// Let all callers reach the in-flight fetch.
+	const settle = 20 * time.Millisecond
+	time.Sleep(settle)
+
+	// Fetches of other IP versions are not collapsed.
+	go func() { _, _ = shared.IP6(context.Background()) }()
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&fetcher.calls) == 2
+	}, time.Second, time.Millisecond)
+	close(fetcher.release)
+	done.Wait()
+
+	for _, ip := range ips {
+		assert.Equal(t, net.IPv4(1, 2, 3, 4), ip)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetcher.calls))
+}
@@ -0,0 +1,71 @@
+package update
+
+import (
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+// RecordState is the runtime state of a record
+// kept by the updater, for example for metrics.
+type RecordState struct {
+	// Successes is the number of successful updates since launch.
+	Successes uint
+	// Failures is the number of failed updates since launch.
+	Failures uint
+	// Banned is true if the record or its account is banned
+	// by the provider and is not updated for now.
+	Banned bool
+	// CircuitOpen is true if the account of the record failed too
+	// many consecutive times and its records are not updated for now.
+	CircuitOpen bool
+}
+
+type updateCounters struct {
+	// counts maps a record ID to its successes and failures counts.
+	counts map[uint]*[2]uint
+	mutex  sync.Mutex
+}
+
+func newUpdateCounters() *updateCounters {
+	return &updateCounters{
+		counts: make(map[uint]*[2]uint),
+	}
+}
+
+func (c *updateCounters) report(id uint, updateErr error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counts, ok := c.counts[id]
+	if !ok {
+		counts = new([2]uint)
+		c.counts[id] = counts
+	}
+	if updateErr == nil {
+		counts[0]++
+	} else {
+		counts[1]++
+	}
+}
+
+func (c *updateCounters) get(id uint) (successes, failures uint) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	counts, ok := c.counts[id]
+	if !ok {
+		return 0, 0
+	}
+	return counts[0], counts[1]
+}
+
+// RecordState returns the runtime state of the record given.
+func (u *Updater) RecordState(id uint, record records.Record,
+	now time.Time) (state RecordState) {
+	state.Successes, state.Failures = u.counters.get(id)
+	state.Banned, state.CircuitOpen = u.accounts.state(record, now)
+	if now.Before(record.CooldownUntil) {
+		state.Banned = true
+	}
+	return state
+}
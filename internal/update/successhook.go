@@ -0,0 +1,142 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+)
+
+const (
+	// successHookTries is the number of delivery attempts of a webhook.
+	successHookTries = 4
+	// successHookBackoff is the wait duration before the first retry,
+	// doubled on each subsequent retry.
+	successHookBackoff = 2 * time.Second
+	// SignatureHeader is the header containing the HMAC-SHA256 of
+	// the webhook payload, in the form sha256=<hex encoded hash>.
+	SignatureHeader = "X-DDNS-Updater-Signature"
+)
+
+// successHookPayload is the JSON payload sent to the
+// webhooks of a record when it is updated successfully.
+type successHookPayload struct {
+	Domain     string    `json:"domain"`
+	Host       string    `json:"host"`
+	Provider   string    `json:"provider"`
+	IPVersion  string    `json:"ip_version"`
+	IP         string    `json:"ip"`
+	PreviousIP string    `json:"previous_ip,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// sendSuccessHooks delivers the new IP address of the record to its
+// webhooks in the background, such that slow or failing consumers
+// do not delay the updates of other records.
+func (u *Updater) sendSuccessHooks(record records.Record, previousIP, newIP net.IP,
+	now time.Time) {
+	if len(record.Options.Webhooks) == 0 {
+		return
+	}
+
+	payload := successHookPayload{
+		Domain:    record.Settings.Domain(),
+		Host:      record.Settings.Host(),
+		Provider:  string(record.Settings.Provider()),
+		IPVersion: record.Settings.IPVersion().String(),
+		IP:        newIP.String(),
+		Time:      now,
+	}
+	if previousIP != nil {
+		payload.PreviousIP = previousIP.String()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		u.logger.Error("encoding webhook payload: " + err.Error())
+		return
+	}
+
+	for _, webhook := range record.Options.Webhooks {
+		go func(webhook records.Webhook) {
+			err := deliverSuccessHook(context.Background(), u.client,
+				webhook, body, successHookBackoff)
+			if err != nil {
+				u.logger.Warn("webhook for " + record.Settings.BuildDomainName() +
+					": " + err.Error())
+			}
+		}(webhook)
+	}
+}
+
+var (
+	ErrWebhookStatus   = errors.New("webhook responded with a bad HTTP status")
+	ErrWebhookAttempts = errors.New("webhook delivery failed")
+)
+
+// deliverSuccessHook posts the body to the webhook, retrying with an
+// exponential backoff on network errors, server errors and rate limiting.
+func deliverSuccessHook(ctx context.Context, client *http.Client,
+	webhook records.Webhook, body []byte, backoff time.Duration) (err error) {
+	var signature string
+	if webhook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(webhook.Secret))
+		_, _ = mac.Write(body)
+		signature = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	for try := 1; ; try++ {
+		var retry bool
+		retry, err = postSuccessHook(ctx, client, webhook.URL, body, signature)
+		if err == nil {
+			return nil
+		} else if !retry || try == successHookTries {
+			return fmt.Errorf("%w: after %d tries: %s", ErrWebhookAttempts, try, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func postSuccessHook(ctx context.Context, client *http.Client, url string,
+	body []byte, signature string) (retry bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		request.Header.Set(SignatureHeader, signature)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return true, err
+	}
+	_ = response.Body.Close()
+
+	switch {
+	case response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices:
+		return false, nil
+	case response.StatusCode == http.StatusTooManyRequests,
+		response.StatusCode >= http.StatusInternalServerError:
+		return true, fmt.Errorf("%w: %d", ErrWebhookStatus, response.StatusCode)
+	default:
+		return false, fmt.Errorf("%w: %d", ErrWebhookStatus, response.StatusCode)
+	}
+}
@@ -0,0 +1,75 @@
+package update
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_deliverSuccessHook(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"ip":"1.2.3.4"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write(body)
+	expectedSignature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	testCases := map[string]struct {
+		statuses []int
+		tries    int
+		err      error
+	}{
+		"success": {
+			statuses: []int{http.StatusNoContent},
+			tries:    1,
+		},
+		"retried server error": {
+			statuses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK},
+			tries:    3,
+		},
+		"client error not retried": {
+			statuses: []int{http.StatusNotFound},
+			tries:    1,
+			err:      ErrWebhookAttempts,
+		},
+		"all tries failing": {
+			statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError,
+				http.StatusInternalServerError, http.StatusInternalServerError},
+			tries: successHookTries,
+			err:   ErrWebhookAttempts,
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tries := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedBody, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, body, receivedBody)
+				assert.Equal(t, expectedSignature, r.Header.Get(SignatureHeader))
+				w.WriteHeader(testCase.statuses[tries])
+				tries++
+			}))
+			defer server.Close()
+
+			webhook := records.Webhook{URL: server.URL, Secret: "secret"}
+			err := deliverSuccessHook(context.Background(), server.Client(), webhook, body, 0)
+
+			require.ErrorIs(t, err, testCase.err)
+			assert.Equal(t, testCase.tries, tries)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package update
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// CycleSummary summarizes the last update cycle of an IP version.
+type CycleSummary struct {
+	IPVersion ipversion.IPVersion
+	// Start is the time the cycle started at.
+	Start time.Time
+	// Duration is the total duration of the cycle.
+	Duration time.Duration
+	// Updated is the number of records updated successfully.
+	Updated uint
+	// Unchanged is the number of records which did not need an update.
+	Unchanged uint
+	// Failed is the number of records which failed to be updated.
+	Failed uint
+	// IPs are the public IP addresses detected during the cycle.
+	IPs []net.IP
+}
+
+func (s CycleSummary) String() string {
+	ips := make([]string, len(s.IPs))
+	for i, ip := range s.IPs {
+		ips[i] = ip.String()
+	}
+	ipsString := "no public IP address detected"
+	if len(ips) > 0 {
+		ipsString = "public IP " + strings.Join(ips, ", ")
+	}
+	return fmt.Sprintf("%s cycle: %d updated, %d unchanged, %d failed in %s, %s",
+		s.IPVersion, s.Updated, s.Unchanged, s.Failed,
+		s.Duration.Round(time.Millisecond), ipsString)
+}
+
+// newCycleSummary creates the summary of a cycle given the number of
+// records of the IP version, the IDs of the records which required an
+// update and the update errors by record ID.
+func newCycleSummary(ipVersion ipversion.IPVersion, start, end time.Time,
+	recordsCount int, recordIDs map[uint]struct{}, updateErrors map[uint]error,
+	detectedIPs ...net.IP) (summary CycleSummary) {
+	summary = CycleSummary{
+		IPVersion: ipVersion,
+		Start:     start,
+		Duration:  end.Sub(start),
+		Failed:    uint(len(updateErrors)),
+		Updated:   uint(len(recordIDs) - len(updateErrors)),
+	}
+	if recordsCount > len(recordIDs) {
+		summary.Unchanged = uint(recordsCount - len(recordIDs))
+	}
+	for _, ip := range detectedIPs {
+		if ip != nil {
+			summary.IPs = append(summary.IPs, ip)
+		}
+	}
+	return summary
+}
+
+type cycleSummaries struct {
+	ipVersionToSummary map[ipversion.IPVersion]CycleSummary
+	mutex              sync.RWMutex
+}
+
+func newCycleSummaries() *cycleSummaries {
+	return &cycleSummaries{
+		ipVersionToSummary: make(map[ipversion.IPVersion]CycleSummary),
+	}
+}
+
+func (c *cycleSummaries) set(summary CycleSummary) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.ipVersionToSummary[summary.IPVersion] = summary
+}
+
+func (c *cycleSummaries) get() (summaries []CycleSummary) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	summaries = make([]CycleSummary, 0, len(c.ipVersionToSummary))
+	for _, summary := range c.ipVersionToSummary {
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].IPVersion < summaries[j].IPVersion
+	})
+	return summaries
+}
+
+// CycleSummaries returns the summary of the last update
+// cycle of each IP version, ordered by IP version.
+func (r *Runner) CycleSummaries() (summaries []CycleSummary) {
+	return r.summaries.get()
+}
@@ -0,0 +1,85 @@
+package update
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newCycleSummary(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1000, 0)
+	errTest := errors.New("test error")
+
+	testCases := map[string]struct {
+		recordsCount int
+		recordIDs    map[uint]struct{}
+		updateErrors map[uint]error
+		detectedIPs  []net.IP
+		summary      CycleSummary
+		text         string
+	}{
+		"no record to update": {
+			recordsCount: 3,
+			detectedIPs:  []net.IP{nil, net.IPv4(1, 2, 3, 4), nil},
+			summary: CycleSummary{
+				IPVersion: ipversion.IP4,
+				Start:     start,
+				Duration:  time.Second,
+				Unchanged: 3,
+				IPs:       []net.IP{net.IPv4(1, 2, 3, 4)},
+			},
+			text: "ipv4 cycle: 0 updated, 3 unchanged, 0 failed in 1s, public IP 1.2.3.4",
+		},
+		"updated and failed records": {
+			recordsCount: 4,
+			recordIDs:    map[uint]struct{}{0: {}, 2: {}, 3: {}},
+			updateErrors: map[uint]error{3: errTest},
+			summary: CycleSummary{
+				IPVersion: ipversion.IP4,
+				Start:     start,
+				Duration:  time.Second,
+				Updated:   2,
+				Unchanged: 1,
+				Failed:    1,
+			},
+			text: "ipv4 cycle: 2 updated, 1 unchanged, 1 failed in 1s, no public IP address detected",
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			summary := newCycleSummary(ipversion.IP4, start, start.Add(time.Second),
+				testCase.recordsCount, testCase.recordIDs, testCase.updateErrors,
+				testCase.detectedIPs...)
+
+			assert.Equal(t, testCase.summary, summary)
+			assert.Equal(t, testCase.text, summary.String())
+		})
+	}
+}
+
+func Test_cycleSummaries(t *testing.T) {
+	t.Parallel()
+
+	summaries := newCycleSummaries()
+	assert.Empty(t, summaries.get())
+
+	summaries.set(CycleSummary{IPVersion: ipversion.IP6, Updated: 1})
+	summaries.set(CycleSummary{IPVersion: ipversion.IP4, Updated: 1})
+	summaries.set(CycleSummary{IPVersion: ipversion.IP4, Updated: 2})
+
+	expected := []CycleSummary{
+		{IPVersion: ipversion.IP4, Updated: 2},
+		{IPVersion: ipversion.IP6, Updated: 1},
+	}
+	assert.Equal(t, expected, summaries.get())
+}
@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/qdm12/ddns-updater/internal/constants"
 	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings"
 	settingserrors "github.com/qdm12/ddns-updater/internal/settings/errors"
+	"github.com/qdm12/ddns-updater/internal/usage"
 	"github.com/qdm12/golibs/logging"
 )
 
@@ -18,45 +23,157 @@ type Updater struct {
 	db     Database
 	client *http.Client
 	notify notifyFunc
-	logger logging.Logger
+	// tenantNotify maps a tenant name to the function
+	// notifying the tenant notification targets.
+	tenantNotify map[string]func(message string)
+	template     *template.Template
+	accounts     *accounts
+	fallbacks    *fallbacks
+	counters     *updateCounters
+	usage        UsageTracker
+	rateLimiter  RateLimiter
+	backoff      BackoffSettings
+	// dryRun is true to log the provider calls instead of sending them.
+	dryRun bool
+	// propagation verifies the propagation of the IP address
+	// after each successful update, and is nil if disabled.
+	propagation PropagationChecker
+	// propagationChecks tracks the propagation checks running,
+	// which are canceled with propagationCancel once Run stops.
+	propagationChecks sync.WaitGroup
+	propagationMutex  sync.Mutex
+	propagationCtx    context.Context //nolint:containedctx
+	propagationCancel context.CancelFunc
+	leader            Leader
+	logger            logging.Logger
 }
 
 type notifyFunc func(message string)
 
-func NewUpdater(db Database, client *http.Client, notify notifyFunc, logger logging.Logger) *Updater {
+// NewUpdater creates an updater. The notification template can be nil,
+// in which case default notification messages are used.
+// Notifications of records of a tenant are sent with the notify function
+// and with the function of their tenant in tenantNotify, if any.
+// The propagation checker can be nil to not verify the propagation
+// of IP addresses after updates.
+func NewUpdater(db Database, client *http.Client, notify notifyFunc,
+	tenantNotify map[string]func(message string), notificationTemplate *template.Template, usageTracker UsageTracker,
+	rateLimiter RateLimiter, backoff BackoffSettings, dryRun bool, propagation PropagationChecker, leader Leader, logger logging.Logger) *Updater {
 	client = makeLogClient(client, logger)
+	propagationCtx, propagationCancel := context.WithCancel(context.Background())
 	return &Updater{
-		db:     db,
-		client: client,
-		notify: notify,
-		logger: logger,
+		db:                db,
+		client:            client,
+		notify:            notify,
+		tenantNotify:      tenantNotify,
+		template:          notificationTemplate,
+		accounts:          newAccounts(),
+		fallbacks:         newFallbacks(),
+		counters:          newUpdateCounters(),
+		usage:             usageTracker,
+		rateLimiter:       rateLimiter,
+		backoff:           backoff,
+		dryRun:            dryRun,
+		propagation:       propagation,
+		propagationCtx:    propagationCtx,
+		propagationCancel: propagationCancel,
+		leader:            leader,
+		logger:            logger,
 	}
 }
 
+// updateProvider updates the record with its provider. If the record is
+// a member of the record set of its host, only the IP address previously
+// published by the program is replaced in the record set.
+func updateProvider(ctx context.Context, client *http.Client,
+	record librecords.Record, ip net.IP) (newIP net.IP, err error) {
+	if isRRSetMember(record.Settings) {
+		member := record.Settings.(settings.RRSetMember)
+		return member.UpdateMember(ctx, client, record.History.GetCurrentIP(), ip)
+	}
+	return record.Settings.Update(ctx, client, ip)
+}
+
+func isRRSetMember(recordSettings settings.Settings) bool {
+	member, ok := recordSettings.(settings.RRSetMember)
+	return ok && member.IsRRSetMember()
+}
+
+var ErrNotLeader = errors.New("instance is not the leader")
+
 func (u *Updater) Update(ctx context.Context, id uint, ip net.IP, now time.Time) (err error) {
+	if !u.leader.IsLeader() {
+		return fmt.Errorf("%w: standing by", ErrNotLeader)
+	}
+	if u.dryRun {
+		record, err := u.db.Select(id)
+		if err != nil {
+			return err
+		}
+		u.dryRunUpdate(ctx, record, ip)
+		return nil
+	}
+	defer func() {
+		u.counters.report(id, err)
+	}()
+
 	record, err := u.db.Select(id)
 	if err != nil {
 		return err
 	}
 	record.Time = now
 	record.Status = constants.UPDATING
+	record.Confirmed = false
 	if err := u.db.Update(id, record); err != nil {
 		return err
 	}
 	record.Status = constants.FAIL
-	newIP, err := record.Settings.Update(ctx, u.client, ip)
+	err = checkPublishable(ip, record.Options.AllowULA)
+	if err == nil {
+		err = u.accounts.check(record, now)
+	}
+	if err != nil {
+		record.Message = err.Error()
+		record.ErrorCode = ErrorCode(err)
+		if updateErr := u.db.Update(id, record); updateErr != nil {
+			return fmt.Errorf("%w (with database update error: %s)", err, updateErr)
+		}
+		return err
+	}
+
+	client := u.usageClient(string(record.Settings.Provider()), record.Options.Account)
+	err = u.checkCNAME(ctx, client, record)
 	if err != nil {
 		record.Message = err.Error()
+		record.ErrorCode = ErrorCode(err)
+		if updateErr := u.db.Update(id, record); updateErr != nil {
+			return fmt.Errorf("%w (with database update error: %s)", err, updateErr)
+		}
+		return err
+	}
+	retryAfter := newRetryAfterRecorder(client.Transport, now)
+	client.Transport = retryAfter
+	newIP, err := updateProvider(ctx, client, record, ip)
+	if !errors.Is(err, usage.ErrDailyBudgetExceeded) {
+		u.accounts.report(record, err, now)
+	}
+	u.updateFallback(ctx, id, record, ip, err)
+	u.setBackoff(&record, err, now)
+	if err != nil {
+		record.Message = err.Error()
+		record.ErrorCode = ErrorCode(err)
 		if errors.Is(err, settingserrors.ErrAbuse) {
-			lastBan := time.Unix(now.Unix(), 0)
-			record.LastBan = &lastBan
+			cooldown := cooldownDuration(record.Settings.Provider(), retryAfter.duration())
+			record.CooldownUntil = time.Unix(now.Add(cooldown).Unix(), 0)
+			record.Status = constants.COOLDOWN
 			domainName := record.Settings.BuildDomainName()
 			message := domainName + ": " + record.Message +
-				", no more updates will be attempted for an hour"
-			u.notify(message)
-			err = fmt.Errorf("%w: for domain %s, no more update will be attempted for 1h", err, domainName)
+				", no more updates will be attempted for " + cooldown.String()
+			u.notifyRecord(record, newNotificationData(record, ip, err), message)
+			err = fmt.Errorf("%w: for domain %s, no more update will be attempted until %s",
+				err, domainName, record.CooldownUntil.Format(time.RFC3339))
 		} else {
-			record.LastBan = nil // clear a previous ban
+			record.CooldownUntil = time.Time{} // clear a previous cooldown
 		}
 		if updateErr := u.db.Update(id, record); updateErr != nil {
 			return fmt.Errorf("%w (with database update error: %s)", err, updateErr)
@@ -64,11 +181,43 @@ func (u *Updater) Update(ctx context.Context, id uint, ip net.IP, now time.Time)
 		return err
 	}
 	record.Status = constants.SUCCESS
+	record.ErrorCode = ""
 	record.Message = fmt.Sprintf("changed to %s", ip.String())
-	record.History = append(record.History, models.HistoryEvent{
-		IP:   newIP,
-		Time: now,
-	})
-	u.notify(record.Settings.BuildDomainName() + " " + record.Message)
-	return u.db.Update(id, record) // persists some data if needed (i.e new IP)
+	record.LastUpdate = now
+	previousIP := record.History.GetCurrentIP()
+	ipChanged := !newIP.Equal(previousIP)
+	if !ipChanged { // keepalive update
+		record.Message = fmt.Sprintf("refreshed with %s", ip.String())
+	}
+	notificationData := newNotificationData(record, newIP, nil)
+	u.sendSuccessHooks(record, previousIP, newIP, now)
+	u.updatePTR(ctx, record, newIP)
+	if ipChanged {
+		record.History = append(record.History, models.HistoryEvent{
+			IP:   newIP,
+			Time: now,
+		})
+	}
+	u.notifyRecord(record, notificationData, record.Settings.BuildDomainName()+" "+record.Message)
+	verifyPropagation := u.propagation != nil && !record.Settings.Proxied()
+	if verifyPropagation {
+		record.Status = constants.NOTPROPAGATED
+	}
+	err = u.db.Update(id, record) // persists some data if needed (i.e new IP)
+	if err == nil && verifyPropagation {
+		u.startPropagationCheck(id, record, newIP)
+	}
+	return err
+}
+
+// usageClient returns an HTTP client counting its requests
+// against the API usage of the provider account given, and
+// waiting for the rate limit of the provider account.
+func (u *Updater) usageClient(provider, account string) *http.Client {
+	key := usage.Key(provider, account)
+	transport := u.usage.RoundTripper(u.client.Transport, key)
+	return &http.Client{
+		Timeout:   u.client.Timeout,
+		Transport: u.rateLimiter.RoundTripper(transport, provider, account),
+	}
 }
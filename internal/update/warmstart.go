@@ -0,0 +1,92 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	"github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/qdm12/golibs/logging"
+)
+
+var ErrWarmStartNoIP = errors.New("no IP address found in DNS")
+
+const (
+	warmStartParallelism   = 16
+	warmStartLookupTimeout = 5 * time.Second
+)
+
+// WarmStart seeds the current IP address of records without history
+// from their DNS answer, such that records already pointing to the
+// public IP address are not all updated on the first run.
+// Records failing to resolve are left untouched and are updated as usual.
+func WarmStart(ctx context.Context, allRecords []records.Record,
+	logger logging.Logger, timeNow func() time.Time) {
+	now := timeNow()
+	var seeded uint32
+	semaphore := make(chan struct{}, warmStartParallelism)
+	wg := &sync.WaitGroup{}
+	for i := range allRecords {
+		if len(allRecords[i].History) > 0 {
+			continue
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(record *records.Record) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			ip, err := lookupWarmStartIP(ctx, *record)
+			if err != nil {
+				logger.Debug("warm start: " + record.Settings.String() + ": " + err.Error())
+				return
+			}
+			record.History = models.History{{IP: ip, Time: now}}
+			atomic.AddUint32(&seeded, 1)
+		}(&allRecords[i])
+	}
+	wg.Wait()
+
+	if seeded > 0 {
+		logger.Info("warm start: seeded the current IP address of " +
+			strconv.Itoa(int(seeded)) + " records from DNS")
+	}
+}
+
+func lookupWarmStartIP(ctx context.Context, record records.Record) (ip net.IP, err error) {
+	resolver := record.Options.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, warmStartLookupTimeout)
+	defer cancel()
+	ipv4, ipv6, err := lookupIPs(ctx, resolver, record.Settings.BuildDomainName())
+	if err != nil {
+		return nil, err
+	}
+
+	switch record.Settings.IPVersion() {
+	case ipversion.IP4:
+		ip = ipv4
+	case ipversion.IP6:
+		ip = ipv6
+	default:
+		ip = ipv4
+		if ip == nil {
+			ip = ipv6
+		}
+	}
+	if ip == nil {
+		return nil, fmt.Errorf("%w: for IP version %s", ErrWarmStartNoIP, record.Settings.IPVersion())
+	}
+	return ip, nil
+}
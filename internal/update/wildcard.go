@@ -0,0 +1,131 @@
+package update
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+)
+
+// wildcardGroup is a group of records sharing the same provider,
+// domain and IP version, where one of the records has the wildcard
+// host "*" and the others are its explicit siblings.
+type wildcardGroup struct {
+	domain    string
+	recordIDs []uint
+}
+
+func makeWildcardGroups(records []librecords.Record) (groups []wildcardGroup) {
+	type groupKey struct {
+		provider  models.Provider
+		domain    string
+		ipVersion ipversion.IPVersion
+	}
+	keyToRecordIDs := make(map[groupKey][]uint)
+	keyHasWildcard := make(map[groupKey]bool)
+	var keys []groupKey // to keep the groups ordered
+	for i, record := range records {
+		key := groupKey{
+			provider:  record.Settings.Provider(),
+			domain:    record.Settings.Domain(),
+			ipVersion: record.Settings.IPVersion(),
+		}
+		if _, ok := keyToRecordIDs[key]; !ok {
+			keys = append(keys, key)
+		}
+		keyToRecordIDs[key] = append(keyToRecordIDs[key], uint(i))
+		if record.Settings.Host() == "*" {
+			keyHasWildcard[key] = true
+		}
+	}
+
+	for _, key := range keys {
+		recordIDs := keyToRecordIDs[key]
+		if !keyHasWildcard[key] || len(recordIDs) == 1 {
+			continue
+		}
+		groups = append(groups, wildcardGroup{
+			domain:    key.domain,
+			recordIDs: recordIDs,
+		})
+	}
+	return groups
+}
+
+// addWildcardSiblings adds to the record IDs to update all the
+// records of the wildcard groups having at least one record to update,
+// such that the wildcard and its siblings do not drift apart.
+// A sibling is only added if it is due, which excludes records held
+// for their onboarding, and if it is not held back by its ban,
+// cooldown or backoff period.
+func (r *Runner) addWildcardSiblings(groups []wildcardGroup, records []librecords.Record,
+	dueIDs, recordIDs map[uint]struct{}, now time.Time) {
+	for _, group := range groups {
+		groupNeedsUpdate := false
+		for _, id := range group.recordIDs {
+			if _, ok := recordIDs[id]; ok {
+				groupNeedsUpdate = true
+				break
+			}
+		}
+		if !groupNeedsUpdate {
+			continue
+		}
+
+		for _, id := range group.recordIDs {
+			if _, ok := recordIDs[id]; ok {
+				continue
+			}
+			if _, due := dueIDs[id]; !due {
+				continue
+			}
+			record := records[id]
+			if r.isHeldBack(record, now) {
+				continue
+			}
+			r.logger.Debug("record " + record.Settings.BuildDomainName() +
+				" is updated with its wildcard group for domain " + group.domain)
+			recordIDs[id] = struct{}{}
+		}
+	}
+}
+
+var ErrWildcardGroupPartialUpdate = errors.New("wildcard group partially updated")
+
+// logWildcardGroupsStatus logs the combined status of each wildcard group
+// updated, and returns an error for each group partially updated.
+func (r *Runner) logWildcardGroupsStatus(groups []wildcardGroup,
+	recordIDs map[uint]struct{}, updateErrors map[uint]error) (errs []error) {
+	for _, group := range groups {
+		updated, failed := 0, 0
+		for _, id := range group.recordIDs {
+			if _, ok := recordIDs[id]; !ok {
+				continue
+			}
+			if updateErrors[id] != nil {
+				failed++
+			} else {
+				updated++
+			}
+		}
+
+		if updated+failed == 0 {
+			continue
+		}
+
+		summary := "wildcard group for domain " + group.domain + ": " +
+			strconv.Itoa(updated) + " updated, " + strconv.Itoa(failed) + " failed"
+		if failed == 0 {
+			r.logger.Info(summary)
+			continue
+		}
+		err := fmt.Errorf("%w: %s", ErrWildcardGroupPartialUpdate, summary)
+		r.logger.Warn(err.Error())
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -0,0 +1,200 @@
+package update
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/gandi"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newNS1Record(t *testing.T, domain, host string,
+	ipVersion ipversion.IPVersion) librecords.Record {
+	t.Helper()
+	settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		domain, host, ipVersion)
+	require.NoError(t, err)
+	return librecords.New(settings, librecords.Options{}, nil)
+}
+
+func newGandiRecord(t *testing.T, domain, host string) librecords.Record {
+	t.Helper()
+	settings, err := gandi.New(json.RawMessage(`{"key": "key"}`),
+		domain, host, ipversion.IP4)
+	require.NoError(t, err)
+	return librecords.New(settings, librecords.Options{}, nil)
+}
+
+func Test_makeWildcardGroups(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		records func(t *testing.T) []librecords.Record
+		groups  []wildcardGroup
+	}{
+		"no record": {
+			records: func(t *testing.T) []librecords.Record { return nil },
+		},
+		"no wildcard": {
+			records: func(t *testing.T) []librecords.Record {
+				return []librecords.Record{
+					newNS1Record(t, "example.com", "@", ipversion.IP4),
+					newNS1Record(t, "example.com", "www", ipversion.IP4),
+				}
+			},
+		},
+		"wildcard alone": {
+			records: func(t *testing.T) []librecords.Record {
+				return []librecords.Record{
+					newNS1Record(t, "example.com", "*", ipversion.IP4),
+				}
+			},
+		},
+		"wildcard with siblings": {
+			records: func(t *testing.T) []librecords.Record {
+				return []librecords.Record{
+					newNS1Record(t, "example.com", "@", ipversion.IP4),
+					newNS1Record(t, "example.com", "*", ipversion.IP4),
+					newNS1Record(t, "example.com", "www", ipversion.IP4),
+				}
+			},
+			groups: []wildcardGroup{
+				{domain: "example.com", recordIDs: []uint{0, 1, 2}},
+			},
+		},
+		"siblings of other domain, provider or IP version": {
+			records: func(t *testing.T) []librecords.Record {
+				return []librecords.Record{
+					newNS1Record(t, "example.com", "*", ipversion.IP4),
+					newNS1Record(t, "example.org", "@", ipversion.IP4),
+					newGandiRecord(t, "example.com", "@"),
+					newNS1Record(t, "example.com", "@", ipversion.IP6),
+					newNS1Record(t, "example.com", "www", ipversion.IP4),
+				}
+			},
+			groups: []wildcardGroup{
+				{domain: "example.com", recordIDs: []uint{0, 4}},
+			},
+		},
+		"multiple groups": {
+			records: func(t *testing.T) []librecords.Record {
+				return []librecords.Record{
+					newNS1Record(t, "example.org", "*", ipversion.IP4),
+					newNS1Record(t, "example.com", "*", ipversion.IP4),
+					newNS1Record(t, "example.com", "@", ipversion.IP4),
+					newNS1Record(t, "example.org", "@", ipversion.IP4),
+				}
+			},
+			groups: []wildcardGroup{
+				{domain: "example.org", recordIDs: []uint{0, 3}},
+				{domain: "example.com", recordIDs: []uint{1, 2}},
+			},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			groups := makeWildcardGroups(testCase.records(t))
+
+			assert.Equal(t, testCase.groups, groups)
+		})
+	}
+}
+
+func Test_Runner_addWildcardSiblings(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(10000, 0)
+	const cooldown = time.Hour
+
+	testCases := map[string]struct {
+		modify    func(sibling *librecords.Record)
+		due       bool
+		recordIDs map[uint]struct{}
+		expected  map[uint]struct{}
+	}{
+		"no record to update": {
+			due:       true,
+			recordIDs: map[uint]struct{}{},
+			expected:  map[uint]struct{}{},
+		},
+		"sibling added": {
+			due:       true,
+			recordIDs: map[uint]struct{}{0: {}},
+			expected:  map[uint]struct{}{0: {}, 1: {}},
+		},
+		"sibling not due": {
+			recordIDs: map[uint]struct{}{0: {}},
+			expected:  map[uint]struct{}{0: {}},
+		},
+		"sibling within ban period": {
+			modify: func(sibling *librecords.Record) {
+				sibling.CooldownUntil = now.Add(time.Minute)
+			},
+			due:       true,
+			recordIDs: map[uint]struct{}{0: {}},
+			expected:  map[uint]struct{}{0: {}},
+		},
+		"sibling within cooldown period": {
+			modify: func(sibling *librecords.Record) {
+				sibling.LastUpdate = now.Add(-time.Minute)
+			},
+			due:       true,
+			recordIDs: map[uint]struct{}{0: {}},
+			expected:  map[uint]struct{}{0: {}},
+		},
+		"sibling after cooldown period": {
+			modify: func(sibling *librecords.Record) {
+				sibling.LastUpdate = now.Add(-2 * cooldown)
+			},
+			due:       true,
+			recordIDs: map[uint]struct{}{0: {}},
+			expected:  map[uint]struct{}{0: {}, 1: {}},
+		},
+		"sibling backing off": {
+			modify: func(sibling *librecords.Record) {
+				sibling.Failures = 2
+				sibling.BackoffUntil = now.Add(time.Minute)
+			},
+			due:       true,
+			recordIDs: map[uint]struct{}{0: {}},
+			expected:  map[uint]struct{}{0: {}},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			records := []librecords.Record{
+				newNS1Record(t, "example.com", "*", ipversion.IP4),
+				newNS1Record(t, "example.com", "www", ipversion.IP4),
+			}
+			if testCase.modify != nil {
+				testCase.modify(&records[1])
+			}
+			dueIDs := map[uint]struct{}{0: {}}
+			if testCase.due {
+				dueIDs[1] = struct{}{}
+			}
+			runner := &Runner{
+				cooldown: cooldown,
+				logger:   noopLogger{},
+			}
+			groups := makeWildcardGroups(records)
+
+			runner.addWildcardSiblings(groups, records, dueIDs, testCase.recordIDs, now)
+
+			assert.Equal(t, testCase.expected, testCase.recordIDs)
+		})
+	}
+}
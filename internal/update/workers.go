@@ -0,0 +1,86 @@
+package update
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+
+	"github.com/qdm12/ddns-updater/internal/models"
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+)
+
+// updateRecords updates the records given concurrently, with at most
+// r.workers providers updated at the same time. Records of the same
+// provider are updated one after the other, in the order of their IDs,
+// such that a slow provider only delays its own records and records
+// of the same host are updated in the configuration order.
+func (r *Runner) updateRecords(ctx context.Context, records []librecords.Record,
+	recordIDs map[uint]struct{}, ip, ipv4, ipv6 net.IP) (updateErrors map[uint]error) {
+	queues := makeProviderQueues(records, recordIDs)
+
+	workers := r.workers
+	if workers == 0 {
+		workers = 1
+	}
+	semaphore := make(chan struct{}, workers)
+	updateErrors = make(map[uint]error, len(recordIDs))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for _, queue := range queues {
+		wg.Add(1)
+		go func(queue []uint) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			for _, id := range queue {
+				err := r.updateRecord(ctx, id, records[id], ip, ipv4, ipv6)
+				if err != nil {
+					mutex.Lock()
+					updateErrors[id] = err
+					mutex.Unlock()
+				}
+			}
+		}(queue)
+	}
+	wg.Wait()
+	return updateErrors
+}
+
+func (r *Runner) updateRecord(ctx context.Context, id uint, record librecords.Record,
+	ip, ipv4, ipv6 net.IP) (err error) {
+	updateIP := getIPMatchingVersion(ip, ipv4, ipv6, record.Settings.IPVersion())
+	updateIP = record.Options.Transforms.Apply(updateIP)
+	r.logger.Info("Updating record " + record.Settings.String() + " to use " + updateIP.String())
+	err = r.updater.Update(ctx, id, updateIP, r.timeNow())
+	r.onboarding.done(id, err, r.timeNow())
+	if err != nil {
+		r.logger.Error(err.Error())
+	}
+	return err
+}
+
+// makeProviderQueues groups the record IDs given by provider,
+// each queue being sorted by record ID, and the queues being
+// sorted by their first record ID.
+func makeProviderQueues(records []librecords.Record,
+	recordIDs map[uint]struct{}) (queues [][]uint) {
+	ids := make([]uint, 0, len(recordIDs))
+	for id := range recordIDs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	providerToIndex := make(map[models.Provider]int)
+	for _, id := range ids {
+		provider := records[id].Settings.Provider()
+		index, ok := providerToIndex[provider]
+		if !ok {
+			index = len(queues)
+			providerToIndex[provider] = index
+			queues = append(queues, nil)
+		}
+		queues[index] = append(queues[index], id)
+	}
+	return queues
+}
@@ -0,0 +1,38 @@
+package update
+
+import (
+	"encoding/json"
+	"testing"
+
+	librecords "github.com/qdm12/ddns-updater/internal/records"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/domeneshop"
+	"github.com/qdm12/ddns-updater/internal/settings/providers/ns1"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_makeProviderQueues(t *testing.T) {
+	t.Parallel()
+
+	ns1Settings, err := ns1.New(json.RawMessage(`{"api_key": "key"}`),
+		"example.com", "@", ipversion.IP4)
+	require.NoError(t, err)
+	domeneshopSettings, err := domeneshop.New(json.RawMessage(`{"token": "token", "secret": "secret"}`),
+		"example.com", "home", ipversion.IP4)
+	require.NoError(t, err)
+
+	records := []librecords.Record{
+		{Settings: domeneshopSettings},
+		{Settings: ns1Settings},
+		{Settings: domeneshopSettings},
+		{Settings: ns1Settings},
+		{Settings: ns1Settings},
+	}
+	recordIDs := map[uint]struct{}{4: {}, 3: {}, 1: {}, 0: {}}
+
+	queues := makeProviderQueues(records, recordIDs)
+
+	expected := [][]uint{{0}, {1, 3, 4}}
+	assert.Equal(t, expected, queues)
+}
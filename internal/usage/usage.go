@@ -0,0 +1,179 @@
+// Package usage counts the API requests made to each provider
+// account per day, and optionally enforces a daily budget.
+package usage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qdm12/golibs/files"
+)
+
+var ErrDailyBudgetExceeded = errors.New("daily API requests budget exceeded")
+
+const (
+	dayFormat = "2006-01-02"
+	// daysKept is the number of days of usage kept in the file.
+	daysKept = 7
+)
+
+// Tracker counts API requests per key and per UTC day,
+// persisting the counts to a JSON file.
+type Tracker struct {
+	// days maps a day formatted as dayFormat to a map of
+	// key to number of requests made that day.
+	days        map[string]map[string]uint
+	budget      uint
+	filepath    string
+	fileManager files.FileManager
+	logger      Warner
+	timeNow     func() time.Time
+	mutex       sync.Mutex
+}
+
+type Warner interface {
+	Warn(s string)
+}
+
+// New creates a usage tracker persisting its data to usage.json
+// in the data directory given. The daily budget is the maximum
+// number of API requests allowed per key and per day, and is
+// disabled if set to 0.
+func New(dataDir string, dailyBudget uint, logger Warner,
+	timeNow func() time.Time) (tracker *Tracker, err error) {
+	tracker = &Tracker{
+		days:        make(map[string]map[string]uint),
+		budget:      dailyBudget,
+		filepath:    filepath.Join(dataDir, "usage.json"),
+		fileManager: files.NewFileManager(),
+		logger:      logger,
+		timeNow:     timeNow,
+	}
+
+	exists, err := tracker.fileManager.FileExists(tracker.filepath)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return tracker, nil
+	}
+
+	data, err := tracker.fileManager.ReadFile(tracker.filepath)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &tracker.days)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", tracker.filepath, err)
+	}
+	return tracker, nil
+}
+
+// Key returns the usage key for a provider and an optional account.
+func Key(provider, account string) (key string) {
+	if account == "" {
+		return provider
+	}
+	return provider + "/" + account
+}
+
+// Today returns the number of API requests made today for each key.
+func (t *Tracker) Today() (counts map[string]uint) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	today := t.days[t.timeNow().UTC().Format(dayFormat)]
+	counts = make(map[string]uint, len(today))
+	for key, count := range today {
+		counts[key] = count
+	}
+	return counts
+}
+
+// Budget returns the daily budget of API requests per key,
+// which is 0 if disabled.
+func (t *Tracker) Budget() uint {
+	return t.budget
+}
+
+// count increments the number of API requests made today for
+// the key given, and returns an error if the budget is exceeded,
+// in which case the count is not incremented.
+func (t *Tracker) count(key string) (err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	day := t.timeNow().UTC().Format(dayFormat)
+	counts, ok := t.days[day]
+	if !ok {
+		counts = make(map[string]uint)
+		t.days[day] = counts
+		t.removeOldDays()
+	}
+
+	if t.budget > 0 && counts[key] >= t.budget {
+		return fmt.Errorf("%w: %d requests made today for %s",
+			ErrDailyBudgetExceeded, counts[key], key)
+	}
+	counts[key]++
+
+	err = t.write()
+	if err != nil {
+		// Failing to persist the usage should not prevent the update
+		// since the count is still tracked in memory.
+		t.logger.Warn("cannot persist API usage: " + err.Error())
+	}
+	return nil
+}
+
+func (t *Tracker) removeOldDays() {
+	days := make([]string, 0, len(t.days))
+	for day := range t.days {
+		days = append(days, day)
+	}
+	if len(days) <= daysKept {
+		return
+	}
+	sort.Strings(days)
+	for _, day := range days[:len(days)-daysKept] {
+		delete(t.days, day)
+	}
+}
+
+func (t *Tracker) write() (err error) {
+	data, err := json.MarshalIndent(t.days, "", "  ")
+	if err != nil {
+		return err
+	}
+	return t.fileManager.WriteToFile(t.filepath, data)
+}
+
+// RoundTripper returns an HTTP round tripper counting each
+// request sent for the key given, and refusing to send
+// requests once the daily budget is exceeded.
+func (t *Tracker) RoundTripper(proxied http.RoundTripper,
+	key string) http.RoundTripper {
+	return &roundTripper{
+		proxied: proxied,
+		tracker: t,
+		key:     key,
+	}
+}
+
+type roundTripper struct {
+	proxied http.RoundTripper
+	tracker *Tracker
+	key     string
+}
+
+func (r *roundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	err := r.tracker.count(r.key)
+	if err != nil {
+		return nil, err
+	}
+	return r.proxied.RoundTrip(request)
+}
@@ -0,0 +1,60 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopWarner struct{}
+
+func (noopWarner) Warn(string) {}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func Test_Tracker(t *testing.T) {
+	t.Parallel()
+
+	dataDir := t.TempDir()
+	now := time.Date(2022, 1, 1, 23, 0, 0, 0, time.UTC)
+	timeNow := func() time.Time { return now }
+
+	const budget = 2
+	tracker, err := New(dataDir, budget, noopWarner{}, timeNow)
+	require.NoError(t, err)
+
+	proxied := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	roundTripper := tracker.RoundTripper(proxied, Key("cloudflare", "personal"))
+	request, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < budget; i++ {
+		_, err = roundTripper.RoundTrip(request)
+		require.NoError(t, err)
+	}
+	_, err = roundTripper.RoundTrip(request)
+	assert.True(t, errors.Is(err, ErrDailyBudgetExceeded))
+	assert.Equal(t, map[string]uint{"cloudflare/personal": budget}, tracker.Today())
+
+	// Usage is persisted
+	tracker, err = New(dataDir, budget, noopWarner{}, timeNow)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint{"cloudflare/personal": budget}, tracker.Today())
+
+	// Budget resets the next day
+	now = now.Add(2 * time.Hour)
+	roundTripper = tracker.RoundTripper(proxied, Key("cloudflare", "personal"))
+	_, err = roundTripper.RoundTrip(request)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]uint{"cloudflare/personal": 1}, tracker.Today())
+}
@@ -0,0 +1,213 @@
+// Package plugin defines the gRPC protocol used by ddns-updater to talk
+// to out-of-tree DNS provider plugins, as described in plugin.proto.
+// Plugin authors writing Go can implement the Server interface and call
+// Serve to listen on a unix socket in the plugins directory.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ServiceName is the fully qualified name of the gRPC service.
+const ServiceName = "ddnsupdater.plugin.v1.Provider"
+
+// Description is the response to the Describe call.
+type Description struct {
+	Name string
+}
+
+// UpdateRequest is the request of the Update call.
+type UpdateRequest struct {
+	Domain     string
+	Host       string
+	FQDN       string
+	IP         string
+	RecordType string
+	Settings   map[string]interface{}
+}
+
+// UpdateResponse is the response to the Update call.
+type UpdateResponse struct {
+	// IP is the IP address set by the provider, and can be left empty.
+	IP string
+}
+
+// Server is implemented by plugins.
+type Server interface {
+	Describe(ctx context.Context) (description Description, err error)
+	Update(ctx context.Context, request UpdateRequest) (response UpdateResponse, err error)
+}
+
+var ErrMissingName = errors.New("plugin name is missing")
+
+// Serve listens on the unix socket at socketPath and serves
+// the plugin server until the context is canceled.
+// Any stale socket file at socketPath is removed first.
+func Serve(ctx context.Context, socketPath string, server Server) (err error) {
+	err = os.Remove(socketPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return err
+	}
+
+	grpcServer := grpc.NewServer()
+	Register(grpcServer, server)
+
+	serveErr := make(chan error)
+	go func() {
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case <-ctx.Done():
+		grpcServer.GracefulStop()
+		<-serveErr
+		return nil
+	case err := <-serveErr:
+		return err
+	}
+}
+
+// Register registers the plugin server on the gRPC server.
+func Register(grpcServer *grpc.Server, server Server) {
+	grpcServer.RegisterService(&serviceDesc, &serverAdapter{server: server})
+}
+
+type serverAdapter struct {
+	server Server
+}
+
+func (s *serverAdapter) describe(ctx context.Context, _ *structpb.Struct) (*structpb.Struct, error) {
+	description, err := s.server.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"name": description.Name,
+	})
+}
+
+func (s *serverAdapter) update(ctx context.Context, request *structpb.Struct) (*structpb.Struct, error) {
+	response, err := s.server.Update(ctx, decodeUpdateRequest(request))
+	if err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"ip": response.IP,
+	})
+}
+
+// Client calls a plugin over an established gRPC connection.
+type Client struct {
+	connection grpc.ClientConnInterface
+}
+
+func NewClient(connection grpc.ClientConnInterface) *Client {
+	return &Client{connection: connection}
+}
+
+func (c *Client) Describe(ctx context.Context) (description Description, err error) {
+	response := new(structpb.Struct)
+	err = c.connection.Invoke(ctx, "/"+ServiceName+"/Describe", new(structpb.Struct), response)
+	if err != nil {
+		return description, err
+	}
+	description.Name = response.GetFields()["name"].GetStringValue()
+	if description.Name == "" {
+		return description, ErrMissingName
+	}
+	return description, nil
+}
+
+func (c *Client) Update(ctx context.Context, request UpdateRequest) (response UpdateResponse, err error) {
+	requestStruct, err := encodeUpdateRequest(request)
+	if err != nil {
+		return response, fmt.Errorf("encoding request: %w", err)
+	}
+	responseStruct := new(structpb.Struct)
+	err = c.connection.Invoke(ctx, "/"+ServiceName+"/Update", requestStruct, responseStruct)
+	if err != nil {
+		return response, err
+	}
+	response.IP = responseStruct.GetFields()["ip"].GetStringValue()
+	return response, nil
+}
+
+func encodeUpdateRequest(request UpdateRequest) (*structpb.Struct, error) {
+	settings := request.Settings
+	if settings == nil {
+		settings = map[string]interface{}{}
+	}
+	return structpb.NewStruct(map[string]interface{}{
+		"domain":      request.Domain,
+		"host":        request.Host,
+		"fqdn":        request.FQDN,
+		"ip":          request.IP,
+		"record_type": request.RecordType,
+		"settings":    settings,
+	})
+}
+
+func decodeUpdateRequest(request *structpb.Struct) UpdateRequest {
+	fields := request.GetFields()
+	return UpdateRequest{
+		Domain:     fields["domain"].GetStringValue(),
+		Host:       fields["host"].GetStringValue(),
+		FQDN:       fields["fqdn"].GetStringValue(),
+		IP:         fields["ip"].GetStringValue(),
+		RecordType: fields["record_type"].GetStringValue(),
+		Settings:   fields["settings"].GetStructValue().AsMap(),
+	}
+}
+
+// serviceDesc is written by hand instead of being generated by protoc,
+// since the messages are all google.protobuf.Struct.
+var serviceDesc = grpc.ServiceDesc{ //nolint:gochecknoglobals
+	ServiceName: ServiceName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Describe", Handler: makeHandler("Describe", (*serverAdapter).describe)},
+		{MethodName: "Update", Handler: makeHandler("Update", (*serverAdapter).update)},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+type adapterMethod func(s *serverAdapter, ctx context.Context,
+	request *structpb.Struct) (*structpb.Struct, error)
+
+type methodHandler = func(srv interface{}, ctx context.Context, decode func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor) (interface{}, error)
+
+func makeHandler(name string, method adapterMethod) methodHandler {
+	return func(srv interface{}, ctx context.Context, decode func(interface{}) error,
+		interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		request := new(structpb.Struct)
+		if err := decode(request); err != nil {
+			return nil, err
+		}
+		adapter := srv.(*serverAdapter) //nolint:forcetypeassert
+		if interceptor == nil {
+			return method(adapter, ctx, request)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: "/" + ServiceName + "/" + name,
+		}
+		handler := func(ctx context.Context, request interface{}) (interface{}, error) {
+			return method(adapter, ctx, request.(*structpb.Struct)) //nolint:forcetypeassert
+		}
+		return interceptor(ctx, request, info, handler)
+	}
+}
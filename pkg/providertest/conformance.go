@@ -0,0 +1,221 @@
+package providertest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Updater is the interface a provider must implement to be tested.
+type Updater interface {
+	Update(ctx context.Context, client *http.Client, ip net.IP) (newIP net.IP, err error)
+}
+
+// Behaviors contains the provider specific responses
+// used to build the common conformance scenarios.
+type Behaviors struct {
+	// Success returns the responses of a successful update
+	// for which the registrar reports the IP address string given.
+	// The string given can be malformed or different from the
+	// IP address sent to test the provider handles these cases.
+	Success func(reportedIP string) []Response
+	// ReportsIP indicates the registrar reports the IP address
+	// in its response, and enables the malformed and mismatched
+	// IP address scenarios.
+	ReportsIP bool
+	// IP is the IP address to update the record with,
+	// and defaults to 203.0.113.1 if left unset.
+	IP net.IP
+	// Scenarios are provider specific scenarios run
+	// after the common conformance scenarios.
+	Scenarios map[string]Scenario
+}
+
+// Scenario is a provider specific update scenario, with the
+// responses scripted for the registrar and the requests expected.
+type Scenario struct {
+	// Updater is the provider to update, and defaults to
+	// the provider created by the newUpdater function given.
+	Updater Updater
+	// IP is the IP address to update the record with,
+	// and defaults to the IP address of the behaviors.
+	IP net.IP
+	// Responses are the responses of the registrar, in order.
+	Responses []Response
+	// Requests are the requests expected, each formatted as its method
+	// followed by its request URI, for example "GET /zones?name=home".
+	// They are not checked if left nil.
+	Requests []string
+	// ErrWrapped is the error the update error should wrap,
+	// and the update should succeed if it is nil.
+	ErrWrapped error
+	// ErrMessage, if set, is the error message expected.
+	ErrMessage string
+	// Check, if set, is called to check further the requests
+	// received by the registrar, for example their bodies.
+	Check func(t *testing.T, requests []Request)
+}
+
+// Run runs the common conformance scenarios against the provider
+// created by newUpdater, each scenario as a subtest:
+//   - a successful update
+//   - a bad HTTP status
+//   - a malformed IP address reported by the registrar
+//   - a mismatching IP address reported by the registrar
+//   - rate limiting with the HTTP status 429
+//   - a timeout of the registrar
+//
+// followed by the provider specific scenarios of the behaviors.
+func Run(t *testing.T, newUpdater func(t *testing.T) Updater, behaviors Behaviors) {
+	t.Helper()
+
+	ip := behaviors.IP
+	if ip == nil {
+		ip = net.IPv4(203, 0, 113, 1)
+	}
+	mismatchingIP := net.IPv4(203, 0, 113, 2)
+	if ip.To4() == nil {
+		mismatchingIP = net.ParseIP("2001:db8::2")
+	}
+
+	t.Run("success", func(t *testing.T) {
+		registrar := NewRegistrar()
+		defer registrar.Close()
+		registrar.Script(behaviors.Success(ip.String())...)
+
+		newIP, err := newUpdater(t).Update(context.Background(), registrar.Client(), ip)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !newIP.Equal(ip) {
+			t.Errorf("expected IP address %s but got %s", ip, newIP)
+		}
+	})
+
+	t.Run("bad status", func(t *testing.T) {
+		registrar := NewRegistrar()
+		defer registrar.Close()
+		registrar.Script(Response{
+			Status: http.StatusInternalServerError,
+			Body:   "internal server error",
+		})
+
+		assertFails(t, newUpdater(t), registrar, ip, time.Second)
+	})
+
+	if behaviors.ReportsIP {
+		t.Run("malformed IP", func(t *testing.T) {
+			registrar := NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(behaviors.Success("300.1.2.3.4")...)
+
+			assertFails(t, newUpdater(t), registrar, ip, time.Second)
+		})
+
+		t.Run("mismatching IP", func(t *testing.T) {
+			registrar := NewRegistrar()
+			defer registrar.Close()
+			registrar.Script(behaviors.Success(mismatchingIP.String())...)
+
+			assertFails(t, newUpdater(t), registrar, ip, time.Second)
+		})
+	}
+
+	t.Run("rate limited", func(t *testing.T) {
+		registrar := NewRegistrar()
+		defer registrar.Close()
+		registrar.Script(Response{
+			Status: http.StatusTooManyRequests,
+			Header: http.Header{"Retry-After": []string{"60"}},
+			Body:   "too many requests",
+		})
+
+		assertFails(t, newUpdater(t), registrar, ip, time.Second)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		registrar := NewRegistrar()
+		defer registrar.Close()
+		const timeout = 50 * time.Millisecond
+		// Copy the responses to not modify the ones given.
+		responses := append([]Response(nil), behaviors.Success(ip.String())...)
+		for i := range responses {
+			responses[i].Delay = time.Minute
+		}
+		registrar.Script(responses...)
+
+		start := time.Now()
+		assertFails(t, newUpdater(t), registrar, ip, timeout)
+		const margin = time.Second
+		if elapsed := time.Since(start); elapsed > timeout+margin {
+			t.Errorf("update did not respect the context deadline, it took %s", elapsed)
+		}
+	})
+
+	for name, scenario := range behaviors.Scenarios {
+		scenario := scenario
+		t.Run(name, func(t *testing.T) {
+			updater := scenario.Updater
+			if updater == nil {
+				updater = newUpdater(t)
+			}
+			scenarioIP := scenario.IP
+			if scenarioIP == nil {
+				scenarioIP = ip
+			}
+			runScenario(t, updater, scenarioIP, scenario)
+		})
+	}
+}
+
+func runScenario(t *testing.T, updater Updater, ip net.IP, scenario Scenario) {
+	t.Helper()
+	registrar := NewRegistrar()
+	defer registrar.Close()
+	registrar.Script(scenario.Responses...)
+
+	newIP, err := updater.Update(context.Background(), registrar.Client(), ip)
+	switch {
+	case scenario.ErrWrapped != nil && !errors.Is(err, scenario.ErrWrapped):
+		t.Errorf("expected error wrapping %q but got: %v", scenario.ErrWrapped, err)
+	case scenario.ErrWrapped == nil && err != nil:
+		t.Errorf("unexpected error: %s", err)
+	case scenario.ErrWrapped == nil && !newIP.Equal(ip):
+		t.Errorf("expected IP address %s but got %s", ip, newIP)
+	case scenario.ErrMessage != "" && err.Error() != scenario.ErrMessage:
+		t.Errorf("expected error message %q but got %q", scenario.ErrMessage, err)
+	}
+
+	requests := registrar.Requests()
+	if scenario.Requests != nil {
+		lines := make([]string, len(requests))
+		for i, request := range requests {
+			lines[i] = request.Method + " " + request.URL.RequestURI()
+		}
+		if !reflect.DeepEqual(lines, scenario.Requests) {
+			t.Errorf("expected requests %q but got %q", scenario.Requests, lines)
+		}
+	}
+	if scenario.Check != nil {
+		scenario.Check(t, requests)
+	}
+}
+
+func assertFails(t *testing.T, updater Updater, registrar *Registrar,
+	ip net.IP, timeout time.Duration) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	newIP, err := updater.Update(ctx, registrar.Client(), ip)
+	if err == nil {
+		t.Errorf("expected an error but got none, with IP address %s returned", newIP)
+	}
+	if newIP != nil {
+		t.Errorf("expected no IP address returned but got %s", newIP)
+	}
+}
@@ -0,0 +1,155 @@
+// Package providertest provides a mock registrar HTTP server and
+// conformance scenarios to test DNS provider implementations.
+package providertest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Response is a response scripted for the mock registrar.
+type Response struct {
+	// Status is the HTTP status code, and defaults to 200.
+	Status int
+	// Header contains extra headers to set on the response.
+	Header http.Header
+	// Body is the body of the response.
+	Body string
+	// Delay is the time to wait before responding,
+	// unless the request context is canceled first.
+	Delay time.Duration
+}
+
+// Request is a request received by the mock registrar.
+type Request struct {
+	Method string
+	URL    *url.URL
+	Header http.Header
+	Body   string
+}
+
+// Registrar is a mock registrar HTTP server responding
+// with the responses scripted, in order. Once all the scripted
+// responses are consumed, the last one is repeated.
+type Registrar struct {
+	server    *httptest.Server
+	mutex     sync.Mutex
+	responses []Response
+	requests  []Request
+}
+
+// NewRegistrar creates and starts a mock registrar HTTP server.
+// It should be closed with Close once done.
+func NewRegistrar() *Registrar {
+	r := &Registrar{}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+// Close shuts down the mock registrar HTTP server.
+func (r *Registrar) Close() {
+	r.server.Close()
+}
+
+// Script sets the responses to reply with, in order, and
+// resets the requests recorded.
+func (r *Registrar) Script(responses ...Response) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.responses = responses
+	r.requests = nil
+}
+
+// Requests returns the requests received since the last Script call.
+func (r *Registrar) Requests() (requests []Request) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	requests = make([]Request, len(r.requests))
+	copy(requests, r.requests)
+	return requests
+}
+
+// Client returns an HTTP client sending all its requests to the
+// mock registrar, whatever their original scheme and host are,
+// such that providers with hardcoded API URLs can be tested.
+func (r *Registrar) Client() *http.Client {
+	serverURL, _ := url.Parse(r.server.URL) // cannot fail
+	return &http.Client{
+		Transport: &redirectRoundTripper{
+			scheme: serverURL.Scheme,
+			host:   serverURL.Host,
+			next:   r.server.Client().Transport,
+		},
+	}
+}
+
+func (r *Registrar) serveHTTP(w http.ResponseWriter, request *http.Request) {
+	body, _ := io.ReadAll(request.Body)
+	recorded := Request{
+		Method: request.Method,
+		URL:    request.URL,
+		Header: request.Header.Clone(),
+		Body:   string(body),
+	}
+	if originalHost := request.Header.Get(originalHostHeader); originalHost != "" {
+		recorded.URL.Host = originalHost
+		recorded.Header.Del(originalHostHeader)
+	}
+
+	r.mutex.Lock()
+	r.requests = append(r.requests, recorded)
+	var response Response
+	switch len(r.responses) {
+	case 0:
+		response = Response{Status: http.StatusNotImplemented}
+	case 1:
+		response = r.responses[0]
+	default:
+		response = r.responses[0]
+		r.responses = r.responses[1:]
+	}
+	r.mutex.Unlock()
+
+	if response.Delay > 0 {
+		timer := time.NewTimer(response.Delay)
+		select {
+		case <-timer.C:
+		case <-request.Context().Done():
+			timer.Stop()
+			return
+		}
+	}
+
+	for key, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	status := response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(response.Body))
+}
+
+const originalHostHeader = "X-Providertest-Original-Host"
+
+type redirectRoundTripper struct {
+	scheme string
+	host   string
+	next   http.RoundTripper
+}
+
+func (r *redirectRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	request = request.Clone(request.Context())
+	request.Header.Set(originalHostHeader, request.URL.Host)
+	request.URL.Scheme = r.scheme
+	request.URL.Host = r.host
+	request.Host = r.host
+	return r.next.RoundTrip(request)
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 
 	"github.com/miekg/dns"
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipaddr"
 )
 
 var (
@@ -59,7 +60,7 @@ func fetch(ctx context.Context, client Client, providerData providerData) (
 	}
 	ipString := txt.Txt[0]
 
-	publicIP = net.ParseIP(ipString)
+	publicIP = ipaddr.Parse(ipString)
 	if publicIP == nil {
 		return nil, fmt.Errorf("%w: %q", ErrIPMalformed, ipString)
 	}
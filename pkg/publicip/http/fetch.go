@@ -10,16 +10,22 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/qdm12/ddns-updater/pkg/publicip/ipaddr"
 	"github.com/qdm12/ddns-updater/pkg/publicip/ipversion"
 )
 
 var (
-	ErrNoIPFound   = errors.New("no IP address found")
-	ErrTooManyIPs  = errors.New("too many IP addresses")
-	ErrIPMalformed = errors.New("IP address malformed")
-	ErrBanned      = errors.New("we got banned")
+	ErrNoIPFound    = errors.New("no IP address found")
+	ErrTooManyIPs   = errors.New("too many IP addresses")
+	ErrIPMalformed  = errors.New("IP address malformed")
+	ErrBanned       = errors.New("we got banned")
+	ErrBodyTooLarge = errors.New("response body is too large")
 )
 
+// maxBodySize is the maximum size of a response body containing
+// a public IP address, which should only be a few bytes long.
+const maxBodySize = 16 * 1024
+
 var (
 	ipv4Regex = regexp.MustCompile(`(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\.){3}(25[0-5]|2[0-4][0-9]|1[0-9]{2}|[1-9][0-9]|[0-9])`)                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                                             //nolint:lll
 	ipv6Regex = regexp.MustCompile(`(([0-9a-fA-F]{1,4}:){7,7}[0-9a-fA-F]{1,4}|([0-9a-fA-F]{1,4}:){1,7}:|([0-9a-fA-F]{1,4}:){1,6}:[0-9a-fA-F]{1,4}|([0-9a-fA-F]{1,4}:){1,5}(:[0-9a-fA-F]{1,4}){1,2}|([0-9a-fA-F]{1,4}:){1,4}(:[0-9a-fA-F]{1,4}){1,3}|([0-9a-fA-F]{1,4}:){1,3}(:[0-9a-fA-F]{1,4}){1,4}|([0-9a-fA-F]{1,4}:){1,2}(:[0-9a-fA-F]{1,4}){1,5}|[0-9a-fA-F]{1,4}:((:[0-9a-fA-F]{1,4}){1,6})|:((:[0-9a-fA-F]{1,4}){1,7}|:)|fe80:(:[0-9a-fA-F]{0,4}){0,4}%[0-9a-zA-Z]{1,}|::(ffff(:0{1,4}){0,1}:){0,1}((25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9])\.){3,3}(25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9])|([0-9a-fA-F]{1,4}:){1,4}:((25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9])\.){3,3}(25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9]))`) //nolint:lll
@@ -45,9 +51,11 @@ func fetch(ctx context.Context, client *http.Client, url string, version ipversi
 			response.StatusCode, bodyToSingleLine(response.Body))
 	}
 
-	b, err := io.ReadAll(response.Body)
+	b, err := io.ReadAll(io.LimitReader(response.Body, maxBodySize+1))
 	if err != nil {
 		return nil, err
+	} else if len(b) > maxBodySize {
+		return nil, fmt.Errorf("%w: from %q exceeds %d bytes", ErrBodyTooLarge, url, maxBodySize)
 	}
 
 	if err := response.Body.Close(); err != nil {
@@ -98,7 +106,7 @@ func fetch(ctx context.Context, client *http.Client, url string, version ipversi
 		}
 	}
 
-	publicIP = net.ParseIP(ipString)
+	publicIP = ipaddr.Parse(ipString)
 	if publicIP == nil {
 		return nil, fmt.Errorf("%w: %s", ErrIPMalformed, ipString)
 	}
@@ -107,7 +115,9 @@ func fetch(ctx context.Context, client *http.Client, url string, version ipversi
 }
 
 func bodyToSingleLine(body io.Reader) (s string) {
-	b, err := io.ReadAll(body)
+	// the body is only used in error messages, so only read its beginning
+	const maxBytes = 1024
+	b, err := io.ReadAll(io.LimitReader(body, maxBytes))
 	if err != nil {
 		return ""
 	}
@@ -107,6 +107,19 @@ func (provider Provider) url(version ipversion.IPVersion) (url string, ok bool)
 	return url, true
 }
 
+// Hostname returns the hostname of the provider for the IP version given.
+func (provider Provider) Hostname(version ipversion.IPVersion) (hostname string, ok bool) {
+	rawURL, ok := provider.url(version)
+	if !ok {
+		return "", false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+	return u.Hostname(), true
+}
+
 func (provider Provider) SupportsVersion(version ipversion.IPVersion) bool {
 	_, ok := provider.url(version)
 	return ok
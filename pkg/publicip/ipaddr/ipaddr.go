@@ -0,0 +1,19 @@
+// Package ipaddr parses IP addresses as published to records.
+package ipaddr
+
+import (
+	"net"
+	"net/netip"
+)
+
+// Parse parses an IP address string, removing any IPv6 zone
+// such as %eth0 since the zone is not part of the address itself.
+// IPv4-mapped IPv6 addresses are returned as IPv4 addresses.
+// It returns nil if the string is not a valid IP address.
+func Parse(s string) (ip net.IP) {
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return nil
+	}
+	return net.IP(addr.WithZone("").Unmap().AsSlice())
+}
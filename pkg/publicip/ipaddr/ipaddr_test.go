@@ -0,0 +1,49 @@
+package ipaddr
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		s  string
+		ip net.IP
+	}{
+		"empty": {},
+		"malformed": {
+			s: "300.1.2.3",
+		},
+		"IPv4": {
+			s:  "203.0.113.1",
+			ip: net.IP{203, 0, 113, 1},
+		},
+		"IPv6": {
+			s:  "2001:db8::1",
+			ip: net.ParseIP("2001:db8::1"),
+		},
+		"IPv6 with zone": {
+			s:  "fe80::1%eth0",
+			ip: net.ParseIP("fe80::1"),
+		},
+		"IPv4-mapped IPv6": {
+			s:  "::ffff:203.0.113.1",
+			ip: net.IP{203, 0, 113, 1},
+		},
+	}
+
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ip := Parse(testCase.s)
+
+			assert.Equal(t, testCase.ip, ip)
+		})
+	}
+}